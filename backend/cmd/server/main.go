@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"rackview/internal/api"
 	"rackview/internal/database"
+	"rackview/internal/services"
+	"rackview/internal/store"
 )
 
 func main() {
@@ -17,11 +25,22 @@ func main() {
 	}
 	defer database.Close()
 
+	// "server migrate ..." manages the schema and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
 	// Run migrations
 	if err := database.RunMigrations(); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
+	// Racks, devices and connections are stored in the configured database
+	st := store.NewPostgresStore(database.DB)
+
 	// Determine static file paths
 	// In production, these will be set by Docker
 	// In development, we'll use placeholder paths
@@ -36,8 +55,26 @@ func main() {
 		indexPath = filepath.Join(staticPath, "index.html")
 	}
 
+	// Stop background work and the HTTP server on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start webhook delivery for status transition alerts
+	notifier := services.NewAlertNotifier(services.LoadAlertConfig(), services.NewWebhookService(st.Webhooks), st.AlertStates)
+	notifier.Start(ctx)
+
+	// Start background health checks; manual and scheduled checks share the same service
+	healthService := services.NewHealthService(st.Devices, st.HealthProbes, st.HealthChecks).WithAlerts(notifier)
+	scheduler := services.NewHealthScheduler(services.LoadHealthSchedulerConfig(), healthService)
+	scheduler.Start(ctx)
+
 	// Setup routes
-	router := api.SetupRoutes(staticPath, indexPath)
+	router := api.SetupRoutes(staticPath, indexPath, api.Dependencies{
+		Store:         st,
+		HealthService: healthService,
+		Scheduler:     scheduler,
+		Notifier:      notifier,
+	})
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
@@ -49,7 +86,35 @@ func main() {
 	fmt.Printf("Static path: %s\n", staticPath)
 	fmt.Printf("Index path: %s\n", indexPath)
 
-	if err := router.Run(":" + port); err != nil {
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case err := <-serverErr:
 		log.Fatalf("Failed to start server: %v", err)
+	case <-ctx.Done():
+	}
+
+	fmt.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown error: %v", err)
+	}
+	if err := scheduler.Stop(shutdownCtx); err != nil {
+		log.Printf("Health check scheduler shutdown error: %v", err)
+	}
+	if err := notifier.Stop(shutdownCtx); err != nil {
+		log.Printf("Alert notifier shutdown error: %v", err)
 	}
 }
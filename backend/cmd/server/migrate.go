@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"rackview/internal/database"
+)
+
+const migrateUsage = "usage: server migrate <up|down|status|to-version VERSION>"
+
+// runMigrate handles the migrate subcommand on a connected database
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		return database.RunMigrations()
+	case "down":
+		return database.RollbackMigration()
+	case "to-version":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return database.MigrateTo(version)
+	case "status":
+		return printMigrationStatus()
+	}
+	return errors.New(migrateUsage)
+}
+
+// printMigrationStatus prints one line per migration
+func printMigrationStatus() error {
+	statuses, err := database.MigrationStatuses()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		switch {
+		case status.Missing:
+			state = "applied, not in this build"
+		case status.Modified:
+			state = "applied, modified since"
+		case status.Applied:
+			state = "applied"
+		}
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
@@ -4,10 +4,21 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"rackview/internal/handlers"
+	"rackview/internal/metrics"
+	"rackview/internal/services"
+	"rackview/internal/store"
 )
 
+// Dependencies holds the long-lived services shared between the router and background workers
+type Dependencies struct {
+	Store         *store.Store
+	HealthService *services.HealthService
+	Scheduler     *services.HealthScheduler
+	Notifier      *services.AlertNotifier
+}
+
 // SetupRoutes configures all API routes
-func SetupRoutes(staticPath, indexPath string) *gin.Engine {
+func SetupRoutes(staticPath, indexPath string, deps Dependencies) *gin.Engine {
 	router := gin.Default()
 
 	// CORS configuration
@@ -17,20 +28,63 @@ func SetupRoutes(staticPath, indexPath string) *gin.Engine {
 	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
 	router.Use(cors.New(config))
 
+	// Prometheus metrics
+	registry := metrics.NewRegistry(deps.Store)
+	router.Use(registry.HTTP.Middleware())
+	router.GET("/metrics", gin.WrapH(registry.Handler()))
+
 	// Initialize handlers
-	rackHandler := handlers.NewRackHandler()
-	deviceHandler := handlers.NewDeviceHandler()
-	networkHandler := handlers.NewNetworkHandler()
+	rackHandler := handlers.NewRackHandler(deps.Store)
+	locationHandler := handlers.NewLocationHandler(deps.Store)
+	deviceHandler := handlers.NewDeviceHandler(deps.HealthService, deps.Store)
+	deviceTypeHandler := handlers.NewDeviceTypeHandler(deps.Store)
+	networkHandler := handlers.NewNetworkHandler(deps.Store)
+	cableHandler := handlers.NewCableHandler(deps.Store)
+	ipamHandler := handlers.NewIPAMHandler(deps.Store)
+	vlanHandler := handlers.NewVLANHandler(deps.Store)
+	healthHandler := handlers.NewHealthHandler(deps.Scheduler)
+	webhookHandler := handlers.NewWebhookHandler(deps.Notifier, deps.Store)
+	discoveryHandler := handlers.NewDiscoveryHandler(deps.Store)
 	staticHandler := handlers.NewStaticHandler(staticPath, indexPath)
 
 	// API routes
 	api := router.Group("/api")
 	{
+		// Location routes
+		sites := api.Group("/sites")
+		{
+			sites.GET("", locationHandler.GetAllSites)
+			sites.GET("/:id", locationHandler.GetSiteByID)
+			sites.GET("/:id/tree", locationHandler.GetSiteTree)
+			sites.POST("", locationHandler.CreateSite)
+			sites.PUT("/:id", locationHandler.UpdateSite)
+			sites.DELETE("/:id", locationHandler.DeleteSite)
+		}
+
+		rooms := api.Group("/rooms")
+		{
+			rooms.GET("", locationHandler.GetAllRooms)
+			rooms.GET("/:id", locationHandler.GetRoomByID)
+			rooms.POST("", locationHandler.CreateRoom)
+			rooms.PUT("/:id", locationHandler.UpdateRoom)
+			rooms.DELETE("/:id", locationHandler.DeleteRoom)
+		}
+
+		rows := api.Group("/rows")
+		{
+			rows.GET("", locationHandler.GetAllRows)
+			rows.GET("/:id", locationHandler.GetRowByID)
+			rows.POST("", locationHandler.CreateRow)
+			rows.PUT("/:id", locationHandler.UpdateRow)
+			rows.DELETE("/:id", locationHandler.DeleteRow)
+		}
+
 		// Rack routes
 		racks := api.Group("/racks")
 		{
 			racks.GET("", rackHandler.GetAllRacks)
 			racks.GET("/:id", rackHandler.GetRackByID)
+			racks.GET("/:id/power", rackHandler.GetRackPower)
 			racks.POST("", rackHandler.CreateRack)
 			racks.PUT("/:id", rackHandler.UpdateRack)
 			racks.DELETE("/:id", rackHandler.DeleteRack)
@@ -45,6 +99,51 @@ func SetupRoutes(staticPath, indexPath string) *gin.Engine {
 			devices.PUT("/:id", deviceHandler.UpdateDevice)
 			devices.DELETE("/:id", deviceHandler.DeleteDevice)
 			devices.POST("/:id/health-check", deviceHandler.CheckDeviceHealth)
+			devices.GET("/:id/health-history", deviceHandler.GetHealthHistory)
+			devices.GET("/:id/probes", deviceHandler.GetDeviceProbes)
+			devices.POST("/:id/probes", deviceHandler.CreateDeviceProbe)
+			devices.PUT("/:id/probes/:probeId", deviceHandler.UpdateDeviceProbe)
+			devices.DELETE("/:id/probes/:probeId", deviceHandler.DeleteDeviceProbe)
+			devices.GET("/:id/interfaces", deviceHandler.GetDeviceInterfaces)
+			devices.POST("/:id/interfaces", deviceHandler.CreateDeviceInterface)
+			devices.PUT("/:id/interfaces/:interfaceId", deviceHandler.UpdateDeviceInterface)
+			devices.DELETE("/:id/interfaces/:interfaceId", deviceHandler.DeleteDeviceInterface)
+			devices.PUT("/:id/interfaces/:interfaceId/vlans", vlanHandler.SetInterfaceVLANs)
+		}
+
+		// Device type catalog routes
+		deviceTypes := api.Group("/device-types")
+		{
+			deviceTypes.GET("", deviceTypeHandler.GetAllDeviceTypes)
+			deviceTypes.GET("/report", deviceTypeHandler.GetModelReport)
+			deviceTypes.GET("/:id", deviceTypeHandler.GetDeviceTypeByID)
+			deviceTypes.POST("", deviceTypeHandler.CreateDeviceType)
+			deviceTypes.PUT("/:id", deviceTypeHandler.UpdateDeviceType)
+			deviceTypes.DELETE("/:id", deviceTypeHandler.DeleteDeviceType)
+		}
+
+		// Health check scheduler routes
+		health := api.Group("/health")
+		{
+			health.GET("/scheduler", healthHandler.GetSchedulerStatus)
+		}
+
+		// Service discovery routes
+		discovery := api.Group("/discovery")
+		{
+			discovery.GET("/prometheus", discoveryHandler.GetPrometheusTargets)
+		}
+
+		// Webhook routes
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.GET("", webhookHandler.GetAllWebhooks)
+			webhooks.GET("/:id", webhookHandler.GetWebhookByID)
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+			webhooks.POST("/:id/test", webhookHandler.TestWebhook)
 		}
 
 		// Network routes
@@ -57,7 +156,67 @@ func SetupRoutes(staticPath, indexPath string) *gin.Engine {
 				connections.POST("", networkHandler.CreateConnection)
 				connections.PUT("/:id", networkHandler.UpdateConnection)
 				connections.DELETE("/:id", networkHandler.DeleteConnection)
+				connections.PUT("/:id/vlans", vlanHandler.SetConnectionVLANs)
+			}
+			network.GET("/port-usage", networkHandler.GetPortUsage)
+			network.GET("/trace", networkHandler.TraceCablePath)
+			network.GET("/topology", networkHandler.GetTopology)
+			network.GET("/analysis", networkHandler.GetNetworkAnalysis)
+			network.POST("/lldp/import", networkHandler.ImportLLDPNeighbors)
+		}
+
+		// Cable inventory routes
+		cables := api.Group("/cables")
+		{
+			cables.GET("", cableHandler.GetAllCables)
+			cables.GET("/:id", cableHandler.GetCableByID)
+			cables.GET("/:id/label", cableHandler.GetCableLabel)
+			cables.POST("", cableHandler.CreateCable)
+			cables.PUT("/:id", cableHandler.UpdateCable)
+			cables.DELETE("/:id", cableHandler.DeleteCable)
+		}
+
+		// IP address management routes
+		ipam := api.Group("/ipam")
+		{
+			prefixes := ipam.Group("/prefixes")
+			{
+				prefixes.GET("", ipamHandler.GetAllPrefixes)
+				prefixes.GET("/:id", ipamHandler.GetPrefixByID)
+				prefixes.GET("/:id/available", ipamHandler.GetAvailableAddresses)
+				prefixes.POST("", ipamHandler.CreatePrefix)
+				prefixes.PUT("/:id", ipamHandler.UpdatePrefix)
+				prefixes.DELETE("/:id", ipamHandler.DeletePrefix)
 			}
+			addresses := ipam.Group("/addresses")
+			{
+				addresses.GET("", ipamHandler.GetAllIPAddresses)
+				addresses.GET("/:id", ipamHandler.GetIPAddressByID)
+				addresses.POST("", ipamHandler.CreateIPAddress)
+				addresses.PUT("/:id", ipamHandler.UpdateIPAddress)
+				addresses.DELETE("/:id", ipamHandler.DeleteIPAddress)
+			}
+			ipam.GET("/conflicts", ipamHandler.GetConflicts)
+		}
+
+		// VLAN routes
+		vlanGroups := api.Group("/vlan-groups")
+		{
+			vlanGroups.GET("", vlanHandler.GetAllVLANGroups)
+			vlanGroups.GET("/:id", vlanHandler.GetVLANGroupByID)
+			vlanGroups.POST("", vlanHandler.CreateVLANGroup)
+			vlanGroups.PUT("/:id", vlanHandler.UpdateVLANGroup)
+			vlanGroups.DELETE("/:id", vlanHandler.DeleteVLANGroup)
+		}
+
+		vlans := api.Group("/vlans")
+		{
+			vlans.GET("", vlanHandler.GetAllVLANs)
+			vlans.GET("/:id", vlanHandler.GetVLANByID)
+			vlans.GET("/:id/devices", vlanHandler.GetVLANDevices)
+			vlans.POST("", vlanHandler.CreateVLAN)
+			vlans.PUT("/:id", vlanHandler.UpdateVLAN)
+			vlans.DELETE("/:id", vlanHandler.DeleteVLAN)
 		}
 	}
 
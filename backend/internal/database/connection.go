@@ -11,8 +11,20 @@ import (
 // DB is the database connection pool
 var DB *sql.DB
 
-// Connect initializes the database connection
+// Connect initializes the database connection for the dialect selected by DB_DRIVER
 func Connect() error {
+	switch dialect := Dialect(getEnv("DB_DRIVER", string(Postgres))); dialect {
+	case Postgres:
+		return connectPostgres()
+	case SQLite:
+		return connectSQLite()
+	default:
+		return fmt.Errorf("unsupported DB_DRIVER %q (expected postgres or sqlite)", dialect)
+	}
+}
+
+// connectPostgres connects to the Postgres server given by the DB_* settings
+func connectPostgres() error {
 	host := getEnv("DB_HOST", "localhost")
 	port := getEnv("DB_PORT", "5432")
 	user := getEnv("DB_USER", "rackview")
@@ -37,6 +49,29 @@ func Connect() error {
 	DB.SetMaxOpenConns(25)
 	DB.SetMaxIdleConns(5)
 
+	CurrentDialect = Postgres
+	return nil
+}
+
+// connectSQLite opens (or creates) the SQLite database file at DB_PATH
+func connectSQLite() error {
+	path := getEnv("DB_PATH", "rackview.db")
+
+	var err error
+	DB, err = sql.Open(sqliteDriverName, sqliteDSN(path))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := DB.Ping(); err != nil {
+		return fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	// SQLite has a single writer; more connections only help concurrent readers
+	DB.SetMaxOpenConns(8)
+	DB.SetMaxIdleConns(8)
+
+	CurrentDialect = SQLite
 	return nil
 }
 
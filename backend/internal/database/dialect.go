@@ -0,0 +1,73 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect identifies the SQL database behind DB
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// CurrentDialect is the dialect of DB, set by Connect
+var CurrentDialect = Postgres
+
+// IsConstraintViolation reports whether err was raised by the named database constraint.
+// SQLite names UNIQUE violations by their columns, so a constraint matches there when it follows
+// the Postgres table_column_key or the repo's idx_table_column naming. Triggers standing in for
+// Postgres constraints raise "EXCLUDE constraint failed: <name>".
+func IsConstraintViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint == constraint
+	}
+
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code()&0xff != sqlite3.SQLITE_CONSTRAINT {
+		return false
+	}
+	for _, name := range sqliteConstraintNames(sqliteErr.Error()) {
+		if name == constraint {
+			return true
+		}
+	}
+	return false
+}
+
+// sqliteConstraintNames returns the constraint names an SQLite constraint error may stand for, e.g.
+// "UNIQUE constraint failed: cables.label (2067)" gives cables_label_key and idx_cables_label
+func sqliteConstraintNames(message string) []string {
+	_, detail, found := strings.Cut(message, " constraint failed: ")
+	if !found {
+		return nil
+	}
+	if i := strings.LastIndex(detail, " ("); i >= 0 {
+		detail = detail[:i]
+	}
+
+	// Expression indexes are reported as index 'name', named CHECK constraints by name
+	if name, ok := strings.CutPrefix(detail, "index '"); ok {
+		return []string{strings.TrimSuffix(name, "'")}
+	}
+	if !strings.Contains(detail, ".") {
+		return []string{detail}
+	}
+
+	var table string
+	var columns []string
+	for _, column := range strings.Split(detail, ", ") {
+		t, c, _ := strings.Cut(column, ".")
+		table = t
+		columns = append(columns, c)
+	}
+	name := table + "_" + strings.Join(columns, "_")
+	return []string{name + "_key", "idx_" + name}
+}
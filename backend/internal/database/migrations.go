@@ -1,111 +1,498 @@
 package database
 
 import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
 	"embed"
+	"encoding/hex"
 	"fmt"
 	"io/fs"
-	"path/filepath"
+	"path"
+	"regexp"
 	"sort"
-	"strings"
+	"strconv"
+	"time"
 
 	_ "github.com/lib/pq"
 )
 
-//go:embed migrations/*.sql
+//go:embed migrations/*.sql migrations/sqlite/*.sql
 var migrationsFS embed.FS
 
-// initMigrationTable creates the migrations tracking table if it doesn't exist
-func initMigrationTable() error {
-	_, err := DB.Exec(`
+// migrationLockID is the Postgres advisory lock key held while migrating, so replicas that start
+// together apply each migration once
+const migrationLockID = 727463686
+
+// migrationFilePattern matches migration files such as 003_add_speed_to_connections.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// recordedVersionPattern extracts the version from a filename recorded before versions were stored
+var recordedVersionPattern = regexp.MustCompile(`^\d+`)
+
+// Migration is a versioned schema change with the SQL to apply and to revert it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the SHA-256 of Up, recorded when the migration is applied
+	Checksum string
+
+	upFile string
+}
+
+// MigrationStatus describes a migration and whether it has been applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified is set when the up file differs from the one that was applied
+	Modified bool
+	// Missing is set when the database records a migration this build does not contain
+	Missing bool
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	filename   string
+	checksum   string
+	executedAt time.Time
+}
+
+// migrationsDir returns the embedded directory holding the migrations of the current dialect.
+// SQLite starts from a consolidated schema rather than replaying the Postgres history.
+func migrationsDir() string {
+	if CurrentDialect == SQLite {
+		return "migrations/sqlite"
+	}
+	return "migrations"
+}
+
+// loadMigrations reads the migrations of the current dialect in version order. Every version needs
+// both an up and a down file.
+func loadMigrations() ([]Migration, error) {
+	files, err := fs.ReadDir(migrationsFS, migrationsDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration filename %s (expected NNN_name.up.sql or NNN_name.down.sql)", file.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migration.Name, match[2], version)
+		}
+
+		content, err := migrationsFS.ReadFile(path.Join(migrationsDir(), file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file.Name(), err)
+		}
+		if match[3] == "up" {
+			sum := sha256.Sum256(content)
+			migration.Up = string(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+			migration.upFile = file.Name()
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.upFile == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// migrator runs migrations on a single connection that holds the migration lock
+type migrator struct {
+	ctx        context.Context
+	conn       *sql.Conn
+	migrations []Migration
+	applied    map[int]appliedMigration
+}
+
+// withMigrator locks out other migrating processes, brings the tracking table up to date and runs fn
+func withMigrator(fn func(m *migrator) error) error {
+	ctx := context.Background()
+	conn, err := DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	unlock, err := lockMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m := &migrator{ctx: ctx, conn: conn}
+	if err := m.initMigrationTable(); err != nil {
+		return err
+	}
+	if m.migrations, err = loadMigrations(); err != nil {
+		return err
+	}
+	if err := m.loadApplied(); err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+// lockMigrations takes the Postgres advisory lock, waiting for another process that holds it.
+// SQLite has no advisory locks; there each migration's transaction takes the write lock and
+// rechecks whether the migration was applied in the meantime.
+func lockMigrations(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if CurrentDialect != Postgres {
+		return func() {}, nil
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationLockID).Scan(&locked); err != nil {
+		return nil, fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	if !locked {
+		fmt.Println("Waiting for another process to finish migrating")
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+			return nil, fmt.Errorf("failed to take migration lock: %w", err)
+		}
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID); err != nil {
+			fmt.Printf("Failed to release migration lock: %v\n", err)
+		}
+	}, nil
+}
+
+// initMigrationTable creates the migrations tracking table if it doesn't exist, and adds the version
+// and checksum columns to tables created before migrations were versioned
+func (m *migrator) initMigrationTable() error {
+	idColumn := "id SERIAL PRIMARY KEY"
+	if CurrentDialect == SQLite {
+		idColumn = "id INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	_, err := m.conn.ExecContext(m.ctx, `
 		CREATE TABLE IF NOT EXISTS schema_migrations (
-			id SERIAL PRIMARY KEY,
+			`+idColumn+`,
+			version INTEGER,
 			filename VARCHAR(255) NOT NULL UNIQUE,
+			checksum VARCHAR(64),
 			executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
+
+	hasVersion, err := m.hasColumn("schema_migrations", "version")
+	if err != nil {
+		return err
+	}
+	if !hasVersion {
+		for _, column := range []string{"version INTEGER", "checksum VARCHAR(64)"} {
+			if _, err := m.conn.ExecContext(m.ctx, "ALTER TABLE schema_migrations ADD COLUMN "+column); err != nil {
+				return fmt.Errorf("failed to upgrade migrations table: %w", err)
+			}
+		}
+	}
+
+	// Older rows only record the filename, which starts with the version
+	rows, err := m.conn.QueryContext(m.ctx, "SELECT id, filename FROM schema_migrations WHERE version IS NULL")
+	if err != nil {
+		return fmt.Errorf("failed to upgrade migrations table: %w", err)
+	}
+	versions := map[int]int{}
+	for rows.Next() {
+		var id int
+		var filename string
+		if err := rows.Scan(&id, &filename); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to upgrade migrations table: %w", err)
+		}
+		match := recordedVersionPattern.FindString(filename)
+		if match == "" {
+			rows.Close()
+			return fmt.Errorf("recorded migration %s has no version", filename)
+		}
+		versions[id], _ = strconv.Atoi(match)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to upgrade migrations table: %w", err)
+	}
+	for id, version := range versions {
+		if _, err := m.conn.ExecContext(m.ctx, "UPDATE schema_migrations SET version = $1 WHERE id = $2", version, id); err != nil {
+			return fmt.Errorf("failed to upgrade migrations table: %w", err)
+		}
+	}
+
+	if _, err := m.conn.ExecContext(m.ctx, "CREATE UNIQUE INDEX IF NOT EXISTS idx_schema_migrations_version ON schema_migrations(version)"); err != nil {
+		return fmt.Errorf("failed to upgrade migrations table: %w", err)
+	}
 	return nil
 }
 
-// hasMigrationBeenRun checks if a migration has already been executed
-func hasMigrationBeenRun(filename string) (bool, error) {
+// hasColumn reports whether table has the named column
+func (m *migrator) hasColumn(table, column string) (bool, error) {
+	query := "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2"
+	if CurrentDialect == SQLite {
+		query = "SELECT COUNT(*) FROM pragma_table_info($1) WHERE name = $2"
+	}
 	var count int
-	err := DB.QueryRow(
-		"SELECT COUNT(*) FROM schema_migrations WHERE filename = $1",
-		filename,
-	).Scan(&count)
-	if err != nil {
-		return false, fmt.Errorf("failed to check migration status: %w", err)
+	if err := m.conn.QueryRowContext(m.ctx, query, table, column).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to inspect %s: %w", table, err)
 	}
 	return count > 0, nil
 }
 
-// recordMigration records that a migration has been executed
-func recordMigration(filename string) error {
-	_, err := DB.Exec(
-		"INSERT INTO schema_migrations (filename) VALUES ($1) ON CONFLICT (filename) DO NOTHING",
-		filename,
-	)
+// loadApplied reads the applied migrations. Migrations applied before checksums were recorded get
+// the checksum of the current file.
+func (m *migrator) loadApplied() error {
+	rows, err := m.conn.QueryContext(m.ctx, "SELECT version, filename, COALESCE(checksum, ''), executed_at FROM schema_migrations")
 	if err != nil {
-		return fmt.Errorf("failed to record migration: %w", err)
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	m.applied = map[int]appliedMigration{}
+	for rows.Next() {
+		var version int
+		var applied appliedMigration
+		if err := rows.Scan(&version, &applied.filename, &applied.checksum, &applied.executedAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		m.applied[version] = applied
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	for _, migration := range m.migrations {
+		applied, ok := m.applied[migration.Version]
+		if !ok || applied.checksum != "" {
+			continue
+		}
+		if _, err := m.conn.ExecContext(m.ctx,
+			"UPDATE schema_migrations SET checksum = $1 WHERE version = $2",
+			migration.Checksum, migration.Version,
+		); err != nil {
+			return fmt.Errorf("failed to record checksum of %s: %w", applied.filename, err)
+		}
+		applied.checksum = migration.Checksum
+		m.applied[migration.Version] = applied
 	}
 	return nil
 }
 
-// RunMigrations executes all SQL migration files in order, skipping already executed ones
-func RunMigrations() error {
-	// Initialize migration tracking table
-	if err := initMigrationTable(); err != nil {
-		return err
+// verify checks that every applied migration is part of this build and unchanged since it was applied
+func (m *migrator) verify() error {
+	known := map[int]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		if applied, ok := m.applied[migration.Version]; ok && applied.checksum != migration.Checksum {
+			return fmt.Errorf("migration %s was modified after it was applied (checksum %s, recorded %s)",
+				migration.upFile, migration.Checksum, applied.checksum)
+		}
+	}
+	for version, applied := range m.applied {
+		if !known[version] {
+			return fmt.Errorf("applied migration %s is not part of this build", applied.filename)
+		}
 	}
+	return nil
+}
 
-	// Read all migration files
-	files, err := fs.ReadDir(migrationsFS, "migrations")
+// apply runs the up SQL of a migration and records it in one transaction
+func (m *migrator) apply(migration Migration) error {
+	tx, err := m.conn.BeginTx(m.ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Filter and sort SQL files
-	var sqlFiles []string
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
-			sqlFiles = append(sqlFiles, file.Name())
-		}
+	// Without an advisory lock another process may have applied it since the status was read
+	var count int
+	if err := tx.QueryRowContext(m.ctx, "SELECT COUNT(*) FROM schema_migrations WHERE version = $1", migration.Version).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check migration status: %w", err)
+	}
+	if count > 0 {
+		fmt.Printf("Skipping already executed migration: %s\n", migration.upFile)
+		return nil
 	}
-	sort.Strings(sqlFiles)
 
-	// Execute each migration that hasn't been run yet
-	for _, filename := range sqlFiles {
-		// Check if migration has already been run
-		alreadyRun, err := hasMigrationBeenRun(filename)
-		if err != nil {
-			return err
+	if _, err := tx.ExecContext(m.ctx, migration.Up); err != nil {
+		return fmt.Errorf("failed to execute migration %s: %w", migration.upFile, err)
+	}
+	if _, err := tx.ExecContext(m.ctx,
+		"INSERT INTO schema_migrations (version, filename, checksum) VALUES ($1, $2, $3)",
+		migration.Version, migration.upFile, migration.Checksum,
+	); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", migration.upFile, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", migration.upFile, err)
+	}
+
+	m.applied[migration.Version] = appliedMigration{filename: migration.upFile, checksum: migration.Checksum, executedAt: time.Now()}
+	fmt.Printf("Executed migration: %s\n", migration.upFile)
+	return nil
+}
+
+// revert runs the down SQL of a migration and removes its record in one transaction
+func (m *migrator) revert(migration Migration) error {
+	tx, err := m.conn.BeginTx(m.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(m.ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+	if err != nil {
+		return fmt.Errorf("failed to remove migration record: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected == 0 {
+		fmt.Printf("Skipping already reverted migration: %03d_%s\n", migration.Version, migration.Name)
+		return nil
+	}
+
+	if _, err := tx.ExecContext(m.ctx, migration.Down); err != nil {
+		return fmt.Errorf("failed to revert migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	delete(m.applied, migration.Version)
+	fmt.Printf("Reverted migration: %03d_%s\n", migration.Version, migration.Name)
+	return nil
+}
+
+// migrateTo applies pending migrations up to version and reverts applied ones above it
+func (m *migrator) migrateTo(version int) error {
+	if err := m.verify(); err != nil {
+		return err
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := m.applied[migration.Version]; ok && migration.Version > version {
+			if err := m.revert(migration); err != nil {
+				return err
+			}
 		}
+	}
+	for _, migration := range m.migrations {
+		if _, ok := m.applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.apply(migration); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
-		if alreadyRun {
-			fmt.Printf("Skipping already executed migration: %s\n", filename)
-			continue
+// RunMigrations applies every pending migration in version order
+func RunMigrations() error {
+	return withMigrator(func(m *migrator) error {
+		if len(m.migrations) == 0 {
+			return nil
 		}
+		return m.migrateTo(m.migrations[len(m.migrations)-1].Version)
+	})
+}
 
-		content, err := migrationsFS.ReadFile(filepath.Join("migrations", filename))
-		if err != nil {
-			return fmt.Errorf("failed to read migration %s: %w", filename, err)
+// RollbackMigration reverts the most recently applied migration
+func RollbackMigration() error {
+	return withMigrator(func(m *migrator) error {
+		latest := 0
+		for version := range m.applied {
+			if version > latest {
+				latest = version
+			}
+		}
+		if latest == 0 {
+			fmt.Println("No migrations to revert")
+			return nil
 		}
 
-		// Execute the migration
-		if _, err := DB.Exec(string(content)); err != nil {
-			return fmt.Errorf("failed to execute migration %s: %w", filename, err)
+		target := 0
+		for _, migration := range m.migrations {
+			if migration.Version < latest {
+				target = migration.Version
+			}
 		}
+		return m.migrateTo(target)
+	})
+}
 
-		// Record that this migration has been executed
-		if err := recordMigration(filename); err != nil {
-			return fmt.Errorf("failed to record migration %s: %w", filename, err)
+// MigrateTo applies or reverts migrations until version is the latest applied one; version 0
+// reverts every migration
+func MigrateTo(version int) error {
+	return withMigrator(func(m *migrator) error {
+		if version != 0 {
+			found := false
+			for _, migration := range m.migrations {
+				found = found || migration.Version == version
+			}
+			if !found {
+				return fmt.Errorf("unknown migration version %d", version)
+			}
 		}
+		return m.migrateTo(version)
+	})
+}
 
-		fmt.Printf("Executed migration: %s\n", filename)
+// MigrationStatuses lists every migration of this build and any unknown applied ones, in version order
+func MigrationStatuses() ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := withMigrator(func(m *migrator) error {
+		known := map[int]bool{}
+		for _, migration := range m.migrations {
+			known[migration.Version] = true
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if applied, ok := m.applied[migration.Version]; ok {
+				executedAt := applied.executedAt
+				status.Applied = true
+				status.AppliedAt = &executedAt
+				status.Modified = applied.checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		for version, applied := range m.applied {
+			if known[version] {
+				continue
+			}
+			executedAt := applied.executedAt
+			statuses = append(statuses, MigrationStatus{
+				Version: version, Name: applied.filename, Applied: true, AppliedAt: &executedAt, Missing: true,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
 }
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// connectTestSQLite connects DB to a new SQLite database for the rest of the test
+func connectTestSQLite(t *testing.T) {
+	t.Helper()
+	t.Setenv("DB_DRIVER", string(SQLite))
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "rackview.db"))
+	if err := Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { Close() })
+}
+
+func TestSeedRollbackKeepsAddedDevices(t *testing.T) {
+	connectTestSQLite(t)
+	if err := RunMigrations(); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	// A device added to a seed rack, and a rack of its own
+	if _, err := DB.Exec(`INSERT INTO devices (rack_id, name, icon, type, position_u, size_u, status, model)
+		VALUES (1, 'web-1', '🖥️', 'server', 24, 1, 'online', 'R650')`); err != nil {
+		t.Fatalf("insert device: %v", err)
+	}
+	if _, err := DB.Exec(`INSERT INTO racks (name, description, size_u) VALUES ('Rack C', 'Added rack', 42)`); err != nil {
+		t.Fatalf("insert rack: %v", err)
+	}
+
+	if err := MigrateTo(1); err != nil {
+		t.Fatalf("migrate to 1: %v", err)
+	}
+
+	var devices []string
+	rows, err := DB.Query("SELECT name FROM devices ORDER BY id")
+	if err != nil {
+		t.Fatalf("query devices: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scan device: %v", err)
+		}
+		devices = append(devices, name)
+	}
+	if len(devices) != 1 || devices[0] != "web-1" {
+		t.Errorf("devices after rolling back the seed = %v, want only web-1", devices)
+	}
+
+	var racks []string
+	rackRows, err := DB.Query("SELECT name FROM racks ORDER BY id")
+	if err != nil {
+		t.Fatalf("query racks: %v", err)
+	}
+	defer rackRows.Close()
+	for rackRows.Next() {
+		var name string
+		if err := rackRows.Scan(&name); err != nil {
+			t.Fatalf("scan rack: %v", err)
+		}
+		racks = append(racks, name)
+	}
+	if want := []string{"Rack A — Compute", "Rack C"}; len(racks) != 2 || racks[0] != want[0] || racks[1] != want[1] {
+		t.Errorf("racks after rolling back the seed = %v, want %v", racks, want)
+	}
+}
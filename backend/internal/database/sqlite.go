@@ -0,0 +1,334 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteDriverName is the database/sql driver that runs the services' Postgres SQL on SQLite
+const sqliteDriverName = "rackview-sqlite"
+
+func init() {
+	sql.Register(sqliteDriverName, &sqliteDriver{updatedAt: map[string]bool{}})
+}
+
+// sqliteTimeFormat matches the format of SQLite's CURRENT_TIMESTAMP, so stored times compare as text
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999"
+
+// sqliteDSN builds the connection string for a database file. Writers wait for each other instead
+// of failing, and transactions take the write lock up front like Postgres row locks would.
+func sqliteDSN(path string) string {
+	return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate", path)
+}
+
+// sqliteDriver wraps the SQLite driver and rewrites the Postgres constructs the services use.
+// Each rewrite keeps the meaning of the Postgres query for the way the services call it:
+//
+//   - ::TYPE casts are dropped; SQLite compares by affinity
+//   - ILIKE becomes LIKE, which is case-insensitive for ASCII in SQLite
+//   - FOR [NO KEY] UPDATE is dropped; transactions already hold the write lock
+//   - host(address) becomes address; SQLite stores addresses without a prefix length
+//   - = ANY($n) and cardinality($n) read the pq.Array parameter as a JSON array
+//   - ARRAY(SELECT ...) becomes a group_concat in Postgres array syntax, so pq.Array scans it
+//   - EXTRACT(EPOCH FROM col) becomes strftime('%s', col)
+//   - UPDATE sets updated_at itself, since RETURNING does not see the changes of triggers
+//
+// String literals, quoted identifiers and comments are set aside before rewriting and put back
+// unchanged, so only the SQL around them is translated.
+type sqliteDriver struct {
+	base sqlite.Driver
+
+	mu sync.Mutex
+	// updatedAt records which tables have an updated_at column
+	updatedAt map[string]bool
+}
+
+// sqliteBaseConn is the part of the SQLite connection the wrapper forwards to
+type sqliteBaseConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+func (d *sqliteDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	base, ok := conn.(sqliteBaseConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("sqlite connection does not support contexts")
+	}
+	return &sqliteConn{sqliteBaseConn: base, driver: d}, nil
+}
+
+// sqliteConn rewrites every statement before handing it to the SQLite connection
+type sqliteConn struct {
+	sqliteBaseConn
+	driver *sqliteDriver
+}
+
+func (c *sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	query, err := c.rewrite(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := c.sqliteBaseConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteStmt{Stmt: stmt}, nil
+}
+
+func (c *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	query, err := c.rewrite(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return c.sqliteBaseConn.ExecContext(ctx, query, sqliteArgs(args))
+}
+
+func (c *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	query, err := c.rewrite(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return c.sqliteBaseConn.QueryContext(ctx, query, sqliteArgs(args))
+}
+
+// sqliteStmt converts the arguments of a prepared statement
+type sqliteStmt struct {
+	driver.Stmt
+}
+
+func (s *sqliteStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *sqliteStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *sqliteStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.Stmt.(driver.StmtExecContext).ExecContext(ctx, sqliteArgs(args))
+}
+
+func (s *sqliteStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.Stmt.(driver.StmtQueryContext).QueryContext(ctx, sqliteArgs(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// sqliteArgs stores times as UTC text in the format of CURRENT_TIMESTAMP
+func sqliteArgs(args []driver.NamedValue) []driver.NamedValue {
+	converted := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		if t, ok := arg.Value.(time.Time); ok {
+			arg.Value = t.UTC().Format(sqliteTimeFormat)
+		}
+		converted[i] = arg
+	}
+	return converted
+}
+
+var (
+	sqliteCastPattern      = regexp.MustCompile(`::[A-Z]+(?: PRECISION)?(?:\[\])?`)
+	sqliteILikePattern     = regexp.MustCompile(`\bILIKE\b`)
+	sqliteForUpdatePattern = regexp.MustCompile(`\s+FOR (?:NO KEY )?UPDATE\b`)
+	sqliteHostPattern      = regexp.MustCompile(`\bhost\(([^()]*)\)`)
+	sqliteAnyPattern       = regexp.MustCompile(`= ANY\((\$\d+)\)`)
+	sqliteCardinalityRegex = regexp.MustCompile(`\bcardinality\((\$\d+)\)`)
+	sqliteEpochPattern     = regexp.MustCompile(`\bEXTRACT\(EPOCH FROM ([\w.]+)\)`)
+	sqliteUpdatePattern    = regexp.MustCompile(`^\s*UPDATE\s+(\w+)\s+SET\s+`)
+	sqliteQuotedPattern    = regexp.MustCompile(`\x00(\d+)\x00`)
+)
+
+// maskQuoted replaces string literals, quoted identifiers and comments with numbered
+// placeholders that none of the rewrites match, and returns the text they stand for
+func maskQuoted(query string) (string, []string) {
+	var b strings.Builder
+	var quoted []string
+	for i := 0; i < len(query); i++ {
+		end := -1
+		switch {
+		case query[i] == '\'' || query[i] == '"':
+			end = closingQuote(query, i)
+		case strings.HasPrefix(query[i:], "--"):
+			end = strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			end = strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i + 4
+			}
+		}
+		if end < 0 {
+			b.WriteByte(query[i])
+			continue
+		}
+		fmt.Fprintf(&b, "\x00%d\x00", len(quoted))
+		quoted = append(quoted, query[i:end])
+		i = end - 1
+	}
+	return b.String(), quoted
+}
+
+// closingQuote returns the index just past the quote closing the one at open, reading a doubled
+// quote as an escaped one. An unterminated quote runs to the end of the query.
+func closingQuote(s string, open int) int {
+	quote := s[open]
+	for i := open + 1; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(s)
+}
+
+// unmaskQuoted puts back the text maskQuoted set aside
+func unmaskQuoted(query string, quoted []string) string {
+	return sqliteQuotedPattern.ReplaceAllStringFunc(query, func(match string) string {
+		i, _ := strconv.Atoi(sqliteQuotedPattern.FindStringSubmatch(match)[1])
+		return quoted[i]
+	})
+}
+
+// sqliteJSONArray turns a pq.Array parameter such as {1,2} into a JSON array expression
+func sqliteJSONArray(param string) string {
+	return fmt.Sprintf("('[' || substr(%[1]s, 2, length(%[1]s) - 2) || ']')", param)
+}
+
+// rewrite translates a Postgres query into SQLite
+func (c *sqliteConn) rewrite(ctx context.Context, query string) (string, error) {
+	query, quoted := maskQuoted(query)
+	query = sqliteCastPattern.ReplaceAllString(query, "")
+	query = sqliteILikePattern.ReplaceAllString(query, "LIKE")
+	query = sqliteForUpdatePattern.ReplaceAllString(query, "")
+	query = sqliteHostPattern.ReplaceAllString(query, "$1")
+	query = sqliteEpochPattern.ReplaceAllString(query, "CAST(strftime('%s', $1) AS INTEGER)")
+	query = sqliteAnyPattern.ReplaceAllStringFunc(query, func(match string) string {
+		param := sqliteAnyPattern.FindStringSubmatch(match)[1]
+		return "IN (SELECT value FROM json_each" + sqliteJSONArray(param) + ")"
+	})
+	query = sqliteCardinalityRegex.ReplaceAllStringFunc(query, func(match string) string {
+		param := sqliteCardinalityRegex.FindStringSubmatch(match)[1]
+		return "json_array_length" + sqliteJSONArray(param)
+	})
+	query = rewriteArraySubqueries(query)
+
+	if match := sqliteUpdatePattern.FindStringSubmatchIndex(query); match != nil && !strings.Contains(query, "updated_at =") {
+		hasUpdatedAt, err := c.hasUpdatedAt(ctx, query[match[2]:match[3]])
+		if err != nil {
+			return "", err
+		}
+		if hasUpdatedAt {
+			query = query[:match[1]] + "updated_at = CURRENT_TIMESTAMP, " + query[match[1]:]
+		}
+	}
+
+	return unmaskQuoted(query, quoted), nil
+}
+
+// rewriteArraySubqueries turns ARRAY(SELECT e FROM ... ORDER BY o) into a subquery that builds the
+// same array literal, '{e1,e2}', with group_concat
+func rewriteArraySubqueries(query string) string {
+	for {
+		start := strings.Index(query, "ARRAY(SELECT ")
+		if start < 0 {
+			return query
+		}
+		open := start + len("ARRAY")
+		end := matchingParen(query, open)
+		if end < 0 {
+			return query
+		}
+
+		expr, from, _ := strings.Cut(strings.TrimPrefix(query[open+1:end], "SELECT "), " FROM ")
+		order := ""
+		if i := strings.LastIndex(from, " ORDER BY "); i >= 0 {
+			from, order = from[:i], from[i:]
+		}
+		query = query[:start] +
+			"(SELECT '{' || COALESCE(group_concat(" + expr + ", ','" + order + "), '') || '}' FROM " + from + ")" +
+			query[end+1:]
+	}
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open, or -1
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// hasUpdatedAt reports whether table has an updated_at column, caching the answer
+func (c *sqliteConn) hasUpdatedAt(ctx context.Context, table string) (bool, error) {
+	c.driver.mu.Lock()
+	hasColumn, ok := c.driver.updatedAt[table]
+	c.driver.mu.Unlock()
+	if ok {
+		return hasColumn, nil
+	}
+
+	rows, err := c.sqliteBaseConn.QueryContext(ctx,
+		"SELECT COUNT(*) FROM pragma_table_info($1) WHERE name = 'updated_at'",
+		[]driver.NamedValue{{Ordinal: 1, Value: table}})
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	count, _ := values[0].(int64)
+
+	c.driver.mu.Lock()
+	c.driver.updatedAt[table] = count > 0
+	c.driver.mu.Unlock()
+	return count > 0, nil
+}
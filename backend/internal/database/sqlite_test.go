@@ -0,0 +1,206 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// openSQLiteConn opens a rewriting connection to a new database with one table that has an
+// updated_at column and one that does not
+func openSQLiteConn(t *testing.T) *sqliteConn {
+	t.Helper()
+	d := &sqliteDriver{updatedAt: map[string]bool{}}
+	conn, err := d.Open(sqliteDSN(filepath.Join(t.TempDir(), "rewrite.db")))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	c := conn.(*sqliteConn)
+	for _, stmt := range []string{
+		"CREATE TABLE racks (id INTEGER PRIMARY KEY, name TEXT, updated_at TIMESTAMP)",
+		"CREATE TABLE connection_vlans (connection_id INTEGER, vlan_id INTEGER)",
+	} {
+		if _, err := c.ExecContext(context.Background(), stmt, nil); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	return c
+}
+
+func TestSQLiteRewrite(t *testing.T) {
+	c := openSQLiteConn(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "casts",
+			query: "SELECT $1::INTEGER, latency::DOUBLE PRECISION, $2::INT[] FROM racks",
+			want:  "SELECT $1, latency, $2 FROM racks",
+		},
+		{
+			name:  "ilike",
+			query: "SELECT id FROM cables WHERE label ILIKE $1",
+			want:  "SELECT id FROM cables WHERE label LIKE $1",
+		},
+		{
+			name:  "for update",
+			query: "SELECT id FROM devices WHERE id = $1 FOR UPDATE",
+			want:  "SELECT id FROM devices WHERE id = $1",
+		},
+		{
+			name:  "for no key update",
+			query: "SELECT id FROM racks WHERE id = $1 FOR NO KEY UPDATE",
+			want:  "SELECT id FROM racks WHERE id = $1",
+		},
+		{
+			name:  "host",
+			query: "SELECT host(address) FROM ip_addresses WHERE host(a.address) = $1",
+			want:  "SELECT address FROM ip_addresses WHERE a.address = $1",
+		},
+		{
+			name:  "any",
+			query: "DELETE FROM connection_vlans WHERE vlan_id = ANY($2)",
+			want:  "DELETE FROM connection_vlans WHERE vlan_id IN (SELECT value FROM json_each('[' || substr($2, 2, length($2) - 2) || ']'))",
+		},
+		{
+			name:  "cardinality",
+			query: "SELECT id FROM racks WHERE cardinality($1) = 0",
+			want:  "SELECT id FROM racks WHERE json_array_length('[' || substr($1, 2, length($1) - 2) || ']') = 0",
+		},
+		{
+			name:  "array subquery",
+			query: "SELECT ARRAY(SELECT vlan_id FROM connection_vlans WHERE connection_id = c.id ORDER BY vlan_id) FROM network_connections c",
+			want:  "SELECT (SELECT '{' || COALESCE(group_concat(vlan_id, ',' ORDER BY vlan_id), '') || '}' FROM connection_vlans WHERE connection_id = c.id) FROM network_connections c",
+		},
+		{
+			name:  "nested array subquery",
+			query: "SELECT ARRAY(SELECT v.id FROM vlans v WHERE v.id IN (SELECT vlan_id FROM connection_vlans))",
+			want:  "SELECT (SELECT '{' || COALESCE(group_concat(v.id, ','), '') || '}' FROM vlans v WHERE v.id IN (SELECT vlan_id FROM connection_vlans))",
+		},
+		{
+			name:  "extract epoch",
+			query: "SELECT FLOOR(EXTRACT(EPOCH FROM hc.checked_at) / $2) FROM health_checks hc",
+			want:  "SELECT FLOOR(CAST(strftime('%s', hc.checked_at) AS INTEGER) / $2) FROM health_checks hc",
+		},
+		{
+			name:  "update sets updated_at",
+			query: "UPDATE racks SET name = $1 WHERE id = $2",
+			want:  "UPDATE racks SET updated_at = CURRENT_TIMESTAMP, name = $1 WHERE id = $2",
+		},
+		{
+			name:  "update keeps explicit updated_at",
+			query: "UPDATE racks SET name = $1, updated_at = $2 WHERE id = $3",
+			want:  "UPDATE racks SET name = $1, updated_at = $2 WHERE id = $3",
+		},
+		{
+			name:  "update of table without updated_at",
+			query: "UPDATE connection_vlans SET vlan_id = $1 WHERE connection_id = $2",
+			want:  "UPDATE connection_vlans SET vlan_id = $1 WHERE connection_id = $2",
+		},
+		{
+			name:  "quoted literals",
+			query: "SELECT id FROM racks WHERE name = '::TEXT' OR name ILIKE 'ILIKE' OR notes = 'host(x) FOR UPDATE'",
+			want:  "SELECT id FROM racks WHERE name = '::TEXT' OR name LIKE 'ILIKE' OR notes = 'host(x) FOR UPDATE'",
+		},
+		{
+			name:  "escaped quotes",
+			query: "SELECT id FROM racks WHERE name = 'it''s ILIKE' AND host(address) = $1",
+			want:  "SELECT id FROM racks WHERE name = 'it''s ILIKE' AND address = $1",
+		},
+		{
+			name:  "quoted identifiers",
+			query: `SELECT "host(""x"")"::TEXT, "EXTRACT(EPOCH FROM t)" FROM racks`,
+			want:  `SELECT "host(""x"")", "EXTRACT(EPOCH FROM t)" FROM racks`,
+		},
+		{
+			name:  "update with updated_at in a literal",
+			query: "UPDATE racks SET name = 'updated_at = now' WHERE id = $1",
+			want:  "UPDATE racks SET updated_at = CURRENT_TIMESTAMP, name = 'updated_at = now' WHERE id = $1",
+		},
+		{
+			name:  "array subquery with parentheses in a literal",
+			query: "SELECT ARRAY(SELECT name FROM racks WHERE name <> ')' ORDER BY name) FROM racks",
+			want:  "SELECT (SELECT '{' || COALESCE(group_concat(name, ',' ORDER BY name), '') || '}' FROM racks WHERE name <> ')') FROM racks",
+		},
+		{
+			name:  "comments",
+			query: "SELECT id -- isn't ILIKE\nFROM racks /* FOR UPDATE */ WHERE name ILIKE $1",
+			want:  "SELECT id -- isn't ILIKE\nFROM racks /* FOR UPDATE */ WHERE name LIKE $1",
+		},
+		{
+			name:  "unchanged",
+			query: "SELECT id, name FROM racks WHERE name LIKE $1 ORDER BY id",
+			want:  "SELECT id, name FROM racks WHERE name LIKE $1 ORDER BY id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.rewrite(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("rewrite: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("rewrite(%q)\n got %q\nwant %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLiteArrayParameters(t *testing.T) {
+	db, err := sql.Open(sqliteDriverName, sqliteDSN(filepath.Join(t.TempDir(), "arrays.db")))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE connection_vlans (connection_id INTEGER, vlan_id INTEGER)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	for _, vlanID := range []int{10, 20, 30} {
+		if _, err := db.Exec("INSERT INTO connection_vlans (connection_id, vlan_id) VALUES ($1, $2)", 1, vlanID); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM connection_vlans WHERE vlan_id = ANY($1)", pq.Array([]int{10, 30})).Scan(&count); err != nil {
+		t.Fatalf("= ANY: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("= ANY matched %d rows, want 2", count)
+	}
+
+	var empty bool
+	if err := db.QueryRow("SELECT cardinality($1) = 0", pq.Array([]int{})).Scan(&empty); err != nil {
+		t.Fatalf("cardinality: %v", err)
+	}
+	if !empty {
+		t.Errorf("cardinality of an empty array is not 0")
+	}
+
+	var vlanIDs []int64
+	if err := db.QueryRow("SELECT ARRAY(SELECT vlan_id FROM connection_vlans WHERE connection_id = $1 ORDER BY vlan_id DESC)", 1).
+		Scan(pq.Array(&vlanIDs)); err != nil {
+		t.Fatalf("ARRAY(SELECT ...): %v", err)
+	}
+	if want := []int64{30, 20, 10}; !reflect.DeepEqual(vlanIDs, want) {
+		t.Errorf("ARRAY(SELECT ...) = %v, want %v", vlanIDs, want)
+	}
+
+	if err := db.QueryRow("SELECT ARRAY(SELECT vlan_id FROM connection_vlans WHERE connection_id = $1)", 2).
+		Scan(pq.Array(&vlanIDs)); err != nil {
+		t.Fatalf("empty ARRAY(SELECT ...): %v", err)
+	}
+	if len(vlanIDs) != 0 {
+		t.Errorf("empty ARRAY(SELECT ...) = %v, want none", vlanIDs)
+	}
+}
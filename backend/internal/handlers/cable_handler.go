@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"rackview/internal/models"
+	"rackview/internal/services"
+	"rackview/internal/store"
+)
+
+// CableHandler handles cable inventory HTTP requests
+type CableHandler struct {
+	service *services.CableService
+}
+
+// NewCableHandler creates a new cable handler
+func NewCableHandler(st *store.Store) *CableHandler {
+	return &CableHandler{
+		service: services.NewCableService(st.Cables),
+	}
+}
+
+// GetAllCables handles GET /api/cables
+func (h *CableHandler) GetAllCables(c *gin.Context) {
+	filter := models.CableFilter{
+		Label:  c.Query("label"),
+		Media:  models.CableMedia(c.Query("media")),
+		Status: models.CableStatus(c.Query("status")),
+	}
+
+	cables, err := h.service.GetAllCables(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cables)
+}
+
+// GetCableByID handles GET /api/cables/:id
+func (h *CableHandler) GetCableByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cable ID"})
+		return
+	}
+
+	cable, err := h.service.GetCableByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cable)
+}
+
+// GetCableLabel handles GET /api/cables/:id/label
+func (h *CableHandler) GetCableLabel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cable ID"})
+		return
+	}
+
+	cable, err := h.service.GetCableByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.String(http.StatusOK, cable.PrintableLabel+"\n")
+}
+
+// CreateCable handles POST /api/cables
+func (h *CableHandler) CreateCable(c *gin.Context) {
+	var req models.CreateCableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cable, err := h.service.CreateCable(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, cable)
+}
+
+// UpdateCable handles PUT /api/cables/:id
+func (h *CableHandler) UpdateCable(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cable ID"})
+		return
+	}
+
+	var req models.UpdateCableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cable, err := h.service.UpdateCable(id, req)
+	if err != nil {
+		if err.Error() == "cable not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cable)
+}
+
+// DeleteCable handles DELETE /api/cables/:id
+func (h *CableHandler) DeleteCable(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cable ID"})
+		return
+	}
+
+	if err := h.service.DeleteCable(id); err != nil {
+		if err.Error() == "cable not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "cable deleted successfully"})
+}
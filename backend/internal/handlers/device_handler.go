@@ -1,25 +1,30 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"rackview/internal/models"
 	"rackview/internal/services"
+	"rackview/internal/store"
 )
 
 // DeviceHandler handles device-related HTTP requests
 type DeviceHandler struct {
-	service      *services.DeviceService
-	healthService *services.HealthService
+	service          *services.DeviceService
+	healthService    *services.HealthService
+	interfaceService *services.InterfaceService
 }
 
 // NewDeviceHandler creates a new device handler
-func NewDeviceHandler() *DeviceHandler {
+func NewDeviceHandler(healthService *services.HealthService, st *store.Store) *DeviceHandler {
 	return &DeviceHandler{
-		service:       services.NewDeviceService(),
-		healthService: services.NewHealthService(),
+		service:          services.NewDeviceService(st.Devices, st.Racks, st.DeviceTypes, st.Interfaces, st.IPAM),
+		healthService:    healthService,
+		interfaceService: services.NewInterfaceService(st.Interfaces, st.Devices),
 	}
 }
 
@@ -140,3 +145,244 @@ func (h *DeviceHandler) CheckDeviceHealth(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// GetHealthHistory handles GET /api/devices/:id/health-history
+func (h *DeviceHandler) GetHealthHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+
+	var query models.HealthHistoryQuery
+	if from := c.Query("from"); from != "" {
+		if query.From, err = time.Parse(time.RFC3339, from); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from (expected RFC3339)"})
+			return
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if query.To, err = time.Parse(time.RFC3339, to); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to (expected RFC3339)"})
+			return
+		}
+	}
+	if bucket := c.Query("bucket"); bucket != "" {
+		if query.Bucket, err = time.ParseDuration(bucket); err != nil || query.Bucket <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bucket (expected duration such as 5m or 1h)"})
+			return
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	history, err := h.healthService.GetHealthHistory(id, query)
+	if err != nil {
+		if err.Error() == "device not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetDeviceProbes handles GET /api/devices/:id/probes
+func (h *DeviceHandler) GetDeviceProbes(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+
+	probes, err := h.healthService.GetDeviceProbes(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, probes)
+}
+
+// CreateDeviceProbe handles POST /api/devices/:id/probes
+func (h *DeviceHandler) CreateDeviceProbe(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+
+	var req models.CreateHealthProbeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	probe, err := h.healthService.CreateProbe(id, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, probe)
+}
+
+// UpdateDeviceProbe handles PUT /api/devices/:id/probes/:probeId
+func (h *DeviceHandler) UpdateDeviceProbe(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+	probeID, err := strconv.Atoi(c.Param("probeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid probe ID"})
+		return
+	}
+
+	var req models.UpdateHealthProbeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	probe, err := h.healthService.UpdateProbe(id, probeID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, probe)
+}
+
+// DeleteDeviceProbe handles DELETE /api/devices/:id/probes/:probeId
+func (h *DeviceHandler) DeleteDeviceProbe(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+	probeID, err := strconv.Atoi(c.Param("probeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid probe ID"})
+		return
+	}
+
+	if err := h.healthService.DeleteProbe(id, probeID); err != nil {
+		if errors.Is(err, store.ErrHealthProbeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "health probe deleted successfully"})
+}
+
+// GetDeviceInterfaces handles GET /api/devices/:id/interfaces
+func (h *DeviceHandler) GetDeviceInterfaces(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+
+	interfaces, err := h.interfaceService.GetDeviceInterfaces(id)
+	if err != nil {
+		if err.Error() == "device not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, interfaces)
+}
+
+// CreateDeviceInterface handles POST /api/devices/:id/interfaces
+func (h *DeviceHandler) CreateDeviceInterface(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+
+	var req models.CreateDeviceInterfaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	iface, err := h.interfaceService.CreateInterface(id, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, iface)
+}
+
+// UpdateDeviceInterface handles PUT /api/devices/:id/interfaces/:interfaceId
+func (h *DeviceHandler) UpdateDeviceInterface(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+	interfaceID, err := strconv.Atoi(c.Param("interfaceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid interface ID"})
+		return
+	}
+
+	var req models.UpdateDeviceInterfaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	iface, err := h.interfaceService.UpdateInterface(id, interfaceID, req)
+	if err != nil {
+		if err.Error() == "interface not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, iface)
+}
+
+// DeleteDeviceInterface handles DELETE /api/devices/:id/interfaces/:interfaceId
+func (h *DeviceHandler) DeleteDeviceInterface(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+	interfaceID, err := strconv.Atoi(c.Param("interfaceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid interface ID"})
+		return
+	}
+
+	if err := h.interfaceService.DeleteInterface(id, interfaceID); err != nil {
+		if err.Error() == "interface not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "interface deleted successfully"})
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"rackview/internal/models"
+	"rackview/internal/services"
+	"rackview/internal/store"
+)
+
+// DeviceTypeHandler handles device type catalog HTTP requests
+type DeviceTypeHandler struct {
+	service *services.DeviceTypeService
+}
+
+// NewDeviceTypeHandler creates a new device type handler
+func NewDeviceTypeHandler(st *store.Store) *DeviceTypeHandler {
+	return &DeviceTypeHandler{
+		service: services.NewDeviceTypeService(st.DeviceTypes, st.Devices),
+	}
+}
+
+// GetAllDeviceTypes handles GET /api/device-types
+func (h *DeviceTypeHandler) GetAllDeviceTypes(c *gin.Context) {
+	deviceTypes, err := h.service.GetAllDeviceTypes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deviceTypes)
+}
+
+// GetDeviceTypeByID handles GET /api/device-types/:id
+func (h *DeviceTypeHandler) GetDeviceTypeByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device type ID"})
+		return
+	}
+
+	deviceType, err := h.service.GetDeviceTypeByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deviceType)
+}
+
+// CreateDeviceType handles POST /api/device-types
+func (h *DeviceTypeHandler) CreateDeviceType(c *gin.Context) {
+	var req models.CreateDeviceTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deviceType, err := h.service.CreateDeviceType(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, deviceType)
+}
+
+// UpdateDeviceType handles PUT /api/device-types/:id
+func (h *DeviceTypeHandler) UpdateDeviceType(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device type ID"})
+		return
+	}
+
+	var req models.UpdateDeviceTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deviceType, err := h.service.UpdateDeviceType(id, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deviceType)
+}
+
+// DeleteDeviceType handles DELETE /api/device-types/:id
+func (h *DeviceTypeHandler) DeleteDeviceType(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device type ID"})
+		return
+	}
+
+	if err := h.service.DeleteDeviceType(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "device type deleted successfully"})
+}
+
+// GetModelReport handles GET /api/device-types/report
+func (h *DeviceTypeHandler) GetModelReport(c *gin.Context) {
+	report, err := h.service.GetModelReport()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"rackview/internal/models"
+	"rackview/internal/services"
+	"rackview/internal/store"
+)
+
+// DiscoveryHandler handles service discovery HTTP requests
+type DiscoveryHandler struct {
+	service *services.DiscoveryService
+}
+
+// NewDiscoveryHandler creates a new discovery handler
+func NewDiscoveryHandler(st *store.Store) *DiscoveryHandler {
+	return &DiscoveryHandler{
+		service: services.NewDiscoveryService(st.Racks, st.Devices),
+	}
+}
+
+// GetPrometheusTargets handles GET /api/discovery/prometheus
+func (h *DiscoveryHandler) GetPrometheusTargets(c *gin.Context) {
+	filter := models.PrometheusTargetFilter{
+		Type:   c.Query("type"),
+		Rack:   c.Query("rack"),
+		Status: c.Query("status"),
+		Specs:  map[string]string{},
+	}
+
+	// spec=Key requires the key to be present, spec=Key=Value requires an exact value
+	for _, spec := range c.QueryArray("spec") {
+		key, value, _ := strings.Cut(spec, "=")
+		if key == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid spec filter (expected key or key=value)"})
+			return
+		}
+		filter.Specs[key] = value
+	}
+
+	for _, labels := range c.QueryArray("spec_labels") {
+		for _, key := range strings.Split(labels, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				filter.SpecLabels = append(filter.SpecLabels, key)
+			}
+		}
+	}
+
+	if portStr := c.Query("port"); portStr != "" {
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port < 1 || port > 65535 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid port"})
+			return
+		}
+		filter.Port = port
+	}
+
+	groups, err := h.service.GetPrometheusTargets(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
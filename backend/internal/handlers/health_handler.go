@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"rackview/internal/services"
+)
+
+// HealthHandler handles health check scheduler HTTP requests
+type HealthHandler struct {
+	scheduler *services.HealthScheduler
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(scheduler *services.HealthScheduler) *HealthHandler {
+	return &HealthHandler{
+		scheduler: scheduler,
+	}
+}
+
+// GetSchedulerStatus handles GET /api/health/scheduler
+func (h *HealthHandler) GetSchedulerStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.scheduler.Status())
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"rackview/internal/models"
+	"rackview/internal/services"
+	"rackview/internal/store"
+)
+
+// maxAvailableAddresses bounds the count of GET /api/ipam/prefixes/:id/available
+const maxAvailableAddresses = 256
+
+// IPAMHandler handles prefix and IP address HTTP requests
+type IPAMHandler struct {
+	service *services.IPAMService
+}
+
+// NewIPAMHandler creates a new IPAM handler
+func NewIPAMHandler(st *store.Store) *IPAMHandler {
+	return &IPAMHandler{
+		service: services.NewIPAMService(st.IPAM, st.Devices, st.Interfaces),
+	}
+}
+
+// GetAllPrefixes handles GET /api/ipam/prefixes
+func (h *IPAMHandler) GetAllPrefixes(c *gin.Context) {
+	prefixes, err := h.service.GetAllPrefixes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefixes)
+}
+
+// GetPrefixByID handles GET /api/ipam/prefixes/:id
+func (h *IPAMHandler) GetPrefixByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prefix ID"})
+		return
+	}
+
+	prefix, err := h.service.GetPrefixByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefix)
+}
+
+// GetAvailableAddresses handles GET /api/ipam/prefixes/:id/available
+func (h *IPAMHandler) GetAvailableAddresses(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prefix ID"})
+		return
+	}
+
+	count := 1
+	if countStr := c.Query("count"); countStr != "" {
+		count, err = strconv.Atoi(countStr)
+		if err != nil || count < 1 || count > maxAvailableAddresses {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid count (expected 1-256)"})
+			return
+		}
+	}
+
+	addresses, err := h.service.GetAvailableAddresses(id, count)
+	if err != nil {
+		if err.Error() == "prefix not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"prefix_id": id, "addresses": addresses})
+}
+
+// CreatePrefix handles POST /api/ipam/prefixes
+func (h *IPAMHandler) CreatePrefix(c *gin.Context) {
+	var req models.CreateIPPrefixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefix, err := h.service.CreatePrefix(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, prefix)
+}
+
+// UpdatePrefix handles PUT /api/ipam/prefixes/:id
+func (h *IPAMHandler) UpdatePrefix(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prefix ID"})
+		return
+	}
+
+	var req models.UpdateIPPrefixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefix, err := h.service.UpdatePrefix(id, req)
+	if err != nil {
+		if err.Error() == "prefix not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefix)
+}
+
+// DeletePrefix handles DELETE /api/ipam/prefixes/:id
+func (h *IPAMHandler) DeletePrefix(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prefix ID"})
+		return
+	}
+
+	if err := h.service.DeletePrefix(id); err != nil {
+		if err.Error() == "prefix not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "prefix deleted successfully"})
+}
+
+// GetAllIPAddresses handles GET /api/ipam/addresses
+func (h *IPAMHandler) GetAllIPAddresses(c *gin.Context) {
+	var filter models.IPAddressFilter
+	var ok bool
+	if filter.DeviceID, ok = optionalIDQuery(c, "device_id"); !ok {
+		return
+	}
+	if filter.PrefixID, ok = optionalIDQuery(c, "prefix_id"); !ok {
+		return
+	}
+
+	addresses, err := h.service.GetAllIPAddresses(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, addresses)
+}
+
+// GetIPAddressByID handles GET /api/ipam/addresses/:id
+func (h *IPAMHandler) GetIPAddressByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ip address ID"})
+		return
+	}
+
+	address, err := h.service.GetIPAddressByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// CreateIPAddress handles POST /api/ipam/addresses
+func (h *IPAMHandler) CreateIPAddress(c *gin.Context) {
+	var req models.CreateIPAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	address, err := h.service.CreateIPAddress(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, address)
+}
+
+// UpdateIPAddress handles PUT /api/ipam/addresses/:id
+func (h *IPAMHandler) UpdateIPAddress(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ip address ID"})
+		return
+	}
+
+	var req models.UpdateIPAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	address, err := h.service.UpdateIPAddress(id, req)
+	if err != nil {
+		if err.Error() == "ip address not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// DeleteIPAddress handles DELETE /api/ipam/addresses/:id
+func (h *IPAMHandler) DeleteIPAddress(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ip address ID"})
+		return
+	}
+
+	if err := h.service.DeleteIPAddress(id); err != nil {
+		if err.Error() == "ip address not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "ip address deleted successfully"})
+}
+
+// GetConflicts handles GET /api/ipam/conflicts
+func (h *IPAMHandler) GetConflicts(c *gin.Context) {
+	conflicts, err := h.service.GetConflicts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, conflicts)
+}
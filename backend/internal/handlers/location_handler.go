@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"rackview/internal/models"
+	"rackview/internal/services"
+	"rackview/internal/store"
+)
+
+// LocationHandler handles site, room and row HTTP requests
+type LocationHandler struct {
+	service *services.LocationService
+}
+
+// NewLocationHandler creates a new location handler
+func NewLocationHandler(st *store.Store) *LocationHandler {
+	return &LocationHandler{
+		service: services.NewLocationService(st.Locations, st.Racks, st.Devices),
+	}
+}
+
+// optionalIDQuery parses an optional integer ID query parameter
+func optionalIDQuery(c *gin.Context, name string) (*int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return nil, false
+	}
+	return &id, true
+}
+
+// GetAllSites handles GET /api/sites
+func (h *LocationHandler) GetAllSites(c *gin.Context) {
+	sites, err := h.service.GetAllSites()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sites)
+}
+
+// GetSiteByID handles GET /api/sites/:id
+func (h *LocationHandler) GetSiteByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid site ID"})
+		return
+	}
+
+	site, err := h.service.GetSiteByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, site)
+}
+
+// GetSiteTree handles GET /api/sites/:id/tree
+func (h *LocationHandler) GetSiteTree(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid site ID"})
+		return
+	}
+
+	tree, err := h.service.GetSiteTree(id)
+	if err != nil {
+		if err.Error() == "site not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// CreateSite handles POST /api/sites
+func (h *LocationHandler) CreateSite(c *gin.Context) {
+	var req models.CreateSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	site, err := h.service.CreateSite(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, site)
+}
+
+// UpdateSite handles PUT /api/sites/:id
+func (h *LocationHandler) UpdateSite(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid site ID"})
+		return
+	}
+
+	var req models.UpdateSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	site, err := h.service.UpdateSite(id, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, site)
+}
+
+// DeleteSite handles DELETE /api/sites/:id
+func (h *LocationHandler) DeleteSite(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid site ID"})
+		return
+	}
+
+	if err := h.service.DeleteSite(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "site deleted successfully"})
+}
+
+// GetAllRooms handles GET /api/rooms
+func (h *LocationHandler) GetAllRooms(c *gin.Context) {
+	siteID, ok := optionalIDQuery(c, "site_id")
+	if !ok {
+		return
+	}
+
+	rooms, err := h.service.GetAllRooms(siteID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rooms)
+}
+
+// GetRoomByID handles GET /api/rooms/:id
+func (h *LocationHandler) GetRoomByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room ID"})
+		return
+	}
+
+	room, err := h.service.GetRoomByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, room)
+}
+
+// CreateRoom handles POST /api/rooms
+func (h *LocationHandler) CreateRoom(c *gin.Context) {
+	var req models.CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	room, err := h.service.CreateRoom(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, room)
+}
+
+// UpdateRoom handles PUT /api/rooms/:id
+func (h *LocationHandler) UpdateRoom(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room ID"})
+		return
+	}
+
+	var req models.UpdateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	room, err := h.service.UpdateRoom(id, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, room)
+}
+
+// DeleteRoom handles DELETE /api/rooms/:id
+func (h *LocationHandler) DeleteRoom(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room ID"})
+		return
+	}
+
+	if err := h.service.DeleteRoom(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "room deleted successfully"})
+}
+
+// GetAllRows handles GET /api/rows
+func (h *LocationHandler) GetAllRows(c *gin.Context) {
+	roomID, ok := optionalIDQuery(c, "room_id")
+	if !ok {
+		return
+	}
+
+	rows, err := h.service.GetAllRows(roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rows)
+}
+
+// GetRowByID handles GET /api/rows/:id
+func (h *LocationHandler) GetRowByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid row ID"})
+		return
+	}
+
+	row, err := h.service.GetRowByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, row)
+}
+
+// CreateRow handles POST /api/rows
+func (h *LocationHandler) CreateRow(c *gin.Context) {
+	var req models.CreateRowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	row, err := h.service.CreateRow(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, row)
+}
+
+// UpdateRow handles PUT /api/rows/:id
+func (h *LocationHandler) UpdateRow(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid row ID"})
+		return
+	}
+
+	var req models.UpdateRowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	row, err := h.service.UpdateRow(id, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, row)
+}
+
+// DeleteRow handles DELETE /api/rows/:id
+func (h *LocationHandler) DeleteRow(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid row ID"})
+		return
+	}
+
+	if err := h.service.DeleteRow(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "row deleted successfully"})
+}
@@ -3,21 +3,26 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"rackview/internal/models"
 	"rackview/internal/services"
+	"rackview/internal/store"
 )
 
 // NetworkHandler handles network connection-related HTTP requests
 type NetworkHandler struct {
-	service *services.NetworkService
+	service         *services.NetworkService
+	analysisService *services.AnalysisService
 }
 
 // NewNetworkHandler creates a new network handler
-func NewNetworkHandler() *NetworkHandler {
+func NewNetworkHandler(st *store.Store) *NetworkHandler {
+	service := services.NewNetworkService(st.Connections, st.Devices, st.Racks, st.Interfaces, st.Cables)
 	return &NetworkHandler{
-		service: services.NewNetworkService(),
+		service:         service,
+		analysisService: services.NewAnalysisService(service),
 	}
 }
 
@@ -104,3 +109,198 @@ func (h *NetworkHandler) DeleteConnection(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "connection deleted successfully"})
 }
+
+// GetPortUsage handles GET /api/network/port-usage
+func (h *NetworkHandler) GetPortUsage(c *gin.Context) {
+	var rackID *int
+	if rackIDStr := c.Query("rack_id"); rackIDStr != "" {
+		id, err := strconv.Atoi(rackIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rack ID"})
+			return
+		}
+		rackID = &id
+	}
+
+	usage, err := h.service.GetPortUsage(rackID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// TraceCablePath handles GET /api/network/trace
+func (h *NetworkHandler) TraceCablePath(c *gin.Context) {
+	if interfaceIDStr := c.Query("interface_id"); interfaceIDStr != "" {
+		interfaceID, err := strconv.Atoi(interfaceIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid interface ID"})
+			return
+		}
+
+		path, err := h.service.TraceInterface(interfaceID)
+		if err != nil {
+			if err.Error() == "interface not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, []models.CablePath{*path})
+		return
+	}
+
+	deviceIDStr := c.Query("device_id")
+	if deviceIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "device_id or interface_id is required"})
+		return
+	}
+	deviceID, err := strconv.Atoi(deviceIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+
+	paths, err := h.service.TraceDevice(deviceID)
+	if err != nil {
+		if err.Error() == "device not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, paths)
+}
+
+// GetTopology handles GET /api/network/topology
+func (h *NetworkHandler) GetTopology(c *gin.Context) {
+	format := models.TopologyFormat(c.DefaultQuery("format", string(models.TopologyFormatJSON)))
+	switch format {
+	case models.TopologyFormatJSON, models.TopologyFormatDOT, models.TopologyFormatGraphML, models.TopologyFormatMermaid:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format (expected json, dot, graphml or mermaid)"})
+		return
+	}
+
+	// rack_id and type may be repeated or comma-separated
+	filter := models.TopologyFilter{ConnectionType: c.Query("connection_type")}
+	for _, value := range queryList(c, "rack_id") {
+		rackID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rack ID"})
+			return
+		}
+		filter.RackIDs = append(filter.RackIDs, rackID)
+	}
+	for _, value := range queryList(c, "type") {
+		filter.DeviceTypes = append(filter.DeviceTypes, models.DeviceType(value))
+	}
+
+	topology, err := h.service.GetTopology(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch format {
+	case models.TopologyFormatDOT:
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(services.RenderTopologyDOT(topology)))
+	case models.TopologyFormatMermaid:
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(services.RenderTopologyMermaid(topology)))
+	case models.TopologyFormatGraphML:
+		out, err := services.RenderTopologyGraphML(topology)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/graphml+xml; charset=utf-8", []byte(out))
+	default:
+		c.JSON(http.StatusOK, topology)
+	}
+}
+
+// queryList collects a query parameter that may be repeated or comma-separated
+func queryList(c *gin.Context, name string) []string {
+	var values []string
+	for _, param := range c.QueryArray(name) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// GetNetworkAnalysis handles GET /api/network/analysis
+func (h *NetworkHandler) GetNetworkAnalysis(c *gin.Context) {
+	statusAware := false
+	if value := c.Query("status_aware"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status_aware (expected true or false)"})
+			return
+		}
+		statusAware = parsed
+	}
+
+	analysis, err := h.analysisService.AnalyzeNetwork(statusAware)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// ImportLLDPNeighbors handles POST /api/network/lldp/import
+//
+// The body is either an LLDPImportRequest or, with a text/plain content type, raw
+// `show lldp neighbors detail` output for the device in ?device_id=.
+func (h *NetworkHandler) ImportLLDPNeighbors(c *gin.Context) {
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run (expected true or false)"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	var req models.LLDPImportRequest
+	if c.ContentType() == "text/plain" {
+		deviceID, err := strconv.Atoi(c.Query("device_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+			return
+		}
+		body, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		req = models.LLDPImportRequest{DeviceID: deviceID, Output: string(body)}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.ImportLLDPNeighbors(req, dryRun)
+	if err != nil {
+		if err.Error() == "device not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
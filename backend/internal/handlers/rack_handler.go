@@ -7,23 +7,38 @@ import (
 	"github.com/gin-gonic/gin"
 	"rackview/internal/models"
 	"rackview/internal/services"
+	"rackview/internal/store"
 )
 
 // RackHandler handles rack-related HTTP requests
 type RackHandler struct {
-	service *services.RackService
+	service      *services.RackService
+	powerService *services.PowerService
 }
 
 // NewRackHandler creates a new rack handler
-func NewRackHandler() *RackHandler {
+func NewRackHandler(st *store.Store) *RackHandler {
 	return &RackHandler{
-		service: services.NewRackService(),
+		service:      services.NewRackService(st.Racks, st.Devices, st.Locations),
+		powerService: services.NewPowerService(st.Racks, st.Devices),
 	}
 }
 
 // GetAllRacks handles GET /api/racks
 func (h *RackHandler) GetAllRacks(c *gin.Context) {
-	racks, err := h.service.GetAllRacks()
+	var filter models.RackFilter
+	var ok bool
+	if filter.SiteID, ok = optionalIDQuery(c, "site_id"); !ok {
+		return
+	}
+	if filter.RoomID, ok = optionalIDQuery(c, "room_id"); !ok {
+		return
+	}
+	if filter.RowID, ok = optionalIDQuery(c, "row_id"); !ok {
+		return
+	}
+
+	racks, err := h.service.GetAllRacks(filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -49,6 +64,37 @@ func (h *RackHandler) GetRackByID(c *gin.Context) {
 	c.JSON(http.StatusOK, rack)
 }
 
+// GetRackPower handles GET /api/racks/:id/power
+func (h *RackHandler) GetRackPower(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rack ID"})
+		return
+	}
+
+	var threshold *float64
+	if thresholdStr := c.Query("threshold"); thresholdStr != "" {
+		value, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil || value <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid threshold"})
+			return
+		}
+		threshold = &value
+	}
+
+	report, err := h.powerService.GetRackPower(id, threshold)
+	if err != nil {
+		if err.Error() == "rack not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // CreateRack handles POST /api/racks
 func (h *RackHandler) CreateRack(c *gin.Context) {
 	var req models.CreateRackRequest
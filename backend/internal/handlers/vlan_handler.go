@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"rackview/internal/models"
+	"rackview/internal/services"
+	"rackview/internal/store"
+)
+
+// VLANHandler handles VLAN group, VLAN and tagging HTTP requests
+type VLANHandler struct {
+	service *services.VLANService
+}
+
+// NewVLANHandler creates a new VLAN handler
+func NewVLANHandler(st *store.Store) *VLANHandler {
+	return &VLANHandler{
+		service: services.NewVLANService(
+			st.VLANs,
+			services.NewNetworkService(st.Connections, st.Devices, st.Racks, st.Interfaces, st.Cables),
+			services.NewLocationService(st.Locations, st.Racks, st.Devices),
+		),
+	}
+}
+
+// GetAllVLANGroups handles GET /api/vlan-groups
+func (h *VLANHandler) GetAllVLANGroups(c *gin.Context) {
+	groups, err := h.service.GetAllVLANGroups()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// GetVLANGroupByID handles GET /api/vlan-groups/:id
+func (h *VLANHandler) GetVLANGroupByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vlan group ID"})
+		return
+	}
+
+	group, err := h.service.GetVLANGroupByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// CreateVLANGroup handles POST /api/vlan-groups
+func (h *VLANHandler) CreateVLANGroup(c *gin.Context) {
+	var req models.CreateVLANGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := h.service.CreateVLANGroup(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// UpdateVLANGroup handles PUT /api/vlan-groups/:id
+func (h *VLANHandler) UpdateVLANGroup(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vlan group ID"})
+		return
+	}
+
+	var req models.UpdateVLANGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := h.service.UpdateVLANGroup(id, req)
+	if err != nil {
+		if err.Error() == "vlan group not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteVLANGroup handles DELETE /api/vlan-groups/:id
+func (h *VLANHandler) DeleteVLANGroup(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vlan group ID"})
+		return
+	}
+
+	if err := h.service.DeleteVLANGroup(id); err != nil {
+		if err.Error() == "vlan group not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "vlan group deleted successfully"})
+}
+
+// GetAllVLANs handles GET /api/vlans
+func (h *VLANHandler) GetAllVLANs(c *gin.Context) {
+	var filter models.VLANFilter
+	var ok bool
+	if filter.GroupID, ok = optionalIDQuery(c, "group_id"); !ok {
+		return
+	}
+	if filter.SiteID, ok = optionalIDQuery(c, "site_id"); !ok {
+		return
+	}
+
+	vlans, err := h.service.GetAllVLANs(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, vlans)
+}
+
+// GetVLANByID handles GET /api/vlans/:id
+func (h *VLANHandler) GetVLANByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vlan ID"})
+		return
+	}
+
+	vlan, err := h.service.GetVLANByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, vlan)
+}
+
+// GetVLANDevices handles GET /api/vlans/:id/devices
+func (h *VLANHandler) GetVLANDevices(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vlan ID"})
+		return
+	}
+	deviceID, ok := optionalIDQuery(c, "device_id")
+	if !ok {
+		return
+	}
+
+	reachability, err := h.service.GetVLANDevices(id, deviceID)
+	if err != nil {
+		if err.Error() == "vlan not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reachability)
+}
+
+// CreateVLAN handles POST /api/vlans
+func (h *VLANHandler) CreateVLAN(c *gin.Context) {
+	var req models.CreateVLANRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	vlan, err := h.service.CreateVLAN(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, vlan)
+}
+
+// UpdateVLAN handles PUT /api/vlans/:id
+func (h *VLANHandler) UpdateVLAN(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vlan ID"})
+		return
+	}
+
+	var req models.UpdateVLANRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	vlan, err := h.service.UpdateVLAN(id, req)
+	if err != nil {
+		if err.Error() == "vlan not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, vlan)
+}
+
+// DeleteVLAN handles DELETE /api/vlans/:id
+func (h *VLANHandler) DeleteVLAN(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vlan ID"})
+		return
+	}
+
+	if err := h.service.DeleteVLAN(id); err != nil {
+		if err.Error() == "vlan not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "vlan deleted successfully"})
+}
+
+// SetInterfaceVLANs handles PUT /api/devices/:id/interfaces/:interfaceId/vlans
+func (h *VLANHandler) SetInterfaceVLANs(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device ID"})
+		return
+	}
+	interfaceID, err := strconv.Atoi(c.Param("interfaceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid interface ID"})
+		return
+	}
+
+	var req models.SetVLANTaggingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	iface, err := h.service.SetInterfaceVLANs(id, interfaceID, req)
+	if err != nil {
+		if err.Error() == "interface not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, iface)
+}
+
+// SetConnectionVLANs handles PUT /api/network/connections/:id/vlans
+func (h *VLANHandler) SetConnectionVLANs(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid connection ID"})
+		return
+	}
+
+	var req models.SetVLANTaggingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := h.service.SetConnectionVLANs(id, req)
+	if err != nil {
+		if err.Error() == "connection not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, conn)
+}
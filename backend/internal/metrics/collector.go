@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"rackview/internal/models"
+	"rackview/internal/services"
+)
+
+var (
+	devicesDesc = prometheus.NewDesc(
+		"rackview_devices",
+		"Number of devices by rack, type and status.",
+		[]string{"rack", "rack_id", "type", "status"}, nil,
+	)
+	rackUnitsDesc = prometheus.NewDesc(
+		"rackview_rack_units",
+		"Rack height in U.",
+		[]string{"rack", "rack_id"}, nil,
+	)
+	rackUnitsUsedDesc = prometheus.NewDesc(
+		"rackview_rack_units_used",
+		"Rack units occupied by devices.",
+		[]string{"rack", "rack_id"}, nil,
+	)
+	rackUtilizationDesc = prometheus.NewDesc(
+		"rackview_rack_utilization_ratio",
+		"Fraction of rack units occupied by devices.",
+		[]string{"rack", "rack_id"}, nil,
+	)
+	connectionsDesc = prometheus.NewDesc(
+		"rackview_connections",
+		"Number of network connections by connection type.",
+		[]string{"connection_type"}, nil,
+	)
+	deviceConnectionsDesc = prometheus.NewDesc(
+		"rackview_device_connections",
+		"Number of network connections attached to a device.",
+		[]string{"rack", "device", "device_id"}, nil,
+	)
+	healthStatusDesc = prometheus.NewDesc(
+		"rackview_device_health_status",
+		"Result of the last health check (1 for the reported status, 0 otherwise).",
+		[]string{"rack", "device", "device_id", "status"}, nil,
+	)
+	healthLatencyDesc = prometheus.NewDesc(
+		"rackview_device_health_latency_milliseconds",
+		"Latency of the last health check.",
+		[]string{"rack", "device", "device_id"}, nil,
+	)
+	healthTimestampDesc = prometheus.NewDesc(
+		"rackview_device_health_last_check_timestamp_seconds",
+		"Unix time of the last health check.",
+		[]string{"rack", "device", "device_id"}, nil,
+	)
+	scrapeErrorDesc = prometheus.NewDesc(
+		"rackview_inventory_scrape_error",
+		"1 if reading the inventory from the database failed during this scrape.",
+		nil, nil,
+	)
+)
+
+// healthStatuses are exported as a state set so every device reports each status
+var healthStatuses = []models.DeviceStatus{
+	models.DeviceStatusOnline,
+	models.DeviceStatusOffline,
+	models.DeviceStatusWarning,
+	models.DeviceStatusUnknown,
+}
+
+// InventoryCollector exports inventory and health gauges, read from the database on every scrape
+type InventoryCollector struct {
+	service *services.MetricsService
+}
+
+// NewInventoryCollector creates a new inventory collector
+func NewInventoryCollector(service *services.MetricsService) *InventoryCollector {
+	return &InventoryCollector{
+		service: service,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *InventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- devicesDesc
+	ch <- rackUnitsDesc
+	ch <- rackUnitsUsedDesc
+	ch <- rackUtilizationDesc
+	ch <- connectionsDesc
+	ch <- deviceConnectionsDesc
+	ch <- healthStatusDesc
+	ch <- healthLatencyDesc
+	ch <- healthTimestampDesc
+	ch <- scrapeErrorDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *InventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	inventory, err := c.service.GetInventoryMetrics()
+	if err != nil {
+		log.Printf("Metrics: %v", err)
+		ch <- prometheus.MustNewConstMetric(scrapeErrorDesc, prometheus.GaugeValue, 1)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(scrapeErrorDesc, prometheus.GaugeValue, 0)
+
+	// Rack names aren't unique, so rack_id keeps label sets distinct
+	for _, count := range inventory.DeviceCounts {
+		ch <- prometheus.MustNewConstMetric(devicesDesc, prometheus.GaugeValue, float64(count.Count),
+			count.Rack, strconv.Itoa(count.RackID), string(count.Type), string(count.Status))
+	}
+
+	for _, rack := range inventory.Racks {
+		rackID := strconv.Itoa(rack.RackID)
+		ch <- prometheus.MustNewConstMetric(rackUnitsDesc, prometheus.GaugeValue, float64(rack.SizeU), rack.Name, rackID)
+		ch <- prometheus.MustNewConstMetric(rackUnitsUsedDesc, prometheus.GaugeValue, float64(rack.UsedU), rack.Name, rackID)
+		if rack.SizeU > 0 {
+			ch <- prometheus.MustNewConstMetric(rackUtilizationDesc, prometheus.GaugeValue,
+				float64(rack.UsedU)/float64(rack.SizeU), rack.Name, rackID)
+		}
+	}
+
+	for _, count := range inventory.ConnectionCounts {
+		ch <- prometheus.MustNewConstMetric(connectionsDesc, prometheus.GaugeValue, float64(count.Count), count.ConnectionType)
+	}
+
+	for _, device := range inventory.Devices {
+		// Device names aren't unique, so device_id keeps label sets distinct
+		id := strconv.Itoa(device.DeviceID)
+		ch <- prometheus.MustNewConstMetric(deviceConnectionsDesc, prometheus.GaugeValue, float64(device.Connections),
+			device.Rack, device.Name, id)
+
+		if device.LastCheckedAt == nil {
+			continue
+		}
+		for _, status := range healthStatuses {
+			value := 0.0
+			if device.LastStatus == status {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(healthStatusDesc, prometheus.GaugeValue, value,
+				device.Rack, device.Name, id, string(status))
+		}
+		if device.LastLatency != nil {
+			ch <- prometheus.MustNewConstMetric(healthLatencyDesc, prometheus.GaugeValue, float64(*device.LastLatency),
+				device.Rack, device.Name, id)
+		}
+		ch <- prometheus.MustNewConstMetric(healthTimestampDesc, prometheus.GaugeValue, float64(device.LastCheckedAt.Unix()),
+			device.Rack, device.Name, id)
+	}
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"rackview/internal/services"
+	"rackview/internal/store"
+)
+
+// Registry bundles the Prometheus registry with the HTTP request metrics
+type Registry struct {
+	registry *prometheus.Registry
+	HTTP     *HTTPMetrics
+}
+
+// NewRegistry creates a registry with the runtime, inventory and HTTP collectors
+func NewRegistry(st *store.Store) *Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		NewInventoryCollector(services.NewMetricsService(st.Racks, st.Devices, st.Connections, st.HealthChecks)),
+	)
+
+	return &Registry{
+		registry: registry,
+		HTTP:     NewHTTPMetrics(registry),
+	}
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,52 @@
+package models
+
+// NetworkComponent is a set of devices connected to each other
+type NetworkComponent struct {
+	ID        int   `json:"id"`
+	DeviceIDs []int `json:"device_ids"`
+	Size      int   `json:"size"`
+	// Main marks the largest component, which the other devices are isolated from
+	Main bool `json:"main"`
+}
+
+// ArticulationPoint is a device whose failure splits the network
+type ArticulationPoint struct {
+	Device TopologyNode `json:"device"`
+	// Isolates are the devices cut off from the rest of the network if the device fails
+	Isolates []TopologyNode `json:"isolates"`
+}
+
+// NetworkBridge is a connection whose failure splits the network
+type NetworkBridge struct {
+	ConnectionID int          `json:"connection_id"`
+	Source       TopologyNode `json:"source"`
+	Target       TopologyNode `json:"target"`
+	// Isolates are the devices on the smaller side of the connection
+	Isolates []TopologyNode `json:"isolates"`
+}
+
+// DeviceRedundancy counts a device's links to network devices
+type DeviceRedundancy struct {
+	Device TopologyNode `json:"device"`
+	// Uplinks is the number of connections to network devices
+	Uplinks int `json:"uplinks"`
+	// UpstreamDevices is the number of distinct network devices connected to
+	UpstreamDevices int `json:"upstream_devices"`
+	// Redundant is set when the device survives the loss of any one upstream device
+	Redundant bool `json:"redundant"`
+}
+
+// NetworkAnalysis is the single-point-of-failure and redundancy report for the network graph
+type NetworkAnalysis struct {
+	// StatusAware is set when offline devices were left out of the graph
+	StatusAware        bool                `json:"status_aware"`
+	ExcludedDevices    []TopologyNode      `json:"excluded_devices"`
+	Components         []NetworkComponent  `json:"components"`
+	ArticulationPoints []ArticulationPoint `json:"articulation_points"`
+	Bridges            []NetworkBridge     `json:"bridges"`
+	Redundancy         []DeviceRedundancy  `json:"redundancy"`
+	// Isolated are cabled devices outside the main component
+	Isolated []TopologyNode `json:"isolated"`
+	// Unconnected are devices without any connection
+	Unconnected []TopologyNode `json:"unconnected"`
+}
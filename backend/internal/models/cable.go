@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// CableMedia represents the media type of a cable
+type CableMedia string
+
+const (
+	CableMediaCat5e CableMedia = "Cat5e"
+	CableMediaCat6  CableMedia = "Cat6"
+	CableMediaCat6a CableMedia = "Cat6a"
+	CableMediaOM3   CableMedia = "OM3"
+	CableMediaOM4   CableMedia = "OM4"
+	CableMediaOS2   CableMedia = "OS2"
+	CableMediaDAC   CableMedia = "DAC"
+	CableMediaAOC   CableMedia = "AOC"
+)
+
+// CableStatus represents the lifecycle status of a cable
+type CableStatus string
+
+const (
+	CableStatusPlanned         CableStatus = "planned"
+	CableStatusInstalled       CableStatus = "installed"
+	CableStatusDecommissioning CableStatus = "decommissioning"
+)
+
+// Cable represents a physical cable
+type Cable struct {
+	ID          int         `json:"id" db:"id"`
+	Label       string      `json:"label" db:"label"`
+	Media       CableMedia  `json:"media" db:"media"`
+	LengthM     *float64    `json:"length_m" db:"length_m"`
+	Color       string      `json:"color" db:"color"`
+	Status      CableStatus `json:"status" db:"status"`
+	Description string      `json:"description" db:"description"`
+	// ConnectionID is the connection made with this cable, if any
+	ConnectionID *int `json:"connection_id"`
+	// AEnd and BEnd describe the ports at either end of the connection, e.g. "web-01 eth0"
+	AEnd string `json:"a_end,omitempty"`
+	BEnd string `json:"b_end,omitempty"`
+	// PrintableLabel is the text for a cable label printer, one field per line
+	PrintableLabel string    `json:"printable_label"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateCableRequest represents a request to create a cable
+type CreateCableRequest struct {
+	Label       string      `json:"label" binding:"required"`
+	Media       CableMedia  `json:"media" binding:"omitempty,oneof=Cat5e Cat6 Cat6a OM3 OM4 OS2 DAC AOC"`
+	LengthM     *float64    `json:"length_m" binding:"omitempty,gt=0"`
+	Color       string      `json:"color"`
+	Status      CableStatus `json:"status" binding:"omitempty,oneof=planned installed decommissioning"`
+	Description string      `json:"description"`
+}
+
+// UpdateCableRequest represents a request to update a cable
+type UpdateCableRequest struct {
+	Label       *string      `json:"label"`
+	Media       *CableMedia  `json:"media" binding:"omitempty,oneof=Cat5e Cat6 Cat6a OM3 OM4 OS2 DAC AOC"`
+	LengthM     *float64     `json:"length_m" binding:"omitempty,gte=0"`
+	Color       *string      `json:"color"`
+	Status      *CableStatus `json:"status" binding:"omitempty,oneof=planned installed decommissioning"`
+	Description *string      `json:"description"`
+}
+
+// CableFilter represents the filters for listing cables
+type CableFilter struct {
+	// Label matches any part of the label, case-insensitively
+	Label  string
+	Media  CableMedia
+	Status CableStatus
+}
@@ -4,28 +4,46 @@ import "time"
 
 // NetworkConnection represents a network connection between devices
 type NetworkConnection struct {
-	ID             int       `json:"id" db:"id"`
-	SourceDeviceID int       `json:"source_device_id" db:"source_device_id"`
-	TargetDeviceID int       `json:"target_device_id" db:"target_device_id"`
-	ConnectionType string    `json:"connection_type" db:"connection_type"`
-	PortInfo       string    `json:"port_info" db:"port_info"`
-	Speed          string    `json:"speed" db:"speed"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-	SourceDevice   *Device   `json:"source_device,omitempty"`
-	TargetDevice   *Device   `json:"target_device,omitempty"`
+	ID                int  `json:"id" db:"id"`
+	SourceDeviceID    int  `json:"source_device_id" db:"source_device_id"`
+	TargetDeviceID    int  `json:"target_device_id" db:"target_device_id"`
+	SourceInterfaceID *int `json:"source_interface_id" db:"source_interface_id"`
+	TargetInterfaceID *int `json:"target_interface_id" db:"target_interface_id"`
+	CableID           *int `json:"cable_id" db:"cable_id"`
+	VLANTagging
+	ConnectionType  string           `json:"connection_type" db:"connection_type"`
+	PortInfo        string           `json:"port_info" db:"port_info"`
+	Speed           string           `json:"speed" db:"speed"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	SourceDevice    *Device          `json:"source_device,omitempty"`
+	TargetDevice    *Device          `json:"target_device,omitempty"`
+	SourceInterface *DeviceInterface `json:"source_interface,omitempty"`
+	TargetInterface *DeviceInterface `json:"target_interface,omitempty"`
+	Cable           *Cable           `json:"cable,omitempty"`
 }
 
 // CreateConnectionRequest represents a request to create a network connection
 type CreateConnectionRequest struct {
-	SourceDeviceID int    `json:"source_device_id" binding:"required"`
-	TargetDeviceID int    `json:"target_device_id" binding:"required"`
-	ConnectionType string `json:"connection_type"`
-	PortInfo       string `json:"port_info"`
-	Speed          string `json:"speed"`
+	SourceDeviceID int `json:"source_device_id" binding:"required"`
+	TargetDeviceID int `json:"target_device_id" binding:"required"`
+	// Interfaces are optional; each must belong to its device and carry no other connection
+	SourceInterfaceID *int `json:"source_interface_id"`
+	TargetInterfaceID *int `json:"target_interface_id"`
+	// CableID attaches an existing cable; Cable creates one together with the connection
+	CableID        *int                `json:"cable_id"`
+	Cable          *CreateCableRequest `json:"cable"`
+	ConnectionType string              `json:"connection_type"`
+	PortInfo       string              `json:"port_info"`
+	Speed          string              `json:"speed"`
 }
 
 // UpdateConnectionRequest represents a request to update a network connection
 type UpdateConnectionRequest struct {
+	// Interface changes are optional; 0 detaches the connection from an interface
+	SourceInterfaceID *int `json:"source_interface_id"`
+	TargetInterfaceID *int `json:"target_interface_id"`
+	// CableID of 0 detaches the cable
+	CableID        *int   `json:"cable_id"`
 	ConnectionType string `json:"connection_type"`
 	PortInfo       string `json:"port_info"`
 	Speed          string `json:"speed"`
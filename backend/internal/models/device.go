@@ -21,6 +21,41 @@ const (
 	DeviceStatusUnknown DeviceStatus = "unknown"
 )
 
+// MountFace represents the rails a device is mounted on
+type MountFace string
+
+const (
+	MountFaceFront MountFace = "front"
+	MountFaceRear  MountFace = "rear"
+)
+
+// DeviceDepth represents how far a device extends from its mounting face
+type DeviceDepth string
+
+const (
+	// DeviceDepthFull occupies both faces of its U range
+	DeviceDepthFull DeviceDepth = "full"
+	// DeviceDepthHalf only occupies its mounting face, leaving the opposite face free
+	DeviceDepthHalf DeviceDepth = "half"
+)
+
+// ZeroUSide represents the side of a rack a zero-U device is attached to
+type ZeroUSide string
+
+const (
+	ZeroUSideLeft  ZeroUSide = "left"
+	ZeroUSideRight ZeroUSide = "right"
+)
+
+// PSURedundancy represents how a device's power supplies back each other up
+type PSURedundancy string
+
+const (
+	PSURedundancyNone PSURedundancy = "none"
+	PSURedundancyN1   PSURedundancy = "n+1"
+	PSURedundancy2N   PSURedundancy = "2n"
+)
+
 // Device represents a device in a rack
 type Device struct {
 	ID             int                    `json:"id" db:"id"`
@@ -30,13 +65,62 @@ type Device struct {
 	Type           DeviceType             `json:"type" db:"type"`
 	PositionU      int                    `json:"position_u" db:"position_u"`
 	SizeU          int                    `json:"size_u" db:"size_u"`
+	MountFace      MountFace              `json:"mount_face" db:"mount_face"`
+	Depth          DeviceDepth            `json:"depth" db:"depth"`
+	// Faces lists the rack faces the device occupies, derived from MountFace and Depth
+	Faces          []MountFace            `json:"faces"`
+	// ZeroUSide is set for zero-U devices, which have position_u = size_u = 0
+	ZeroUSide      ZeroUSide              `json:"zero_u_side,omitempty" db:"zero_u_side"`
+	ZeroUSlot      string                 `json:"zero_u_slot,omitempty" db:"zero_u_slot"`
+	// ParentDeviceID and Bay are set for devices installed in a chassis bay instead of rack U
+	ParentDeviceID *int                   `json:"parent_device_id,omitempty" db:"parent_device_id"`
+	Bay            int                    `json:"bay,omitempty" db:"bay"`
+	// BayCount is the number of bays a chassis provides for child devices
+	BayCount       int                    `json:"bay_count,omitempty" db:"bay_count"`
 	Status         DeviceStatus           `json:"status" db:"status"`
 	Model          string                 `json:"model" db:"model"`
+	DeviceTypeID   *int                   `json:"device_type_id,omitempty" db:"device_type_id"`
 	IPAddress      string                 `json:"ip_address" db:"ip_address"`
 	HealthCheckURL string                 `json:"health_check_url" db:"health_check_url"`
+	// HealthCheckInterval is the scheduled check interval in seconds (0 uses the scheduler default)
+	HealthCheckInterval int               `json:"health_check_interval,omitempty" db:"health_check_interval"`
+	HealthCheckMode HealthCheckMode       `json:"health_check_mode" db:"health_check_mode"`
+	// Power draw in watts; 0 means not recorded
+	NameplateWatts int                    `json:"nameplate_watts,omitempty" db:"nameplate_watts"`
+	MeasuredWatts  int                    `json:"measured_watts,omitempty" db:"measured_watts"`
+	PSUCount       int                    `json:"psu_count,omitempty" db:"psu_count"`
+	PSURedundancy  PSURedundancy          `json:"psu_redundancy" db:"psu_redundancy"`
 	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time              `json:"updated_at" db:"updated_at"`
 	Specs          map[string]string      `json:"specs,omitempty"`
+	// Children is loaded recursively by GetDeviceByID
+	Children       []Device               `json:"children,omitempty"`
+}
+
+// DevicePlacement is a device's slot range as seen from one rack face
+type DevicePlacement struct {
+	DeviceID int         `json:"device_id"`
+	Name     string      `json:"name"`
+	TopU     int         `json:"top_u"`
+	BottomU  int         `json:"bottom_u"`
+	Depth    DeviceDepth `json:"depth"`
+	// Mounted is false when a full-depth device is seen from its opposite face
+	Mounted bool `json:"mounted"`
+}
+
+// ZeroUPlacement is a zero-U device's position on the side of a rack
+type ZeroUPlacement struct {
+	DeviceID int       `json:"device_id"`
+	Name     string    `json:"name"`
+	Side     ZeroUSide `json:"side"`
+	Slot     string    `json:"slot,omitempty"`
+}
+
+// RackElevation lists the placements visible from each rack face and the zero-U devices on its sides
+type RackElevation struct {
+	Front []DevicePlacement `json:"front"`
+	Rear  []DevicePlacement `json:"rear"`
+	ZeroU []ZeroUPlacement  `json:"zero_u"`
 }
 
 // DeviceSpec represents a device specification
@@ -53,13 +137,30 @@ type CreateDeviceRequest struct {
 	RackID         int         `json:"rack_id" binding:"required"`
 	Name           string      `json:"name" binding:"required"`
 	Icon           string      `json:"icon"`
-	Type           DeviceType  `json:"type" binding:"required,oneof=server network storage"`
-	PositionU      int         `json:"position_u" binding:"required,min=1"`
-	SizeU          int         `json:"size_u" binding:"required,min=1"`
+	// DeviceTypeID fills type, model, size_u, icon, specs, power and bays from the catalog; request fields override them
+	DeviceTypeID   *int        `json:"device_type_id"`
+	Type           DeviceType  `json:"type" binding:"omitempty,oneof=server network storage"`
+	// PositionU and SizeU are required unless ZeroUSide is set
+	PositionU      int         `json:"position_u" binding:"min=0"`
+	SizeU          int         `json:"size_u" binding:"min=0"`
+	MountFace      MountFace   `json:"mount_face" binding:"omitempty,oneof=front rear"`
+	Depth          DeviceDepth `json:"depth" binding:"omitempty,oneof=full half"`
+	ZeroUSide      ZeroUSide   `json:"zero_u_side" binding:"omitempty,oneof=left right"`
+	ZeroUSlot      string      `json:"zero_u_slot" binding:"omitempty,oneof=A B"`
+	// ParentDeviceID and Bay install the device into a chassis bay instead of rack U
+	ParentDeviceID *int        `json:"parent_device_id"`
+	Bay            int         `json:"bay" binding:"omitempty,min=1"`
+	BayCount       int         `json:"bay_count" binding:"omitempty,min=0"`
 	Status         DeviceStatus `json:"status" binding:"oneof=online offline warning unknown"`
 	Model          string      `json:"model"`
 	IPAddress      string      `json:"ip_address"`
 	HealthCheckURL string      `json:"health_check_url"`
+	HealthCheckInterval int    `json:"health_check_interval" binding:"omitempty,min=0"`
+	HealthCheckMode HealthCheckMode `json:"health_check_mode" binding:"omitempty,oneof=all any majority"`
+	NameplateWatts int         `json:"nameplate_watts" binding:"omitempty,min=0"`
+	MeasuredWatts  int         `json:"measured_watts" binding:"omitempty,min=0"`
+	PSUCount       int         `json:"psu_count" binding:"omitempty,min=0"`
+	PSURedundancy  PSURedundancy `json:"psu_redundancy" binding:"omitempty,oneof=none n+1 2n"`
 	Specs          map[string]string `json:"specs"`
 }
 
@@ -71,9 +172,26 @@ type UpdateDeviceRequest struct {
 	Type           *DeviceType  `json:"type"`
 	PositionU      *int         `json:"position_u"`
 	SizeU          *int         `json:"size_u"`
+	MountFace      *MountFace   `json:"mount_face" binding:"omitempty,oneof=front rear"`
+	Depth          *DeviceDepth `json:"depth" binding:"omitempty,oneof=full half"`
+	// ZeroUSide of "" turns a zero-U device back into a U-mounted one (position_u and size_u are then required)
+	ZeroUSide      *ZeroUSide   `json:"zero_u_side"`
+	ZeroUSlot      *string      `json:"zero_u_slot"`
+	// ParentDeviceID of 0 removes the device from its chassis (a rack placement is then required)
+	ParentDeviceID *int         `json:"parent_device_id"`
+	Bay            *int         `json:"bay" binding:"omitempty,min=1"`
+	BayCount       *int         `json:"bay_count" binding:"omitempty,min=0"`
 	Status         *DeviceStatus `json:"status"`
 	Model          *string      `json:"model"`
+	// DeviceTypeID links the device to a catalog entry without changing its fields; 0 unlinks it
+	DeviceTypeID   *int         `json:"device_type_id"`
 	IPAddress      *string      `json:"ip_address"`
 	HealthCheckURL *string      `json:"health_check_url"`
+	HealthCheckInterval *int    `json:"health_check_interval" binding:"omitempty,min=0"`
+	HealthCheckMode *HealthCheckMode `json:"health_check_mode" binding:"omitempty,oneof=all any majority"`
+	NameplateWatts *int         `json:"nameplate_watts" binding:"omitempty,min=0"`
+	MeasuredWatts  *int         `json:"measured_watts" binding:"omitempty,min=0"`
+	PSUCount       *int         `json:"psu_count" binding:"omitempty,min=0"`
+	PSURedundancy  *PSURedundancy `json:"psu_redundancy" binding:"omitempty,oneof=none n+1 2n"`
 	Specs          map[string]string `json:"specs"`
 }
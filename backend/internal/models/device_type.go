@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// DeviceTypeTemplate is a device type catalog entry holding the defaults shared by every device of a make and model
+type DeviceTypeTemplate struct {
+	ID             int               `json:"id" db:"id"`
+	Manufacturer   string            `json:"manufacturer" db:"manufacturer"`
+	Model          string            `json:"model" db:"model"`
+	Type           DeviceType        `json:"type" db:"type"`
+	SizeU          int               `json:"size_u" db:"size_u"`
+	Icon           string            `json:"icon" db:"icon"`
+	Specs          map[string]string `json:"specs" db:"specs"`
+	NameplateWatts int               `json:"nameplate_watts,omitempty" db:"nameplate_watts"`
+	PSUCount       int               `json:"psu_count,omitempty" db:"psu_count"`
+	PSURedundancy  PSURedundancy     `json:"psu_redundancy" db:"psu_redundancy"`
+	BayCount       int               `json:"bay_count,omitempty" db:"bay_count"`
+	Ports          []PortTemplate    `json:"ports" db:"ports"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// PortTemplate describes one port in a device type's port layout
+type PortTemplate struct {
+	Name  string `json:"name" binding:"required"`
+	Type  string `json:"type,omitempty"`
+	Speed string `json:"speed,omitempty"`
+}
+
+// CreateDeviceTypeRequest represents a request to add a device type to the catalog
+type CreateDeviceTypeRequest struct {
+	Manufacturer   string            `json:"manufacturer" binding:"required"`
+	Model          string            `json:"model" binding:"required"`
+	Type           DeviceType        `json:"type" binding:"required,oneof=server network storage"`
+	SizeU          *int              `json:"size_u" binding:"omitempty,min=0"`
+	Icon           string            `json:"icon"`
+	Specs          map[string]string `json:"specs"`
+	NameplateWatts int               `json:"nameplate_watts" binding:"omitempty,min=0"`
+	PSUCount       int               `json:"psu_count" binding:"omitempty,min=0"`
+	PSURedundancy  PSURedundancy     `json:"psu_redundancy" binding:"omitempty,oneof=none n+1 2n"`
+	BayCount       int               `json:"bay_count" binding:"omitempty,min=0"`
+	Ports          []PortTemplate    `json:"ports" binding:"dive"`
+}
+
+// UpdateDeviceTypeRequest represents a request to update a device type; existing devices keep their values
+type UpdateDeviceTypeRequest struct {
+	Manufacturer   *string           `json:"manufacturer"`
+	Model          *string           `json:"model"`
+	Type           *DeviceType       `json:"type" binding:"omitempty,oneof=server network storage"`
+	SizeU          *int              `json:"size_u" binding:"omitempty,min=0"`
+	Icon           *string           `json:"icon"`
+	Specs          map[string]string `json:"specs"`
+	NameplateWatts *int              `json:"nameplate_watts" binding:"omitempty,min=0"`
+	PSUCount       *int              `json:"psu_count" binding:"omitempty,min=0"`
+	PSURedundancy  *PSURedundancy    `json:"psu_redundancy" binding:"omitempty,oneof=none n+1 2n"`
+	BayCount       *int              `json:"bay_count" binding:"omitempty,min=0"`
+	Ports          []PortTemplate    `json:"ports" binding:"omitempty,dive"`
+}
+
+// DeviceModelCount is the number of devices of one model, used by the model report
+type DeviceModelCount struct {
+	// DeviceTypeID is nil for devices not linked to the catalog, which are grouped by their model text
+	DeviceTypeID *int   `json:"device_type_id"`
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	Devices      int    `json:"devices"`
+	Racks        int    `json:"racks"`
+}
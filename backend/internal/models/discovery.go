@@ -0,0 +1,20 @@
+package models
+
+// PrometheusTargetGroup is one entry in a Prometheus http_sd_config response
+type PrometheusTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// PrometheusTargetFilter represents the filters for Prometheus service discovery
+type PrometheusTargetFilter struct {
+	Type   string
+	Rack   string
+	Status string
+	// Specs requires each key to be present, and to equal the value when one is given
+	Specs map[string]string
+	// SpecLabels lists the spec keys exported as labels
+	SpecLabels []string
+	// Port is appended to each target address when set
+	Port int
+}
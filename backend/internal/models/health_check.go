@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// HealthCheck represents a persisted health check result
+type HealthCheck struct {
+	ID        int64        `json:"id" db:"id"`
+	DeviceID  int          `json:"device_id" db:"device_id"`
+	Status    DeviceStatus `json:"status" db:"status"`
+	Latency   *int64       `json:"latency_ms,omitempty" db:"latency_ms"`
+	Message   string       `json:"message" db:"message"`
+	CheckedAt time.Time    `json:"checked_at" db:"checked_at"`
+}
+
+// HealthHistoryBucket aggregates the health checks that fall within one time bucket
+type HealthHistoryBucket struct {
+	Start        time.Time `json:"start"`
+	Checks       int       `json:"checks"`
+	Online       int       `json:"online"`
+	Offline      int       `json:"offline"`
+	Warning      int       `json:"warning"`
+	Unknown      int       `json:"unknown"`
+	AvgLatency   *float64  `json:"avg_latency_ms,omitempty"`
+	MinLatency   *int64    `json:"min_latency_ms,omitempty"`
+	MaxLatency   *int64    `json:"max_latency_ms,omitempty"`
+	Availability float64   `json:"availability"`
+}
+
+// HealthHistory is the response for a device's health history query
+type HealthHistory struct {
+	DeviceID      int                   `json:"device_id"`
+	From          time.Time             `json:"from"`
+	To            time.Time             `json:"to"`
+	BucketSeconds int                   `json:"bucket_seconds,omitempty"`
+	Checks        []HealthCheck         `json:"checks,omitempty"`
+	Buckets       []HealthHistoryBucket `json:"buckets,omitempty"`
+}
+
+// HealthHistoryQuery represents the filters for a health history query
+type HealthHistoryQuery struct {
+	From   time.Time
+	To     time.Time
+	Bucket time.Duration
+	Limit  int
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// HealthProbeType represents the kind of health probe
+type HealthProbeType string
+
+const (
+	HealthProbeTypeTCP  HealthProbeType = "tcp"
+	HealthProbeTypeHTTP HealthProbeType = "http"
+	HealthProbeTypeTLS  HealthProbeType = "tls"
+	HealthProbeTypeDNS  HealthProbeType = "dns"
+)
+
+// HealthCheckMode represents how probe results are combined into a device status
+type HealthCheckMode string
+
+const (
+	// HealthCheckModeAll requires every probe to pass (the worst result wins)
+	HealthCheckModeAll HealthCheckMode = "all"
+	// HealthCheckModeAny requires a single probe to pass (the best result wins)
+	HealthCheckModeAny HealthCheckMode = "any"
+	// HealthCheckModeMajority requires more than half of the probes to pass
+	HealthCheckModeMajority HealthCheckMode = "majority"
+)
+
+// HealthProbe represents a configured health probe for a device
+type HealthProbe struct {
+	ID        int               `json:"id" db:"id"`
+	DeviceID  int               `json:"device_id" db:"device_id"`
+	Name      string            `json:"name" db:"name"`
+	Type      HealthProbeType   `json:"type" db:"type"`
+	Config    HealthProbeConfig `json:"config" db:"config"`
+	Enabled   bool              `json:"enabled" db:"enabled"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// HealthProbeConfig holds the type-specific probe settings; unused fields are omitted
+type HealthProbeConfig struct {
+	// Host defaults to the device IP address (tcp, tls)
+	Host      string `json:"host,omitempty"`
+	TimeoutMs int    `json:"timeout_ms,omitempty"`
+
+	// TCP: every port must accept a connection
+	Ports []int `json:"ports,omitempty"`
+
+	// HTTP: URL defaults to the device health check URL
+	URL                string            `json:"url,omitempty"`
+	Method             string            `json:"method,omitempty"`
+	RequestHeaders     map[string]string `json:"request_headers,omitempty"`
+	ExpectedStatus     []int             `json:"expected_status,omitempty"`
+	BodyContains       string            `json:"body_contains,omitempty"`
+	ExpectedHeaders    map[string]string `json:"expected_headers,omitempty"`
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+
+	// TLS: port defaults to 443; certificate expiry thresholds in days
+	Port         int    `json:"port,omitempty"`
+	ServerName   string `json:"server_name,omitempty"`
+	WarnDays     int    `json:"warn_days,omitempty"`
+	CriticalDays int    `json:"critical_days,omitempty"`
+
+	// DNS: resolve QueryName (type A, AAAA, CNAME, MX or TXT) and optionally expect values
+	QueryName  string   `json:"query_name,omitempty"`
+	RecordType string   `json:"record_type,omitempty"`
+	Expected   []string `json:"expected,omitempty"`
+	// Resolver is a host:port DNS server; the system resolver is used when empty
+	Resolver string `json:"resolver,omitempty"`
+}
+
+// CreateHealthProbeRequest represents a request to add a health probe to a device
+type CreateHealthProbeRequest struct {
+	Name    string            `json:"name"`
+	Type    HealthProbeType   `json:"type" binding:"required,oneof=tcp http tls dns"`
+	Config  HealthProbeConfig `json:"config"`
+	Enabled *bool             `json:"enabled"`
+}
+
+// UpdateHealthProbeRequest represents a request to update a health probe
+type UpdateHealthProbeRequest struct {
+	Name    *string            `json:"name"`
+	Config  *HealthProbeConfig `json:"config"`
+	Enabled *bool              `json:"enabled"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// DeviceInterface represents a port on a device
+type DeviceInterface struct {
+	ID          int    `json:"id" db:"id"`
+	DeviceID    int    `json:"device_id" db:"device_id"`
+	Name        string `json:"name" db:"name"`
+	Type        string `json:"type" db:"type"`
+	Speed       string `json:"speed" db:"speed"`
+	MACAddress  string `json:"mac_address" db:"mac_address"`
+	Enabled     bool   `json:"enabled" db:"enabled"`
+	Description string `json:"description" db:"description"`
+	// RearPortID is set on a patch panel front port and names its paired rear port
+	RearPortID *int `json:"rear_port_id" db:"rear_port_id"`
+	// FrontPortID is set on a patch panel rear port and names its paired front port
+	FrontPortID *int `json:"front_port_id" db:"front_port_id"`
+	VLANTagging
+	// ConnectionID is the connection plugged into this interface, if any
+	ConnectionID *int      `json:"connection_id" db:"connection_id"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateDeviceInterfaceRequest represents a request to add an interface to a device
+type CreateDeviceInterfaceRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Type        string `json:"type"`
+	Speed       string `json:"speed"`
+	MACAddress  string `json:"mac_address" binding:"omitempty,mac"`
+	Enabled     *bool  `json:"enabled"`
+	Description string `json:"description"`
+	// RearPortID pairs this interface as a patch panel front port with a rear port on the same device
+	RearPortID *int `json:"rear_port_id"`
+}
+
+// UpdateDeviceInterfaceRequest represents a request to update an interface
+type UpdateDeviceInterfaceRequest struct {
+	Name        *string `json:"name"`
+	Type        *string `json:"type"`
+	Speed       *string `json:"speed"`
+	MACAddress  *string `json:"mac_address" binding:"omitempty,mac"`
+	Enabled     *bool   `json:"enabled"`
+	Description *string `json:"description"`
+	// RearPortID of 0 unpairs the front port
+	RearPortID *int `json:"rear_port_id"`
+}
+
+// PortUsage is the number of free and used interfaces on a device
+type PortUsage struct {
+	DeviceID   int    `json:"device_id"`
+	DeviceName string `json:"device_name"`
+	RackID     int    `json:"rack_id"`
+	Total      int    `json:"total"`
+	Used       int    `json:"used"`
+	// Free counts enabled interfaces without a connection
+	Free     int `json:"free"`
+	Disabled int `json:"disabled"`
+}
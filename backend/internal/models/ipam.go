@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// IPPrefix represents a subnet managed by rackview
+type IPPrefix struct {
+	ID          int    `json:"id" db:"id"`
+	Prefix      string `json:"prefix" db:"prefix"`
+	Description string `json:"description" db:"description"`
+	// Used counts assigned addresses in the prefix, from ip_addresses and device ip_address fields
+	Used int `json:"used"`
+	// Utilization is Used as a fraction of the usable addresses
+	Utilization float64   `json:"utilization"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateIPPrefixRequest represents a request to create a prefix
+type CreateIPPrefixRequest struct {
+	Prefix      string `json:"prefix" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateIPPrefixRequest represents a request to update a prefix
+type UpdateIPPrefixRequest struct {
+	Prefix      *string `json:"prefix"`
+	Description *string `json:"description"`
+}
+
+// IPAddress represents an address assigned to a device, optionally on one of its interfaces
+type IPAddress struct {
+	ID          int    `json:"id" db:"id"`
+	Address     string `json:"address" db:"address"`
+	DeviceID    int    `json:"device_id" db:"device_id"`
+	InterfaceID *int   `json:"interface_id" db:"interface_id"`
+	// PrefixID is the most specific prefix containing the address
+	PrefixID    *int      `json:"prefix_id" db:"prefix_id"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateIPAddressRequest represents a request to assign an address
+type CreateIPAddressRequest struct {
+	Address     string `json:"address" binding:"required"`
+	DeviceID    int    `json:"device_id" binding:"required"`
+	InterfaceID *int   `json:"interface_id"`
+	Description string `json:"description"`
+}
+
+// UpdateIPAddressRequest represents a request to update an address assignment
+type UpdateIPAddressRequest struct {
+	Address *string `json:"address"`
+	// InterfaceID of 0 assigns the address to the device as a whole
+	InterfaceID *int    `json:"interface_id"`
+	Description *string `json:"description"`
+}
+
+// IPAddressFilter represents the filters for listing addresses
+type IPAddressFilter struct {
+	DeviceID *int
+	PrefixID *int
+	// Address matches one address in canonical form
+	Address string
+}
+
+// IPConflictKind describes an IPAM problem
+type IPConflictKind string
+
+const (
+	// IPConflictDuplicate means an address is assigned to more than one device
+	IPConflictDuplicate IPConflictKind = "duplicate"
+	// IPConflictOutOfSubnet means an address is in no known prefix, or outside the prefix it was assigned from
+	IPConflictOutOfSubnet IPConflictKind = "out_of_subnet"
+	// IPConflictInvalid means a device ip_address does not parse as an address
+	IPConflictInvalid IPConflictKind = "invalid"
+)
+
+// IPAssignment is one place an address is used
+type IPAssignment struct {
+	DeviceID    int    `json:"device_id"`
+	DeviceName  string `json:"device_name"`
+	IPAddressID *int   `json:"ip_address_id,omitempty"`
+	InterfaceID *int   `json:"interface_id,omitempty"`
+}
+
+// IPConflict is an address with an IPAM problem
+type IPConflict struct {
+	Address     string         `json:"address"`
+	Kind        IPConflictKind `json:"kind"`
+	Message     string         `json:"message"`
+	Assignments []IPAssignment `json:"assignments"`
+}
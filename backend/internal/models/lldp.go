@@ -0,0 +1,58 @@
+package models
+
+// LLDPNeighbor is one neighbor entry reported by a device over LLDP or CDP
+type LLDPNeighbor struct {
+	// LocalPort is the reporting device's interface the neighbor was seen on
+	LocalPort             string `json:"local_port"`
+	RemoteSystemName      string `json:"remote_system_name"`
+	RemoteChassisID       string `json:"remote_chassis_id,omitempty"`
+	RemotePortID          string `json:"remote_port_id"`
+	RemotePortDescription string `json:"remote_port_description,omitempty"`
+	RemoteManagementIP    string `json:"remote_management_ip,omitempty"`
+}
+
+// LLDPImportRequest represents a neighbor table reported by a device, as entries or raw CLI output
+type LLDPImportRequest struct {
+	DeviceID  int            `json:"device_id" binding:"required"`
+	Neighbors []LLDPNeighbor `json:"neighbors"`
+	// Output is `show lldp neighbors detail` or `show cdp neighbors detail` text, parsed into Neighbors
+	Output string `json:"output"`
+}
+
+// LLDPNeighborStatus is the outcome of reconciling one neighbor against the stored connections
+type LLDPNeighborStatus string
+
+const (
+	// LLDPNeighborMatched means a stored connection already describes the link
+	LLDPNeighborMatched LLDPNeighborStatus = "matched"
+	// LLDPNeighborCreated means the connection was missing and has been created
+	LLDPNeighborCreated LLDPNeighborStatus = "created"
+	// LLDPNeighborMissing means the connection is missing and would be created outside a dry run
+	LLDPNeighborMissing LLDPNeighborStatus = "missing"
+	// LLDPNeighborMismatch means a stored connection on one of the ports disagrees with the neighbor
+	LLDPNeighborMismatch LLDPNeighborStatus = "mismatch"
+	// LLDPNeighborUnresolved means the neighbor's device could not be found
+	LLDPNeighborUnresolved LLDPNeighborStatus = "unresolved"
+)
+
+// LLDPNeighborResult is one neighbor with the devices, interfaces and connection it resolved to
+type LLDPNeighborResult struct {
+	Neighbor          LLDPNeighbor       `json:"neighbor"`
+	Status            LLDPNeighborStatus `json:"status"`
+	Message           string             `json:"message,omitempty"`
+	LocalInterfaceID  *int               `json:"local_interface_id,omitempty"`
+	RemoteDeviceID    *int               `json:"remote_device_id,omitempty"`
+	RemoteInterfaceID *int               `json:"remote_interface_id,omitempty"`
+	// ConnectionID is the matched, created or conflicting connection
+	ConnectionID *int `json:"connection_id,omitempty"`
+}
+
+// LLDPImportResult is the diff between a device's neighbor table and its stored connections
+type LLDPImportResult struct {
+	DeviceID  int                  `json:"device_id"`
+	DryRun    bool                 `json:"dry_run"`
+	Neighbors []LLDPNeighborResult `json:"neighbors"`
+	// Stale lists the device's connections that no neighbor matched
+	Stale   []NetworkConnection `json:"stale"`
+	Summary map[string]int      `json:"summary"`
+}
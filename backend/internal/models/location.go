@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// Site represents a datacenter or other physical location
+type Site struct {
+	ID          int       `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Address     string    `json:"address" db:"address"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Room represents a room within a site
+type Room struct {
+	ID          int       `json:"id" db:"id"`
+	SiteID      int       `json:"site_id" db:"site_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Row represents a row of racks within a room
+type Row struct {
+	ID          int       `json:"id" db:"id"`
+	RoomID      int       `json:"room_id" db:"room_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateSiteRequest represents a request to create a new site
+type CreateSiteRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Address     string `json:"address"`
+}
+
+// UpdateSiteRequest represents a request to update a site
+type UpdateSiteRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Address     *string `json:"address"`
+}
+
+// CreateRoomRequest represents a request to create a new room
+type CreateRoomRequest struct {
+	SiteID      int    `json:"site_id" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateRoomRequest represents a request to update a room
+type UpdateRoomRequest struct {
+	SiteID      *int    `json:"site_id"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// CreateRowRequest represents a request to create a new row
+type CreateRowRequest struct {
+	RoomID      int    `json:"room_id" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateRowRequest represents a request to update a row
+type UpdateRowRequest struct {
+	RoomID      *int    `json:"room_id"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// RackSummary is a rack with its device count, used in location trees
+type RackSummary struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	SizeU       int    `json:"size_u"`
+	DeviceCount int    `json:"device_count"`
+}
+
+// RowTree is a row with its racks
+type RowTree struct {
+	Row
+	Racks       []RackSummary `json:"racks"`
+	DeviceCount int           `json:"device_count"`
+}
+
+// RoomTree is a room with its rows and any racks not placed in a row
+type RoomTree struct {
+	Room
+	Rows        []RowTree     `json:"rows"`
+	Racks       []RackSummary `json:"racks"`
+	DeviceCount int           `json:"device_count"`
+}
+
+// SiteTree is a site with its rooms and any racks not placed in a room
+type SiteTree struct {
+	Site
+	Rooms       []RoomTree    `json:"rooms"`
+	Racks       []RackSummary `json:"racks"`
+	DeviceCount int           `json:"device_count"`
+}
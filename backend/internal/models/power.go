@@ -0,0 +1,42 @@
+package models
+
+// PowerSource identifies where a device's power draw figure comes from
+type PowerSource string
+
+const (
+	PowerSourceMeasured  PowerSource = "measured"
+	PowerSourceNameplate PowerSource = "nameplate"
+	PowerSourceUnknown   PowerSource = "unknown"
+)
+
+// DevicePower is a device's contribution to its rack's power draw
+type DevicePower struct {
+	DeviceID       int           `json:"device_id"`
+	Name           string        `json:"name"`
+	PositionU      int           `json:"position_u"`
+	NameplateWatts int           `json:"nameplate_watts"`
+	MeasuredWatts  int           `json:"measured_watts"`
+	DrawWatts      int           `json:"draw_watts"`
+	Source         PowerSource   `json:"source"`
+	PSUCount       int           `json:"psu_count"`
+	PSURedundancy  PSURedundancy `json:"psu_redundancy"`
+}
+
+// RackPower is the power budget report for a rack
+type RackPower struct {
+	RackID         int    `json:"rack_id"`
+	RackName       string `json:"rack_name"`
+	CapacityWatts  int    `json:"capacity_watts"`
+	NameplateWatts int    `json:"nameplate_watts"`
+	MeasuredWatts  int    `json:"measured_watts"`
+	// DrawWatts sums measured draw where known, falling back to nameplate
+	DrawWatts int `json:"draw_watts"`
+	// HeadroomWatts and Utilization are omitted when the rack has no capacity set
+	HeadroomWatts  *int          `json:"headroom_watts,omitempty"`
+	Utilization    *float64      `json:"utilization,omitempty"`
+	Threshold      float64       `json:"threshold"`
+	OverThreshold  bool          `json:"over_threshold"`
+	OverCapacity   bool          `json:"over_capacity"`
+	UnknownDevices int           `json:"unknown_devices"`
+	Devices        []DevicePower `json:"devices"`
+}
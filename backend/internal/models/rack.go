@@ -4,20 +4,31 @@ import "time"
 
 // Rack represents a server rack
 type Rack struct {
-	ID          int       `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	SizeU       int       `json:"size_u" db:"size_u"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
-	Devices     []Device  `json:"devices,omitempty"`
+	ID          int    `json:"id" db:"id"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+	SizeU       int    `json:"size_u" db:"size_u"`
+	SiteID      *int   `json:"site_id" db:"site_id"`
+	RoomID      *int   `json:"room_id" db:"room_id"`
+	RowID       *int   `json:"row_id" db:"row_id"`
+	// PowerCapacityWatts is the usable PDU budget; 0 means untracked
+	PowerCapacityWatts int            `json:"power_capacity_watts,omitempty" db:"power_capacity_watts"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+	Devices            []Device       `json:"devices,omitempty"`
+	Elevation          *RackElevation `json:"elevation,omitempty"`
 }
 
 // CreateRackRequest represents a request to create a new rack
 type CreateRackRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
-	SizeU       int    `json:"size_u" binding:"required,min=1"`
+	Name               string `json:"name" binding:"required"`
+	Description        string `json:"description"`
+	SizeU              int    `json:"size_u" binding:"required,min=1"`
+	PowerCapacityWatts int    `json:"power_capacity_watts" binding:"omitempty,min=0"`
+	// Location: the most specific of site_id, room_id or row_id; parents are filled in automatically
+	SiteID *int `json:"site_id"`
+	RoomID *int `json:"room_id"`
+	RowID  *int `json:"row_id"`
 }
 
 // UpdateRackRequest represents a request to update a rack
@@ -25,4 +36,17 @@ type UpdateRackRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	SizeU       *int   `json:"size_u"`
+	// PowerCapacityWatts of 0 stops tracking the rack budget
+	PowerCapacityWatts *int `json:"power_capacity_watts" binding:"omitempty,min=0"`
+	// Location changes replace the whole location; 0 clears it
+	SiteID *int `json:"site_id"`
+	RoomID *int `json:"room_id"`
+	RowID  *int `json:"row_id"`
+}
+
+// RackFilter represents the location filters for listing racks
+type RackFilter struct {
+	SiteID *int
+	RoomID *int
+	RowID  *int
 }
@@ -0,0 +1,54 @@
+package models
+
+// TopologyFormat is the output format of a topology export
+type TopologyFormat string
+
+const (
+	TopologyFormatJSON    TopologyFormat = "json"
+	TopologyFormatDOT     TopologyFormat = "dot"
+	TopologyFormatGraphML TopologyFormat = "graphml"
+	TopologyFormatMermaid TopologyFormat = "mermaid"
+)
+
+// TopologyFilter narrows a topology export. Edges are kept when both of their devices are.
+type TopologyFilter struct {
+	RackIDs        []int
+	DeviceTypes    []DeviceType
+	ConnectionType string
+}
+
+// TopologyRack groups the devices of a rack
+type TopologyRack struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	DeviceIDs []int  `json:"device_ids"`
+}
+
+// TopologyNode is a device in the topology
+type TopologyNode struct {
+	ID       int          `json:"id"`
+	Name     string       `json:"name"`
+	Type     DeviceType   `json:"type"`
+	Status   DeviceStatus `json:"status"`
+	RackID   int          `json:"rack_id"`
+	RackName string       `json:"rack_name"`
+}
+
+// TopologyEdge is a network connection in the topology
+type TopologyEdge struct {
+	ID             int    `json:"id"`
+	Source         int    `json:"source"`
+	Target         int    `json:"target"`
+	SourcePort     string `json:"source_port,omitempty"`
+	TargetPort     string `json:"target_port,omitempty"`
+	ConnectionType string `json:"connection_type,omitempty"`
+	Speed          string `json:"speed,omitempty"`
+	PortInfo       string `json:"port_info,omitempty"`
+}
+
+// Topology is the device graph used for topology exports
+type Topology struct {
+	Racks []TopologyRack `json:"racks"`
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// TraceStatus describes how a cable path trace ended
+type TraceStatus string
+
+const (
+	// TraceStatusComplete means the path ended on a device port that is not a pass-through
+	TraceStatusComplete TraceStatus = "complete"
+	// TraceStatusBroken means the path ended at a patch panel port with no cable
+	TraceStatusBroken TraceStatus = "broken"
+	// TraceStatusLoop means the path returned to a port it had already passed
+	TraceStatusLoop TraceStatus = "loop"
+	// TraceStatusUnconnected means the starting port has no cable
+	TraceStatusUnconnected TraceStatus = "unconnected"
+)
+
+// TraceEndpoint is a device port at either end of a cable; the interface is empty for
+// connections made to the device as a whole
+type TraceEndpoint struct {
+	DeviceID      int    `json:"device_id"`
+	DeviceName    string `json:"device_name"`
+	InterfaceID   *int   `json:"interface_id"`
+	InterfaceName string `json:"interface_name,omitempty"`
+}
+
+// TraceHop is one cable of a path
+type TraceHop struct {
+	ConnectionID   int           `json:"connection_id"`
+	ConnectionType string        `json:"connection_type"`
+	PortInfo       string        `json:"port_info"`
+	Speed          string        `json:"speed"`
+	From           TraceEndpoint `json:"from"`
+	To             TraceEndpoint `json:"to"`
+	// PassThrough is set when To is a patch panel port and the path continues from its paired port
+	PassThrough *TraceEndpoint `json:"pass_through,omitempty"`
+}
+
+// CablePath is the ordered list of cables from a port to the far end
+type CablePath struct {
+	Origin      TraceEndpoint  `json:"origin"`
+	Hops        []TraceHop     `json:"hops"`
+	Destination *TraceEndpoint `json:"destination"`
+	Status      TraceStatus    `json:"status"`
+	Message     string         `json:"message,omitempty"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import "time"
+
+// VLANGroup scopes a set of VLAN IDs, optionally to a site
+type VLANGroup struct {
+	ID          int       `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	SiteID      *int      `json:"site_id" db:"site_id"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateVLANGroupRequest represents a request to create a VLAN group
+type CreateVLANGroupRequest struct {
+	Name        string `json:"name" binding:"required"`
+	SiteID      *int   `json:"site_id"`
+	Description string `json:"description"`
+}
+
+// UpdateVLANGroupRequest represents a request to update a VLAN group
+type UpdateVLANGroupRequest struct {
+	Name *string `json:"name"`
+	// SiteID of 0 makes the group global
+	SiteID      *int    `json:"site_id"`
+	Description *string `json:"description"`
+}
+
+// VLAN represents an 802.1Q VLAN
+type VLAN struct {
+	ID      int    `json:"id" db:"id"`
+	VID     int    `json:"vid" db:"vid"`
+	Name    string `json:"name" db:"name"`
+	GroupID *int   `json:"group_id" db:"group_id"`
+	// SiteID limits the VLAN to devices in racks at the site; grouped VLANs take their group's site
+	SiteID      *int      `json:"site_id" db:"site_id"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateVLANRequest represents a request to create a VLAN
+type CreateVLANRequest struct {
+	VID         int    `json:"vid" binding:"required,min=1,max=4094"`
+	Name        string `json:"name" binding:"required"`
+	GroupID     *int   `json:"group_id"`
+	SiteID      *int   `json:"site_id"`
+	Description string `json:"description"`
+}
+
+// UpdateVLANRequest represents a request to update a VLAN
+type UpdateVLANRequest struct {
+	VID         *int    `json:"vid" binding:"omitempty,min=1,max=4094"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// VLANFilter represents the filters for listing VLANs
+type VLANFilter struct {
+	GroupID *int
+	SiteID  *int
+}
+
+// VLANMode represents how an interface or connection carries VLANs
+type VLANMode string
+
+const (
+	// VLANModeAccess carries a single untagged VLAN
+	VLANModeAccess VLANMode = "access"
+	// VLANModeTrunk carries tagged VLANs and an optional native (untagged) VLAN
+	VLANModeTrunk VLANMode = "trunk"
+)
+
+// VLANTagging is the VLAN configuration of an interface or connection
+type VLANTagging struct {
+	VLANMode VLANMode `json:"vlan_mode,omitempty"`
+	// UntaggedVLANID is the access VLAN, or the native VLAN of a trunk
+	UntaggedVLANID *int `json:"untagged_vlan_id,omitempty"`
+	// TaggedVLANIDs lists the VLANs allowed on a trunk; empty allows all
+	TaggedVLANIDs []int `json:"tagged_vlan_ids,omitempty"`
+}
+
+// Carries reports whether the tagging carries a VLAN
+func (t VLANTagging) Carries(vlanID int) bool {
+	switch t.VLANMode {
+	case VLANModeAccess:
+		return t.UntaggedVLANID != nil && *t.UntaggedVLANID == vlanID
+	case VLANModeTrunk:
+		if (t.UntaggedVLANID != nil && *t.UntaggedVLANID == vlanID) || len(t.TaggedVLANIDs) == 0 {
+			return true
+		}
+		for _, id := range t.TaggedVLANIDs {
+			if id == vlanID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetVLANTaggingRequest represents a request to set the VLANs of an interface or connection
+type SetVLANTaggingRequest struct {
+	// VLANMode of "" clears the tagging
+	VLANMode       VLANMode `json:"vlan_mode" binding:"omitempty,oneof=access trunk"`
+	UntaggedVLANID *int     `json:"untagged_vlan_id"`
+	TaggedVLANIDs  []int    `json:"tagged_vlan_ids"`
+}
+
+// VLANSegment is a set of devices connected to each other on a VLAN
+type VLANSegment struct {
+	Devices []TopologyNode `json:"devices"`
+}
+
+// VLANReachability lists the devices on a VLAN, grouped by the segments they can reach
+type VLANReachability struct {
+	VLAN     VLAN           `json:"vlan"`
+	Devices  []TopologyNode `json:"devices"`
+	Segments []VLANSegment  `json:"segments"`
+}
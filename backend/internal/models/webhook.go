@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook event names
+const (
+	WebhookEventDeviceStatusChanged = "device.status_changed"
+	WebhookEventTest                = "webhook.test"
+)
+
+// Webhook represents a registered webhook endpoint
+type Webhook struct {
+	ID        int    `json:"id" db:"id"`
+	Name      string `json:"name" db:"name"`
+	URL       string `json:"url" db:"url"`
+	Secret    string `json:"-" db:"secret"`
+	HasSecret bool   `json:"has_secret"`
+	// Statuses limits alerts to transitions into these statuses (empty for all)
+	Statuses  []DeviceStatus `json:"statuses" db:"statuses"`
+	Enabled   bool           `json:"enabled" db:"enabled"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery represents one delivery attempt to a webhook
+type WebhookDelivery struct {
+	ID         int64           `json:"id" db:"id"`
+	WebhookID  int             `json:"webhook_id" db:"webhook_id"`
+	Event      string          `json:"event" db:"event"`
+	DeviceID   *int            `json:"device_id,omitempty" db:"device_id"`
+	Payload    json.RawMessage `json:"payload" db:"payload"`
+	Attempt    int             `json:"attempt" db:"attempt"`
+	Success    bool            `json:"success" db:"success"`
+	StatusCode *int            `json:"status_code,omitempty" db:"status_code"`
+	Error      string          `json:"error,omitempty" db:"error"`
+	DurationMs int64           `json:"duration_ms" db:"duration_ms"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AlertState is the debounce state of a device's status alerts
+type AlertState struct {
+	DeviceID int `json:"device_id" db:"device_id"`
+	// AlertStatus is the last status that was confirmed and alerted on
+	AlertStatus DeviceStatus `json:"alert_status" db:"alert_status"`
+	// CandidateStatus is the status the checks since AlertStatus point to, if any
+	CandidateStatus DeviceStatus `json:"candidate_status,omitempty" db:"candidate_status"`
+	// CandidateCount is the number of consecutive checks that returned CandidateStatus
+	CandidateCount int       `json:"candidate_count" db:"candidate_count"`
+	ChangedAt      time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// StatusChangeEvent is the payload sent when a device's confirmed status changes
+type StatusChangeEvent struct {
+	Event             string       `json:"event"`
+	Timestamp         time.Time    `json:"timestamp"`
+	Device            EventDevice  `json:"device"`
+	PreviousStatus    DeviceStatus `json:"previous_status"`
+	Status            DeviceStatus `json:"status"`
+	Message           string       `json:"message"`
+	Latency           int64        `json:"latency_ms,omitempty"`
+	ConsecutiveChecks int          `json:"consecutive_checks"`
+}
+
+// EventDevice identifies the device in a webhook payload
+type EventDevice struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	RackID    int    `json:"rack_id"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+// CreateWebhookRequest represents a request to register a webhook
+type CreateWebhookRequest struct {
+	Name     string         `json:"name" binding:"required"`
+	URL      string         `json:"url" binding:"required,url"`
+	Secret   string         `json:"secret"`
+	Statuses []DeviceStatus `json:"statuses" binding:"dive,oneof=online offline warning unknown"`
+	Enabled  *bool          `json:"enabled"`
+}
+
+// UpdateWebhookRequest represents a request to update a webhook
+type UpdateWebhookRequest struct {
+	Name     *string        `json:"name"`
+	URL      *string        `json:"url" binding:"omitempty,url"`
+	Secret   *string        `json:"secret"`
+	Statuses []DeviceStatus `json:"statuses" binding:"omitempty,dive,oneof=online offline warning unknown"`
+	Enabled  *bool          `json:"enabled"`
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// AlertConfig controls status transition debouncing and webhook delivery
+type AlertConfig struct {
+	// FailureThreshold is the number of consecutive offline/warning checks before alerting
+	FailureThreshold int
+	// RecoveryThreshold is the number of consecutive online checks before alerting a recovery
+	RecoveryThreshold int
+	Workers           int
+	QueueSize         int
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	Timeout           time.Duration
+}
+
+// LoadAlertConfig reads the alerting configuration from the environment
+func LoadAlertConfig() AlertConfig {
+	config := AlertConfig{
+		FailureThreshold:  getEnvInt("ALERT_FAILURE_THRESHOLD", 3),
+		RecoveryThreshold: getEnvInt("ALERT_RECOVERY_THRESHOLD", 1),
+		Workers:           getEnvInt("WEBHOOK_WORKERS", 2),
+		QueueSize:         getEnvInt("WEBHOOK_QUEUE_SIZE", 100),
+		MaxAttempts:       getEnvInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		InitialBackoff:    getEnvDuration("WEBHOOK_INITIAL_BACKOFF", time.Second),
+		MaxBackoff:        getEnvDuration("WEBHOOK_MAX_BACKOFF", time.Minute),
+		Timeout:           getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+	}
+
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = 1
+	}
+	if config.RecoveryThreshold < 1 {
+		config.RecoveryThreshold = 1
+	}
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.QueueSize < 1 {
+		config.QueueSize = 1
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = time.Second
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	return config
+}
+
+// webhookJob is a payload queued for delivery to one webhook
+type webhookJob struct {
+	webhook  models.Webhook
+	event    string
+	deviceID *int
+	payload  []byte
+}
+
+// AlertNotifier detects debounced device status transitions and delivers them to webhooks
+type AlertNotifier struct {
+	config   AlertConfig
+	webhooks *WebhookService
+	alerts   store.AlertStateStore
+	client   *http.Client
+	jobs     chan webhookJob
+
+	mu      sync.Mutex
+	running bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewAlertNotifier creates a new alert notifier
+func NewAlertNotifier(config AlertConfig, webhooks *WebhookService, alerts store.AlertStateStore) *AlertNotifier {
+	return &AlertNotifier{
+		config:   config,
+		webhooks: webhooks,
+		alerts:   alerts,
+		client:   &http.Client{Timeout: config.Timeout},
+		jobs:     make(chan webhookJob, config.QueueSize),
+	}
+}
+
+// Start launches the delivery workers; it returns immediately
+func (n *AlertNotifier) Start(ctx context.Context) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.running {
+		return
+	}
+	ctx, n.cancel = context.WithCancel(ctx)
+	n.running = true
+
+	for i := 0; i < n.config.Workers; i++ {
+		n.wg.Add(1)
+		go n.worker(ctx)
+	}
+}
+
+// Stop cancels pending retries and waits for in-flight deliveries to finish or ctx to expire
+func (n *AlertNotifier) Stop(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.running {
+		n.mu.Unlock()
+		return nil
+	}
+	n.running = false
+	n.cancel()
+	n.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		if pending := len(n.jobs); pending > 0 {
+			log.Printf("Alert notifier stopped with %d undelivered webhooks", pending)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ObserveHealthCheck feeds a check result into the debounce state and queues webhooks on a confirmed transition
+func (n *AlertNotifier) ObserveHealthCheck(device *models.Device, result *HealthCheckResult) error {
+	// Unknown means nothing is configured to check, which isn't a transition worth alerting on
+	if result.Status == models.DeviceStatusUnknown {
+		return nil
+	}
+
+	previous, count, transitioned, err := n.advanceAlertState(device.ID, result.Status)
+	if err != nil || !transitioned {
+		return err
+	}
+
+	event := models.StatusChangeEvent{
+		Event:     models.WebhookEventDeviceStatusChanged,
+		Timestamp: result.Timestamp.UTC(),
+		Device: models.EventDevice{
+			ID:        device.ID,
+			Name:      device.Name,
+			RackID:    device.RackID,
+			IPAddress: device.IPAddress,
+		},
+		PreviousStatus:    previous,
+		Status:            result.Status,
+		Message:           result.Message,
+		Latency:           result.Latency,
+		ConsecutiveChecks: count,
+	}
+	log.Printf("Device %d (%s) changed status: %s -> %s", device.ID, device.Name, previous, result.Status)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode status change event: %w", err)
+	}
+
+	webhooks, err := n.webhooks.getWebhooksForStatus(result.Status)
+	if err != nil {
+		return err
+	}
+
+	deviceID := device.ID
+	for _, webhook := range webhooks {
+		select {
+		case n.jobs <- webhookJob{webhook: webhook, event: event.Event, deviceID: &deviceID, payload: payload}:
+		default:
+			log.Printf("Webhook queue full, dropping %s for webhook %d", event.Event, webhook.ID)
+		}
+	}
+
+	return nil
+}
+
+// failing reports whether a status counts toward FailureThreshold
+func failing(status models.DeviceStatus) bool {
+	return status == models.DeviceStatusWarning || status == models.DeviceStatusOffline
+}
+
+// advanceAlertState records a check result and reports whether it confirms a transition. Warning
+// and offline are one failing class: a device flapping between them keeps counting toward
+// FailureThreshold, and the transition is to the latest of them.
+func (n *AlertNotifier) advanceAlertState(deviceID int, status models.DeviceStatus) (models.DeviceStatus, int, bool, error) {
+	var previous models.DeviceStatus
+	var confirmed int
+	transitioned := false
+
+	err := n.alerts.AdvanceAlertState(deviceID, func(state *models.AlertState) {
+		previous = state.AlertStatus
+		switch {
+		case status == state.AlertStatus:
+			state.CandidateStatus = ""
+			state.CandidateCount = 0
+		case state.CandidateStatus == status,
+			state.CandidateStatus != "" && failing(state.CandidateStatus) && failing(status):
+			state.CandidateStatus = status
+			state.CandidateCount++
+		default:
+			state.CandidateStatus = status
+			state.CandidateCount = 1
+		}
+
+		threshold := n.config.FailureThreshold
+		if status == models.DeviceStatusOnline {
+			threshold = n.config.RecoveryThreshold
+		}
+		confirmed = state.CandidateCount
+		if state.CandidateStatus != "" && state.CandidateCount >= threshold {
+			state.AlertStatus = status
+			state.CandidateStatus = ""
+			state.CandidateCount = 0
+			transitioned = true
+		}
+	})
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	return previous, confirmed, transitioned, nil
+}
+
+// SendTest delivers a single test event to a webhook and returns the logged attempt
+func (n *AlertNotifier) SendTest(webhookID int) (*models.WebhookDelivery, error) {
+	webhook, err := n.webhooks.GetWebhookByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"event":     models.WebhookEventTest,
+		"timestamp": time.Now().UTC(),
+		"webhook":   webhook.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode test event: %w", err)
+	}
+
+	delivery := n.attempt(context.Background(), webhookJob{webhook: *webhook, event: models.WebhookEventTest, payload: payload}, 1)
+	return &delivery, nil
+}
+
+// worker delivers queued webhooks until ctx is cancelled
+func (n *AlertNotifier) worker(ctx context.Context) {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-n.jobs:
+			n.deliver(ctx, job)
+		}
+	}
+}
+
+// deliver posts a job with exponential backoff until it succeeds or runs out of attempts
+func (n *AlertNotifier) deliver(ctx context.Context, job webhookJob) {
+	backoff := n.config.InitialBackoff
+
+	for attempt := 1; attempt <= n.config.MaxAttempts; attempt++ {
+		delivery := n.attempt(ctx, job, attempt)
+		if delivery.Success {
+			return
+		}
+		if attempt == n.config.MaxAttempts {
+			log.Printf("Webhook %d: giving up on %s after %d attempts: %s", job.webhook.ID, job.event, attempt, delivery.Error)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > n.config.MaxBackoff {
+			backoff = n.config.MaxBackoff
+		}
+	}
+}
+
+// attempt makes one delivery attempt and records it in the delivery log
+func (n *AlertNotifier) attempt(ctx context.Context, job webhookJob, attempt int) models.WebhookDelivery {
+	delivery := models.WebhookDelivery{
+		WebhookID: job.webhook.ID,
+		Event:     job.event,
+		DeviceID:  job.deviceID,
+		Payload:   job.payload,
+		Attempt:   attempt,
+	}
+
+	start := time.Now()
+	statusCode, err := n.post(ctx, job)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	delivery.CreatedAt = time.Now()
+
+	if statusCode != 0 {
+		delivery.StatusCode = &statusCode
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		delivery.Success = true
+	}
+
+	if err := n.webhooks.recordDelivery(delivery); err != nil {
+		log.Printf("Webhook %d: %v", job.webhook.ID, err)
+	}
+
+	return delivery
+}
+
+// post sends the payload, signing it with the webhook secret when one is set
+func (n *AlertNotifier) post(ctx context.Context, job webhookJob) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.webhook.URL, bytes.NewReader(job.payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rackview-webhook")
+	req.Header.Set("X-Rackview-Event", job.event)
+
+	if job.webhook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(job.webhook.Secret))
+		mac.Write(job.payload)
+		req.Header.Set("X-Rackview-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
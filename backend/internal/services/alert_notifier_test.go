@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// check is one health check result fed to the notifier and the outcome it should have
+type check struct {
+	status       models.DeviceStatus
+	transitioned bool
+	previous     models.DeviceStatus
+	count        int
+}
+
+func runChecks(t *testing.T, checks []check) {
+	t.Helper()
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "web-1", 10, 1))
+	config := LoadAlertConfig()
+	config.FailureThreshold = 3
+	config.RecoveryThreshold = 2
+	notifier := NewAlertNotifier(config, NewWebhookService(s.Webhooks), s.AlertStates)
+
+	for i, c := range checks {
+		previous, count, transitioned, err := notifier.advanceAlertState(device.ID, c.status)
+		if err != nil {
+			t.Fatalf("check %d (%s): %v", i+1, c.status, err)
+		}
+		if transitioned != c.transitioned || previous != c.previous || count != c.count {
+			t.Errorf("check %d (%s) = (%s, %d, %v), want (%s, %d, %v)",
+				i+1, c.status, previous, count, transitioned, c.previous, c.count, c.transitioned)
+		}
+	}
+}
+
+func TestAlertDebounceFlapping(t *testing.T) {
+	online, warning, offline := models.DeviceStatusOnline, models.DeviceStatusWarning, models.DeviceStatusOffline
+
+	// A device flapping between warning and offline is failing the whole time, and alerts on the
+	// latest status once the failures reach the threshold
+	runChecks(t, []check{
+		{warning, false, online, 1},
+		{offline, false, online, 2},
+		{warning, true, online, 3},
+		// Already alerted as failing: flapping on does not alert again until it settles offline
+		{offline, false, warning, 1},
+		{warning, false, warning, 0},
+		{offline, false, warning, 1},
+		{offline, false, warning, 2},
+		{offline, true, warning, 3},
+		// Recovery has its own threshold, and one failure in between starts it over
+		{online, false, offline, 1},
+		{warning, false, offline, 1},
+		{online, false, offline, 1},
+		{online, true, offline, 2},
+	})
+}
+
+func TestAlertDebounceResetsOnCurrentStatus(t *testing.T) {
+	online, warning, offline := models.DeviceStatusOnline, models.DeviceStatusWarning, models.DeviceStatusOffline
+
+	runChecks(t, []check{
+		{offline, false, online, 1},
+		{warning, false, online, 2},
+		{online, false, online, 0},
+		{warning, false, online, 1},
+		{offline, false, online, 2},
+		{offline, true, online, 3},
+	})
+}
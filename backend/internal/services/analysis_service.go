@@ -0,0 +1,274 @@
+package services
+
+import (
+	"rackview/internal/models"
+)
+
+// graphEdge is one end of a connection in the adjacency list
+type graphEdge struct {
+	to int
+	id int
+}
+
+// networkGraph is an undirected multigraph of devices; parallel connections are kept so
+// that a doubled link is not reported as a bridge
+type networkGraph struct {
+	nodes []int
+	adj   map[int][]graphEdge
+}
+
+// newNetworkGraph builds the graph of the topology's devices, leaving out excluded devices
+func newNetworkGraph(topology *models.Topology, excluded map[int]bool) *networkGraph {
+	g := &networkGraph{adj: map[int][]graphEdge{}}
+	for _, node := range topology.Nodes {
+		if !excluded[node.ID] {
+			g.nodes = append(g.nodes, node.ID)
+			g.adj[node.ID] = nil
+		}
+	}
+	for _, edge := range topology.Edges {
+		if excluded[edge.Source] || excluded[edge.Target] || edge.Source == edge.Target {
+			continue
+		}
+		g.adj[edge.Source] = append(g.adj[edge.Source], graphEdge{to: edge.Target, id: edge.ID})
+		g.adj[edge.Target] = append(g.adj[edge.Target], graphEdge{to: edge.Source, id: edge.ID})
+	}
+	return g
+}
+
+// cutVertices finds articulation points and bridges with Tarjan's low-link algorithm
+func (g *networkGraph) cutVertices() (map[int]bool, []int) {
+	disc := map[int]int{}
+	low := map[int]int{}
+	articulation := map[int]bool{}
+	var bridges []int
+	timer := 0
+
+	var visit func(u, parentEdge int)
+	visit = func(u, parentEdge int) {
+		timer++
+		disc[u], low[u] = timer, timer
+		children := 0
+
+		for _, e := range g.adj[u] {
+			// Skip only the edge we arrived by, so a parallel edge counts as a back edge
+			if e.id == parentEdge {
+				continue
+			}
+			if _, seen := disc[e.to]; seen {
+				low[u] = min(low[u], disc[e.to])
+				continue
+			}
+			children++
+			visit(e.to, e.id)
+			low[u] = min(low[u], low[e.to])
+			if low[e.to] > disc[u] {
+				bridges = append(bridges, e.id)
+			}
+			if parentEdge != 0 && low[e.to] >= disc[u] {
+				articulation[u] = true
+			}
+		}
+
+		if parentEdge == 0 && children > 1 {
+			articulation[u] = true
+		}
+	}
+
+	for _, u := range g.nodes {
+		if _, seen := disc[u]; !seen {
+			visit(u, 0)
+		}
+	}
+	return articulation, bridges
+}
+
+// components returns the connected components in node order, ignoring a removed device
+// and a removed connection (0 for none)
+func (g *networkGraph) components(removedNode, removedEdge int) [][]int {
+	seen := map[int]bool{removedNode: true}
+	var components [][]int
+	for _, start := range g.nodes {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		component := []int{start}
+		for i := 0; i < len(component); i++ {
+			for _, e := range g.adj[component[i]] {
+				if e.id == removedEdge || seen[e.to] {
+					continue
+				}
+				seen[e.to] = true
+				component = append(component, e.to)
+			}
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// largest returns the index of the largest component; ties go to the first
+func largest(components [][]int) int {
+	best := -1
+	for i, component := range components {
+		if best < 0 || len(component) > len(components[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// AnalysisService computes single points of failure and redundancy on the network graph
+type AnalysisService struct {
+	network *NetworkService
+}
+
+// NewAnalysisService creates a new analysis service
+func NewAnalysisService(network *NetworkService) *AnalysisService {
+	return &AnalysisService{network: network}
+}
+
+// AnalyzeNetwork analyzes the graph of all devices and connections. With statusAware set,
+// offline devices are left out so the report shows what is isolated right now.
+func (s *AnalysisService) AnalyzeNetwork(statusAware bool) (*models.NetworkAnalysis, error) {
+	topology, err := s.network.GetTopology(models.TopologyFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := topologyNodesByID(topology)
+	analysis := &models.NetworkAnalysis{
+		StatusAware:        statusAware,
+		ExcludedDevices:    []models.TopologyNode{},
+		Components:         []models.NetworkComponent{},
+		ArticulationPoints: []models.ArticulationPoint{},
+		Bridges:            []models.NetworkBridge{},
+		Redundancy:         []models.DeviceRedundancy{},
+		Isolated:           []models.TopologyNode{},
+		Unconnected:        []models.TopologyNode{},
+	}
+
+	excluded := map[int]bool{}
+	if statusAware {
+		for _, node := range topology.Nodes {
+			if node.Status == models.DeviceStatusOffline {
+				excluded[node.ID] = true
+				analysis.ExcludedDevices = append(analysis.ExcludedDevices, node)
+			}
+		}
+	}
+
+	// Cabling is judged on the full graph, so a device whose only switch is offline is isolated, not unconnected
+	cabled := map[int]bool{}
+	for _, edge := range topology.Edges {
+		cabled[edge.Source], cabled[edge.Target] = true, true
+	}
+
+	g := newNetworkGraph(topology, excluded)
+	toNodes := func(ids []int) []models.TopologyNode {
+		result := make([]models.TopologyNode, 0, len(ids))
+		for _, id := range ids {
+			result = append(result, nodes[id])
+		}
+		return result
+	}
+
+	components := g.components(0, 0)
+	main := largest(components)
+	componentOf := map[int]int{}
+	for i, component := range components {
+		for _, id := range component {
+			componentOf[id] = i
+		}
+	}
+	for i, component := range components {
+		analysis.Components = append(analysis.Components, models.NetworkComponent{
+			ID:        i + 1,
+			DeviceIDs: component,
+			Size:      len(component),
+			Main:      i == main,
+		})
+		if i == main {
+			continue
+		}
+		for _, id := range component {
+			if cabled[id] {
+				analysis.Isolated = append(analysis.Isolated, nodes[id])
+			} else {
+				analysis.Unconnected = append(analysis.Unconnected, nodes[id])
+			}
+		}
+	}
+
+	articulation, bridges := g.cutVertices()
+	for _, id := range g.nodes {
+		if !articulation[id] {
+			continue
+		}
+		// Removing the device splits its component; everything off the largest piece is cut off
+		var pieces [][]int
+		for _, piece := range g.components(id, 0) {
+			if componentOf[piece[0]] == componentOf[id] {
+				pieces = append(pieces, piece)
+			}
+		}
+		keep := largest(pieces)
+		var isolates []int
+		for i, piece := range pieces {
+			if i != keep {
+				isolates = append(isolates, piece...)
+			}
+		}
+		analysis.ArticulationPoints = append(analysis.ArticulationPoints, models.ArticulationPoint{
+			Device:   nodes[id],
+			Isolates: toNodes(isolates),
+		})
+	}
+
+	edges := make(map[int]models.TopologyEdge, len(topology.Edges))
+	for _, edge := range topology.Edges {
+		edges[edge.ID] = edge
+	}
+	for _, id := range bridges {
+		edge := edges[id]
+		// The two sides of a bridge are the pieces holding its ends; the smaller one is cut off
+		var sourceSide, targetSide []int
+		for _, piece := range g.components(0, id) {
+			for _, member := range piece {
+				if member == edge.Source {
+					sourceSide = piece
+				}
+				if member == edge.Target {
+					targetSide = piece
+				}
+			}
+		}
+		isolates := targetSide
+		if len(sourceSide) < len(targetSide) {
+			isolates = sourceSide
+		}
+		analysis.Bridges = append(analysis.Bridges, models.NetworkBridge{
+			ConnectionID: id,
+			Source:       nodes[edge.Source],
+			Target:       nodes[edge.Target],
+			Isolates:     toNodes(isolates),
+		})
+	}
+
+	for _, id := range g.nodes {
+		redundancy := models.DeviceRedundancy{Device: nodes[id]}
+		upstream := map[int]bool{}
+		for _, e := range g.adj[id] {
+			if nodes[e.to].Type == models.DeviceTypeNetwork {
+				redundancy.Uplinks++
+				upstream[e.to] = true
+			}
+		}
+		redundancy.UpstreamDevices = len(upstream)
+		redundancy.Redundant = redundancy.UpstreamDevices >= 2
+		analysis.Redundancy = append(analysis.Redundancy, redundancy)
+	}
+
+	return analysis, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// testTopology builds a topology of devices 1..n with one edge per pair, numbered from 1
+func testTopology(n int, pairs ...[2]int) *models.Topology {
+	topology := &models.Topology{}
+	for id := 1; id <= n; id++ {
+		topology.Nodes = append(topology.Nodes, models.TopologyNode{ID: id})
+	}
+	for i, pair := range pairs {
+		topology.Edges = append(topology.Edges, models.TopologyEdge{ID: i + 1, Source: pair[0], Target: pair[1]})
+	}
+	return topology
+}
+
+func sortedKeys(set map[int]bool) []int {
+	keys := []int{}
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func TestCutVertices(t *testing.T) {
+	tests := []struct {
+		name             string
+		topology         *models.Topology
+		wantArticulation []int
+		wantBridges      []int
+	}{
+		{"triangle", testTopology(3, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 1}), []int{}, []int{}},
+		{"path", testTopology(3, [2]int{1, 2}, [2]int{2, 3}), []int{2}, []int{1, 2}},
+		{"star", testTopology(4, [2]int{1, 2}, [2]int{1, 3}, [2]int{1, 4}), []int{1}, []int{1, 2, 3}},
+		// A doubled link survives the loss of either connection
+		{"parallel links", testTopology(2, [2]int{1, 2}, [2]int{1, 2}), []int{}, []int{}},
+		{"self loop", testTopology(2, [2]int{1, 1}, [2]int{1, 2}), []int{}, []int{2}},
+		// Two triangles joined at device 3, and a tail hanging off device 5
+		{"bowtie with a tail", testTopology(6,
+			[2]int{1, 2}, [2]int{2, 3}, [2]int{3, 1},
+			[2]int{3, 4}, [2]int{4, 5}, [2]int{5, 3},
+			[2]int{5, 6},
+		), []int{3, 5}, []int{7}},
+		{"separate components", testTopology(5, [2]int{1, 2}, [2]int{3, 4}, [2]int{4, 5}), []int{4}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articulation, bridges := newNetworkGraph(tt.topology, nil).cutVertices()
+			if got := sortedKeys(articulation); !reflect.DeepEqual(got, tt.wantArticulation) {
+				t.Errorf("articulation points = %v, want %v", got, tt.wantArticulation)
+			}
+			got := append([]int{}, bridges...)
+			sort.Ints(got)
+			if !reflect.DeepEqual(got, tt.wantBridges) {
+				t.Errorf("bridges = %v, want %v", got, tt.wantBridges)
+			}
+		})
+	}
+}
+
+func TestNetworkGraphExcludesDevices(t *testing.T) {
+	// Leaving out the middle of a path leaves two components and nothing to cut
+	g := newNetworkGraph(testTopology(3, [2]int{1, 2}, [2]int{2, 3}), map[int]bool{2: true})
+	if got, want := g.components(0, 0), [][]int{{1}, {3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("components = %v, want %v", got, want)
+	}
+	articulation, bridges := g.cutVertices()
+	if len(articulation) != 0 || len(bridges) != 0 {
+		t.Errorf("cutVertices = %v, %v; want none", articulation, bridges)
+	}
+}
+
+func nodeIDs(nodes []models.TopologyNode) []int {
+	ids := []int{}
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
+
+func TestAnalyzeNetwork(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	newSwitch := func(name string, positionU int) *models.Device {
+		device := newDevice(rack.ID, name, positionU, 1)
+		device.Type = models.DeviceTypeNetwork
+		return mustCreateDevice(t, s, device)
+	}
+	core1 := newSwitch("core-1", 42)
+	core2 := newSwitch("core-2", 41)
+	access := newSwitch("access-1", 40)
+	dual := mustCreateDevice(t, s, newDevice(rack.ID, "dual", 30, 1))
+	single := mustCreateDevice(t, s, newDevice(rack.ID, "single", 29, 1))
+	spare := mustCreateDevice(t, s, newDevice(rack.ID, "spare", 28, 1))
+
+	mustConnect(t, s, core1.ID, nil, core2.ID, nil)
+	mustConnect(t, s, dual.ID, nil, core1.ID, nil)
+	mustConnect(t, s, dual.ID, nil, core2.ID, nil)
+	uplink := mustConnect(t, s, access.ID, nil, core1.ID, nil)
+	mustConnect(t, s, single.ID, nil, access.ID, nil)
+
+	service := NewAnalysisService(newNetworkService(s))
+	analysis, err := service.AnalyzeNetwork(false)
+	if err != nil {
+		t.Fatalf("AnalyzeNetwork: %v", err)
+	}
+
+	if len(analysis.Components) != 2 || !analysis.Components[0].Main || analysis.Components[0].Size != 5 {
+		t.Errorf("components = %+v, want the 5 cabled devices as the main one", analysis.Components)
+	}
+	if got, want := nodeIDs(analysis.Unconnected), []int{spare.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unconnected = %v, want %v", got, want)
+	}
+	if len(analysis.Isolated) != 0 {
+		t.Errorf("isolated = %v, want none", nodeIDs(analysis.Isolated))
+	}
+
+	// core-1 cuts off access-1 and single; access-1 cuts off single
+	cuts := map[int][]int{}
+	for _, point := range analysis.ArticulationPoints {
+		cuts[point.Device.ID] = nodeIDs(point.Isolates)
+	}
+	if want := map[int][]int{core1.ID: {access.ID, single.ID}, access.ID: {single.ID}}; !reflect.DeepEqual(cuts, want) {
+		t.Errorf("articulation points = %v, want %v", cuts, want)
+	}
+
+	bridges := map[int][]int{}
+	for _, bridge := range analysis.Bridges {
+		bridges[bridge.ConnectionID] = nodeIDs(bridge.Isolates)
+	}
+	if got := bridges[uplink.ID]; !reflect.DeepEqual(got, []int{access.ID, single.ID}) {
+		t.Errorf("bridge %d isolates %v, want the access switch and its server", uplink.ID, got)
+	}
+	if len(bridges) != 2 {
+		t.Errorf("bridges = %v, want the access uplink and the single server's link", bridges)
+	}
+
+	redundant := map[int]bool{}
+	for _, r := range analysis.Redundancy {
+		redundant[r.Device.ID] = r.Redundant
+	}
+	if !redundant[dual.ID] || redundant[single.ID] || redundant[access.ID] {
+		t.Errorf("redundancy = %v, want only devices with two upstream switches redundant", redundant)
+	}
+
+	// With core-1 offline, the access switch and its server are cut off right now
+	if err := s.Devices.UpdateDeviceStatus(core1.ID, models.DeviceStatusOffline); err != nil {
+		t.Fatalf("UpdateDeviceStatus: %v", err)
+	}
+	analysis, err = service.AnalyzeNetwork(true)
+	if err != nil {
+		t.Fatalf("AnalyzeNetwork status aware: %v", err)
+	}
+	if got, want := nodeIDs(analysis.ExcludedDevices), []int{core1.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("excluded = %v, want %v", got, want)
+	}
+	isolated := nodeIDs(analysis.Isolated)
+	sort.Ints(isolated)
+	if want := []int{access.ID, single.ID}; !reflect.DeepEqual(isolated, want) {
+		t.Errorf("isolated with core-1 offline = %v, want %v", isolated, want)
+	}
+	if got, want := nodeIDs(analysis.Unconnected), []int{spare.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unconnected with core-1 offline = %v, want %v", got, want)
+	}
+}
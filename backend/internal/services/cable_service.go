@@ -0,0 +1,171 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// printableCableLabel formats a cable for a label printer: the label, then media, length and
+// color, then one line per connected end
+func printableCableLabel(cable *models.Cable) string {
+	lines := []string{cable.Label}
+
+	details := []string{}
+	if cable.Media != "" {
+		details = append(details, string(cable.Media))
+	}
+	if cable.LengthM != nil {
+		details = append(details, strconv.FormatFloat(*cable.LengthM, 'f', -1, 64)+"m")
+	}
+	if cable.Color != "" {
+		details = append(details, cable.Color)
+	}
+	if len(details) > 0 {
+		lines = append(lines, strings.Join(details, " "))
+	}
+
+	if cable.AEnd != "" {
+		lines = append(lines, "A: "+cable.AEnd)
+	}
+	if cable.BEnd != "" {
+		lines = append(lines, "B: "+cable.BEnd)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// CableService handles cable-related business logic
+type CableService struct {
+	cables store.CableStore
+}
+
+// NewCableService creates a new cable service
+func NewCableService(cables store.CableStore) *CableService {
+	return &CableService{cables: cables}
+}
+
+// GetAllCables retrieves all cables matching the filter
+func (s *CableService) GetAllCables(filter models.CableFilter) ([]models.Cable, error) {
+	cables, err := s.cables.ListCables(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if cables == nil {
+		cables = []models.Cable{}
+	}
+	for i := range cables {
+		cables[i].PrintableLabel = printableCableLabel(&cables[i])
+	}
+	return cables, nil
+}
+
+// GetCableByID retrieves a cable by ID
+func (s *CableService) GetCableByID(id int) (*models.Cable, error) {
+	cable, err := s.cables.GetCable(id)
+	if err != nil {
+		return nil, err
+	}
+	cable.PrintableLabel = printableCableLabel(cable)
+	return cable, nil
+}
+
+// CreateCable creates a new cable
+func (s *CableService) CreateCable(req models.CreateCableRequest) (*models.Cable, error) {
+	cable := newCable(req)
+	if err := s.cables.CreateCable(cable); err != nil {
+		return nil, cableLabelError(err, cable.Label)
+	}
+	return s.GetCableByID(cable.ID)
+}
+
+// newCable builds a cable from a creation request; cables start out planned
+func newCable(req models.CreateCableRequest) *models.Cable {
+	if req.Status == "" {
+		req.Status = models.CableStatusPlanned
+	}
+	return &models.Cable{
+		Label:       req.Label,
+		Media:       req.Media,
+		LengthM:     req.LengthM,
+		Color:       req.Color,
+		Status:      req.Status,
+		Description: req.Description,
+	}
+}
+
+// cableLabelError names the label in a store error for a label that is already in use
+func cableLabelError(err error, label string) error {
+	if errors.Is(err, store.ErrCableLabelInUse) {
+		return fmt.Errorf("cable label %s is already in use", label)
+	}
+	return err
+}
+
+// UpdateCable updates an existing cable
+func (s *CableService) UpdateCable(id int, req models.UpdateCableRequest) (*models.Cable, error) {
+	cable, err := s.cables.GetCable(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != nil {
+		if *req.Label == "" {
+			return nil, fmt.Errorf("cable label cannot be empty")
+		}
+		cable.Label = *req.Label
+	}
+	if req.Media != nil {
+		cable.Media = *req.Media
+	}
+	if req.LengthM != nil {
+		// A length of 0 clears it
+		cable.LengthM = nil
+		if *req.LengthM > 0 {
+			cable.LengthM = req.LengthM
+		}
+	}
+	if req.Color != nil {
+		cable.Color = *req.Color
+	}
+	if req.Status != nil {
+		cable.Status = *req.Status
+	}
+	if req.Description != nil {
+		cable.Description = *req.Description
+	}
+
+	if err := s.cables.UpdateCable(cable); err != nil {
+		return nil, cableLabelError(err, cable.Label)
+	}
+	cable.PrintableLabel = printableCableLabel(cable)
+	return cable, nil
+}
+
+// DeleteCable deletes a cable; its connection is kept without a cable
+func (s *CableService) DeleteCable(id int) error {
+	return s.cables.DeleteCable(id)
+}
+
+// checkConnectionCable verifies that a cable exists and makes no other connection.
+// A nil or 0 ID means no cable.
+func (s *CableService) checkConnectionCable(cableID *int, excludeConnectionID *int) (*int, error) {
+	if cableID == nil || *cableID == 0 {
+		return nil, nil
+	}
+
+	cable, err := s.cables.GetCable(*cableID)
+	if err != nil {
+		return nil, err
+	}
+	if cable.ConnectionID != nil && (excludeConnectionID == nil || *cable.ConnectionID != *excludeConnectionID) {
+		return nil, fmt.Errorf("cable %s is already in use (connection %d)", cable.Label, *cable.ConnectionID)
+	}
+
+	return cableID, nil
+}
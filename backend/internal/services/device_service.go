@@ -1,188 +1,210 @@
 package services
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
 
-	"rackview/internal/database"
 	"rackview/internal/models"
+	"rackview/internal/store"
 )
 
-// DeviceService handles device-related business logic
-type DeviceService struct{}
-
-// NewDeviceService creates a new device service
-func NewDeviceService() *DeviceService {
-	return &DeviceService{}
-}
-
-// GetDevicesByRackID retrieves all devices for a specific rack
-func (s *DeviceService) GetDevicesByRackID(rackID int) ([]models.Device, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, rack_id, name, icon, type, position_u, size_u, status, model, ip_address, health_check_url, created_at, updated_at
-		FROM devices
-		WHERE rack_id = $1
-		ORDER BY position_u DESC
-	`, rackID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query devices: %w", err)
-	}
-	defer rows.Close()
-
-	var devices []models.Device
-	for rows.Next() {
-		var device models.Device
-		var ipAddress, healthCheckURL sql.NullString
-		if err := rows.Scan(
-			&device.ID, &device.RackID, &device.Name, &device.Icon, &device.Type,
-			&device.PositionU, &device.SizeU, &device.Status, &device.Model,
-			&ipAddress, &healthCheckURL,
-			&device.CreatedAt, &device.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan device: %w", err)
+// buildElevation splits a rack's devices into the placements seen from the front and rear
+func buildElevation(devices []models.Device) *models.RackElevation {
+	elevation := &models.RackElevation{
+		Front: []models.DevicePlacement{},
+		Rear:  []models.DevicePlacement{},
+		ZeroU: []models.ZeroUPlacement{},
+	}
+	for _, device := range devices {
+		// Child devices are drawn inside their chassis
+		if device.ParentDeviceID != nil {
+			continue
 		}
-		// Convert NullString to string
-		if ipAddress.Valid {
-			device.IPAddress = ipAddress.String
+		if device.ZeroUSide != "" {
+			elevation.ZeroU = append(elevation.ZeroU, models.ZeroUPlacement{
+				DeviceID: device.ID,
+				Name:     device.Name,
+				Side:     device.ZeroUSide,
+				Slot:     device.ZeroUSlot,
+			})
+			continue
 		}
-		if healthCheckURL.Valid {
-			device.HealthCheckURL = healthCheckURL.String
+		for _, face := range device.Faces {
+			placement := models.DevicePlacement{
+				DeviceID: device.ID,
+				Name:     device.Name,
+				TopU:     device.PositionU,
+				BottomU:  device.PositionU - device.SizeU + 1,
+				Depth:    device.Depth,
+				Mounted:  face == device.MountFace,
+			}
+			if face == models.MountFaceFront {
+				elevation.Front = append(elevation.Front, placement)
+			} else {
+				elevation.Rear = append(elevation.Rear, placement)
+			}
 		}
+	}
+	return elevation
+}
 
-		// Load specs
-		specs, err := s.getDeviceSpecs(device.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load specs: %w", err)
-		}
-		device.Specs = specs
+// DeviceService handles device-related business logic
+type DeviceService struct {
+	devices     store.DeviceStore
+	racks       store.RackStore
+	deviceTypes *DeviceTypeService
+	ipam        *IPAMService
+	power       PowerConfig
+}
 
-		devices = append(devices, device)
+// NewDeviceService creates a new device service
+func NewDeviceService(devices store.DeviceStore, racks store.RackStore, deviceTypes store.DeviceTypeStore,
+	interfaces store.InterfaceStore, ipam store.IPAMStore) *DeviceService {
+	return &DeviceService{
+		devices:     devices,
+		racks:       racks,
+		deviceTypes: NewDeviceTypeService(deviceTypes, devices),
+		ipam:        NewIPAMService(ipam, devices, interfaces),
+		power:       LoadPowerConfig(),
 	}
+}
 
-	return devices, nil
+// GetDevicesByRackID retrieves all devices for a specific rack
+func (s *DeviceService) GetDevicesByRackID(rackID int) ([]models.Device, error) {
+	return s.devices.ListDevices(store.DeviceFilter{RackID: &rackID})
 }
 
 // GetAllDevices retrieves all devices, optionally filtered by rack
 func (s *DeviceService) GetAllDevices(rackID *int) ([]models.Device, error) {
-	var rows *sql.Rows
-	var err error
+	return s.devices.ListDevices(store.DeviceFilter{RackID: rackID})
+}
 
-	if rackID != nil {
-		return s.GetDevicesByRackID(*rackID)
-	}
+// GetDeviceByID retrieves a device by ID
+func (s *DeviceService) GetDeviceByID(id int) (*models.Device, error) {
+	return loadDevice(s.devices, id)
+}
 
-	rows, err = database.DB.Query(`
-		SELECT id, rack_id, name, icon, type, position_u, size_u, status, model, ip_address, health_check_url, created_at, updated_at
-		FROM devices
-		ORDER BY rack_id, position_u DESC
-	`)
+// loadDevice retrieves a device with its chassis contents
+func loadDevice(devices store.DeviceStore, id int) (*models.Device, error) {
+	device, err := devices.GetDevice(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query devices: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	var devices []models.Device
-	for rows.Next() {
-		var device models.Device
-		var ipAddress, healthCheckURL sql.NullString
-		if err := rows.Scan(
-			&device.ID, &device.RackID, &device.Name, &device.Icon, &device.Type,
-			&device.PositionU, &device.SizeU, &device.Status, &device.Model,
-			&ipAddress, &healthCheckURL,
-			&device.CreatedAt, &device.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan device: %w", err)
-		}
-		// Convert NullString to string
-		if ipAddress.Valid {
-			device.IPAddress = ipAddress.String
-		}
-		if healthCheckURL.Valid {
-			device.HealthCheckURL = healthCheckURL.String
-		}
-
-		// Load specs
-		specs, err := s.getDeviceSpecs(device.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load specs: %w", err)
-		}
-		device.Specs = specs
 
-		devices = append(devices, device)
+	// Load chassis contents
+	children, err := childDevices(devices, device.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load child devices: %w", err)
 	}
+	device.Children = children
 
-	return devices, nil
+	return device, nil
 }
 
-// GetDeviceByID retrieves a device by ID
-func (s *DeviceService) GetDeviceByID(id int) (*models.Device, error) {
-	var device models.Device
-	var ipAddress, healthCheckURL sql.NullString
-	err := database.DB.QueryRow(`
-		SELECT id, rack_id, name, icon, type, position_u, size_u, status, model, ip_address, health_check_url, created_at, updated_at
-		FROM devices
-		WHERE id = $1
-	`, id).Scan(
-		&device.ID, &device.RackID, &device.Name, &device.Icon, &device.Type,
-		&device.PositionU, &device.SizeU, &device.Status, &device.Model,
-		&ipAddress, &healthCheckURL,
-		&device.CreatedAt, &device.UpdatedAt,
-	)
-	
-	// Convert NullString to string
-	if ipAddress.Valid {
-		device.IPAddress = ipAddress.String
-	}
-	if healthCheckURL.Valid {
-		device.HealthCheckURL = healthCheckURL.String
-	}
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("device not found")
-	}
+// childDevices recursively loads the devices installed in a chassis, ordered by bay
+func childDevices(devices store.DeviceStore, parentID int) ([]models.Device, error) {
+	children, err := devices.ListDevices(store.DeviceFilter{ParentDeviceID: &parentID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query device: %w", err)
+		return nil, err
 	}
 
-	// Load specs
-	specs, err := s.getDeviceSpecs(device.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load specs: %w", err)
+	for i := range children {
+		if children[i].Children, err = childDevices(devices, children[i].ID); err != nil {
+			return nil, err
+		}
 	}
-	device.Specs = specs
 
-	return &device, nil
+	return children, nil
+}
+
+// rackSize returns the height of a rack in U
+func (s *DeviceService) rackSize(rackID int) (int, error) {
+	rack, err := s.racks.GetRack(rackID)
+	if err != nil {
+		return 0, err
+	}
+	return rack.SizeU, nil
 }
 
 // CreateDevice creates a new device
 func (s *DeviceService) CreateDevice(req models.CreateDeviceRequest) (*models.Device, error) {
-	// Validate device fits in rack
-	var rackSize int
-	err := database.DB.QueryRow("SELECT size_u FROM racks WHERE id = $1", req.RackID).Scan(&rackSize)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("rack not found")
+	// Fill defaults from the catalog entry; fields set in the request win
+	var deviceType *models.DeviceTypeTemplate
+	if req.DeviceTypeID != nil {
+		var err error
+		if deviceType, err = s.deviceTypes.applyDeviceType(&req); err != nil {
+			return nil, err
+		}
+	}
+	if req.Type == "" {
+		return nil, fmt.Errorf("type is required unless device_type_id is set")
+	}
+	if req.IPAddress != "" {
+		ipAddress, err := s.ipam.checkDeviceIPAddress(req.IPAddress, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.IPAddress = ipAddress
 	}
+
+	// Validate device fits in rack
+	rackSize, err := s.rackSize(req.RackID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query rack: %w", err)
+		return nil, err
 	}
 
-	// position_u is the TOP slot, device extends downward
-	// So bottom U = position_u - size_u + 1
-	bottomU := req.PositionU - req.SizeU + 1
-	if bottomU < 1 {
-		return nil, fmt.Errorf("device does not fit in rack (position %d - size %d + 1 = %d is below U1)", req.PositionU, req.SizeU, bottomU)
+	if req.MountFace == "" {
+		req.MountFace = models.MountFaceFront
 	}
-	if req.PositionU > rackSize {
-		return nil, fmt.Errorf("device does not fit in rack (position %d exceeds rack size %d)", req.PositionU, rackSize)
+	if req.Depth == "" {
+		req.Depth = models.DeviceDepthFull
 	}
 
-	// Check for overlaps
-	overlaps, err := s.checkDeviceOverlap(req.RackID, req.PositionU, req.SizeU, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check overlaps: %w", err)
+	switch {
+	case req.ParentDeviceID != nil:
+		// Child devices live in a chassis bay and take no rack space of their own
+		if req.PositionU != 0 || req.SizeU != 0 || req.ZeroUSide != "" || req.ZeroUSlot != "" {
+			return nil, fmt.Errorf("devices in a chassis bay cannot have a rack position")
+		}
+		parentRackID, err := s.checkBay(*req.ParentDeviceID, req.Bay, nil)
+		if err != nil {
+			return nil, err
+		}
+		if parentRackID != req.RackID {
+			return nil, fmt.Errorf("device must be in the same rack as its parent chassis (rack %d)", parentRackID)
+		}
+	case req.ZeroUSide != "":
+		// Zero-U devices sit on a rack side and take no U slots
+		if req.PositionU != 0 || req.SizeU != 0 {
+			return nil, fmt.Errorf("zero-U devices cannot have a position_u or size_u")
+		}
+	default:
+		if req.ZeroUSlot != "" {
+			return nil, fmt.Errorf("zero_u_slot requires zero_u_side")
+		}
+		if req.PositionU < 1 || req.SizeU < 1 {
+			return nil, fmt.Errorf("position_u and size_u must be at least 1 unless zero_u_side or parent_device_id is set")
+		}
+
+		// position_u is the TOP slot, device extends downward
+		// So bottom U = position_u - size_u + 1
+		bottomU := req.PositionU - req.SizeU + 1
+		if bottomU < 1 {
+			return nil, fmt.Errorf("device does not fit in rack (position %d - size %d + 1 = %d is below U1)", req.PositionU, req.SizeU, bottomU)
+		}
+		if req.PositionU > rackSize {
+			return nil, fmt.Errorf("device does not fit in rack (position %d exceeds rack size %d)", req.PositionU, rackSize)
+		}
 	}
-	if overlaps {
-		return nil, fmt.Errorf("device overlaps with existing device")
+
+	// Enforce the rack power budget in strict mode
+	var powerCheck store.PlacementCheck
+	if s.power.Strict {
+		draw, _ := devicePowerDraw(req.NameplateWatts, req.MeasuredWatts)
+		if powerCheck, err = rackPowerBudgetCheck(s.racks, req.RackID, draw); err != nil {
+			return nil, err
+		}
 	}
 
 	// Set defaults
@@ -192,32 +214,58 @@ func (s *DeviceService) CreateDevice(req models.CreateDeviceRequest) (*models.De
 	if req.Status == "" {
 		req.Status = models.DeviceStatusOnline
 	}
-
-	var device models.Device
-	err = database.DB.QueryRow(`
-		INSERT INTO devices (rack_id, name, icon, type, position_u, size_u, status, model, ip_address, health_check_url)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, rack_id, name, icon, type, position_u, size_u, status, model, ip_address, health_check_url, created_at, updated_at
-	`, req.RackID, req.Name, req.Icon, req.Type, req.PositionU, req.SizeU, req.Status, req.Model, req.IPAddress, req.HealthCheckURL).Scan(
-		&device.ID, &device.RackID, &device.Name, &device.Icon, &device.Type,
-		&device.PositionU, &device.SizeU, &device.Status, &device.Model,
-		&device.IPAddress, &device.HealthCheckURL,
-		&device.CreatedAt, &device.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to create device: %w", err)
-	}
-
-	// Insert specs
-	if req.Specs != nil {
-		if err := s.setDeviceSpecs(device.ID, req.Specs); err != nil {
-			return nil, fmt.Errorf("failed to set specs: %w", err)
+	if req.HealthCheckMode == "" {
+		req.HealthCheckMode = models.HealthCheckModeAll
+	}
+	if req.PSURedundancy == "" {
+		req.PSURedundancy = models.PSURedundancyNone
+	}
+
+	device := &models.Device{
+		RackID:              req.RackID,
+		Name:                req.Name,
+		Icon:                req.Icon,
+		Type:                req.Type,
+		PositionU:           req.PositionU,
+		SizeU:               req.SizeU,
+		MountFace:           req.MountFace,
+		Depth:               req.Depth,
+		ZeroUSide:           req.ZeroUSide,
+		ZeroUSlot:           req.ZeroUSlot,
+		ParentDeviceID:      req.ParentDeviceID,
+		Bay:                 req.Bay,
+		BayCount:            req.BayCount,
+		Status:              req.Status,
+		Model:               req.Model,
+		DeviceTypeID:        req.DeviceTypeID,
+		IPAddress:           req.IPAddress,
+		HealthCheckURL:      req.HealthCheckURL,
+		HealthCheckInterval: req.HealthCheckInterval,
+		HealthCheckMode:     req.HealthCheckMode,
+		NameplateWatts:      req.NameplateWatts,
+		MeasuredWatts:       req.MeasuredWatts,
+		PSUCount:            req.PSUCount,
+		PSURedundancy:       req.PSURedundancy,
+		Specs:               req.Specs,
+	}
+	// Instantiate the catalog's port templates as interfaces
+	var interfaces []*models.DeviceInterface
+	if deviceType != nil {
+		interfaces = templateInterfaces(deviceType.Ports)
+	}
+
+	// Overlaps, zero-U slots, bays and the power budget are checked in the same transaction as the
+	// insert, and the interfaces are created in it too
+	if err := s.devices.CreateDevice(device, interfaces, combineChecks(placementCheck(device), powerCheck)); err != nil {
+		// The schema rejects overlaps the check could not see, and ip addresses assigned since
+		// checkDeviceIPAddress
+		if errors.Is(err, store.ErrDeviceOverlap) {
+			return nil, s.explainOverlap(device)
 		}
-		device.Specs = req.Specs
+		return nil, ipamError(err, device.IPAddress)
 	}
 
-	return &device, nil
+	return device, nil
 }
 
 // UpdateDevice updates an existing device
@@ -228,274 +276,351 @@ func (s *DeviceService) UpdateDevice(id int, req models.UpdateDeviceRequest) (*m
 		return nil, err
 	}
 
-	// Build update query
-	updates := []string{}
-	args := []interface{}{}
-	argPos := 1
+	// Apply the request to a copy; fields not in the request keep their current value
+	device := *current
 
 	if req.RackID != nil {
-		updates = append(updates, fmt.Sprintf("rack_id = $%d", argPos))
-		args = append(args, *req.RackID)
-		argPos++
+		device.RackID = *req.RackID
 	}
 	if req.Name != nil {
-		updates = append(updates, fmt.Sprintf("name = $%d", argPos))
-		args = append(args, *req.Name)
-		argPos++
+		device.Name = *req.Name
 	}
 	if req.Icon != nil {
-		updates = append(updates, fmt.Sprintf("icon = $%d", argPos))
-		args = append(args, *req.Icon)
-		argPos++
+		device.Icon = *req.Icon
 	}
 	if req.Type != nil {
-		updates = append(updates, fmt.Sprintf("type = $%d", argPos))
-		args = append(args, *req.Type)
-		argPos++
+		device.Type = *req.Type
 	}
-	if req.PositionU != nil {
-		updates = append(updates, fmt.Sprintf("position_u = $%d", argPos))
-		args = append(args, *req.PositionU)
-		argPos++
+	if req.MountFace != nil {
+		device.MountFace = *req.MountFace
 	}
-	if req.SizeU != nil {
-		updates = append(updates, fmt.Sprintf("size_u = $%d", argPos))
-		args = append(args, *req.SizeU)
-		argPos++
+	if req.Depth != nil {
+		device.Depth = *req.Depth
+	}
+	if req.BayCount != nil {
+		highestBay := 0
+		for _, child := range current.Children {
+			if child.Bay > highestBay {
+				highestBay = child.Bay
+			}
+		}
+		if *req.BayCount < highestBay {
+			return nil, fmt.Errorf("bay_count cannot be less than occupied bay %d", highestBay)
+		}
+		device.BayCount = *req.BayCount
 	}
 	if req.Status != nil {
-		updates = append(updates, fmt.Sprintf("status = $%d", argPos))
-		args = append(args, *req.Status)
-		argPos++
+		device.Status = *req.Status
 	}
 	if req.Model != nil {
-		updates = append(updates, fmt.Sprintf("model = $%d", argPos))
-		args = append(args, *req.Model)
-		argPos++
+		device.Model = *req.Model
+	}
+	if req.DeviceTypeID != nil {
+		deviceTypeID := positiveOrNil(req.DeviceTypeID)
+		if deviceTypeID != nil {
+			if _, err := s.deviceTypes.GetDeviceTypeByID(*deviceTypeID); err != nil {
+				return nil, err
+			}
+		}
+		device.DeviceTypeID = deviceTypeID
 	}
 	if req.IPAddress != nil {
-		updates = append(updates, fmt.Sprintf("ip_address = $%d", argPos))
-		args = append(args, *req.IPAddress)
-		argPos++
+		// An empty ip_address clears it
+		ipAddress := *req.IPAddress
+		if ipAddress != "" {
+			if ipAddress, err = s.ipam.checkDeviceIPAddress(ipAddress, &id); err != nil {
+				return nil, err
+			}
+		}
+		device.IPAddress = ipAddress
 	}
 	if req.HealthCheckURL != nil {
-		updates = append(updates, fmt.Sprintf("health_check_url = $%d", argPos))
-		args = append(args, *req.HealthCheckURL)
-		argPos++
+		device.HealthCheckURL = *req.HealthCheckURL
 	}
-
-	// Determine which rack to use for validation (new rack if changing, otherwise current)
-	targetRackID := current.RackID
-	if req.RackID != nil {
-		targetRackID = *req.RackID
+	if req.HealthCheckInterval != nil {
+		device.HealthCheckInterval = *req.HealthCheckInterval
+	}
+	if req.HealthCheckMode != nil {
+		device.HealthCheckMode = *req.HealthCheckMode
+	}
+	if req.NameplateWatts != nil {
+		device.NameplateWatts = *req.NameplateWatts
+	}
+	if req.MeasuredWatts != nil {
+		device.MeasuredWatts = *req.MeasuredWatts
+	}
+	if req.PSUCount != nil {
+		device.PSUCount = *req.PSUCount
+	}
+	if req.PSURedundancy != nil {
+		device.PSURedundancy = *req.PSURedundancy
 	}
 
-	// Validate position/size if changed
-	if req.PositionU != nil || req.SizeU != nil || req.RackID != nil {
-		positionU := current.PositionU
-		sizeU := current.SizeU
+	// Validate placement if changed
+	var check store.PlacementCheck
+	if req.PositionU != nil || req.SizeU != nil || req.RackID != nil || req.MountFace != nil || req.Depth != nil ||
+		req.ZeroUSide != nil || req.ZeroUSlot != nil || req.ParentDeviceID != nil || req.Bay != nil {
 		if req.PositionU != nil {
-			positionU = *req.PositionU
+			device.PositionU = *req.PositionU
 		}
 		if req.SizeU != nil {
-			sizeU = *req.SizeU
+			device.SizeU = *req.SizeU
 		}
-
-		// Check rack size using the target rack (new rack if moving, otherwise current)
-		var rackSize int
-		err := database.DB.QueryRow("SELECT size_u FROM racks WHERE id = $1", targetRackID).Scan(&rackSize)
-		if err != nil {
-			return nil, fmt.Errorf("failed to query rack: %w", err)
+		if req.ZeroUSide != nil {
+			device.ZeroUSide = *req.ZeroUSide
 		}
-
-		// position_u is the TOP slot, device extends downward
-		// So bottom U = position_u - size_u + 1
-		bottomU := positionU - sizeU + 1
-		if bottomU < 1 {
-			return nil, fmt.Errorf("device does not fit in rack (position %d - size %d + 1 = %d is below U1)", positionU, sizeU, bottomU)
+		if req.ZeroUSlot != nil {
+			device.ZeroUSlot = *req.ZeroUSlot
 		}
-		if positionU > rackSize {
-			return nil, fmt.Errorf("device does not fit in rack (position %d exceeds rack size %d)", positionU, rackSize)
+		if req.ParentDeviceID != nil {
+			device.ParentDeviceID = positiveOrNil(req.ParentDeviceID)
+		}
+		if req.Bay != nil {
+			device.Bay = *req.Bay
 		}
 
-		// Check overlaps in the TARGET rack (new rack if moving, otherwise current)
-		overlaps, err := s.checkDeviceOverlap(targetRackID, positionU, sizeU, &id)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check overlaps: %w", err)
+		switch device.ZeroUSide {
+		case "":
+			device.ZeroUSlot = ""
+		case models.ZeroUSideLeft, models.ZeroUSideRight:
+		default:
+			return nil, fmt.Errorf("zero_u_side must be left, right or empty")
 		}
-		if overlaps {
-			return nil, fmt.Errorf("device overlaps with existing device")
+		if device.ZeroUSlot != "" && device.ZeroUSlot != "A" && device.ZeroUSlot != "B" {
+			return nil, fmt.Errorf("zero_u_slot must be A, B or empty")
 		}
-	}
-
-	if len(updates) == 0 && req.Specs == nil {
-		return current, nil
-	}
 
-	if len(updates) > 0 {
-		args = append(args, id)
-		setClause := ""
-		for i, update := range updates {
-			if i > 0 {
-				setClause += ", "
+		switch {
+		case device.ParentDeviceID != nil:
+			// Child devices live in a chassis bay and take no rack space of their own
+			if (req.PositionU != nil && *req.PositionU != 0) || (req.SizeU != nil && *req.SizeU != 0) || device.ZeroUSide != "" {
+				return nil, fmt.Errorf("devices in a chassis bay cannot have a rack position")
 			}
-			setClause += update
-		}
+			device.PositionU, device.SizeU = 0, 0
 
-		query := fmt.Sprintf(`
-			UPDATE devices
-			SET %s
-			WHERE id = $%d
-			RETURNING id, rack_id, name, icon, type, position_u, size_u, status, model, ip_address, health_check_url, created_at, updated_at
-		`, setClause, argPos)
+			parentRackID, err := s.checkBay(*device.ParentDeviceID, device.Bay, &id)
+			if err != nil {
+				return nil, err
+			}
+			if req.RackID != nil && *req.RackID != parentRackID {
+				return nil, fmt.Errorf("device must be in the same rack as its parent chassis (rack %d)", parentRackID)
+			}
+			device.RackID = parentRackID
+		case device.ZeroUSide != "":
+			// Zero-U devices sit on a rack side and take no U slots
+			if (req.PositionU != nil && *req.PositionU != 0) || (req.SizeU != nil && *req.SizeU != 0) {
+				return nil, fmt.Errorf("zero-U devices cannot have a position_u or size_u")
+			}
+			device.PositionU, device.SizeU = 0, 0
+		default:
+			if device.PositionU < 1 || device.SizeU < 1 {
+				return nil, fmt.Errorf("position_u and size_u must be at least 1 unless zero_u_side or parent_device_id is set")
+			}
 
-		var ipAddress, healthCheckURL sql.NullString
-		err = database.DB.QueryRow(query, args...).Scan(
-			&current.ID, &current.RackID, &current.Name, &current.Icon, &current.Type,
-			&current.PositionU, &current.SizeU, &current.Status, &current.Model,
-			&ipAddress, &healthCheckURL,
-			&current.CreatedAt, &current.UpdatedAt,
-		)
+			// Check rack size using the target rack (new rack if moving, otherwise current)
+			rackSize, err := s.rackSize(device.RackID)
+			if err != nil {
+				return nil, err
+			}
 
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("device not found")
+			// position_u is the TOP slot, device extends downward
+			// So bottom U = position_u - size_u + 1
+			bottomU := device.PositionU - device.SizeU + 1
+			if bottomU < 1 {
+				return nil, fmt.Errorf("device does not fit in rack (position %d - size %d + 1 = %d is below U1)", device.PositionU, device.SizeU, bottomU)
+			}
+			if device.PositionU > rackSize {
+				return nil, fmt.Errorf("device does not fit in rack (position %d exceeds rack size %d)", device.PositionU, rackSize)
+			}
 		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to update device: %w", err)
+		if device.ParentDeviceID == nil {
+			device.Bay = 0
 		}
-		
-		// Convert NullString to string
-		if ipAddress.Valid {
-			current.IPAddress = ipAddress.String
-		} else {
-			current.IPAddress = ""
+
+		// Overlaps, zero-U slots and bays are checked in the TARGET rack (new rack if moving,
+		// otherwise current) in the same transaction as the update
+		check = placementCheck(&device)
+	}
+
+	// Enforce the rack power budget in strict mode when the device moves or its draw changes
+	var powerCheck store.PlacementCheck
+	if s.power.Strict && (device.RackID != current.RackID || req.NameplateWatts != nil || req.MeasuredWatts != nil) {
+		// Lowering the draw of a device that stays put is always allowed
+		draw, _ := devicePowerDraw(device.NameplateWatts, device.MeasuredWatts)
+		currentDraw, _ := devicePowerDraw(current.NameplateWatts, current.MeasuredWatts)
+		if device.RackID != current.RackID {
+			// A chassis brings its children along
+			childDraw, err := descendantPowerDraw(s.devices, id)
+			if err != nil {
+				return nil, err
+			}
+			draw += childDraw
 		}
-		if healthCheckURL.Valid {
-			current.HealthCheckURL = healthCheckURL.String
-		} else {
-			current.HealthCheckURL = ""
+		if device.RackID != current.RackID || draw > currentDraw {
+			if powerCheck, err = rackPowerBudgetCheck(s.racks, device.RackID, draw); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	// Update specs if provided
-	if req.Specs != nil {
-		if err := s.setDeviceSpecs(id, req.Specs); err != nil {
-			return nil, fmt.Errorf("failed to update specs: %w", err)
-		}
-		current.Specs = req.Specs
-	} else {
-		// Reload specs
-		specs, err := s.getDeviceSpecs(id)
-		if err != nil {
-			return nil, fmt.Errorf("failed to reload specs: %w", err)
+	if req.Specs == nil && reflect.DeepEqual(device, *current) {
+		return current, nil
+	}
+
+	// Specs are only replaced when given; children follow their chassis to a new rack
+	device.Specs = req.Specs
+	if err := s.devices.UpdateDevice(&device, combineChecks(check, powerCheck)); err != nil {
+		// The schema rejects overlaps the check could not see, and ip addresses assigned since
+		// checkDeviceIPAddress
+		if errors.Is(err, store.ErrDeviceOverlap) {
+			return nil, s.explainOverlap(&device)
 		}
-		current.Specs = specs
+		return nil, ipamError(err, device.IPAddress)
+	}
+
+	children, err := childDevices(s.devices, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload child devices: %w", err)
 	}
+	device.Children = children
 
-	return current, nil
+	return &device, nil
 }
 
 // DeleteDevice deletes a device
 func (s *DeviceService) DeleteDevice(id int) error {
-	result, err := database.DB.Exec("DELETE FROM devices WHERE id = $1", id)
+	return s.devices.DeleteDevice(id)
+}
+
+// checkBay validates installing a device into a chassis bay and returns the chassis rack.
+// deviceID is the device being placed, if it already exists, so it can't be installed inside itself.
+func (s *DeviceService) checkBay(parentID, bay int, deviceID *int) (int, error) {
+	parent, err := s.devices.GetDevice(parentID)
+	if errors.Is(err, store.ErrDeviceNotFound) {
+		return 0, fmt.Errorf("parent device not found")
+	}
 	if err != nil {
-		return fmt.Errorf("failed to delete device: %w", err)
+		return 0, fmt.Errorf("failed to query parent device: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if deviceID != nil {
+		// Walk up from the parent; reaching the device means it would end up inside itself
+		for ancestor := parent; ; {
+			if ancestor.ID == *deviceID {
+				return 0, fmt.Errorf("a device cannot be installed in itself or one of its children")
+			}
+			if ancestor.ParentDeviceID == nil {
+				break
+			}
+			if ancestor, err = s.devices.GetDevice(*ancestor.ParentDeviceID); err != nil {
+				return 0, fmt.Errorf("failed to check device hierarchy: %w", err)
+			}
+		}
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("device not found")
+	if parent.BayCount == 0 {
+		return 0, fmt.Errorf("device %s has no bays", parent.Name)
+	}
+	if bay < 1 || bay > parent.BayCount {
+		return 0, fmt.Errorf("bay %d is out of range (device %s has %d bays)", bay, parent.Name, parent.BayCount)
 	}
 
-	return nil
+	return parent.RackID, nil
 }
 
-// getDeviceSpecs retrieves all specs for a device
-func (s *DeviceService) getDeviceSpecs(deviceID int) (map[string]string, error) {
-	rows, err := database.DB.Query(`
-		SELECT spec_key, spec_value
-		FROM device_specs
-		WHERE device_id = $1
-	`, deviceID)
-	if err != nil {
-		return nil, err
+// placementCheck returns the check that a device's placement is free in its rack: U-mounted
+// devices must not overlap, and a zero-U slot or chassis bay holds a single device
+func placementCheck(device *models.Device) store.PlacementCheck {
+	return func(rackDevices []models.Device) error {
+		for i := range rackDevices {
+			other := &rackDevices[i]
+			switch {
+			case device.ParentDeviceID != nil:
+				if other.ParentDeviceID != nil && *other.ParentDeviceID == *device.ParentDeviceID && other.Bay == device.Bay {
+					return fmt.Errorf("bay %d of %s is already occupied by %s", device.Bay, deviceName(rackDevices, *device.ParentDeviceID), other.Name)
+				}
+			case device.ZeroUSide != "":
+				// Devices without a slot never conflict
+				if device.ZeroUSlot != "" && other.ZeroUSide == device.ZeroUSide && other.ZeroUSlot == device.ZeroUSlot {
+					return fmt.Errorf("zero-U slot %s %s is already occupied by %s", device.ZeroUSide, device.ZeroUSlot, other.Name)
+				}
+			default:
+				if devicesOverlap(device, other) {
+					return overlapError(other)
+				}
+			}
+		}
+		return nil
 	}
-	defer rows.Close()
+}
 
-	specs := make(map[string]string)
-	for rows.Next() {
-		var key, value string
-		if err := rows.Scan(&key, &value); err != nil {
-			return nil, err
+// combineChecks returns a placement check running each non-nil check in order, or nil if there are none
+func combineChecks(checks ...store.PlacementCheck) store.PlacementCheck {
+	var active []store.PlacementCheck
+	for _, check := range checks {
+		if check != nil {
+			active = append(active, check)
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	return func(rackDevices []models.Device) error {
+		for _, check := range active {
+			if err := check(rackDevices); err != nil {
+				return err
+			}
 		}
-		specs[key] = value
+		return nil
 	}
+}
 
-	return specs, nil
+// overlapError names the device in the way
+func overlapError(other *models.Device) error {
+	return fmt.Errorf("%w %s at U%d-U%d", store.ErrDeviceOverlap, other.Name, other.PositionU-other.SizeU+1, other.PositionU)
 }
 
-// setDeviceSpecs sets specs for a device (replaces all existing)
-func (s *DeviceService) setDeviceSpecs(deviceID int, specs map[string]string) error {
-	// Delete existing specs
-	_, err := database.DB.Exec("DELETE FROM device_specs WHERE device_id = $1", deviceID)
+// explainOverlap names the device in the way of a write the store rejected as overlapping
+func (s *DeviceService) explainOverlap(device *models.Device) error {
+	devices, err := s.devices.ListDevices(store.DeviceFilter{RackID: &device.RackID})
 	if err != nil {
-		return err
+		return store.ErrDeviceOverlap
 	}
-
-	// Insert new specs
-	for key, value := range specs {
-		_, err := database.DB.Exec(`
-			INSERT INTO device_specs (device_id, spec_key, spec_value)
-			VALUES ($1, $2, $3)
-		`, deviceID, key, value)
-		if err != nil {
-			return err
+	for i := range devices {
+		if devices[i].ID != device.ID && devicesOverlap(device, &devices[i]) {
+			return overlapError(&devices[i])
 		}
 	}
+	return store.ErrDeviceOverlap
+}
 
-	return nil
+// deviceName returns the name of a device in the list, or its ID if it is not there
+func deviceName(devices []models.Device, id int) string {
+	for _, device := range devices {
+		if device.ID == id {
+			return device.Name
+		}
+	}
+	return fmt.Sprintf("device %d", id)
 }
 
-// checkDeviceOverlap checks if a device position overlaps with existing devices
+// devicesOverlap checks if two devices occupy a common U slot
 // position_u is the TOP slot, device extends downward
 // So a device at position_u with size_u occupies: [position_u, position_u - size_u + 1]
-func (s *DeviceService) checkDeviceOverlap(rackID, positionU, sizeU int, excludeDeviceID *int) (bool, error) {
-	// Calculate the bottom U slot for the new device
-	newTopU := positionU
-	newBottomU := positionU - sizeU + 1
-	
-	// Check if any existing device overlaps with the range [newTopU, newBottomU]
-	// An existing device at position_u with size_u occupies: [position_u, position_u - size_u + 1]
+// Half-depth devices on opposite faces may share slots; full-depth devices occupy both faces
+func devicesOverlap(a, b *models.Device) bool {
+	// Zero-U and chassis bay devices take no U slots
+	if a.SizeU <= 0 || b.SizeU <= 0 {
+		return false
+	}
+	// Two half-depth devices on opposite faces don't collide
+	if a.Depth == models.DeviceDepthHalf && b.Depth == models.DeviceDepthHalf && a.MountFace != b.MountFace {
+		return false
+	}
+
 	// Two ranges [a_top, a_bottom] and [b_top, b_bottom] overlap if:
 	//   a_top >= b_bottom AND a_bottom <= b_top
-	query := `
-		SELECT COUNT(*) > 0
-		FROM devices
-		WHERE rack_id = $1
-		AND id != COALESCE($3, -1)
-		AND (
-			-- Existing device's top is within new device's range
-			(position_u <= $2 AND position_u >= $4) OR
-			-- Existing device's bottom is within new device's range
-			(position_u - size_u + 1 <= $2 AND position_u - size_u + 1 >= $4) OR
-			-- Existing device completely contains new device
-			(position_u >= $2 AND position_u - size_u + 1 <= $4) OR
-			-- New device completely contains existing device
-			(position_u <= $2 AND position_u - size_u + 1 >= $4)
-		)
-	`
-
-	var excludeID interface{} = nil
-	if excludeDeviceID != nil {
-		excludeID = *excludeDeviceID
-	}
-
-	var overlaps bool
-	// Parameters: $1=rackID, $2=newTopU, $3=excludeID, $4=newBottomU
-	err := database.DB.QueryRow(query, rackID, newTopU, excludeID, newBottomU).Scan(&overlaps)
-	return overlaps, err
+	aBottomU := a.PositionU - a.SizeU + 1
+	bBottomU := b.PositionU - b.SizeU + 1
+	return a.PositionU >= bBottomU && aBottomU <= b.PositionU
 }
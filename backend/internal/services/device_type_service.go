@@ -0,0 +1,230 @@
+package services
+
+import (
+	"sort"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// DeviceTypeService handles the device type catalog
+type DeviceTypeService struct {
+	deviceTypes store.DeviceTypeStore
+	devices     store.DeviceStore
+}
+
+// NewDeviceTypeService creates a new device type service
+func NewDeviceTypeService(deviceTypes store.DeviceTypeStore, devices store.DeviceStore) *DeviceTypeService {
+	return &DeviceTypeService{deviceTypes: deviceTypes, devices: devices}
+}
+
+// GetAllDeviceTypes retrieves the whole catalog
+func (s *DeviceTypeService) GetAllDeviceTypes() ([]models.DeviceTypeTemplate, error) {
+	deviceTypes, err := s.deviceTypes.ListDeviceTypes()
+	if err != nil {
+		return nil, err
+	}
+	if deviceTypes == nil {
+		deviceTypes = []models.DeviceTypeTemplate{}
+	}
+	return deviceTypes, nil
+}
+
+// GetDeviceTypeByID retrieves a catalog entry by ID
+func (s *DeviceTypeService) GetDeviceTypeByID(id int) (*models.DeviceTypeTemplate, error) {
+	return s.deviceTypes.GetDeviceType(id)
+}
+
+// CreateDeviceType adds a catalog entry
+func (s *DeviceTypeService) CreateDeviceType(req models.CreateDeviceTypeRequest) (*models.DeviceTypeTemplate, error) {
+	sizeU := 1
+	if req.SizeU != nil {
+		sizeU = *req.SizeU
+	}
+	if req.PSURedundancy == "" {
+		req.PSURedundancy = models.PSURedundancyNone
+	}
+
+	deviceType := &models.DeviceTypeTemplate{
+		Manufacturer:   req.Manufacturer,
+		Model:          req.Model,
+		Type:           req.Type,
+		SizeU:          sizeU,
+		Icon:           req.Icon,
+		Specs:          req.Specs,
+		NameplateWatts: req.NameplateWatts,
+		PSUCount:       req.PSUCount,
+		PSURedundancy:  req.PSURedundancy,
+		BayCount:       req.BayCount,
+		Ports:          req.Ports,
+	}
+	if err := s.deviceTypes.CreateDeviceType(deviceType); err != nil {
+		return nil, err
+	}
+
+	return deviceType, nil
+}
+
+// UpdateDeviceType updates a catalog entry; devices created from it are not changed
+func (s *DeviceTypeService) UpdateDeviceType(id int, req models.UpdateDeviceTypeRequest) (*models.DeviceTypeTemplate, error) {
+	deviceType, err := s.deviceTypes.GetDeviceType(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Manufacturer != nil {
+		deviceType.Manufacturer = *req.Manufacturer
+	}
+	if req.Model != nil {
+		deviceType.Model = *req.Model
+	}
+	if req.Type != nil {
+		deviceType.Type = *req.Type
+	}
+	if req.SizeU != nil {
+		deviceType.SizeU = *req.SizeU
+	}
+	if req.Icon != nil {
+		deviceType.Icon = *req.Icon
+	}
+	if req.Specs != nil {
+		deviceType.Specs = req.Specs
+	}
+	if req.Ports != nil {
+		deviceType.Ports = req.Ports
+	}
+	if req.NameplateWatts != nil {
+		deviceType.NameplateWatts = *req.NameplateWatts
+	}
+	if req.PSUCount != nil {
+		deviceType.PSUCount = *req.PSUCount
+	}
+	if req.PSURedundancy != nil {
+		deviceType.PSURedundancy = *req.PSURedundancy
+	}
+	if req.BayCount != nil {
+		deviceType.BayCount = *req.BayCount
+	}
+
+	if err := s.deviceTypes.UpdateDeviceType(deviceType); err != nil {
+		return nil, err
+	}
+
+	return deviceType, nil
+}
+
+// DeleteDeviceType removes a catalog entry; linked devices are kept and unlinked
+func (s *DeviceTypeService) DeleteDeviceType(id int) error {
+	return s.deviceTypes.DeleteDeviceType(id)
+}
+
+// GetModelReport counts devices per model: catalog entries first, then unlinked devices grouped by their model text
+func (s *DeviceTypeService) GetModelReport() ([]models.DeviceModelCount, error) {
+	deviceTypes, err := s.deviceTypes.ListDeviceTypes()
+	if err != nil {
+		return nil, err
+	}
+	byID := map[int]models.DeviceTypeTemplate{}
+	for _, deviceType := range deviceTypes {
+		byID[deviceType.ID] = deviceType
+	}
+
+	devices, err := s.devices.ListDevices(store.DeviceFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Linked devices group by catalog entry, unlinked ones by model text
+	type modelKey struct {
+		deviceTypeID int
+		model        string
+	}
+	counts := map[modelKey]*models.DeviceModelCount{}
+	racks := map[modelKey]map[int]bool{}
+	report := []models.DeviceModelCount{}
+	var keys []modelKey
+	for _, device := range devices {
+		key := modelKey{model: device.Model}
+		count := models.DeviceModelCount{Model: device.Model}
+		if device.DeviceTypeID != nil {
+			deviceType := byID[*device.DeviceTypeID]
+			key = modelKey{deviceTypeID: deviceType.ID}
+			id := deviceType.ID
+			count = models.DeviceModelCount{DeviceTypeID: &id, Manufacturer: deviceType.Manufacturer, Model: deviceType.Model}
+		}
+		if _, ok := counts[key]; !ok {
+			counts[key] = &count
+			racks[key] = map[int]bool{}
+			keys = append(keys, key)
+		}
+		counts[key].Devices++
+		racks[key][device.RackID] = true
+	}
+	for _, key := range keys {
+		count := counts[key]
+		count.Racks = len(racks[key])
+		report = append(report, *count)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if (a.DeviceTypeID == nil) != (b.DeviceTypeID == nil) {
+			return a.DeviceTypeID != nil
+		}
+		if a.Devices != b.Devices {
+			return a.Devices > b.Devices
+		}
+		return a.Model < b.Model
+	})
+
+	return report, nil
+}
+
+// applyDeviceType fills unset fields of a device creation request from its catalog entry
+// and returns the entry so its port templates can be instantiated
+func (s *DeviceTypeService) applyDeviceType(req *models.CreateDeviceRequest) (*models.DeviceTypeTemplate, error) {
+	deviceType, err := s.GetDeviceTypeByID(*req.DeviceTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type == "" {
+		req.Type = deviceType.Type
+	}
+	if req.Model == "" {
+		req.Model = deviceType.Model
+	}
+	if req.Icon == "" {
+		req.Icon = deviceType.Icon
+	}
+	// Zero-U and bay-mounted devices take no U regardless of the catalog height
+	if req.SizeU == 0 && req.ZeroUSide == "" && req.ParentDeviceID == nil {
+		req.SizeU = deviceType.SizeU
+	}
+	if req.NameplateWatts == 0 {
+		req.NameplateWatts = deviceType.NameplateWatts
+	}
+	if req.PSUCount == 0 {
+		req.PSUCount = deviceType.PSUCount
+	}
+	if req.PSURedundancy == "" {
+		req.PSURedundancy = deviceType.PSURedundancy
+	}
+	if req.BayCount == 0 {
+		req.BayCount = deviceType.BayCount
+	}
+
+	// Request specs override individual catalog specs
+	if len(deviceType.Specs) > 0 {
+		specs := make(map[string]string, len(deviceType.Specs)+len(req.Specs))
+		for key, value := range deviceType.Specs {
+			specs[key] = value
+		}
+		for key, value := range req.Specs {
+			specs[key] = value
+		}
+		req.Specs = specs
+	}
+
+	return deviceType, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// DiscoveryService generates monitoring targets from the device inventory
+type DiscoveryService struct {
+	racks   store.RackStore
+	devices store.DeviceStore
+}
+
+// NewDiscoveryService creates a new discovery service
+func NewDiscoveryService(racks store.RackStore, devices store.DeviceStore) *DiscoveryService {
+	return &DiscoveryService{racks: racks, devices: devices}
+}
+
+// GetPrometheusTargets returns one target group per device with an IP address matching the filter
+func (s *DiscoveryService) GetPrometheusTargets(filter models.PrometheusTargetFilter) ([]models.PrometheusTargetGroup, error) {
+	racks, err := s.racks.ListRacks(models.RackFilter{})
+	if err != nil {
+		return nil, err
+	}
+	rackNames := make(map[int]string, len(racks))
+	for _, rack := range racks {
+		rackNames[rack.ID] = rack.Name
+	}
+
+	devices, err := s.devices.ListDevices(store.DeviceFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []models.PrometheusTargetGroup{}
+	for _, device := range devices {
+		if device.IPAddress == "" || !matchesTargetFilter(device, rackNames[device.RackID], filter) {
+			continue
+		}
+
+		// Plain label names are kept on the scraped series; __meta_ labels would be dropped
+		// after relabeling
+		labels := map[string]string{
+			"device":      device.Name,
+			"device_id":   strconv.Itoa(device.ID),
+			"rack":        rackNames[device.RackID],
+			"rack_id":     strconv.Itoa(device.RackID),
+			"device_type": string(device.Type),
+			"model":       device.Model,
+			"status":      string(device.Status),
+		}
+		for _, key := range filter.SpecLabels {
+			if value, ok := device.Specs[key]; ok {
+				labels["spec_"+sanitizeLabelName(key)] = value
+			}
+		}
+
+		groups = append(groups, models.PrometheusTargetGroup{
+			Targets: []string{prometheusTarget(device.IPAddress, filter.Port)},
+			Labels:  labels,
+		})
+	}
+
+	return groups, nil
+}
+
+// prometheusTarget formats an address as a scrape target, bracketing IPv6 addresses as
+// Prometheus expects whether or not a port is given
+func prometheusTarget(ip string, port int) string {
+	if port > 0 {
+		return net.JoinHostPort(ip, strconv.Itoa(port))
+	}
+	if strings.Contains(ip, ":") {
+		return "[" + ip + "]"
+	}
+	return ip
+}
+
+// matchesTargetFilter reports whether a device passes every filter; rack matches by name or ID
+func matchesTargetFilter(device models.Device, rackName string, filter models.PrometheusTargetFilter) bool {
+	if filter.Type != "" && string(device.Type) != filter.Type {
+		return false
+	}
+	if filter.Status != "" && string(device.Status) != filter.Status {
+		return false
+	}
+	if filter.Rack != "" && !strings.EqualFold(rackName, filter.Rack) && strconv.Itoa(device.RackID) != filter.Rack {
+		return false
+	}
+	for key, expected := range filter.Specs {
+		value, ok := device.Specs[key]
+		if !ok || (expected != "" && value != expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeLabelName converts a spec key into a valid Prometheus label name fragment
+func sanitizeLabelName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func TestPrometheusTarget(t *testing.T) {
+	tests := []struct {
+		ip   string
+		port int
+		want string
+	}{
+		{"10.0.0.5", 0, "10.0.0.5"},
+		{"10.0.0.5", 9100, "10.0.0.5:9100"},
+		{"2001:db8::5", 0, "[2001:db8::5]"},
+		{"2001:db8::5", 9100, "[2001:db8::5]:9100"},
+	}
+	for _, tt := range tests {
+		if got := prometheusTarget(tt.ip, tt.port); got != tt.want {
+			t.Errorf("prometheusTarget(%q, %d) = %q, want %q", tt.ip, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestGetPrometheusTargets(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "Rack A")
+
+	web := newDevice(rack.ID, "web-1", 10, 1)
+	web.IPAddress = "2001:db8::5"
+	web.Model = "R650"
+	web.Specs = map[string]string{"CPU": "Xeon", "Network": "10GbE"}
+	mustCreateDevice(t, s, web)
+
+	sw := newDevice(rack.ID, "switch-1", 20, 1)
+	sw.Type = models.DeviceTypeNetwork
+	sw.IPAddress = "10.0.0.2"
+	mustCreateDevice(t, s, sw)
+
+	// Devices without an address are not targets
+	mustCreateDevice(t, s, newDevice(rack.ID, "spare-1", 30, 1))
+
+	groups, err := NewDiscoveryService(s.Racks, s.Devices).GetPrometheusTargets(models.PrometheusTargetFilter{
+		Type:       string(models.DeviceTypeServer),
+		Rack:       "rack a",
+		Specs:      map[string]string{"Network": "10GbE"},
+		SpecLabels: []string{"CPU"},
+		Port:       9100,
+	})
+	if err != nil {
+		t.Fatalf("GetPrometheusTargets: %v", err)
+	}
+	want := []models.PrometheusTargetGroup{{
+		Targets: []string{"[2001:db8::5]:9100"},
+		Labels: map[string]string{
+			"device":      "web-1",
+			"device_id":   strconv.Itoa(web.ID),
+			"rack":        "Rack A",
+			"rack_id":     strconv.Itoa(rack.ID),
+			"device_type": string(models.DeviceTypeServer),
+			"model":       "R650",
+			"status":      string(models.DeviceStatusOnline),
+			"spec_cpu":    "Xeon",
+		},
+	}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GetPrometheusTargets =\n%+v\nwant\n%+v", groups, want)
+	}
+
+	groups, err = NewDiscoveryService(s.Racks, s.Devices).GetPrometheusTargets(models.PrometheusTargetFilter{})
+	if err != nil {
+		t.Fatalf("GetPrometheusTargets without a filter: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Errorf("GetPrometheusTargets without a filter returned %d groups, want 2", len(groups))
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// maxHealthHistoryLimit caps the number of raw checks returned by one history query
+const maxHealthHistoryLimit = 10000
+
+// RecordHealthCheck persists a health check result for a device
+func (s *HealthService) RecordHealthCheck(deviceID int, result *HealthCheckResult) error {
+	check := &models.HealthCheck{
+		DeviceID:  deviceID,
+		Status:    result.Status,
+		Message:   result.Message,
+		CheckedAt: result.Timestamp.UTC(),
+	}
+	if result.Latency > 0 {
+		latency := result.Latency
+		check.Latency = &latency
+	}
+
+	if err := s.checks.RecordHealthCheck(check); err != nil {
+		return fmt.Errorf("failed to record health check: %w", err)
+	}
+
+	return nil
+}
+
+// GetHealthHistory returns a device's health checks in a time range, downsampled when query.Bucket is set
+func (s *HealthService) GetHealthHistory(deviceID int, query models.HealthHistoryQuery) (*models.HealthHistory, error) {
+	if _, err := s.devices.GetDevice(deviceID); err != nil {
+		return nil, err
+	}
+
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-24 * time.Hour)
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	history := &models.HealthHistory{
+		DeviceID: deviceID,
+		From:     query.From.UTC(),
+		To:       query.To.UTC(),
+	}
+
+	if query.Bucket > 0 {
+		bucketSeconds := int(query.Bucket.Seconds())
+		if bucketSeconds < 1 {
+			return nil, fmt.Errorf("bucket must be at least 1s")
+		}
+		buckets, err := s.getHealthHistoryBuckets(deviceID, history.From, history.To, bucketSeconds)
+		if err != nil {
+			return nil, err
+		}
+		history.BucketSeconds = bucketSeconds
+		history.Buckets = buckets
+		return history, nil
+	}
+
+	if query.Limit <= 0 || query.Limit > maxHealthHistoryLimit {
+		query.Limit = maxHealthHistoryLimit
+	}
+
+	checks, err := s.checks.ListHealthChecks(deviceID, history.From, history.To, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	history.Checks = checks
+
+	return history, nil
+}
+
+// getHealthHistoryBuckets aggregates a device's checks into fixed-size time buckets
+func (s *HealthService) getHealthHistoryBuckets(deviceID int, from, to time.Time, bucketSeconds int) ([]models.HealthHistoryBucket, error) {
+	buckets, err := s.checks.SummarizeHealthChecks(deviceID, from, to, bucketSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range buckets {
+		bucket := &buckets[i]
+		// Availability counts warning as reachable; unknown checks are excluded
+		if known := bucket.Checks - bucket.Unknown; known > 0 {
+			bucket.Availability = float64(bucket.Online+bucket.Warning) / float64(known)
+		}
+	}
+
+	return buckets, nil
+}
+
+// PruneHealthHistory deletes health checks older than the retention period and returns the number removed
+func (s *HealthService) PruneHealthHistory(retention time.Duration) (int64, error) {
+	removed, err := s.checks.PruneHealthChecks(time.Now().Add(-retention).UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune health history: %w", err)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// GetDeviceProbes retrieves all health probes for a device
+func (s *HealthService) GetDeviceProbes(deviceID int) ([]models.HealthProbe, error) {
+	probes, err := s.probes.ListHealthProbes(store.HealthProbeFilter{DeviceID: &deviceID})
+	if err != nil {
+		return nil, err
+	}
+	if probes == nil {
+		probes = []models.HealthProbe{}
+	}
+	return probes, nil
+}
+
+// getEnabledProbes retrieves the enabled health probes for a device
+func (s *HealthService) getEnabledProbes(deviceID int) ([]models.HealthProbe, error) {
+	return s.probes.ListHealthProbes(store.HealthProbeFilter{DeviceID: &deviceID, EnabledOnly: true})
+}
+
+// GetProbeByID retrieves a device's health probe by ID
+func (s *HealthService) GetProbeByID(deviceID, probeID int) (*models.HealthProbe, error) {
+	probe, err := s.probes.GetHealthProbe(probeID)
+	if err != nil {
+		return nil, err
+	}
+	if probe.DeviceID != deviceID {
+		return nil, store.ErrHealthProbeNotFound
+	}
+	return probe, nil
+}
+
+// CreateProbe adds a health probe to a device
+func (s *HealthService) CreateProbe(deviceID int, req models.CreateHealthProbeRequest) (*models.HealthProbe, error) {
+	if _, err := s.devices.GetDevice(deviceID); err != nil {
+		return nil, err
+	}
+
+	// Validate the configuration up front rather than at check time
+	if _, err := NewProber(models.HealthProbe{Type: req.Type, Config: req.Config}); err != nil {
+		return nil, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	probe := &models.HealthProbe{
+		DeviceID: deviceID,
+		Name:     req.Name,
+		Type:     req.Type,
+		Config:   req.Config,
+		Enabled:  enabled,
+	}
+	if err := s.probes.CreateHealthProbe(probe); err != nil {
+		return nil, fmt.Errorf("failed to create health probe: %w", err)
+	}
+
+	return probe, nil
+}
+
+// UpdateProbe updates a device's health probe
+func (s *HealthService) UpdateProbe(deviceID, probeID int, req models.UpdateHealthProbeRequest) (*models.HealthProbe, error) {
+	current, err := s.GetProbeByID(deviceID, probeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		current.Name = *req.Name
+	}
+	if req.Config != nil {
+		current.Config = *req.Config
+	}
+	if req.Enabled != nil {
+		current.Enabled = *req.Enabled
+	}
+
+	if _, err := NewProber(*current); err != nil {
+		return nil, err
+	}
+
+	if err := s.probes.UpdateHealthProbe(current); err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// DeleteProbe removes a health probe from a device
+func (s *HealthService) DeleteProbe(deviceID, probeID int) error {
+	if _, err := s.GetProbeByID(deviceID, probeID); err != nil {
+		return err
+	}
+	return s.probes.DeleteHealthProbe(probeID)
+}
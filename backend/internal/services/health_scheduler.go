@@ -0,0 +1,413 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// HealthSchedulerConfig controls the background health check scheduler
+type HealthSchedulerConfig struct {
+	Enabled         bool
+	DefaultInterval time.Duration
+	MinInterval     time.Duration
+	Workers         int
+	QueueSize       int
+	// Jitter is the fraction of the interval added at random to each next run (0.1 = up to 10%)
+	Jitter float64
+	// RefreshInterval is how often the device list is reloaded and due checks are dispatched
+	RefreshInterval time.Duration
+	// HistoryRetention is how long health check history is kept (0 keeps it forever)
+	HistoryRetention time.Duration
+	PruneInterval    time.Duration
+}
+
+// LoadHealthSchedulerConfig reads the scheduler configuration from the environment
+func LoadHealthSchedulerConfig() HealthSchedulerConfig {
+	config := HealthSchedulerConfig{
+		Enabled:          getEnvBool("HEALTH_CHECK_ENABLED", true),
+		DefaultInterval:  getEnvDuration("HEALTH_CHECK_INTERVAL", 60*time.Second),
+		MinInterval:      getEnvDuration("HEALTH_CHECK_MIN_INTERVAL", 10*time.Second),
+		Workers:          getEnvInt("HEALTH_CHECK_WORKERS", 4),
+		QueueSize:        getEnvInt("HEALTH_CHECK_QUEUE_SIZE", 100),
+		Jitter:           getEnvFloat("HEALTH_CHECK_JITTER", 0.1),
+		RefreshInterval:  getEnvDuration("HEALTH_CHECK_REFRESH_INTERVAL", 5*time.Second),
+		HistoryRetention: getEnvDuration("HEALTH_HISTORY_RETENTION", 30*24*time.Hour),
+		PruneInterval:    getEnvDuration("HEALTH_HISTORY_PRUNE_INTERVAL", time.Hour),
+	}
+
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.QueueSize < 1 {
+		config.QueueSize = 1
+	}
+	if config.Jitter < 0 {
+		config.Jitter = 0
+	}
+	if config.MinInterval <= 0 {
+		config.MinInterval = time.Second
+	}
+	if config.DefaultInterval < config.MinInterval {
+		config.DefaultInterval = config.MinInterval
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = 5 * time.Second
+	}
+	if config.PruneInterval <= 0 {
+		config.PruneInterval = time.Hour
+	}
+
+	return config
+}
+
+// scheduleEntry tracks the schedule and last outcome for a single device
+type scheduleEntry struct {
+	target      HealthCheckTarget
+	nextRun     time.Time
+	lastRun     time.Time
+	lastStatus  models.DeviceStatus
+	lastMessage string
+	lastLatency int64
+	lastError   string
+	queued      bool
+	inFlight    bool
+}
+
+// ScheduledDeviceStatus describes the scheduler state for one device
+type ScheduledDeviceStatus struct {
+	DeviceID        int                 `json:"device_id"`
+	Name            string              `json:"name"`
+	IntervalSeconds int                 `json:"interval_seconds"`
+	NextRun         time.Time           `json:"next_run"`
+	LastRun         *time.Time          `json:"last_run,omitempty"`
+	LastStatus      models.DeviceStatus `json:"last_status,omitempty"`
+	LastMessage     string              `json:"last_message,omitempty"`
+	LastLatency     int64               `json:"last_latency_ms,omitempty"`
+	LastError       string              `json:"last_error,omitempty"`
+	Queued          bool                `json:"queued"`
+	InFlight        bool                `json:"in_flight"`
+}
+
+// HealthSchedulerStatus is a snapshot of the scheduler and its queue
+type HealthSchedulerStatus struct {
+	Enabled                bool                    `json:"enabled"`
+	Running                bool                    `json:"running"`
+	Workers                int                     `json:"workers"`
+	DefaultIntervalSeconds int                     `json:"default_interval_seconds"`
+	QueueCapacity          int                     `json:"queue_capacity"`
+	QueueLength            int                     `json:"queue_length"`
+	InFlight               int                     `json:"in_flight"`
+	Devices                []ScheduledDeviceStatus `json:"devices"`
+}
+
+// HealthScheduler periodically health checks every device that has an IP address or health check URL
+type HealthScheduler struct {
+	config  HealthSchedulerConfig
+	service *HealthService
+	jobs    chan int
+
+	mu      sync.Mutex
+	entries map[int]*scheduleEntry
+	running bool
+	rand    *rand.Rand
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewHealthScheduler creates a new health check scheduler
+func NewHealthScheduler(config HealthSchedulerConfig, service *HealthService) *HealthScheduler {
+	return &HealthScheduler{
+		config:  config,
+		service: service,
+		jobs:    make(chan int, config.QueueSize),
+		entries: make(map[int]*scheduleEntry),
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Start launches the dispatcher, worker pool and history pruner; it returns immediately
+func (s *HealthScheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.running = true
+	s.mu.Unlock()
+
+	// History from manual checks still needs pruning when scheduling is disabled
+	if s.config.HistoryRetention > 0 {
+		s.wg.Add(1)
+		go s.prune(ctx)
+	}
+
+	if !s.config.Enabled {
+		log.Printf("Health check scheduler disabled")
+		return
+	}
+
+	for i := 0; i < s.config.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker(ctx)
+	}
+
+	s.wg.Add(1)
+	go s.dispatch(ctx)
+
+	log.Printf("Health check scheduler started (%d workers, default interval %s)", s.config.Workers, s.config.DefaultInterval)
+}
+
+// Stop cancels the scheduler and waits for in-flight checks to finish or ctx to expire
+func (s *HealthScheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.running = false
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Health check scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Status returns a snapshot of the scheduler queue
+func (s *HealthScheduler) Status() HealthSchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := HealthSchedulerStatus{
+		Enabled:                s.config.Enabled,
+		Running:                s.running && s.config.Enabled,
+		Workers:                s.config.Workers,
+		DefaultIntervalSeconds: int(s.config.DefaultInterval.Seconds()),
+		QueueCapacity:          cap(s.jobs),
+		QueueLength:            len(s.jobs),
+		Devices:                make([]ScheduledDeviceStatus, 0, len(s.entries)),
+	}
+
+	for _, entry := range s.entries {
+		device := ScheduledDeviceStatus{
+			DeviceID:        entry.target.DeviceID,
+			Name:            entry.target.Name,
+			IntervalSeconds: int(s.intervalFor(entry.target).Seconds()),
+			NextRun:         entry.nextRun,
+			LastStatus:      entry.lastStatus,
+			LastMessage:     entry.lastMessage,
+			LastLatency:     entry.lastLatency,
+			LastError:       entry.lastError,
+			Queued:          entry.queued,
+			InFlight:        entry.inFlight,
+		}
+		if !entry.lastRun.IsZero() {
+			lastRun := entry.lastRun
+			device.LastRun = &lastRun
+		}
+		if entry.inFlight {
+			status.InFlight++
+		}
+		status.Devices = append(status.Devices, device)
+	}
+
+	sort.Slice(status.Devices, func(i, j int) bool {
+		return status.Devices[i].NextRun.Before(status.Devices[j].NextRun)
+	})
+
+	return status
+}
+
+// dispatch reloads the device list and queues due checks on every tick
+func (s *HealthScheduler) dispatch(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		s.refresh()
+		s.enqueueDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune deletes expired health check history on every tick
+func (s *HealthScheduler) prune(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		removed, err := s.service.PruneHealthHistory(s.config.HistoryRetention)
+		if err != nil {
+			log.Printf("Health check scheduler: %v", err)
+		} else if removed > 0 {
+			log.Printf("Pruned %d health checks older than %s", removed, s.config.HistoryRetention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh syncs the schedule with the devices currently configured for health checks
+func (s *HealthScheduler) refresh() {
+	targets, err := s.service.GetHealthCheckTargets()
+	if err != nil {
+		log.Printf("Health check scheduler: %v", err)
+		return
+	}
+
+	now := time.Now()
+	seen := make(map[int]bool, len(targets))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, target := range targets {
+		seen[target.DeviceID] = true
+
+		entry, ok := s.entries[target.DeviceID]
+		if !ok {
+			// Spread first runs across one interval so a restart doesn't probe everything at once
+			interval := s.intervalFor(target)
+			s.entries[target.DeviceID] = &scheduleEntry{
+				target:  target,
+				nextRun: now.Add(time.Duration(s.rand.Int63n(int64(interval)))),
+			}
+			continue
+		}
+
+		if entry.target.Interval != target.Interval && !entry.lastRun.IsZero() {
+			entry.nextRun = entry.lastRun.Add(s.intervalFor(target))
+		}
+		entry.target = target
+	}
+
+	for id := range s.entries {
+		if !seen[id] {
+			delete(s.entries, id)
+		}
+	}
+}
+
+// enqueueDue pushes every due device onto the job queue without blocking
+func (s *HealthScheduler) enqueueDue(ctx context.Context) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, entry := range s.entries {
+		if entry.queued || entry.inFlight || now.Before(entry.nextRun) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case s.jobs <- id:
+			entry.queued = true
+		default:
+			// Queue is full; the device stays due and is retried on the next tick
+			return
+		}
+	}
+}
+
+// worker runs queued health checks until ctx is cancelled
+func (s *HealthScheduler) worker(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case deviceID := <-s.jobs:
+			s.runCheck(deviceID)
+		}
+	}
+}
+
+// runCheck probes a device, persists its status and schedules the next run
+func (s *HealthScheduler) runCheck(deviceID int) {
+	s.mu.Lock()
+	entry, ok := s.entries[deviceID]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	entry.queued = false
+	entry.inFlight = true
+	s.mu.Unlock()
+
+	result, err := s.service.CheckDeviceHealth(deviceID)
+	if err == nil {
+		err = s.service.UpdateDeviceStatusFromHealthCheck(deviceID, result)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.inFlight = false
+	entry.lastRun = time.Now()
+	entry.nextRun = entry.lastRun.Add(s.withJitter(s.intervalFor(entry.target)))
+	entry.lastError = ""
+	if result != nil {
+		entry.lastStatus = result.Status
+		entry.lastMessage = result.Message
+		entry.lastLatency = result.Latency
+	}
+	if err != nil {
+		entry.lastError = err.Error()
+		log.Printf("Health check scheduler: device %d: %v", deviceID, err)
+	}
+}
+
+// intervalFor returns the effective interval for a target, clamped to the configured minimum
+func (s *HealthScheduler) intervalFor(target HealthCheckTarget) time.Duration {
+	interval := s.config.DefaultInterval
+	if target.Interval > 0 {
+		interval = time.Duration(target.Interval) * time.Second
+	}
+	if interval < s.config.MinInterval {
+		interval = s.config.MinInterval
+	}
+	return interval
+}
+
+// withJitter adds a random delay of up to Jitter * interval; callers must hold s.mu
+func (s *HealthScheduler) withJitter(interval time.Duration) time.Duration {
+	maxJitter := int64(float64(interval) * s.config.Jitter)
+	if maxJitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(s.rand.Int63n(maxJitter))
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func newTestScheduler(s *store.Store, config HealthSchedulerConfig) *HealthScheduler {
+	return NewHealthScheduler(config, NewHealthService(s.Devices, s.HealthProbes, s.HealthChecks))
+}
+
+func TestSchedulerRefresh(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	withIP := newDevice(rack.ID, "ip", 10, 1)
+	withIP.IPAddress = "192.0.2.1"
+	mustCreateDevice(t, s, withIP)
+	withURL := newDevice(rack.ID, "url", 11, 1)
+	withURL.HealthCheckURL = "http://192.0.2.2/health"
+	withURL.HealthCheckInterval = 300
+	mustCreateDevice(t, s, withURL)
+	withProbe := mustCreateDevice(t, s, newDevice(rack.ID, "probe", 12, 1))
+	probe := &models.HealthProbe{DeviceID: withProbe.ID, Name: "tcp", Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{22}}, Enabled: true}
+	if err := s.HealthProbes.CreateHealthProbe(probe); err != nil {
+		t.Fatalf("CreateHealthProbe: %v", err)
+	}
+	mustCreateDevice(t, s, newDevice(rack.ID, "unchecked", 13, 1))
+
+	scheduler := newTestScheduler(s, HealthSchedulerConfig{DefaultInterval: time.Minute, MinInterval: 10 * time.Second, Workers: 1, QueueSize: 10})
+	start := time.Now()
+	scheduler.refresh()
+
+	if len(scheduler.entries) != 3 {
+		t.Fatalf("scheduled %d devices, want the 3 with an IP address, URL or enabled probe", len(scheduler.entries))
+	}
+	// First runs are spread across one interval
+	for id, entry := range scheduler.entries {
+		interval := scheduler.intervalFor(entry.target)
+		if entry.nextRun.Before(start) || entry.nextRun.After(start.Add(interval)) {
+			t.Errorf("device %d first run at %v, want within %s of %v", id, entry.nextRun, interval, start)
+		}
+	}
+	if got := scheduler.intervalFor(scheduler.entries[withURL.ID].target); got != 5*time.Minute {
+		t.Errorf("interval of a device with its own interval = %s, want 5m", got)
+	}
+
+	// An interval change reschedules from the last run; dropped devices leave the schedule
+	entry := scheduler.entries[withIP.ID]
+	entry.lastRun = start
+	entry.nextRun = start.Add(time.Minute)
+	withIP.HealthCheckInterval = 1
+	if err := s.Devices.UpdateDevice(withIP, nil); err != nil {
+		t.Fatalf("UpdateDevice: %v", err)
+	}
+	if err := s.Devices.DeleteDevice(withProbe.ID); err != nil {
+		t.Fatalf("DeleteDevice: %v", err)
+	}
+	scheduler.refresh()
+
+	if _, ok := scheduler.entries[withProbe.ID]; ok {
+		t.Errorf("deleted device is still scheduled")
+	}
+	// A 1s interval is clamped to the 10s minimum
+	if want := start.Add(10 * time.Second); !entry.nextRun.Equal(want) {
+		t.Errorf("next run after an interval change = %v, want %v", entry.nextRun, want)
+	}
+}
+
+func TestSchedulerEnqueueDue(t *testing.T) {
+	s := store.NewMemoryStore()
+	scheduler := newTestScheduler(s, HealthSchedulerConfig{DefaultInterval: time.Minute, MinInterval: time.Second, Workers: 1, QueueSize: 2})
+	past := time.Now().Add(-time.Second)
+	scheduler.entries = map[int]*scheduleEntry{
+		1: {target: HealthCheckTarget{DeviceID: 1}, nextRun: past},
+		2: {target: HealthCheckTarget{DeviceID: 2}, nextRun: past, inFlight: true},
+		3: {target: HealthCheckTarget{DeviceID: 3}, nextRun: time.Now().Add(time.Hour)},
+		4: {target: HealthCheckTarget{DeviceID: 4}, nextRun: past},
+		5: {target: HealthCheckTarget{DeviceID: 5}, nextRun: past},
+	}
+
+	scheduler.enqueueDue(context.Background())
+
+	// The queue holds two: the in-flight and not-yet-due devices are skipped, and one due device
+	// waits for the next tick
+	if len(scheduler.jobs) != 2 {
+		t.Fatalf("queued %d checks, want 2", len(scheduler.jobs))
+	}
+	queued := 0
+	for id, entry := range scheduler.entries {
+		if entry.queued {
+			queued++
+			if id == 2 || id == 3 {
+				t.Errorf("device %d was queued", id)
+			}
+		}
+	}
+	if queued != 2 {
+		t.Errorf("%d entries marked queued, want 2", queued)
+	}
+
+	// Queued devices aren't queued twice
+	<-scheduler.jobs
+	scheduler.enqueueDue(context.Background())
+	seen := map[int]bool{}
+	for len(scheduler.jobs) > 0 {
+		id := <-scheduler.jobs
+		if seen[id] {
+			t.Errorf("device %d queued twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestSchedulerRunCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 1)
+	device.HealthCheckURL = server.URL
+	mustCreateDevice(t, s, device)
+
+	config := HealthSchedulerConfig{DefaultInterval: time.Minute, MinInterval: time.Second, Workers: 1, QueueSize: 1, Jitter: 0.5}
+	scheduler := newTestScheduler(s, config)
+	scheduler.refresh()
+	entry := scheduler.entries[device.ID]
+	entry.queued = true
+
+	scheduler.runCheck(device.ID)
+
+	if entry.queued || entry.inFlight || entry.lastRun.IsZero() || entry.lastError != "" {
+		t.Errorf("entry after a check = %+v", entry)
+	}
+	if entry.lastStatus == models.DeviceStatusOnline || entry.lastStatus == "" {
+		t.Errorf("last status = %q, want the failing check's status", entry.lastStatus)
+	}
+	if min, max := entry.lastRun.Add(time.Minute), entry.lastRun.Add(90*time.Second); entry.nextRun.Before(min) || !entry.nextRun.Before(max) {
+		t.Errorf("next run = %v, want one interval plus up to 50%% jitter after %v", entry.nextRun, entry.lastRun)
+	}
+
+	// The scheduler persists the result to the device
+	got, err := s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if got.Status != entry.lastStatus {
+		t.Errorf("device status = %s, want %s", got.Status, entry.lastStatus)
+	}
+
+	// A device that left the schedule is skipped
+	scheduler.runCheck(device.ID + 1000)
+}
+
+func TestSchedulerWorkerPool(t *testing.T) {
+	// Every check holds a request open for a while, so the server sees as many at once as there
+	// are workers
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	for i := 0; i < 6; i++ {
+		device := newDevice(rack.ID, "web-"+string(rune('a'+i)), 10+i, 1)
+		device.HealthCheckURL = server.URL
+		mustCreateDevice(t, s, device)
+	}
+
+	scheduler := newTestScheduler(s, HealthSchedulerConfig{
+		Enabled:         true,
+		DefaultInterval: 10 * time.Millisecond,
+		MinInterval:     time.Millisecond,
+		Workers:         2,
+		QueueSize:       10,
+		RefreshInterval: 5 * time.Millisecond,
+	})
+	scheduler.Start(context.Background())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		checked := 0
+		status := scheduler.Status()
+		for _, device := range status.Devices {
+			if device.LastRun != nil {
+				checked++
+			}
+		}
+		if checked == 6 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d of 6 devices checked: %+v", checked, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := scheduler.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if status := scheduler.Status(); status.Running {
+		t.Errorf("scheduler still running after Stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxActive > 2 {
+		t.Errorf("%d checks ran at once, want at most the 2 workers", maxActive)
+	}
+}
@@ -3,20 +3,34 @@ package services
 import (
 	"context"
 	"fmt"
-	"net"
+	"log"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
-	"rackview/internal/database"
 	"rackview/internal/models"
+	"rackview/internal/store"
 )
 
 // HealthService handles device health checks
-type HealthService struct{}
+type HealthService struct {
+	devices store.DeviceStore
+	probes  store.HealthProbeStore
+	checks  store.HealthCheckStore
+	alerts  *AlertNotifier
+}
 
 // NewHealthService creates a new health service
-func NewHealthService() *HealthService {
-	return &HealthService{}
+func NewHealthService(devices store.DeviceStore, probes store.HealthProbeStore, checks store.HealthCheckStore) *HealthService {
+	return &HealthService{devices: devices, probes: probes, checks: checks}
+}
+
+// WithAlerts makes every health check feed status transitions to the notifier
+func (s *HealthService) WithAlerts(alerts *AlertNotifier) *HealthService {
+	s.alerts = alerts
+	return s
 }
 
 // HealthCheckResult represents the result of a health check
@@ -25,133 +39,155 @@ type HealthCheckResult struct {
 	Message   string              `json:"message"`
 	Latency   int64               `json:"latency_ms,omitempty"`
 	Timestamp time.Time           `json:"timestamp"`
+	Probes    []ProbeResult       `json:"probes,omitempty"`
 }
 
 // CheckDeviceHealth performs a health check on a device
 func (s *HealthService) CheckDeviceHealth(deviceID int) (*HealthCheckResult, error) {
 	// Get device
-	var device models.Device
-	err := database.DB.QueryRow(`
-		SELECT id, name, ip_address, health_check_url, status
-		FROM devices
-		WHERE id = $1
-	`, deviceID).Scan(
-		&device.ID, &device.Name, &device.IPAddress, &device.HealthCheckURL, &device.Status,
-	)
+	device, err := s.devices.GetDevice(deviceID)
+	if err != nil {
+		return nil, err
+	}
 
+	probes, err := s.getEnabledProbes(device.ID)
 	if err != nil {
-		return nil, fmt.Errorf("device not found: %w", err)
+		return nil, err
+	}
+
+	result := s.probeDevice(device, probes)
+
+	// Keep a history of every check; a failed write shouldn't fail the check itself
+	if err := s.RecordHealthCheck(device.ID, result); err != nil {
+		log.Printf("Health check for device %d: %v", device.ID, err)
+	}
+
+	if s.alerts != nil {
+		if err := s.alerts.ObserveHealthCheck(device, result); err != nil {
+			log.Printf("Health check alerting for device %d: %v", device.ID, err)
+		}
 	}
 
+	return result, nil
+}
+
+// probeDevice runs the device's configured probes, or the default HTTP and TCP checks when it has none
+func (s *HealthService) probeDevice(device *models.Device, probes []models.HealthProbe) *HealthCheckResult {
 	result := &HealthCheckResult{
 		Timestamp: time.Now(),
 	}
 
-	// Try HTTP health check first if URL is provided
-	if device.HealthCheckURL != "" {
-		status, latency, err := s.checkHTTPHealth(device.HealthCheckURL)
-		if err == nil {
-			result.Status = status
-			result.Latency = latency
-			result.Message = fmt.Sprintf("HTTP check successful (%dms)", latency)
-			return result, nil
+	var probers []Prober
+	var names []string
+	mode := device.HealthCheckMode
+	for _, probe := range probes {
+		prober, err := NewProber(probe)
+		if err != nil {
+			result.Probes = append(result.Probes, ProbeResult{
+				Name:    probe.Name,
+				Type:    probe.Type,
+				Status:  models.DeviceStatusUnknown,
+				Message: fmt.Sprintf("Invalid probe configuration: %v", err),
+			})
+			continue
 		}
-		result.Message = fmt.Sprintf("HTTP check failed: %v", err)
+		probers = append(probers, prober)
+		names = append(names, probe.Name)
 	}
 
-	// Fall back to ping if IP address is provided
-	if device.IPAddress != "" {
-		latency, err := s.pingDevice(device.IPAddress)
-		if err == nil {
-			result.Status = models.DeviceStatusOnline
-			result.Latency = latency
-			result.Message = fmt.Sprintf("Ping successful (%dms)", latency)
-			return result, nil
+	if len(probes) == 0 {
+		// If no health check method is configured, return unknown
+		if device.IPAddress == "" && device.HealthCheckURL == "" {
+			result.Status = models.DeviceStatusUnknown
+			result.Message = "No health check configured (IP address or health check URL required)"
+			return result
+		}
+
+		// Without configured probes, try the health check URL then the common TCP ports; either passing is enough
+		mode = models.HealthCheckModeAny
+		if device.HealthCheckURL != "" {
+			probers = append(probers, &HTTPProber{Method: http.MethodGet, Timeout: defaultProbeTimeout})
+			names = append(names, "")
 		}
-		if result.Message == "" {
-			result.Message = fmt.Sprintf("Ping failed: %v", err)
-		} else {
-			result.Message += fmt.Sprintf("; Ping failed: %v", err)
+		if device.IPAddress != "" {
+			probers = append(probers, &TCPProber{Ports: defaultTCPPorts, Timeout: 2 * time.Second})
+			names = append(names, "")
 		}
 	}
 
-	// If no health check method is configured, return unknown
-	if device.IPAddress == "" && device.HealthCheckURL == "" {
-		result.Status = models.DeviceStatusUnknown
-		result.Message = "No health check configured (IP address or health check URL required)"
-		return result, nil
+	// Run probes concurrently so a slow probe doesn't delay the others
+	results := make([]ProbeResult, len(probers))
+	var wg sync.WaitGroup
+	for i, prober := range probers {
+		wg.Add(1)
+		go func(i int, prober Prober) {
+			defer wg.Done()
+			results[i] = prober.Probe(context.Background(), device)
+			results[i].Name = names[i]
+		}(i, prober)
 	}
+	wg.Wait()
+	result.Probes = append(result.Probes, results...)
 
-	// All checks failed
-	result.Status = models.DeviceStatusOffline
-	if result.Message == "" {
-		result.Message = "Health check failed"
+	result.Status = CombineProbeResults(mode, result.Probes)
+
+	messages := make([]string, 0, len(result.Probes))
+	for _, probe := range result.Probes {
+		if probe.Status == result.Status && probe.Latency > 0 && result.Latency == 0 {
+			result.Latency = probe.Latency
+		}
+		messages = append(messages, probe.Message)
 	}
+	result.Message = strings.Join(messages, "; ")
 
-	return result, nil
+	return result
 }
 
-// checkHTTPHealth performs an HTTP health check
-func (s *HealthService) checkHTTPHealth(url string) (models.DeviceStatus, int64, error) {
-	start := time.Now()
+// UpdateDeviceStatusFromHealthCheck updates a device's status based on health check result
+func (s *HealthService) UpdateDeviceStatusFromHealthCheck(deviceID int, result *HealthCheckResult) error {
+	if err := s.devices.UpdateDeviceStatus(deviceID, result.Status); err != nil {
+		return fmt.Errorf("failed to update device status: %w", err)
+	}
+
+	return nil
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// HealthCheckTarget is a device that can be probed by the scheduler
+type HealthCheckTarget struct {
+	DeviceID int    `json:"device_id"`
+	Name     string `json:"name"`
+	// Interval is the device's configured interval in seconds (0 means use the scheduler default)
+	Interval int `json:"interval_seconds"`
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// GetHealthCheckTargets lists every device with an IP address, health check URL or enabled probe configured
+func (s *HealthService) GetHealthCheckTargets() ([]HealthCheckTarget, error) {
+	probes, err := s.probes.ListHealthProbes(store.HealthProbeFilter{EnabledOnly: true})
 	if err != nil {
-		return models.DeviceStatusOffline, 0, err
+		return nil, fmt.Errorf("failed to query health check targets: %w", err)
 	}
-
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+	probed := map[int]bool{}
+	for _, probe := range probes {
+		probed[probe.DeviceID] = true
 	}
 
-	resp, err := client.Do(req)
+	devices, err := s.devices.ListDevices(store.DeviceFilter{})
 	if err != nil {
-		return models.DeviceStatusOffline, 0, err
-	}
-	defer resp.Body.Close()
-
-	latency := time.Since(start).Milliseconds()
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return models.DeviceStatusOnline, latency, nil
-	} else if resp.StatusCode >= 500 {
-		return models.DeviceStatusOffline, latency, fmt.Errorf("server error: %d", resp.StatusCode)
-	} else {
-		return models.DeviceStatusWarning, latency, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("failed to query health check targets: %w", err)
 	}
-}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
 
-// pingDevice performs a TCP ping to check if a device is reachable
-func (s *HealthService) pingDevice(ipAddress string) (int64, error) {
-	// Try common ports to see if device is reachable
-	ports := []string{"80", "443", "22", "8080", "8443", "3389"}
-	
-	for _, port := range ports {
-		start := time.Now()
-		conn, err := net.DialTimeout("tcp", net.JoinHostPort(ipAddress, port), 2*time.Second)
-		if err == nil {
-			conn.Close()
-			return time.Since(start).Milliseconds(), nil
+	var targets []HealthCheckTarget
+	for _, device := range devices {
+		if device.IPAddress == "" && device.HealthCheckURL == "" && !probed[device.ID] {
+			continue
 		}
-	}
-	
-	return 0, fmt.Errorf("device unreachable on common ports")
-}
-
-// UpdateDeviceStatusFromHealthCheck updates a device's status based on health check result
-func (s *HealthService) UpdateDeviceStatusFromHealthCheck(deviceID int, result *HealthCheckResult) error {
-	_, err := database.DB.Exec(`
-		UPDATE devices
-		SET status = $1, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $2
-	`, result.Status, deviceID)
-
-	if err != nil {
-		return fmt.Errorf("failed to update device status: %w", err)
+		targets = append(targets, HealthCheckTarget{
+			DeviceID: device.ID,
+			Name:     device.Name,
+			Interval: device.HealthCheckInterval,
+		})
 	}
 
-	return nil
+	return targets, nil
 }
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func mustCreateRack(t *testing.T, s *store.Store, name string) *models.Rack {
+	t.Helper()
+	rack := &models.Rack{Name: name, Description: "test rack", SizeU: 42}
+	if err := s.Racks.CreateRack(rack); err != nil {
+		t.Fatalf("CreateRack(%s): %v", name, err)
+	}
+	return rack
+}
+
+func newDevice(rackID int, name string, positionU, sizeU int) *models.Device {
+	return &models.Device{
+		RackID:          rackID,
+		Name:            name,
+		Icon:            "🖥️",
+		Type:            models.DeviceTypeServer,
+		PositionU:       positionU,
+		SizeU:           sizeU,
+		MountFace:       models.MountFaceFront,
+		Depth:           models.DeviceDepthFull,
+		Status:          models.DeviceStatusOnline,
+		HealthCheckMode: models.HealthCheckModeAll,
+		PSURedundancy:   models.PSURedundancyNone,
+	}
+}
+
+func mustCreateDevice(t *testing.T, s *store.Store, device *models.Device) *models.Device {
+	t.Helper()
+	if err := s.Devices.CreateDevice(device, nil, nil); err != nil {
+		t.Fatalf("CreateDevice(%s): %v", device.Name, err)
+	}
+	return device
+}
+
+func mustCreateInterface(t *testing.T, s *store.Store, deviceID int, name string) *models.DeviceInterface {
+	t.Helper()
+	iface := &models.DeviceInterface{DeviceID: deviceID, Name: name, Type: "ethernet", Enabled: true}
+	if err := s.Interfaces.CreateInterface(iface); err != nil {
+		t.Fatalf("CreateInterface(%s): %v", name, err)
+	}
+	return iface
+}
+
+// mustConnect connects two devices, on the given interfaces when non-nil
+func mustConnect(t *testing.T, s *store.Store, sourceID int, sourceIface *models.DeviceInterface, targetID int, targetIface *models.DeviceInterface) *models.NetworkConnection {
+	t.Helper()
+	conn := &models.NetworkConnection{SourceDeviceID: sourceID, TargetDeviceID: targetID, ConnectionType: "Ethernet"}
+	if sourceIface != nil {
+		conn.SourceInterfaceID = &sourceIface.ID
+	}
+	if targetIface != nil {
+		conn.TargetInterfaceID = &targetIface.ID
+	}
+	if err := s.Connections.CreateConnection(conn, nil); err != nil {
+		t.Fatalf("CreateConnection(%d, %d): %v", sourceID, targetID, err)
+	}
+	return conn
+}
+
+func newNetworkService(s *store.Store) *NetworkService {
+	return NewNetworkService(s.Connections, s.Devices, s.Racks, s.Interfaces, s.Cables)
+}
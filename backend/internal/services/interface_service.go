@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// InterfaceService handles device interface-related business logic
+type InterfaceService struct {
+	interfaces store.InterfaceStore
+	devices    store.DeviceStore
+}
+
+// NewInterfaceService creates a new interface service
+func NewInterfaceService(interfaces store.InterfaceStore, devices store.DeviceStore) *InterfaceService {
+	return &InterfaceService{interfaces: interfaces, devices: devices}
+}
+
+// GetDeviceInterfaces retrieves all interfaces of a device
+func (s *InterfaceService) GetDeviceInterfaces(deviceID int) ([]models.DeviceInterface, error) {
+	if _, err := s.devices.GetDevice(deviceID); err != nil {
+		return nil, err
+	}
+
+	interfaces, err := s.interfaces.ListInterfaces(store.InterfaceFilter{DeviceID: &deviceID})
+	if err != nil {
+		return nil, err
+	}
+	if interfaces == nil {
+		interfaces = []models.DeviceInterface{}
+	}
+	return interfaces, nil
+}
+
+// GetInterfaceByID retrieves an interface by ID regardless of its device
+func (s *InterfaceService) GetInterfaceByID(id int) (*models.DeviceInterface, error) {
+	return s.interfaces.GetInterface(id)
+}
+
+// GetDeviceInterface retrieves a device's interface by ID
+func (s *InterfaceService) GetDeviceInterface(deviceID, interfaceID int) (*models.DeviceInterface, error) {
+	iface, err := s.GetInterfaceByID(interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if iface.DeviceID != deviceID {
+		return nil, store.ErrInterfaceNotFound
+	}
+	return iface, nil
+}
+
+// CreateInterface adds an interface to a device
+func (s *InterfaceService) CreateInterface(deviceID int, req models.CreateDeviceInterfaceRequest) (*models.DeviceInterface, error) {
+	if _, err := s.devices.GetDevice(deviceID); err != nil {
+		return nil, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	rearPortID, err := s.checkRearPort(deviceID, nil, req.RearPortID)
+	if err != nil {
+		return nil, err
+	}
+
+	iface := &models.DeviceInterface{
+		DeviceID:    deviceID,
+		Name:        req.Name,
+		Type:        req.Type,
+		Speed:       req.Speed,
+		MACAddress:  req.MACAddress,
+		Enabled:     enabled,
+		Description: req.Description,
+		RearPortID:  rearPortID,
+	}
+	if err := s.interfaces.CreateInterface(iface); err != nil {
+		return nil, err
+	}
+
+	return iface, nil
+}
+
+// templateInterfaces returns one interface per port template for a new device, leaving out
+// repeated names
+func templateInterfaces(ports []models.PortTemplate) []*models.DeviceInterface {
+	var interfaces []*models.DeviceInterface
+	names := map[string]bool{}
+	for _, port := range ports {
+		if names[port.Name] {
+			continue
+		}
+		names[port.Name] = true
+		interfaces = append(interfaces, &models.DeviceInterface{Name: port.Name, Type: port.Type, Speed: port.Speed, Enabled: true})
+	}
+	return interfaces
+}
+
+// UpdateInterface updates a device's interface
+func (s *InterfaceService) UpdateInterface(deviceID, interfaceID int, req models.UpdateDeviceInterfaceRequest) (*models.DeviceInterface, error) {
+	current, err := s.GetDeviceInterface(deviceID, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			return nil, fmt.Errorf("interface name cannot be empty")
+		}
+		current.Name = *req.Name
+	}
+	if req.Type != nil {
+		current.Type = *req.Type
+	}
+	if req.Speed != nil {
+		current.Speed = *req.Speed
+	}
+	if req.MACAddress != nil {
+		current.MACAddress = *req.MACAddress
+	}
+	if req.Enabled != nil {
+		current.Enabled = *req.Enabled
+	}
+	if req.Description != nil {
+		current.Description = *req.Description
+	}
+	if req.RearPortID != nil {
+		if current.RearPortID, err = s.checkRearPort(deviceID, current, req.RearPortID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.interfaces.UpdateInterface(current); err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// checkRearPort verifies that a front port (nil while it is being created) can be paired with
+// a rear port: both on the same device, the rear port not itself a front port or already paired.
+// A nil or 0 rear port ID means unpaired.
+func (s *InterfaceService) checkRearPort(deviceID int, front *models.DeviceInterface, rearPortID *int) (*int, error) {
+	if rearPortID == nil || *rearPortID == 0 {
+		return nil, nil
+	}
+
+	rear, err := s.GetInterfaceByID(*rearPortID)
+	if err != nil {
+		return nil, fmt.Errorf("rear port not found")
+	}
+	if rear.DeviceID != deviceID {
+		return nil, fmt.Errorf("rear port %s is on a different device", rear.Name)
+	}
+	if front != nil {
+		if rear.ID == front.ID {
+			return nil, fmt.Errorf("an interface cannot be its own rear port")
+		}
+		if front.FrontPortID != nil {
+			return nil, fmt.Errorf("interface %s is already the rear port of interface %d", front.Name, *front.FrontPortID)
+		}
+	}
+	if rear.RearPortID != nil {
+		return nil, fmt.Errorf("rear port %s is itself a front port", rear.Name)
+	}
+	if rear.FrontPortID != nil && (front == nil || *rear.FrontPortID != front.ID) {
+		return nil, fmt.Errorf("rear port %s is already paired with interface %d", rear.Name, *rear.FrontPortID)
+	}
+
+	return rearPortID, nil
+}
+
+// DeleteInterface removes an interface from a device; connections on it are detached, not deleted
+func (s *InterfaceService) DeleteInterface(deviceID, interfaceID int) error {
+	if _, err := s.GetDeviceInterface(deviceID, interfaceID); err != nil {
+		return err
+	}
+	return s.interfaces.DeleteInterface(interfaceID)
+}
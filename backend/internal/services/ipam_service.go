@@ -0,0 +1,489 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"sort"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// parseHostAddress parses a single IPv4 or IPv6 address, without a prefix length
+func parseHostAddress(value string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(value)
+	if err != nil || addr.Zone() != "" {
+		return netip.Addr{}, fmt.Errorf("invalid ip address %q", value)
+	}
+	return addr.Unmap(), nil
+}
+
+// parsePrefix parses a prefix in CIDR notation and rejects host bits
+func parsePrefix(value string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid prefix %q (expected CIDR notation, e.g. 10.0.0.0/24)", value)
+	}
+	if masked := prefix.Masked(); masked != prefix {
+		return netip.Prefix{}, fmt.Errorf("prefix %s has host bits set (did you mean %s?)", prefix, masked)
+	}
+	return prefix, nil
+}
+
+// usableRange returns the first and last assignable addresses of a prefix. IPv4 subnets larger
+// than /31 lose their network and broadcast addresses; IPv6 subnets lose the subnet-router anycast address.
+func usableRange(prefix netip.Prefix) (netip.Addr, netip.Addr, float64) {
+	first := prefix.Addr()
+	hostBits := first.BitLen() - prefix.Bits()
+	last := first
+	for i := 0; i < hostBits; i++ {
+		// Set the host bits one at a time from the right
+		bytes := last.AsSlice()
+		bit := first.BitLen() - 1 - i
+		bytes[bit/8] |= 1 << (7 - bit%8)
+		last, _ = netip.AddrFromSlice(bytes)
+	}
+
+	size := math.Pow(2, float64(hostBits))
+	switch {
+	case first.Is4() && hostBits >= 2:
+		return first.Next(), last.Prev(), size - 2
+	case first.Is6() && hostBits >= 2:
+		return first.Next(), last, size - 1
+	}
+	return first, last, size
+}
+
+// addressUse is one place an address is assigned: an ip_addresses row or a device ip_address field
+type addressUse struct {
+	addr       netip.Addr
+	assignment models.IPAssignment
+}
+
+// IPAMService handles prefix and IP address business logic
+type IPAMService struct {
+	ipam       store.IPAMStore
+	devices    store.DeviceStore
+	interfaces *InterfaceService
+}
+
+// NewIPAMService creates a new IPAM service
+func NewIPAMService(ipam store.IPAMStore, devices store.DeviceStore, interfaces store.InterfaceStore) *IPAMService {
+	return &IPAMService{ipam: ipam, devices: devices, interfaces: NewInterfaceService(interfaces, devices)}
+}
+
+// loadAddressUses loads every assigned address. Device ip_address values that do not parse
+// are returned as invalid conflicts.
+func (s *IPAMService) loadAddressUses() ([]addressUse, []models.IPConflict, error) {
+	var uses []addressUse
+	invalid := []models.IPConflict{}
+
+	devices, err := s.devices.ListDevices(store.DeviceFilter{})
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
+	deviceNames := map[int]string{}
+	for _, device := range devices {
+		deviceNames[device.ID] = device.Name
+	}
+
+	addresses, err := s.ipam.ListIPAddresses(models.IPAddressFilter{})
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].ID < addresses[j].ID })
+	for _, address := range addresses {
+		id := address.ID
+		use := addressUse{assignment: models.IPAssignment{
+			IPAddressID: &id,
+			DeviceID:    address.DeviceID,
+			DeviceName:  deviceNames[address.DeviceID],
+			InterfaceID: address.InterfaceID,
+		}}
+		if use.addr, err = parseHostAddress(address.Address); err != nil {
+			return nil, nil, err
+		}
+		uses = append(uses, use)
+	}
+
+	for _, device := range devices {
+		if device.IPAddress == "" {
+			continue
+		}
+		use := addressUse{assignment: models.IPAssignment{DeviceID: device.ID, DeviceName: device.Name}}
+		if use.addr, err = parseHostAddress(device.IPAddress); err != nil {
+			invalid = append(invalid, models.IPConflict{
+				Address:     device.IPAddress,
+				Kind:        models.IPConflictInvalid,
+				Message:     err.Error(),
+				Assignments: []models.IPAssignment{use.assignment},
+			})
+			continue
+		}
+		uses = append(uses, use)
+	}
+
+	return uses, invalid, nil
+}
+
+// checkIPAddressFree returns an error if addr is assigned to a device other than deviceID,
+// ignoring the ip_addresses row being updated. A device may repeat its own ip_address on one
+// of its interfaces. The store rejects the same address twice in ip_addresses or twice in
+// devices, so an address taken after this check still fails the write.
+func (s *IPAMService) checkIPAddressFree(addr netip.Addr, deviceID *int, excludeIPAddressID *int) error {
+	devices, err := s.devices.ListDevices(store.DeviceFilter{IPAddress: addr.String()})
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if deviceID == nil || device.ID != *deviceID {
+			return fmt.Errorf("ip address %s is already assigned to %s", addr, device.Name)
+		}
+	}
+
+	addresses, err := s.ipam.ListIPAddresses(models.IPAddressFilter{Address: addr.String()})
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if excludeIPAddressID != nil && address.ID == *excludeIPAddressID {
+			continue
+		}
+		if deviceID != nil && address.DeviceID == *deviceID {
+			continue
+		}
+		device, err := s.devices.GetDevice(address.DeviceID)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("ip address %s is already assigned to %s", addr, device.Name)
+	}
+
+	return nil
+}
+
+// checkDeviceIPAddress validates a device's ip_address and returns it in canonical form
+func (s *IPAMService) checkDeviceIPAddress(value string, deviceID *int) (string, error) {
+	addr, err := parseHostAddress(value)
+	if err != nil {
+		return "", err
+	}
+	if err := s.checkIPAddressFree(addr, deviceID, nil); err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+// ipamError maps the store's uniqueness errors to messages naming the value
+func ipamError(err error, value string) error {
+	switch {
+	case errors.Is(err, store.ErrPrefixExists):
+		return fmt.Errorf("prefix %s already exists", value)
+	case errors.Is(err, store.ErrIPAddressInUse):
+		return fmt.Errorf("ip address %s is already assigned", value)
+	}
+	return err
+}
+
+// GetAllPrefixes retrieves all prefixes with their utilization
+func (s *IPAMService) GetAllPrefixes() ([]models.IPPrefix, error) {
+	prefixes, err := s.ipam.ListPrefixes()
+	if err != nil {
+		return nil, err
+	}
+	if prefixes == nil {
+		prefixes = []models.IPPrefix{}
+	}
+
+	if err := s.fillUtilization(prefixes); err != nil {
+		return nil, err
+	}
+	return prefixes, nil
+}
+
+// GetPrefixByID retrieves a prefix by ID with its utilization
+func (s *IPAMService) GetPrefixByID(id int) (*models.IPPrefix, error) {
+	prefix, err := s.ipam.GetPrefix(id)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := []models.IPPrefix{*prefix}
+	if err := s.fillUtilization(prefixes); err != nil {
+		return nil, err
+	}
+	return &prefixes[0], nil
+}
+
+// fillUtilization counts the distinct assigned addresses inside each prefix
+func (s *IPAMService) fillUtilization(prefixes []models.IPPrefix) error {
+	if len(prefixes) == 0 {
+		return nil
+	}
+	uses, _, err := s.loadAddressUses()
+	if err != nil {
+		return err
+	}
+
+	for i := range prefixes {
+		prefix, err := netip.ParsePrefix(prefixes[i].Prefix)
+		if err != nil {
+			return fmt.Errorf("failed to parse prefix %s: %w", prefixes[i].Prefix, err)
+		}
+		used := map[netip.Addr]bool{}
+		for _, use := range uses {
+			if prefix.Contains(use.addr) {
+				used[use.addr] = true
+			}
+		}
+		_, _, size := usableRange(prefix)
+		prefixes[i].Used = len(used)
+		if size > 0 {
+			prefixes[i].Utilization = float64(len(used)) / size
+		}
+	}
+	return nil
+}
+
+// CreatePrefix creates a new prefix
+func (s *IPAMService) CreatePrefix(req models.CreateIPPrefixRequest) (*models.IPPrefix, error) {
+	value, err := parsePrefix(req.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := &models.IPPrefix{Prefix: value.String(), Description: req.Description}
+	if err := s.ipam.CreatePrefix(prefix); err != nil {
+		return nil, ipamError(err, value.String())
+	}
+	return s.GetPrefixByID(prefix.ID)
+}
+
+// UpdatePrefix updates an existing prefix
+func (s *IPAMService) UpdatePrefix(id int, req models.UpdateIPPrefixRequest) (*models.IPPrefix, error) {
+	prefix, err := s.ipam.GetPrefix(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Prefix != nil {
+		value, err := parsePrefix(*req.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		prefix.Prefix = value.String()
+	}
+	if req.Description != nil {
+		prefix.Description = *req.Description
+	}
+
+	if err := s.ipam.UpdatePrefix(prefix); err != nil {
+		return nil, ipamError(err, prefix.Prefix)
+	}
+	return s.GetPrefixByID(id)
+}
+
+// DeletePrefix deletes a prefix. The store points its addresses at the next most specific prefix
+// containing them, or at none.
+func (s *IPAMService) DeletePrefix(id int) error {
+	return s.ipam.DeletePrefix(id)
+}
+
+// GetAvailableAddresses returns up to count free addresses of a prefix, lowest first
+func (s *IPAMService) GetAvailableAddresses(id, count int) ([]string, error) {
+	record, err := s.GetPrefixByID(id)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := netip.ParsePrefix(record.Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prefix %s: %w", record.Prefix, err)
+	}
+
+	uses, _, err := s.loadAddressUses()
+	if err != nil {
+		return nil, err
+	}
+	used := map[netip.Addr]bool{}
+	for _, use := range uses {
+		used[use.addr] = true
+	}
+
+	available := []string{}
+	first, last, _ := usableRange(prefix)
+	for addr := first; addr.IsValid() && addr.Compare(last) <= 0 && len(available) < count; addr = addr.Next() {
+		if !used[addr] {
+			available = append(available, addr.String())
+		}
+	}
+
+	return available, nil
+}
+
+// GetAllIPAddresses retrieves address assignments, optionally for one device or prefix
+func (s *IPAMService) GetAllIPAddresses(filter models.IPAddressFilter) ([]models.IPAddress, error) {
+	addresses, err := s.ipam.ListIPAddresses(filter)
+	if err != nil {
+		return nil, err
+	}
+	if addresses == nil {
+		addresses = []models.IPAddress{}
+	}
+	return addresses, nil
+}
+
+// GetIPAddressByID retrieves an address assignment by ID
+func (s *IPAMService) GetIPAddressByID(id int) (*models.IPAddress, error) {
+	return s.ipam.GetIPAddress(id)
+}
+
+// CreateIPAddress assigns an address to a device
+func (s *IPAMService) CreateIPAddress(req models.CreateIPAddressRequest) (*models.IPAddress, error) {
+	addr, err := parseHostAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.devices.GetDevice(req.DeviceID); err != nil {
+		return nil, err
+	}
+	interfaceID, err := s.checkAddressInterface(req.InterfaceID, req.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkIPAddressFree(addr, &req.DeviceID, nil); err != nil {
+		return nil, err
+	}
+
+	address := &models.IPAddress{
+		Address:     addr.String(),
+		DeviceID:    req.DeviceID,
+		InterfaceID: interfaceID,
+		Description: req.Description,
+	}
+	if err := s.ipam.CreateIPAddress(address); err != nil {
+		return nil, ipamError(err, addr.String())
+	}
+
+	return address, nil
+}
+
+// UpdateIPAddress updates an address assignment
+func (s *IPAMService) UpdateIPAddress(id int, req models.UpdateIPAddressRequest) (*models.IPAddress, error) {
+	current, err := s.GetIPAddressByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Address != nil {
+		addr, err := parseHostAddress(*req.Address)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkIPAddressFree(addr, &current.DeviceID, &id); err != nil {
+			return nil, err
+		}
+		current.Address = addr.String()
+	}
+	if req.InterfaceID != nil {
+		if current.InterfaceID, err = s.checkAddressInterface(req.InterfaceID, current.DeviceID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		current.Description = *req.Description
+	}
+
+	if err := s.ipam.UpdateIPAddress(current); err != nil {
+		return nil, ipamError(err, current.Address)
+	}
+
+	return current, nil
+}
+
+// DeleteIPAddress removes an address assignment
+func (s *IPAMService) DeleteIPAddress(id int) error {
+	return s.ipam.DeleteIPAddress(id)
+}
+
+// checkAddressInterface verifies that an interface belongs to the device. A nil or 0 ID means none.
+func (s *IPAMService) checkAddressInterface(interfaceID *int, deviceID int) (*int, error) {
+	if interfaceID == nil || *interfaceID == 0 {
+		return nil, nil
+	}
+	if _, err := s.interfaces.GetDeviceInterface(deviceID, *interfaceID); err != nil {
+		return nil, err
+	}
+	return interfaceID, nil
+}
+
+// GetConflicts reports duplicate, out-of-subnet and unparseable addresses. Addresses are only
+// reported as out of subnet once at least one prefix exists.
+func (s *IPAMService) GetConflicts() ([]models.IPConflict, error) {
+	uses, conflicts, err := s.loadAddressUses()
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.ipam.ListPrefixes()
+	if err != nil {
+		return nil, err
+	}
+	var prefixes []netip.Prefix
+	for _, record := range records {
+		prefix, err := netip.ParsePrefix(record.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse prefix %s: %w", record.Prefix, err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	byAddress := map[netip.Addr][]models.IPAssignment{}
+	var addrs []netip.Addr
+	for _, use := range uses {
+		if _, seen := byAddress[use.addr]; !seen {
+			addrs = append(addrs, use.addr)
+		}
+		byAddress[use.addr] = append(byAddress[use.addr], use.assignment)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].Less(addrs[j]) })
+
+	for _, addr := range addrs {
+		assignments := byAddress[addr]
+		devices := map[int]bool{}
+		for _, assignment := range assignments {
+			devices[assignment.DeviceID] = true
+		}
+		if len(devices) > 1 {
+			conflicts = append(conflicts, models.IPConflict{
+				Address:     addr.String(),
+				Kind:        models.IPConflictDuplicate,
+				Message:     fmt.Sprintf("ip address %s is assigned to %d devices", addr, len(devices)),
+				Assignments: assignments,
+			})
+		}
+
+		if len(prefixes) == 0 {
+			continue
+		}
+		inPrefix := false
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				inPrefix = true
+				break
+			}
+		}
+		if !inPrefix {
+			conflicts = append(conflicts, models.IPConflict{
+				Address:     addr.String(),
+				Kind:        models.IPConflictOutOfSubnet,
+				Message:     fmt.Sprintf("ip address %s is not in any prefix", addr),
+				Assignments: assignments,
+			})
+		}
+	}
+
+	return conflicts, nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"math"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func TestUsableRange(t *testing.T) {
+	tests := []struct {
+		prefix    string
+		wantFirst string
+		wantLast  string
+		wantSize  float64
+	}{
+		{"10.0.0.0/24", "10.0.0.1", "10.0.0.254", 254},
+		{"10.0.0.0/30", "10.0.0.1", "10.0.0.2", 2},
+		// Point-to-point and host prefixes keep every address
+		{"10.0.0.0/31", "10.0.0.0", "10.0.0.1", 2},
+		{"10.0.0.7/32", "10.0.0.7", "10.0.0.7", 1},
+		{"0.0.0.0/0", "0.0.0.1", "255.255.255.254", math.Pow(2, 32) - 2},
+		// IPv6 has no broadcast address, only the subnet-router anycast address at the start
+		{"2001:db8::/64", "2001:db8::1", "2001:db8::ffff:ffff:ffff:ffff", math.Pow(2, 64) - 1},
+		{"2001:db8::/126", "2001:db8::1", "2001:db8::3", 3},
+		{"2001:db8::/127", "2001:db8::", "2001:db8::1", 2},
+		{"2001:db8::1/128", "2001:db8::1", "2001:db8::1", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			first, last, size := usableRange(netip.MustParsePrefix(tt.prefix))
+			if first.String() != tt.wantFirst || last.String() != tt.wantLast || size != tt.wantSize {
+				t.Errorf("usableRange(%s) = %s, %s, %v, want %s, %s, %v",
+					tt.prefix, first, last, size, tt.wantFirst, tt.wantLast, tt.wantSize)
+			}
+		})
+	}
+}
+
+func mustCreatePrefix(t *testing.T, service *IPAMService, prefix string) *models.IPPrefix {
+	t.Helper()
+	created, err := service.CreatePrefix(models.CreateIPPrefixRequest{Prefix: prefix})
+	if err != nil {
+		t.Fatalf("CreatePrefix(%s): %v", prefix, err)
+	}
+	return created
+}
+
+// mustAssignIPAddress writes an assignment straight to the store, skipping the service's checks
+func mustAssignIPAddress(t *testing.T, s *store.Store, deviceID int, address string) *models.IPAddress {
+	t.Helper()
+	assignment := &models.IPAddress{Address: address, DeviceID: deviceID}
+	if err := s.IPAM.CreateIPAddress(assignment); err != nil {
+		t.Fatalf("CreateIPAddress(%s): %v", address, err)
+	}
+	return assignment
+}
+
+func TestPrefixUtilization(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 1)
+	device.IPAddress = "192.0.2.1"
+	mustCreateDevice(t, s, device)
+	// The device's own address repeated on an assignment is counted once
+	mustAssignIPAddress(t, s, device.ID, "192.0.2.1")
+	mustAssignIPAddress(t, s, device.ID, "192.0.2.3")
+	mustAssignIPAddress(t, s, device.ID, "198.51.100.1")
+
+	service := NewIPAMService(s.IPAM, s.Devices, s.Interfaces)
+	prefix := mustCreatePrefix(t, service, "192.0.2.0/29")
+	if prefix.Used != 2 || prefix.Utilization != 2.0/6 {
+		t.Errorf("used = %d (%v), want 2 of the 6 usable addresses", prefix.Used, prefix.Utilization)
+	}
+
+	available, err := service.GetAvailableAddresses(prefix.ID, 3)
+	if err != nil {
+		t.Fatalf("GetAvailableAddresses: %v", err)
+	}
+	if want := []string{"192.0.2.2", "192.0.2.4", "192.0.2.5"}; !reflect.DeepEqual(available, want) {
+		t.Errorf("available = %v, want %v", available, want)
+	}
+	// The broadcast address is never offered
+	available, err = service.GetAvailableAddresses(prefix.ID, 10)
+	if err != nil {
+		t.Fatalf("GetAvailableAddresses: %v", err)
+	}
+	if want := []string{"192.0.2.2", "192.0.2.4", "192.0.2.5", "192.0.2.6"}; !reflect.DeepEqual(available, want) {
+		t.Errorf("available = %v, want %v", available, want)
+	}
+}
+
+func TestCreateIPAddressRejectsTakenAddresses(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	web := newDevice(rack.ID, "web-1", 10, 1)
+	web.IPAddress = "192.0.2.1"
+	mustCreateDevice(t, s, web)
+	db := mustCreateDevice(t, s, newDevice(rack.ID, "db-1", 11, 1))
+	service := NewIPAMService(s.IPAM, s.Devices, s.Interfaces)
+
+	tests := []struct {
+		name     string
+		deviceID int
+		address  string
+		wantErr  string
+	}{
+		{"another device's ip address", db.ID, "192.0.2.1", "already assigned to web-1"},
+		{"same address in another form", db.ID, "::ffff:192.0.2.1", "already assigned to web-1"},
+		{"not an address", db.ID, "192.0.2.0/24", "invalid ip address"},
+		{"a device repeating its own address", web.ID, "192.0.2.1", ""},
+		{"a free address", db.ID, "192.0.2.2", ""},
+		{"an address taken by an assignment", web.ID, "192.0.2.2", "already assigned to db-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateIPAddress(models.CreateIPAddressRequest{Address: tt.address, DeviceID: tt.deviceID})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CreateIPAddress: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CreateIPAddress: err = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// conflictKinds describes each conflict as "address kind"
+func conflictKinds(conflicts []models.IPConflict) []string {
+	kinds := []string{}
+	for _, conflict := range conflicts {
+		kinds = append(kinds, conflict.Address+" "+string(conflict.Kind))
+	}
+	return kinds
+}
+
+func TestGetConflicts(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	web := newDevice(rack.ID, "web-1", 10, 1)
+	web.IPAddress = "10.0.0.5"
+	mustCreateDevice(t, s, web)
+	db := mustCreateDevice(t, s, newDevice(rack.ID, "db-1", 11, 1))
+	broken := newDevice(rack.ID, "broken", 12, 1)
+	broken.IPAddress = "10.0.0.300"
+	mustCreateDevice(t, s, broken)
+
+	// db-1 was assigned web-1's address; web-1's own assignments are no conflict
+	mustAssignIPAddress(t, s, db.ID, "10.0.0.5")
+	mustAssignIPAddress(t, s, web.ID, "10.0.0.6")
+	mustAssignIPAddress(t, s, web.ID, "192.168.1.1")
+
+	service := NewIPAMService(s.IPAM, s.Devices, s.Interfaces)
+	conflicts, err := service.GetConflicts()
+	if err != nil {
+		t.Fatalf("GetConflicts: %v", err)
+	}
+	// Without prefixes nothing is out of subnet
+	if got, want := conflictKinds(conflicts), []string{"10.0.0.300 invalid", "10.0.0.5 duplicate"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("conflicts = %v, want %v", got, want)
+	}
+	if got := conflicts[0].Assignments; len(got) != 1 || got[0].DeviceID != broken.ID {
+		t.Errorf("invalid address assignments = %+v, want the broken device", got)
+	}
+	duplicate := conflicts[1]
+	if len(duplicate.Assignments) != 2 || duplicate.Assignments[0].DeviceID != db.ID || duplicate.Assignments[0].IPAddressID == nil ||
+		duplicate.Assignments[1].DeviceID != web.ID || duplicate.Assignments[1].IPAddressID != nil {
+		t.Errorf("duplicate assignments = %+v, want db-1's assignment then web-1's ip address", duplicate.Assignments)
+	}
+	if duplicate.Message != "ip address 10.0.0.5 is assigned to 2 devices" {
+		t.Errorf("duplicate message = %q", duplicate.Message)
+	}
+
+	// Once a prefix exists, addresses outside every prefix are reported in address order
+	mustCreatePrefix(t, service, "10.0.0.0/24")
+	mustCreatePrefix(t, service, "172.16.0.0/12")
+	conflicts, err = service.GetConflicts()
+	if err != nil {
+		t.Fatalf("GetConflicts: %v", err)
+	}
+	want := []string{"10.0.0.300 invalid", "10.0.0.5 duplicate", "192.168.1.1 out_of_subnet"}
+	if got := conflictKinds(conflicts); !reflect.DeepEqual(got, want) {
+		t.Errorf("conflicts with prefixes = %v, want %v", got, want)
+	}
+}
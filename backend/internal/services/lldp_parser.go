@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"rackview/internal/models"
+)
+
+// aristaNeighborsHeader starts the neighbors of one interface in EOS `show lldp neighbors detail`
+var aristaNeighborsHeader = regexp.MustCompile(`(?i)^interface\s+(\S+)\s+detected\s+\d+\s+lldp\s+neighbors?`)
+
+// lldpField names the neighbor field a CLI key fills
+type lldpField int
+
+const (
+	lldpLocalPort lldpField = iota
+	// lldpLocalPortID names the local port in NX-OS output; Junos repeats it as an ifIndex after
+	// "Local Interface", so it only fills a local port that is still empty
+	lldpLocalPortID
+	lldpSystemName
+	lldpChassisID
+	lldpPortID
+	lldpPortDescription
+	lldpManagementIP
+)
+
+// lldpKeys maps the normalized keys used by IOS, NX-OS, EOS and Junos LLDP output and by
+// IOS CDP output to neighbor fields
+var lldpKeys = map[string]lldpField{
+	"local intf":              lldpLocalPort,
+	"local interface":         lldpLocalPort,
+	"local port":              lldpLocalPort,
+	"interface":               lldpLocalPort,
+	"local port id":           lldpLocalPortID,
+	"system name":             lldpSystemName,
+	"device id":               lldpSystemName,
+	"chassis id":              lldpChassisID,
+	"port id":                 lldpPortID,
+	"port id (outgoing port)": lldpPortID,
+	"port description":        lldpPortDescription,
+	"management address":      lldpManagementIP,
+	"management addresses":    lldpManagementIP,
+	"ip":                      lldpManagementIP,
+	"ip address":              lldpManagementIP,
+}
+
+// lldpRecordKeys are the fields that identify a neighbor; seeing one again starts the next entry
+var lldpRecordKeys = map[lldpField]bool{
+	lldpLocalPort:  true,
+	lldpSystemName: true,
+	lldpChassisID:  true,
+	lldpPortID:     true,
+}
+
+// lldpParser accumulates neighbor entries from `key: value` lines
+type lldpParser struct {
+	neighbors []models.LLDPNeighbor
+	current   models.LLDPNeighbor
+	seen      map[lldpField]bool
+	// headerPort is the local port of an EOS interface header, shared by its neighbors
+	headerPort string
+}
+
+// flush ends the current entry, keeping it when it names a local port and a neighbor
+func (p *lldpParser) flush() {
+	if p.current.LocalPort == "" {
+		p.current.LocalPort = p.headerPort
+	}
+	if p.current.LocalPort != "" && (p.current.RemoteSystemName != "" || p.current.RemoteChassisID != "") {
+		p.neighbors = append(p.neighbors, p.current)
+	}
+	p.current = models.LLDPNeighbor{}
+	p.seen = map[lldpField]bool{}
+}
+
+// set fills a field of the current entry, starting a new entry when an identifying field repeats
+func (p *lldpParser) set(field lldpField, value string) {
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	// Junos lists a port ID under the management address and EOS one under link aggregation;
+	// neither is the neighbor's port
+	if field == lldpPortID && p.seen[field] && p.current.RemoteManagementIP != "" {
+		return
+	}
+	if lldpRecordKeys[field] && p.seen[field] {
+		p.flush()
+	}
+	p.seen[field] = true
+
+	switch field {
+	case lldpLocalPort:
+		p.current.LocalPort = value
+	case lldpLocalPortID:
+		if p.current.LocalPort == "" {
+			p.current.LocalPort = value
+		}
+	case lldpSystemName:
+		p.current.RemoteSystemName = value
+	case lldpChassisID:
+		p.current.RemoteChassisID = value
+	case lldpPortID:
+		p.current.RemotePortID = value
+	case lldpPortDescription:
+		p.current.RemotePortDescription = value
+	case lldpManagementIP:
+		// CDP repeats the address under "Management address(es)"; the first one wins
+		if p.current.RemoteManagementIP == "" {
+			p.current.RemoteManagementIP = value
+		}
+	}
+}
+
+// ParseLLDPNeighbors parses `show lldp neighbors detail` or `show cdp neighbors detail` output.
+//
+// Entries are read as `key: value` lines regardless of vendor; an entry ends at a separator
+// line, at an EOS interface header or when one of its identifying fields appears again.
+func ParseLLDPNeighbors(output string) ([]models.LLDPNeighbor, error) {
+	p := &lldpParser{seen: map[lldpField]bool{}}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.Trim(line, "-=") == "" {
+			p.flush()
+			p.headerPort = ""
+			continue
+		}
+		if match := aristaNeighborsHeader.FindStringSubmatch(line); match != nil {
+			p.flush()
+			p.headerPort = match[1]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.Join(strings.Fields(strings.TrimLeft(key, "- ")), " "))
+		field, ok := lldpKeys[key]
+		if !ok {
+			continue
+		}
+
+		// CDP puts both ports on one line: "Interface: Gi1/0/1,  Port ID (outgoing port): Gi0/1"
+		if field == lldpLocalPort {
+			if local, rest, found := strings.Cut(value, ","); found {
+				if restKey, restValue, found := strings.Cut(rest, ":"); found {
+					p.set(field, local)
+					if restField, ok := lldpKeys[strings.ToLower(strings.TrimSpace(restKey))]; ok {
+						p.set(restField, restValue)
+					}
+					continue
+				}
+			}
+		}
+		p.set(field, value)
+	}
+	p.flush()
+
+	if len(p.neighbors) == 0 {
+		return nil, fmt.Errorf("no LLDP or CDP neighbors found in output")
+	}
+	return p.neighbors, nil
+}
+
+// interfaceAbbreviations expands the interface name prefixes switches abbreviate in neighbor tables
+var interfaceAbbreviations = map[string]string{
+	"gi":   "gigabitethernet",
+	"gig":  "gigabitethernet",
+	"te":   "tengigabitethernet",
+	"ten":  "tengigabitethernet",
+	"fa":   "fastethernet",
+	"et":   "ethernet",
+	"eth":  "ethernet",
+	"twe":  "twentyfivegige",
+	"fo":   "fortygigabitethernet",
+	"hu":   "hundredgige",
+	"po":   "port-channel",
+	"ma":   "management",
+	"mgmt": "management",
+}
+
+// interfaceKey normalizes an interface name so "Gi1/0/1" and "GigabitEthernet1/0/1" compare equal
+func interfaceKey(name string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(name), ""))
+	i := strings.IndexFunc(key, func(r rune) bool { return r < 'a' || r > 'z' })
+	if i < 0 {
+		return key
+	}
+	if full, ok := interfaceAbbreviations[key[:i]]; ok {
+		return full + key[i:]
+	}
+	return key
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"rackview/internal/models"
+)
+
+const iosLLDPOutput = `
+Capability codes:
+    (R) Router, (B) Bridge, (T) Telephone, (C) DOCSIS Cable Device
+    (W) WLAN Access Point, (P) Repeater, (S) Station, (O) Other
+
+------------------------------------------------
+Local Intf: Gi1/0/1
+Chassis id: 0050.56ab.cd01
+Port id: Gi0/1
+Port Description: GigabitEthernet0/1
+System Name: access-1.example.com
+
+System Description:
+Cisco IOS Software, C2960 Software (C2960-LANBASEK9-M), Version 15.0(2)SE, RELEASE SOFTWARE (fc1)
+Technical Support: http://www.cisco.com/techsupport
+Copyright (c) 1986-2012 by Cisco Systems, Inc.
+Compiled Sat 28-Jul-12 00:29 by prod_rel_team
+
+Time remaining: 105 seconds
+System Capabilities: B
+Enabled Capabilities: B
+Management Addresses:
+    IP: 10.0.0.21
+Auto Negotiation - supported, enabled
+Physical media capabilities:
+    1000baseT(FD)
+    100base-TX(FD)
+Media Attachment Unit type: 30
+Vlan ID: 1
+
+------------------------------------------------
+Local Intf: Gi1/0/2
+Chassis id: 0050.56ab.cd02
+Port id: Gi0/1
+Port Description: GigabitEthernet0/1
+System Name: access-2.example.com
+
+System Description:
+Cisco IOS Software, C2960 Software (C2960-LANBASEK9-M), Version 15.0(2)SE, RELEASE SOFTWARE (fc1)
+
+Time remaining: 98 seconds
+System Capabilities: B
+Enabled Capabilities: B
+Management Addresses:
+    IP: 10.0.0.22
+Auto Negotiation - supported, enabled
+Vlan ID: 1
+
+
+Total entries displayed: 2
+`
+
+const nxosLLDPOutput = `
+Capability codes:
+  (R) Router, (B) Bridge, (T) Telephone, (C) DOCSIS Cable Device
+  (W) WLAN Access Point, (P) Repeater, (S) Station, (O) Other
+Device ID            Local Intf      Hold-time  Capability  Port ID
+
+Chassis id: 5254.0012.3401
+Port id: Ethernet1/49
+Local Port id: Eth1/49
+Port Description: Ethernet1/49
+System Name: spine-1
+System Description: Cisco Nexus Operating System (NX-OS) Software 9.3(8)
+TAC support: http://www.cisco.com/tac
+Copyright (c) 2002-2021, Cisco Systems, Inc. All rights reserved.
+Time remaining: 113 seconds
+System Capabilities: B, R
+Enabled Capabilities: B, R
+Management Address: 10.0.0.1
+Management Address IPV6: not advertised
+Vlan ID: not advertised
+
+
+Chassis id: 5254.0012.3402
+Port id: Ethernet1/49
+Local Port id: Eth1/50
+Port Description: Ethernet1/49
+System Name: spine-2
+System Description: Cisco Nexus Operating System (NX-OS) Software 9.3(8)
+TAC support: http://www.cisco.com/tac
+Copyright (c) 2002-2021, Cisco Systems, Inc. All rights reserved.
+Time remaining: 101 seconds
+System Capabilities: B, R
+Enabled Capabilities: B, R
+Management Address: 10.0.0.2
+Management Address IPV6: not advertised
+Vlan ID: not advertised
+
+Total entries displayed: 2
+`
+
+const eosLLDPOutput = `
+Interface Ethernet1 detected 1 LLDP neighbors:
+
+  Neighbor 001c.7300.0001/Ethernet1, age 3 seconds
+  Discovered 1 day, 2:03:04 ago; Last changed 1 day, 2:03:04 ago
+  - Chassis ID type: MAC address (4)
+    Chassis ID     : 001c.7300.0001
+  - Port ID type: Interface name (5)
+    Port ID        : "Ethernet1"
+  - Time To Live: 120 seconds
+  - Port Description: "to leaf-1"
+  - System Name: "leaf-2"
+  - System Description: "Arista Networks EOS version 4.27.0F running on an Arista Networks DCS-7050SX3-48YC8"
+  - System Capabilities : Bridge, Router
+    Enabled Capabilities: Bridge, Router
+  - Management Address Subtype: IPv4 (1)
+    Management Address        : 10.0.0.12
+    Interface Number Subtype  : ifIndex (2)
+    Interface Number          : 999001
+    OID String                :
+  - IEEE802.1 Port VLAN ID: 1
+  - IEEE802.3 Link Aggregation
+    Link Aggregation Status: Capable, Disabled (1)
+    Port ID                : 0
+  - IEEE802.3 Maximum Frame Size: 9236 bytes
+
+Interface Ethernet2 detected 0 LLDP neighbors:
+
+Interface Management1 detected 1 LLDP neighbors:
+
+  Neighbor 5254.0012.34ff/Gi1/0/48, age 14 seconds
+  Discovered 3 days, 4:05:06 ago; Last changed 3 days, 4:05:06 ago
+  - Chassis ID type: MAC address (4)
+    Chassis ID     : 5254.0012.34ff
+  - Port ID type: Interface name (5)
+    Port ID        : "Gi1/0/48"
+  - Time To Live: 120 seconds
+  - Port Description: "GigabitEthernet1/0/48"
+  - System Name: "oob-1.example.com"
+`
+
+const junosLLDPOutput = `
+LLDP Neighbor Information:
+Local Information:
+Index: 2 Time to live: 120 Time mark: Fri Oct  9 10:15:34 2020 Age: 17 secs
+Local Interface    : ge-0/0/0
+Parent Interface   : -
+Local Port ID      : 513
+Ageout Count       : 0
+
+Neighbour Information:
+Chassis type       : Mac address
+Chassis ID         : 00:05:86:71:e2:c0
+Port type          : Locally assigned
+Port ID            : 512
+Port description   : ge-0/0/1
+System name        : core-1
+
+System Description : Juniper Networks, Inc. ex4300-48t Ethernet Switch, kernel JUNOS 18.4R2.7
+
+System capabilities
+        Supported: Bridge Router
+        Enabled  : Bridge Router
+
+Management Info Type  : IPv4(1)
+Management Address    : 10.0.0.1
+Port ID               : 1
+Subtype               : 2
+Interface Subtype     : ifIndex(2)
+OID                   : 1.3.6.1.2.1.31.1.1.1.1.1
+
+LLDP Neighbor Information:
+Local Information:
+Index: 3 Time to live: 120 Time mark: Fri Oct  9 10:15:41 2020 Age: 10 secs
+Local Interface    : ge-0/0/1
+Parent Interface   : -
+Local Port ID      : 514
+Ageout Count       : 0
+
+Neighbour Information:
+Chassis type       : Mac address
+Chassis ID         : 00:05:86:71:e2:d0
+Port type          : Locally assigned
+Port ID            : 512
+Port description   : ge-0/0/1
+System name        : core-2
+`
+
+const iosCDPOutput = `
+-------------------------
+Device ID: core-1.example.com
+Entry address(es):
+  IP address: 10.0.0.1
+Platform: cisco WS-C3850-24T,  Capabilities: Router Switch IGMP
+Interface: GigabitEthernet1/0/24,  Port ID (outgoing port): GigabitEthernet1/0/1
+Holdtime : 150 sec
+
+Version :
+Cisco IOS Software, IOS-XE Software, Catalyst L3 Switch Software (CAT3K_CAA-UNIVERSALK9-M), Version 03.06.06E RELEASE SOFTWARE (fc1)
+Technical Support: http://www.cisco.com/techsupport
+Copyright (c) 1986-2016 by Cisco Systems, Inc.
+
+advertisement version: 2
+VTP Management Domain: ''
+Native VLAN: 1
+Duplex: full
+Management address(es):
+  IP address: 10.0.0.1
+
+-------------------------
+Device ID: ap-1
+Entry address(es):
+  IP address: 10.0.5.31
+Platform: cisco AIR-AP2802I-B-K9,  Capabilities: Trans-Bridge Source-Route-Bridge IGMP
+Interface: GigabitEthernet1/0/5,  Port ID (outgoing port): GigabitEthernet0
+Holdtime : 167 sec
+
+Version :
+Cisco AP Software, ap3g3-k9w8 Version: 8.5.151.0
+Technical Support: http://www.cisco.com/techsupport
+Copyright (c) 2014-2015 by Cisco Systems, Inc.
+
+advertisement version: 2
+Duplex: full
+Power drawn: 15.400 Watts
+Management address(es):
+  IP address: 10.0.5.31
+
+
+Total cdp entries displayed : 2
+`
+
+func TestParseLLDPNeighbors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []models.LLDPNeighbor
+	}{
+		{
+			name:   "IOS LLDP",
+			output: iosLLDPOutput,
+			want: []models.LLDPNeighbor{
+				{LocalPort: "Gi1/0/1", RemoteSystemName: "access-1.example.com", RemoteChassisID: "0050.56ab.cd01", RemotePortID: "Gi0/1", RemotePortDescription: "GigabitEthernet0/1", RemoteManagementIP: "10.0.0.21"},
+				{LocalPort: "Gi1/0/2", RemoteSystemName: "access-2.example.com", RemoteChassisID: "0050.56ab.cd02", RemotePortID: "Gi0/1", RemotePortDescription: "GigabitEthernet0/1", RemoteManagementIP: "10.0.0.22"},
+			},
+		},
+		{
+			name:   "NX-OS LLDP",
+			output: nxosLLDPOutput,
+			want: []models.LLDPNeighbor{
+				{LocalPort: "Eth1/49", RemoteSystemName: "spine-1", RemoteChassisID: "5254.0012.3401", RemotePortID: "Ethernet1/49", RemotePortDescription: "Ethernet1/49", RemoteManagementIP: "10.0.0.1"},
+				{LocalPort: "Eth1/50", RemoteSystemName: "spine-2", RemoteChassisID: "5254.0012.3402", RemotePortID: "Ethernet1/49", RemotePortDescription: "Ethernet1/49", RemoteManagementIP: "10.0.0.2"},
+			},
+		},
+		{
+			name:   "EOS LLDP",
+			output: eosLLDPOutput,
+			want: []models.LLDPNeighbor{
+				{LocalPort: "Ethernet1", RemoteSystemName: "leaf-2", RemoteChassisID: "001c.7300.0001", RemotePortID: "Ethernet1", RemotePortDescription: "to leaf-1", RemoteManagementIP: "10.0.0.12"},
+				{LocalPort: "Management1", RemoteSystemName: "oob-1.example.com", RemoteChassisID: "5254.0012.34ff", RemotePortID: "Gi1/0/48", RemotePortDescription: "GigabitEthernet1/0/48"},
+			},
+		},
+		{
+			name:   "Junos LLDP",
+			output: junosLLDPOutput,
+			want: []models.LLDPNeighbor{
+				{LocalPort: "ge-0/0/0", RemoteSystemName: "core-1", RemoteChassisID: "00:05:86:71:e2:c0", RemotePortID: "512", RemotePortDescription: "ge-0/0/1", RemoteManagementIP: "10.0.0.1"},
+				{LocalPort: "ge-0/0/1", RemoteSystemName: "core-2", RemoteChassisID: "00:05:86:71:e2:d0", RemotePortID: "512", RemotePortDescription: "ge-0/0/1"},
+			},
+		},
+		{
+			name:   "IOS CDP",
+			output: iosCDPOutput,
+			want: []models.LLDPNeighbor{
+				{LocalPort: "GigabitEthernet1/0/24", RemoteSystemName: "core-1.example.com", RemotePortID: "GigabitEthernet1/0/1", RemoteManagementIP: "10.0.0.1"},
+				{LocalPort: "GigabitEthernet1/0/5", RemoteSystemName: "ap-1", RemotePortID: "GigabitEthernet0", RemoteManagementIP: "10.0.5.31"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLLDPNeighbors(tt.output)
+			if err != nil {
+				t.Fatalf("ParseLLDPNeighbors: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLLDPNeighbors =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLLDPNeighborsWithoutNeighbors(t *testing.T) {
+	for _, output := range []string{"", "Interface Ethernet2 detected 0 LLDP neighbors:\n", "Total entries displayed: 0\n"} {
+		if _, err := ParseLLDPNeighbors(output); err == nil {
+			t.Errorf("ParseLLDPNeighbors(%q) succeeded, want an error", output)
+		}
+	}
+}
+
+func TestInterfaceKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"Gi1/0/1", "GigabitEthernet1/0/1"},
+		{"Eth1/49", "Ethernet1/49"},
+		{"Te1/1/1", "TenGigabitEthernet1/1/1"},
+		{"Po10", "Port-Channel10"},
+		{"Ma1", "Management1"},
+		{"ge-0/0/1", "GE-0/0/1"},
+	}
+	for _, tt := range tests {
+		if interfaceKey(tt.a) != interfaceKey(tt.b) {
+			t.Errorf("interfaceKey(%q) = %q, interfaceKey(%q) = %q, want equal", tt.a, interfaceKey(tt.a), tt.b, interfaceKey(tt.b))
+		}
+	}
+	if interfaceKey("Gi1/0/1") == interfaceKey("Gi1/0/10") {
+		t.Errorf("interfaceKey does not tell Gi1/0/1 from Gi1/0/10")
+	}
+}
@@ -0,0 +1,379 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// lldpDevice is a device that neighbor entries can resolve to
+type lldpDevice struct {
+	id         int
+	name       string
+	ipAddress  string
+	interfaces []models.DeviceInterface
+}
+
+// lldpInventory indexes devices by the names, addresses and MACs neighbors report
+type lldpInventory struct {
+	devices []*lldpDevice
+	byID    map[int]*lldpDevice
+	byMAC   map[string]*lldpDevice
+}
+
+// normalizeMAC reduces a MAC address in any notation to its lowercase hex digits
+func normalizeMAC(mac string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(mac) {
+		if (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() != 12 {
+		return ""
+	}
+	return b.String()
+}
+
+// shortHostname strips the domain from a hostname
+func shortHostname(name string) string {
+	host, _, _ := strings.Cut(strings.ToLower(name), ".")
+	return host
+}
+
+// loadLLDPInventory loads every device with its interfaces
+func (s *NetworkService) loadLLDPInventory() (*lldpInventory, error) {
+	devices, err := s.devices.ListDevices(store.DeviceFilter{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
+
+	inv := &lldpInventory{byID: map[int]*lldpDevice{}, byMAC: map[string]*lldpDevice{}}
+	for _, d := range devices {
+		device := &lldpDevice{id: d.ID, name: d.Name, ipAddress: d.IPAddress}
+		inv.devices = append(inv.devices, device)
+		inv.byID[device.id] = device
+	}
+
+	interfaces, err := s.interfaces.ListInterfaces(store.InterfaceFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, iface := range interfaces {
+		device, ok := inv.byID[iface.DeviceID]
+		if !ok {
+			continue
+		}
+		device.interfaces = append(device.interfaces, iface)
+		if mac := normalizeMAC(iface.MACAddress); mac != "" {
+			inv.byMAC[mac] = device
+		}
+	}
+
+	return inv, nil
+}
+
+// resolveDevice finds the device a neighbor entry describes, by system name, short hostname,
+// management address, then chassis MAC
+func (inv *lldpInventory) resolveDevice(neighbor models.LLDPNeighbor) *lldpDevice {
+	if name := strings.TrimSpace(neighbor.RemoteSystemName); name != "" {
+		for _, device := range inv.devices {
+			if strings.EqualFold(device.name, name) {
+				return device
+			}
+		}
+		for _, device := range inv.devices {
+			if shortHostname(device.name) == shortHostname(name) {
+				return device
+			}
+		}
+	}
+	if ip := strings.TrimSpace(neighbor.RemoteManagementIP); ip != "" {
+		for _, device := range inv.devices {
+			if device.ipAddress == ip {
+				return device
+			}
+		}
+	}
+	if mac := normalizeMAC(neighbor.RemoteChassisID); mac != "" {
+		return inv.byMAC[mac]
+	}
+	return nil
+}
+
+// findInterface finds a device's interface by any of the given names
+func (d *lldpDevice) findInterface(names ...string) *models.DeviceInterface {
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		key := interfaceKey(name)
+		for i := range d.interfaces {
+			if interfaceKey(d.interfaces[i].Name) == key {
+				return &d.interfaces[i]
+			}
+		}
+	}
+	return nil
+}
+
+// connectionEnd orients a connection from one of its devices, returning that device's interface
+// and the far device and interface
+func connectionEnd(conn models.NetworkConnection, deviceID int) (localInterfaceID *int, remoteDeviceID int, remoteInterfaceID *int) {
+	if conn.SourceDeviceID == deviceID {
+		return conn.SourceInterfaceID, conn.TargetDeviceID, conn.TargetInterfaceID
+	}
+	return conn.TargetInterfaceID, conn.SourceDeviceID, conn.SourceInterfaceID
+}
+
+// sameInterface reports whether two optional interface IDs are both set and equal
+func sameInterface(a, b *int) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// lldpReconciler holds the state of one neighbor table import
+type lldpReconciler struct {
+	service *NetworkService
+	inv     *lldpInventory
+	local   *lldpDevice
+	dryRun  bool
+	// connections is every stored connection in ID order, followed by the ones created so far
+	connections     []models.NetworkConnection
+	connectionsByID map[int]models.NetworkConnection
+	// claimed marks the connections a neighbor matched or conflicted with
+	claimed map[int]bool
+}
+
+// ImportLLDPNeighbors reconciles a device's LLDP or CDP neighbor table with its connections.
+//
+// Each neighbor is resolved to a remote device and, where the names match, to interfaces on both
+// ends. A neighbor is matched by a stored connection between the two devices whose interfaces
+// agree with it, flagged as a mismatch when either port is connected elsewhere, and otherwise
+// created as a new connection unless dryRun is set. The device's connections that no neighbor
+// matched are reported as stale but never deleted.
+func (s *NetworkService) ImportLLDPNeighbors(req models.LLDPImportRequest, dryRun bool) (*models.LLDPImportResult, error) {
+	neighbors := req.Neighbors
+	if strings.TrimSpace(req.Output) != "" {
+		parsed, err := ParseLLDPNeighbors(req.Output)
+		if err != nil {
+			return nil, err
+		}
+		neighbors = append(neighbors, parsed...)
+	}
+	if len(neighbors) == 0 {
+		return nil, fmt.Errorf("neighbors or output is required")
+	}
+
+	inv, err := s.loadLLDPInventory()
+	if err != nil {
+		return nil, err
+	}
+	local, ok := inv.byID[req.DeviceID]
+	if !ok {
+		return nil, fmt.Errorf("device not found")
+	}
+
+	connections, err := s.GetAllConnections()
+	if err != nil {
+		return nil, err
+	}
+	r := &lldpReconciler{
+		service:         s,
+		inv:             inv,
+		local:           local,
+		dryRun:          dryRun,
+		connections:     connections,
+		connectionsByID: map[int]models.NetworkConnection{},
+		claimed:         map[int]bool{},
+	}
+	for _, conn := range connections {
+		r.connectionsByID[conn.ID] = conn
+	}
+
+	result := &models.LLDPImportResult{
+		DeviceID:  req.DeviceID,
+		DryRun:    dryRun,
+		Neighbors: []models.LLDPNeighborResult{},
+		Stale:     []models.NetworkConnection{},
+		Summary:   map[string]int{},
+	}
+	seen := map[string]bool{}
+
+	for _, neighbor := range neighbors {
+		// The same link can be listed twice, e.g. when LLDP and CDP output are imported together
+		dedupeKey := interfaceKey(neighbor.LocalPort) + "|" + strings.ToLower(neighbor.RemoteSystemName) + "|" + interfaceKey(neighbor.RemotePortID)
+		if seen[dedupeKey] {
+			continue
+		}
+		seen[dedupeKey] = true
+
+		res, err := r.reconcile(neighbor)
+		if err != nil {
+			return nil, err
+		}
+		result.Neighbors = append(result.Neighbors, res)
+		result.Summary[string(res.Status)]++
+	}
+
+	for _, conn := range connections {
+		if conn.SourceDeviceID == conn.TargetDeviceID || r.claimed[conn.ID] {
+			continue
+		}
+		if conn.SourceDeviceID == req.DeviceID || conn.TargetDeviceID == req.DeviceID {
+			result.Stale = append(result.Stale, conn)
+		}
+	}
+	result.Summary["stale"] = len(result.Stale)
+
+	return result, nil
+}
+
+// reconcile resolves one neighbor and compares it with the stored connections, creating its
+// connection when missing
+func (r *lldpReconciler) reconcile(neighbor models.LLDPNeighbor) (models.LLDPNeighborResult, error) {
+	res := models.LLDPNeighborResult{Neighbor: neighbor}
+	localIface := r.local.findInterface(neighbor.LocalPort)
+	if localIface != nil {
+		res.LocalInterfaceID = &localIface.ID
+	}
+
+	remote := r.inv.resolveDevice(neighbor)
+	if remote == nil {
+		res.Status = models.LLDPNeighborUnresolved
+		res.Message = fmt.Sprintf("no device matches neighbor %s", firstNonEmpty(neighbor.RemoteSystemName, neighbor.RemoteChassisID))
+		return res, nil
+	}
+	res.RemoteDeviceID = &remote.id
+	remoteIface := remote.findInterface(neighbor.RemotePortID, neighbor.RemotePortDescription)
+	if remoteIface != nil {
+		res.RemoteInterfaceID = &remoteIface.ID
+	}
+
+	if conn, message, found := r.existingConnection(remote, localIface, remoteIface); found {
+		res.Status = models.LLDPNeighborMatched
+		if message != "" {
+			res.Status = models.LLDPNeighborMismatch
+			res.Message = message
+		}
+		res.ConnectionID = &conn.ID
+		r.claimed[conn.ID] = true
+		return res, nil
+	}
+
+	if r.dryRun {
+		res.Status = models.LLDPNeighborMissing
+		return res, nil
+	}
+
+	conn, err := r.service.CreateConnection(models.CreateConnectionRequest{
+		SourceDeviceID:    r.local.id,
+		TargetDeviceID:    remote.id,
+		SourceInterfaceID: res.LocalInterfaceID,
+		TargetInterfaceID: res.RemoteInterfaceID,
+		ConnectionType:    "Ethernet",
+		PortInfo:          fmt.Sprintf("%s - %s", neighbor.LocalPort, neighbor.RemotePortID),
+	})
+	if err != nil {
+		return res, err
+	}
+	res.Status = models.LLDPNeighborCreated
+	res.ConnectionID = &conn.ID
+
+	r.connections = append(r.connections, *conn)
+	r.connectionsByID[conn.ID] = *conn
+	r.claimed[conn.ID] = true
+	if localIface != nil {
+		localIface.ConnectionID = &conn.ID
+	}
+	if remoteIface != nil {
+		remoteIface.ConnectionID = &conn.ID
+	}
+	return res, nil
+}
+
+// existingConnection finds the stored connection describing a neighbor link. The message is
+// empty when the connection agrees with the neighbor and explains the mismatch otherwise.
+func (r *lldpReconciler) existingConnection(remote *lldpDevice, localIface, remoteIface *models.DeviceInterface) (models.NetworkConnection, string, bool) {
+	local := r.local
+	var localIfaceID, remoteIfaceID *int
+	if localIface != nil {
+		localIfaceID = &localIface.ID
+	}
+	if remoteIface != nil {
+		remoteIfaceID = &remoteIface.ID
+	}
+
+	// The local port's connection must lead to the reported port
+	if localIface != nil && localIface.ConnectionID != nil {
+		if conn, ok := r.connectionsByID[*localIface.ConnectionID]; ok {
+			_, farDeviceID, farIfaceID := connectionEnd(conn, local.id)
+			switch {
+			case farDeviceID != remote.id:
+				return conn, fmt.Sprintf("%s %s is connected to %s, but the neighbor is %s",
+					local.name, localIface.Name, r.deviceName(farDeviceID), remote.name), true
+			case farIfaceID != nil && remoteIfaceID != nil && *farIfaceID != *remoteIfaceID:
+				return conn, fmt.Sprintf("%s %s is connected to another port of %s, but the neighbor is %s",
+					local.name, localIface.Name, remote.name, remoteIface.Name), true
+			}
+			return conn, "", true
+		}
+	}
+
+	// The remote port's connection must lead back to this device
+	if remoteIface != nil && remoteIface.ConnectionID != nil {
+		if conn, ok := r.connectionsByID[*remoteIface.ConnectionID]; ok {
+			_, farDeviceID, farIfaceID := connectionEnd(conn, remote.id)
+			switch {
+			case farDeviceID != local.id:
+				return conn, fmt.Sprintf("%s %s is connected to %s, but its neighbor is %s",
+					remote.name, remoteIface.Name, r.deviceName(farDeviceID), local.name), true
+			case farIfaceID != nil && localIfaceID != nil && *farIfaceID != *localIfaceID:
+				return conn, fmt.Sprintf("%s %s is connected to another port of %s, but its neighbor is %s",
+					remote.name, remoteIface.Name, local.name, localIface.Name), true
+			}
+			return conn, "", true
+		}
+	}
+
+	// Otherwise an unclaimed connection between the devices matches when its ports do not disagree
+	for _, conn := range r.connections {
+		if r.claimed[conn.ID] || conn.SourceDeviceID == conn.TargetDeviceID {
+			continue
+		}
+		if conn.SourceDeviceID != local.id && conn.TargetDeviceID != local.id {
+			continue
+		}
+		nearIfaceID, farDeviceID, farIfaceID := connectionEnd(conn, local.id)
+		if farDeviceID != remote.id {
+			continue
+		}
+		if (nearIfaceID == nil || localIfaceID == nil || sameInterface(nearIfaceID, localIfaceID)) &&
+			(farIfaceID == nil || remoteIfaceID == nil || sameInterface(farIfaceID, remoteIfaceID)) {
+			return conn, "", true
+		}
+	}
+
+	return models.NetworkConnection{}, "", false
+}
+
+// deviceName returns a device's name for messages
+func (r *lldpReconciler) deviceName(id int) string {
+	if device, ok := r.inv.byID[id]; ok {
+		return device.name
+	}
+	return fmt.Sprintf("device %d", id)
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
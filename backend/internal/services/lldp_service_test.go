@@ -0,0 +1,158 @@
+package services
+
+import (
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func TestImportLLDPNeighbors(t *testing.T) {
+	// leaf-1 Ethernet1 reports spine-1 Ethernet1/49 as its neighbor
+	neighbor := models.LLDPNeighbor{LocalPort: "Et1", RemoteSystemName: "spine-1.example.com", RemotePortID: "Ethernet1/49"}
+
+	type fixture struct {
+		s                      *store.Store
+		leaf, spine            *models.Device
+		leafEth1, leafEth2     *models.DeviceInterface
+		spineEth49, spineEth50 *models.DeviceInterface
+	}
+	tests := []struct {
+		name string
+		// connect stores the connections before the import and returns the one the neighbor
+		// should be reconciled with, if any
+		connect     func(t *testing.T, f fixture) *models.NetworkConnection
+		dryRun      bool
+		wantStatus  models.LLDPNeighborStatus
+		wantMessage bool
+	}{
+		{
+			name: "matching connection",
+			connect: func(t *testing.T, f fixture) *models.NetworkConnection {
+				return mustConnect(t, f.s, f.spine.ID, f.spineEth49, f.leaf.ID, f.leafEth1)
+			},
+			wantStatus: models.LLDPNeighborMatched,
+		},
+		{
+			name: "connection without a local interface",
+			connect: func(t *testing.T, f fixture) *models.NetworkConnection {
+				return mustConnect(t, f.s, f.spine.ID, f.spineEth49, f.leaf.ID, nil)
+			},
+			wantStatus: models.LLDPNeighborMatched,
+		},
+		{
+			name: "connection without any interface",
+			connect: func(t *testing.T, f fixture) *models.NetworkConnection {
+				return mustConnect(t, f.s, f.leaf.ID, nil, f.spine.ID, nil)
+			},
+			wantStatus: models.LLDPNeighborMatched,
+		},
+		{
+			name: "remote port connected to the wrong local port",
+			connect: func(t *testing.T, f fixture) *models.NetworkConnection {
+				return mustConnect(t, f.s, f.spine.ID, f.spineEth49, f.leaf.ID, f.leafEth2)
+			},
+			wantStatus:  models.LLDPNeighborMismatch,
+			wantMessage: true,
+		},
+		{
+			name: "local port connected to the wrong remote port",
+			connect: func(t *testing.T, f fixture) *models.NetworkConnection {
+				return mustConnect(t, f.s, f.leaf.ID, f.leafEth1, f.spine.ID, f.spineEth50)
+			},
+			wantStatus:  models.LLDPNeighborMismatch,
+			wantMessage: true,
+		},
+		{
+			name:       "missing connection in a dry run",
+			connect:    func(t *testing.T, f fixture) *models.NetworkConnection { return nil },
+			dryRun:     true,
+			wantStatus: models.LLDPNeighborMissing,
+		},
+		{
+			name:       "missing connection",
+			connect:    func(t *testing.T, f fixture) *models.NetworkConnection { return nil },
+			wantStatus: models.LLDPNeighborCreated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := store.NewMemoryStore()
+			rack := mustCreateRack(t, s, "A")
+			f := fixture{s: s}
+			f.leaf = mustCreateDevice(t, s, newDevice(rack.ID, "leaf-1", 10, 1))
+			f.spine = mustCreateDevice(t, s, newDevice(rack.ID, "spine-1", 20, 1))
+			f.leafEth1 = mustCreateInterface(t, s, f.leaf.ID, "Ethernet1")
+			f.leafEth2 = mustCreateInterface(t, s, f.leaf.ID, "Ethernet2")
+			f.spineEth49 = mustCreateInterface(t, s, f.spine.ID, "Ethernet1/49")
+			f.spineEth50 = mustCreateInterface(t, s, f.spine.ID, "Ethernet1/50")
+			want := tt.connect(t, f)
+
+			result, err := newNetworkService(s).ImportLLDPNeighbors(models.LLDPImportRequest{
+				DeviceID:  f.leaf.ID,
+				Neighbors: []models.LLDPNeighbor{neighbor},
+			}, tt.dryRun)
+			if err != nil {
+				t.Fatalf("ImportLLDPNeighbors: %v", err)
+			}
+			if len(result.Neighbors) != 1 {
+				t.Fatalf("ImportLLDPNeighbors returned %d neighbors, want 1", len(result.Neighbors))
+			}
+			res := result.Neighbors[0]
+			if res.Status != tt.wantStatus {
+				t.Errorf("Status = %s (%s), want %s", res.Status, res.Message, tt.wantStatus)
+			}
+			if (res.Message != "") != tt.wantMessage {
+				t.Errorf("Message = %q, want a message: %v", res.Message, tt.wantMessage)
+			}
+			if res.LocalInterfaceID == nil || *res.LocalInterfaceID != f.leafEth1.ID {
+				t.Errorf("LocalInterfaceID = %v, want %d", res.LocalInterfaceID, f.leafEth1.ID)
+			}
+			if res.RemoteInterfaceID == nil || *res.RemoteInterfaceID != f.spineEth49.ID {
+				t.Errorf("RemoteInterfaceID = %v, want %d", res.RemoteInterfaceID, f.spineEth49.ID)
+			}
+			if want != nil && (res.ConnectionID == nil || *res.ConnectionID != want.ID) {
+				t.Errorf("ConnectionID = %v, want %d", res.ConnectionID, want.ID)
+			}
+			if len(result.Stale) != 0 {
+				t.Errorf("Stale = %+v, want none", result.Stale)
+			}
+
+			connections, err := s.Connections.ListConnections(store.ConnectionFilter{})
+			if err != nil {
+				t.Fatalf("ListConnections: %v", err)
+			}
+			wantConnections := 1
+			if tt.wantStatus == models.LLDPNeighborMissing {
+				wantConnections = 0
+			}
+			if len(connections) != wantConnections {
+				t.Errorf("%d connections after the import, want %d", len(connections), wantConnections)
+			}
+		})
+	}
+}
+
+func TestImportLLDPNeighborsReportsStaleConnections(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	leaf := mustCreateDevice(t, s, newDevice(rack.ID, "leaf-1", 10, 1))
+	spine := mustCreateDevice(t, s, newDevice(rack.ID, "spine-1", 20, 1))
+	server := mustCreateDevice(t, s, newDevice(rack.ID, "server-1", 30, 1))
+	mustConnect(t, s, leaf.ID, nil, spine.ID, nil)
+	stale := mustConnect(t, s, leaf.ID, nil, server.ID, nil)
+
+	result, err := newNetworkService(s).ImportLLDPNeighbors(models.LLDPImportRequest{
+		DeviceID:  leaf.ID,
+		Neighbors: []models.LLDPNeighbor{{LocalPort: "Ethernet1", RemoteSystemName: "spine-1", RemotePortID: "Ethernet1/49"}},
+	}, true)
+	if err != nil {
+		t.Fatalf("ImportLLDPNeighbors: %v", err)
+	}
+	if len(result.Stale) != 1 || result.Stale[0].ID != stale.ID {
+		t.Errorf("Stale = %+v, want connection %d", result.Stale, stale.ID)
+	}
+	if result.Summary["matched"] != 1 || result.Summary["stale"] != 1 {
+		t.Errorf("Summary = %v, want 1 matched and 1 stale", result.Summary)
+	}
+}
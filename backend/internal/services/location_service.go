@@ -0,0 +1,347 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// LocationService handles the site, room and row hierarchy above racks
+type LocationService struct {
+	locations store.LocationStore
+	racks     store.RackStore
+	devices   store.DeviceStore
+}
+
+// NewLocationService creates a new location service
+func NewLocationService(locations store.LocationStore, racks store.RackStore, devices store.DeviceStore) *LocationService {
+	return &LocationService{locations: locations, racks: racks, devices: devices}
+}
+
+// ResolveLocation validates a rack location and fills in the parents of its most specific level.
+// A zero ID clears that level; all nil/zero means no location.
+func (s *LocationService) ResolveLocation(siteID, roomID, rowID *int) (*int, *int, *int, error) {
+	site := positiveOrNil(siteID)
+	room := positiveOrNil(roomID)
+	row := positiveOrNil(rowID)
+
+	if row != nil {
+		r, err := s.locations.GetRow(*row)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if room != nil && *room != r.RoomID {
+			return nil, nil, nil, fmt.Errorf("row %d is not in room %d", *row, *room)
+		}
+		room = &r.RoomID
+	}
+
+	if room != nil {
+		r, err := s.locations.GetRoom(*room)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if site != nil && *site != r.SiteID {
+			return nil, nil, nil, fmt.Errorf("room %d is not in site %d", *room, *site)
+		}
+		site = &r.SiteID
+	}
+
+	if site != nil {
+		if _, err := s.locations.GetSite(*site); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return site, room, row, nil
+}
+
+// positiveOrNil treats nil and non-positive IDs as unset
+func positiveOrNil(id *int) *int {
+	if id == nil || *id <= 0 {
+		return nil
+	}
+	v := *id
+	return &v
+}
+
+// Sites
+
+// GetAllSites retrieves all sites
+func (s *LocationService) GetAllSites() ([]models.Site, error) {
+	sites, err := s.locations.ListSites()
+	if err != nil {
+		return nil, err
+	}
+	if sites == nil {
+		sites = []models.Site{}
+	}
+	return sites, nil
+}
+
+// GetSiteByID retrieves a site by ID
+func (s *LocationService) GetSiteByID(id int) (*models.Site, error) {
+	return s.locations.GetSite(id)
+}
+
+// CreateSite creates a new site
+func (s *LocationService) CreateSite(req models.CreateSiteRequest) (*models.Site, error) {
+	site := &models.Site{Name: req.Name, Description: req.Description, Address: req.Address}
+	if err := s.locations.CreateSite(site); err != nil {
+		return nil, err
+	}
+	return site, nil
+}
+
+// UpdateSite updates an existing site
+func (s *LocationService) UpdateSite(id int, req models.UpdateSiteRequest) (*models.Site, error) {
+	site, err := s.locations.GetSite(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		site.Name = *req.Name
+	}
+	if req.Description != nil {
+		site.Description = *req.Description
+	}
+	if req.Address != nil {
+		site.Address = *req.Address
+	}
+
+	if err := s.locations.UpdateSite(site); err != nil {
+		return nil, err
+	}
+	return site, nil
+}
+
+// DeleteSite deletes a site and its rooms and rows; its racks are kept without a location
+func (s *LocationService) DeleteSite(id int) error {
+	return s.locations.DeleteSite(id)
+}
+
+// Rooms
+
+// GetAllRooms retrieves all rooms, optionally filtered by site
+func (s *LocationService) GetAllRooms(siteID *int) ([]models.Room, error) {
+	rooms, err := s.locations.ListRooms(siteID)
+	if err != nil {
+		return nil, err
+	}
+	if rooms == nil {
+		rooms = []models.Room{}
+	}
+	return rooms, nil
+}
+
+// GetRoomByID retrieves a room by ID
+func (s *LocationService) GetRoomByID(id int) (*models.Room, error) {
+	return s.locations.GetRoom(id)
+}
+
+// CreateRoom creates a new room in a site
+func (s *LocationService) CreateRoom(req models.CreateRoomRequest) (*models.Room, error) {
+	if _, err := s.GetSiteByID(req.SiteID); err != nil {
+		return nil, err
+	}
+
+	room := &models.Room{SiteID: req.SiteID, Name: req.Name, Description: req.Description}
+	if err := s.locations.CreateRoom(room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// UpdateRoom updates an existing room; moving it to another site moves its racks too
+func (s *LocationService) UpdateRoom(id int, req models.UpdateRoomRequest) (*models.Room, error) {
+	room, err := s.locations.GetRoom(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SiteID != nil {
+		if _, err := s.GetSiteByID(*req.SiteID); err != nil {
+			return nil, err
+		}
+		room.SiteID = *req.SiteID
+	}
+	if req.Name != nil {
+		room.Name = *req.Name
+	}
+	if req.Description != nil {
+		room.Description = *req.Description
+	}
+
+	if err := s.locations.UpdateRoom(room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// DeleteRoom deletes a room and its rows; its racks stay in the site
+func (s *LocationService) DeleteRoom(id int) error {
+	return s.locations.DeleteRoom(id)
+}
+
+// Rows
+
+// GetAllRows retrieves all rows, optionally filtered by room
+func (s *LocationService) GetAllRows(roomID *int) ([]models.Row, error) {
+	rows, err := s.locations.ListRows(store.RowFilter{RoomID: roomID})
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []models.Row{}
+	}
+	return rows, nil
+}
+
+// GetRowByID retrieves a row by ID
+func (s *LocationService) GetRowByID(id int) (*models.Row, error) {
+	return s.locations.GetRow(id)
+}
+
+// CreateRow creates a new row in a room
+func (s *LocationService) CreateRow(req models.CreateRowRequest) (*models.Row, error) {
+	if _, err := s.GetRoomByID(req.RoomID); err != nil {
+		return nil, err
+	}
+
+	row := &models.Row{RoomID: req.RoomID, Name: req.Name, Description: req.Description}
+	if err := s.locations.CreateRow(row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// UpdateRow updates an existing row; moving it to another room moves its racks too
+func (s *LocationService) UpdateRow(id int, req models.UpdateRowRequest) (*models.Row, error) {
+	row, err := s.locations.GetRow(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RoomID != nil {
+		if _, err := s.GetRoomByID(*req.RoomID); err != nil {
+			return nil, err
+		}
+		row.RoomID = *req.RoomID
+	}
+	if req.Name != nil {
+		row.Name = *req.Name
+	}
+	if req.Description != nil {
+		row.Description = *req.Description
+	}
+
+	if err := s.locations.UpdateRow(row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// DeleteRow deletes a row; its racks stay in the room
+func (s *LocationService) DeleteRow(id int) error {
+	return s.locations.DeleteRow(id)
+}
+
+// GetSiteTree returns a site with its rooms, rows and racks, each annotated with device counts
+func (s *LocationService) GetSiteTree(id int) (*models.SiteTree, error) {
+	site, err := s.GetSiteByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms, err := s.GetAllRooms(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.locations.ListRows(store.RowFilter{SiteID: &id})
+	if err != nil {
+		return nil, err
+	}
+	rowsByRoom := make(map[int][]models.Row)
+	for _, row := range rows {
+		rowsByRoom[row.RoomID] = append(rowsByRoom[row.RoomID], row)
+	}
+
+	racks, err := s.racks.ListRacks(models.RackFilter{SiteID: &id})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(racks, func(i, j int) bool { return racks[i].Name < racks[j].Name })
+
+	devices, err := s.devices.ListDevices(store.DeviceFilter{})
+	if err != nil {
+		return nil, err
+	}
+	deviceCounts := map[int]int{}
+	for _, device := range devices {
+		deviceCounts[device.RackID]++
+	}
+
+	racksByRoom := make(map[int][]models.RackSummary)
+	racksByRow := make(map[int][]models.RackSummary)
+	tree := &models.SiteTree{
+		Site:  *site,
+		Rooms: []models.RoomTree{},
+		Racks: []models.RackSummary{},
+	}
+	for _, r := range racks {
+		rack := models.RackSummary{ID: r.ID, Name: r.Name, SizeU: r.SizeU, DeviceCount: deviceCounts[r.ID]}
+		tree.DeviceCount += rack.DeviceCount
+		switch {
+		case r.RowID != nil:
+			racksByRow[*r.RowID] = append(racksByRow[*r.RowID], rack)
+		case r.RoomID != nil:
+			racksByRoom[*r.RoomID] = append(racksByRoom[*r.RoomID], rack)
+		default:
+			tree.Racks = append(tree.Racks, rack)
+		}
+	}
+
+	for _, room := range rooms {
+		roomTree := models.RoomTree{
+			Room:  room,
+			Rows:  []models.RowTree{},
+			Racks: racksOrEmpty(racksByRoom[room.ID]),
+		}
+		roomTree.DeviceCount = sumDevices(roomTree.Racks)
+
+		for _, row := range rowsByRoom[room.ID] {
+			rowTree := models.RowTree{
+				Row:   row,
+				Racks: racksOrEmpty(racksByRow[row.ID]),
+			}
+			rowTree.DeviceCount = sumDevices(rowTree.Racks)
+			roomTree.DeviceCount += rowTree.DeviceCount
+			roomTree.Rows = append(roomTree.Rows, rowTree)
+		}
+
+		tree.Rooms = append(tree.Rooms, roomTree)
+	}
+
+	return tree, nil
+}
+
+// racksOrEmpty keeps empty rack lists as [] in JSON
+func racksOrEmpty(racks []models.RackSummary) []models.RackSummary {
+	if racks == nil {
+		return []models.RackSummary{}
+	}
+	return racks
+}
+
+// sumDevices totals the device counts of racks
+func sumDevices(racks []models.RackSummary) int {
+	total := 0
+	for _, rack := range racks {
+		total += rack.DeviceCount
+	}
+	return total
+}
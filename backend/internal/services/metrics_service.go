@@ -0,0 +1,189 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// MetricsService gathers inventory and health data for the metrics endpoint
+type MetricsService struct {
+	racks        store.RackStore
+	devices      store.DeviceStore
+	connections  store.ConnectionStore
+	healthChecks store.HealthCheckStore
+}
+
+// NewMetricsService creates a new metrics service
+func NewMetricsService(racks store.RackStore, devices store.DeviceStore, connections store.ConnectionStore, healthChecks store.HealthCheckStore) *MetricsService {
+	return &MetricsService{racks: racks, devices: devices, connections: connections, healthChecks: healthChecks}
+}
+
+// DeviceCount is the number of devices in a rack with a given type and status
+type DeviceCount struct {
+	RackID int
+	Rack   string
+	Type   models.DeviceType
+	Status models.DeviceStatus
+	Count  int
+}
+
+// RackUtilization is the U usage of a rack
+type RackUtilization struct {
+	RackID int
+	Name   string
+	SizeU  int
+	UsedU  int
+}
+
+// ConnectionTypeCount is the number of connections of a given type
+type ConnectionTypeCount struct {
+	ConnectionType string
+	Count          int
+}
+
+// DeviceMetrics holds per-device connection and last health check data
+type DeviceMetrics struct {
+	DeviceID      int
+	Name          string
+	Rack          string
+	Connections   int
+	LastStatus    models.DeviceStatus
+	LastLatency   *int64
+	LastCheckedAt *time.Time
+}
+
+// InventoryMetrics is a snapshot of everything exported on /metrics
+type InventoryMetrics struct {
+	DeviceCounts     []DeviceCount
+	Racks            []RackUtilization
+	ConnectionCounts []ConnectionTypeCount
+	Devices          []DeviceMetrics
+}
+
+// GetInventoryMetrics collects the current inventory and health snapshot
+func (s *MetricsService) GetInventoryMetrics() (*InventoryMetrics, error) {
+	metrics := &InventoryMetrics{}
+
+	racks, err := s.racks.ListRacks(models.RackFilter{})
+	if err != nil {
+		return nil, err
+	}
+	devices, err := s.devices.ListDevices(store.DeviceFilter{})
+	if err != nil {
+		return nil, err
+	}
+	connections, err := s.connections.ListConnections(store.ConnectionFilter{})
+	if err != nil {
+		return nil, err
+	}
+	checks, err := s.healthChecks.LatestHealthChecks()
+	if err != nil {
+		return nil, err
+	}
+
+	rackNames := map[int]string{}
+	for _, rack := range racks {
+		rackNames[rack.ID] = rack.Name
+	}
+
+	type countKey struct {
+		rackID int
+		typ    models.DeviceType
+		status models.DeviceStatus
+	}
+	deviceCounts := map[countKey]int{}
+	for _, device := range devices {
+		deviceCounts[countKey{device.RackID, device.Type, device.Status}]++
+	}
+	for key, count := range deviceCounts {
+		metrics.DeviceCounts = append(metrics.DeviceCounts, DeviceCount{
+			RackID: key.rackID,
+			Rack:   rackNames[key.rackID],
+			Type:   key.typ,
+			Status: key.status,
+			Count:  count,
+		})
+	}
+	sort.Slice(metrics.DeviceCounts, func(i, j int) bool {
+		a, b := metrics.DeviceCounts[i], metrics.DeviceCounts[j]
+		if a.RackID != b.RackID {
+			return a.RackID < b.RackID
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Status < b.Status
+	})
+
+	// A half-depth front and rear pair shares its U, so usage counts distinct occupied slots
+	// rather than summing device heights
+	occupied := occupiedUnits(devices)
+	for _, rack := range racks {
+		metrics.Racks = append(metrics.Racks, RackUtilization{
+			RackID: rack.ID,
+			Name:   rack.Name,
+			SizeU:  rack.SizeU,
+			UsedU:  len(occupied[rack.ID]),
+		})
+	}
+
+	connectionCounts := map[string]int{}
+	deviceConnections := map[int]int{}
+	for _, conn := range connections {
+		connectionCounts[conn.ConnectionType]++
+		deviceConnections[conn.SourceDeviceID]++
+		if conn.TargetDeviceID != conn.SourceDeviceID {
+			deviceConnections[conn.TargetDeviceID]++
+		}
+	}
+	for connectionType, count := range connectionCounts {
+		metrics.ConnectionCounts = append(metrics.ConnectionCounts, ConnectionTypeCount{ConnectionType: connectionType, Count: count})
+	}
+	sort.Slice(metrics.ConnectionCounts, func(i, j int) bool {
+		return metrics.ConnectionCounts[i].ConnectionType < metrics.ConnectionCounts[j].ConnectionType
+	})
+
+	latest := map[int]models.HealthCheck{}
+	for _, check := range checks {
+		latest[check.DeviceID] = check
+	}
+	for _, device := range devices {
+		deviceMetrics := DeviceMetrics{
+			DeviceID:    device.ID,
+			Name:        device.Name,
+			Rack:        rackNames[device.RackID],
+			Connections: deviceConnections[device.ID],
+		}
+		if check, ok := latest[device.ID]; ok {
+			checkedAt := check.CheckedAt
+			deviceMetrics.LastStatus = check.Status
+			deviceMetrics.LastLatency = check.Latency
+			deviceMetrics.LastCheckedAt = &checkedAt
+		}
+		metrics.Devices = append(metrics.Devices, deviceMetrics)
+	}
+	sort.Slice(metrics.Devices, func(i, j int) bool { return metrics.Devices[i].DeviceID < metrics.Devices[j].DeviceID })
+
+	return metrics, nil
+}
+
+// occupiedUnits returns the set of U slots taken by devices in each rack. Zero-U and chassis bay
+// devices take none; PositionU is the top slot of a device.
+func occupiedUnits(devices []models.Device) map[int]map[int]bool {
+	occupied := map[int]map[int]bool{}
+	for _, device := range devices {
+		if device.SizeU <= 0 {
+			continue
+		}
+		if occupied[device.RackID] == nil {
+			occupied[device.RackID] = map[int]bool{}
+		}
+		for u := device.PositionU - device.SizeU + 1; u <= device.PositionU; u++ {
+			occupied[device.RackID][u] = true
+		}
+	}
+	return occupied
+}
@@ -1,56 +1,94 @@
 package services
 
 import (
-	"database/sql"
 	"fmt"
+	"sort"
 
-	"rackview/internal/database"
 	"rackview/internal/models"
+	"rackview/internal/store"
 )
 
 // NetworkService handles network connection-related business logic
-type NetworkService struct{}
+type NetworkService struct {
+	connections store.ConnectionStore
+	devices     store.DeviceStore
+	racks       store.RackStore
+	interfaces  store.InterfaceStore
+	cables      *CableService
+}
 
 // NewNetworkService creates a new network service
-func NewNetworkService() *NetworkService {
-	return &NetworkService{}
+func NewNetworkService(connections store.ConnectionStore, devices store.DeviceStore, racks store.RackStore,
+	interfaces store.InterfaceStore, cables store.CableStore) *NetworkService {
+	return &NetworkService{
+		connections: connections,
+		devices:     devices,
+		racks:       racks,
+		interfaces:  interfaces,
+		cables:      NewCableService(cables),
+	}
 }
 
-// GetAllConnections retrieves all network connections
-func (s *NetworkService) GetAllConnections() ([]models.NetworkConnection, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, source_device_id, target_device_id, connection_type, port_info, speed, created_at
-		FROM network_connections
-		ORDER BY id
-	`)
+// loadInterfaceDetails loads the interfaces at both ends of a connection and its cable
+func (s *NetworkService) loadInterfaceDetails(conn *models.NetworkConnection) {
+	if conn.SourceInterfaceID != nil {
+		if iface, err := s.interfaces.GetInterface(*conn.SourceInterfaceID); err == nil {
+			conn.SourceInterface = iface
+		}
+	}
+	if conn.TargetInterfaceID != nil {
+		if iface, err := s.interfaces.GetInterface(*conn.TargetInterfaceID); err == nil {
+			conn.TargetInterface = iface
+		}
+	}
+	if conn.CableID != nil {
+		if cable, err := s.cables.GetCableByID(*conn.CableID); err == nil {
+			conn.Cable = cable
+		}
+	}
+}
+
+// checkConnectionInterface verifies that an interface belongs to the device at its end of a
+// connection and carries no other connection. A nil or 0 ID means no interface.
+func (s *NetworkService) checkConnectionInterface(interfaceID *int, deviceID int, end string, excludeConnectionID *int) (*int, error) {
+	if interfaceID == nil || *interfaceID == 0 {
+		return nil, nil
+	}
+
+	iface, err := s.interfaces.GetInterface(*interfaceID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query connections: %w", err)
+		return nil, fmt.Errorf("%s interface not found", end)
+	}
+	if iface.DeviceID != deviceID {
+		return nil, fmt.Errorf("%s interface %s does not belong to device %d", end, iface.Name, deviceID)
+	}
+	if iface.ConnectionID != nil && (excludeConnectionID == nil || *iface.ConnectionID != *excludeConnectionID) {
+		return nil, fmt.Errorf("interface %s is already connected (connection %d)", iface.Name, *iface.ConnectionID)
 	}
-	defer rows.Close()
 
-	var connections []models.NetworkConnection
-	deviceService := NewDeviceService()
+	return interfaceID, nil
+}
 
-	for rows.Next() {
-		var conn models.NetworkConnection
-		if err := rows.Scan(
-			&conn.ID, &conn.SourceDeviceID, &conn.TargetDeviceID,
-			&conn.ConnectionType, &conn.PortInfo, &conn.Speed, &conn.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan connection: %w", err)
-		}
+// loadConnectionDetails loads the devices at both ends of a connection, their interfaces and its cable
+func (s *NetworkService) loadConnectionDetails(conn *models.NetworkConnection) {
+	if source, err := loadDevice(s.devices, conn.SourceDeviceID); err == nil {
+		conn.SourceDevice = source
+	}
+	if target, err := loadDevice(s.devices, conn.TargetDeviceID); err == nil {
+		conn.TargetDevice = target
+	}
+	s.loadInterfaceDetails(conn)
+}
 
-		// Load device details
-		source, err := deviceService.GetDeviceByID(conn.SourceDeviceID)
-		if err == nil {
-			conn.SourceDevice = source
-		}
-		target, err := deviceService.GetDeviceByID(conn.TargetDeviceID)
-		if err == nil {
-			conn.TargetDevice = target
-		}
+// GetAllConnections retrieves all network connections
+func (s *NetworkService) GetAllConnections() ([]models.NetworkConnection, error) {
+	connections, err := s.connections.ListConnections(store.ConnectionFilter{})
+	if err != nil {
+		return nil, err
+	}
 
-		connections = append(connections, conn)
+	for i := range connections {
+		s.loadConnectionDetails(&connections[i])
 	}
 
 	return connections, nil
@@ -58,125 +96,175 @@ func (s *NetworkService) GetAllConnections() ([]models.NetworkConnection, error)
 
 // GetConnectionByID retrieves a connection by ID
 func (s *NetworkService) GetConnectionByID(id int) (*models.NetworkConnection, error) {
-	var conn models.NetworkConnection
-	err := database.DB.QueryRow(`
-		SELECT id, source_device_id, target_device_id, connection_type, port_info, speed, created_at
-		FROM network_connections
-		WHERE id = $1
-	`, id).Scan(
-		&conn.ID, &conn.SourceDeviceID, &conn.TargetDeviceID,
-		&conn.ConnectionType, &conn.PortInfo, &conn.Speed, &conn.CreatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("connection not found")
-	}
+	conn, err := s.connections.GetConnection(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query connection: %w", err)
+		return nil, err
 	}
 
-	// Load device details
-	deviceService := NewDeviceService()
-	source, err := deviceService.GetDeviceByID(conn.SourceDeviceID)
-	if err == nil {
-		conn.SourceDevice = source
-	}
-	target, err := deviceService.GetDeviceByID(conn.TargetDeviceID)
-	if err == nil {
-		conn.TargetDevice = target
-	}
+	s.loadConnectionDetails(conn)
 
-	return &conn, nil
+	return conn, nil
 }
 
 // CreateConnection creates a new network connection
 func (s *NetworkService) CreateConnection(req models.CreateConnectionRequest) (*models.NetworkConnection, error) {
 	// Validate devices exist
-	deviceService := NewDeviceService()
-	_, err := deviceService.GetDeviceByID(req.SourceDeviceID)
+	_, err := s.devices.GetDevice(req.SourceDeviceID)
 	if err != nil {
 		return nil, fmt.Errorf("source device not found: %w", err)
 	}
 
-	_, err = deviceService.GetDeviceByID(req.TargetDeviceID)
+	_, err = s.devices.GetDevice(req.TargetDeviceID)
 	if err != nil {
 		return nil, fmt.Errorf("target device not found: %w", err)
 	}
 
-	// Allow multiple connections between the same devices (e.g., multiple ports/interfaces)
-	var conn models.NetworkConnection
-	err = database.DB.QueryRow(`
-		INSERT INTO network_connections (source_device_id, target_device_id, connection_type, port_info, speed)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, source_device_id, target_device_id, connection_type, port_info, speed, created_at
-	`, req.SourceDeviceID, req.TargetDeviceID, req.ConnectionType, req.PortInfo, req.Speed).Scan(
-		&conn.ID, &conn.SourceDeviceID, &conn.TargetDeviceID,
-		&conn.ConnectionType, &conn.PortInfo, &conn.Speed, &conn.CreatedAt,
-	)
+	sourceInterfaceID, err := s.checkConnectionInterface(req.SourceInterfaceID, req.SourceDeviceID, "source", nil)
+	if err != nil {
+		return nil, err
+	}
+	targetInterfaceID, err := s.checkConnectionInterface(req.TargetInterfaceID, req.TargetDeviceID, "target", nil)
+	if err != nil {
+		return nil, err
+	}
+	if sourceInterfaceID != nil && targetInterfaceID != nil && *sourceInterfaceID == *targetInterfaceID {
+		return nil, fmt.Errorf("a connection cannot start and end on the same interface")
+	}
 
+	if req.CableID != nil && req.Cable != nil {
+		return nil, fmt.Errorf("set either cable_id or cable, not both")
+	}
+	cableID, err := s.cables.checkConnectionCable(req.CableID, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create connection: %w", err)
+		return nil, err
 	}
 
-	// Load device details
-	source, err := deviceService.GetDeviceByID(conn.SourceDeviceID)
-	if err == nil {
-		conn.SourceDevice = source
+	// A new cable is created in the same transaction as the connection
+	var cable *models.Cable
+	if req.Cable != nil {
+		cable = newCable(*req.Cable)
 	}
-	target, err := deviceService.GetDeviceByID(conn.TargetDeviceID)
-	if err == nil {
-		conn.TargetDevice = target
+
+	// Allow multiple connections between the same devices (e.g., multiple ports/interfaces)
+	conn := &models.NetworkConnection{
+		SourceDeviceID:    req.SourceDeviceID,
+		TargetDeviceID:    req.TargetDeviceID,
+		SourceInterfaceID: sourceInterfaceID,
+		TargetInterfaceID: targetInterfaceID,
+		CableID:           cableID,
+		ConnectionType:    req.ConnectionType,
+		PortInfo:          req.PortInfo,
+		Speed:             req.Speed,
+	}
+	if err := s.connections.CreateConnection(conn, cable); err != nil {
+		if cable != nil {
+			return nil, cableLabelError(err, cable.Label)
+		}
+		return nil, err
 	}
 
-	return &conn, nil
+	s.loadConnectionDetails(conn)
+
+	return conn, nil
 }
 
 // UpdateConnection updates an existing network connection
 func (s *NetworkService) UpdateConnection(id int, req models.UpdateConnectionRequest) (*models.NetworkConnection, error) {
-	var conn models.NetworkConnection
-	err := database.DB.QueryRow(`
-		UPDATE network_connections
-		SET connection_type = $1, port_info = $2, speed = $3
-		WHERE id = $4
-		RETURNING id, source_device_id, target_device_id, connection_type, port_info, speed, created_at
-	`, req.ConnectionType, req.PortInfo, req.Speed, id).Scan(
-		&conn.ID, &conn.SourceDeviceID, &conn.TargetDeviceID,
-		&conn.ConnectionType, &conn.PortInfo, &conn.Speed, &conn.CreatedAt,
-	)
-
+	current, err := s.GetConnectionByID(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update connection: %w", err)
+		return nil, err
 	}
 
-	// Load device details
-	deviceService := NewDeviceService()
-	source, err := deviceService.GetDeviceByID(conn.SourceDeviceID)
-	if err == nil {
-		conn.SourceDevice = source
+	// Interfaces are only changed when given; 0 detaches that end
+	sourceInterfaceID, targetInterfaceID := current.SourceInterfaceID, current.TargetInterfaceID
+	if req.SourceInterfaceID != nil {
+		if sourceInterfaceID, err = s.checkConnectionInterface(req.SourceInterfaceID, current.SourceDeviceID, "source", &id); err != nil {
+			return nil, err
+		}
 	}
-	target, err := deviceService.GetDeviceByID(conn.TargetDeviceID)
-	if err == nil {
-		conn.TargetDevice = target
+	if req.TargetInterfaceID != nil {
+		if targetInterfaceID, err = s.checkConnectionInterface(req.TargetInterfaceID, current.TargetDeviceID, "target", &id); err != nil {
+			return nil, err
+		}
+	}
+	if sourceInterfaceID != nil && targetInterfaceID != nil && *sourceInterfaceID == *targetInterfaceID {
+		return nil, fmt.Errorf("a connection cannot start and end on the same interface")
+	}
+
+	cableID := current.CableID
+	if req.CableID != nil {
+		if cableID, err = s.cables.checkConnectionCable(req.CableID, &id); err != nil {
+			return nil, err
+		}
 	}
 
+	conn := *current
+	conn.ConnectionType = req.ConnectionType
+	conn.PortInfo = req.PortInfo
+	conn.Speed = req.Speed
+	conn.SourceInterfaceID = sourceInterfaceID
+	conn.TargetInterfaceID = targetInterfaceID
+	conn.CableID = cableID
+	if err := s.connections.UpdateConnection(&conn); err != nil {
+		return nil, err
+	}
+
+	s.loadConnectionDetails(&conn)
+
 	return &conn, nil
 }
 
 // DeleteConnection deletes a network connection
 func (s *NetworkService) DeleteConnection(id int) error {
-	result, err := database.DB.Exec("DELETE FROM network_connections WHERE id = $1", id)
+	return s.connections.DeleteConnection(id)
+}
+
+// GetPortUsage counts the free and used interfaces of each device, optionally within one rack.
+// Devices without interfaces are omitted.
+func (s *NetworkService) GetPortUsage(rackID *int) ([]models.PortUsage, error) {
+	devices, err := s.devices.ListDevices(store.DeviceFilter{RackID: rackID})
 	if err != nil {
-		return fmt.Errorf("failed to delete connection: %w", err)
+		return nil, err
 	}
-
-	rowsAffected, err := result.RowsAffected()
+	interfaces, err := s.interfaces.ListInterfaces(store.InterfaceFilter{RackID: rackID})
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("connection not found")
+	byDevice := map[int]*models.PortUsage{}
+	for _, iface := range interfaces {
+		u, ok := byDevice[iface.DeviceID]
+		if !ok {
+			u = &models.PortUsage{DeviceID: iface.DeviceID}
+			byDevice[iface.DeviceID] = u
+		}
+		u.Total++
+		switch {
+		case iface.ConnectionID != nil:
+			u.Used++
+		case iface.Enabled:
+			u.Free++
+		}
+		if !iface.Enabled {
+			u.Disabled++
+		}
 	}
 
-	return nil
+	// Devices are listed by rack, so ordering by rack then ID only needs a stable sort by ID within racks
+	usage := []models.PortUsage{}
+	for _, device := range devices {
+		if u, ok := byDevice[device.ID]; ok {
+			u.DeviceName = device.Name
+			u.RackID = device.RackID
+			usage = append(usage, *u)
+		}
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].RackID != usage[j].RackID {
+			return usage[i].RackID < usage[j].RackID
+		}
+		return usage[i].DeviceID < usage[j].DeviceID
+	})
+
+	return usage, nil
 }
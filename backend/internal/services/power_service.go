@@ -0,0 +1,161 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// PowerConfig controls rack power budget reporting and enforcement
+type PowerConfig struct {
+	// Threshold is the utilization ratio above which a rack is flagged
+	Threshold float64
+	// Strict rejects device placements that would exceed the rack's power capacity
+	Strict bool
+}
+
+// LoadPowerConfig reads the power budget configuration from the environment
+func LoadPowerConfig() PowerConfig {
+	config := PowerConfig{
+		Threshold: getEnvFloat("RACK_POWER_THRESHOLD", 0.8),
+		Strict:    getEnvBool("RACK_POWER_STRICT", false),
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = 0.8
+	}
+	return config
+}
+
+// devicePowerDraw returns the draw used for budgeting: measured when known, otherwise nameplate
+func devicePowerDraw(nameplateWatts, measuredWatts int) (int, models.PowerSource) {
+	switch {
+	case measuredWatts > 0:
+		return measuredWatts, models.PowerSourceMeasured
+	case nameplateWatts > 0:
+		return nameplateWatts, models.PowerSourceNameplate
+	default:
+		return 0, models.PowerSourceUnknown
+	}
+}
+
+// PowerService handles rack power budget reporting
+type PowerService struct {
+	racks   store.RackStore
+	devices store.DeviceStore
+	config  PowerConfig
+}
+
+// NewPowerService creates a new power service
+func NewPowerService(racks store.RackStore, devices store.DeviceStore) *PowerService {
+	return &PowerService{racks: racks, devices: devices, config: LoadPowerConfig()}
+}
+
+// GetRackPower sums the power draw of a rack's devices and compares it against the rack capacity.
+// A nil threshold uses the configured default.
+func (s *PowerService) GetRackPower(rackID int, threshold *float64) (*models.RackPower, error) {
+	report := &models.RackPower{
+		RackID:    rackID,
+		Threshold: s.config.Threshold,
+		Devices:   []models.DevicePower{},
+	}
+	if threshold != nil {
+		report.Threshold = *threshold
+	}
+
+	rack, err := s.racks.GetRack(rackID)
+	if err != nil {
+		return nil, err
+	}
+	report.RackName = rack.Name
+	report.CapacityWatts = rack.PowerCapacityWatts
+
+	devices, err := s.devices.ListDevices(store.DeviceFilter{RackID: &rackID})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(devices, func(i, j int) bool { return devices[i].PositionU > devices[j].PositionU })
+
+	for _, d := range devices {
+		device := models.DevicePower{
+			DeviceID:       d.ID,
+			Name:           d.Name,
+			PositionU:      d.PositionU,
+			NameplateWatts: d.NameplateWatts,
+			MeasuredWatts:  d.MeasuredWatts,
+			PSUCount:       d.PSUCount,
+			PSURedundancy:  d.PSURedundancy,
+		}
+		device.DrawWatts, device.Source = devicePowerDraw(device.NameplateWatts, device.MeasuredWatts)
+
+		report.NameplateWatts += device.NameplateWatts
+		report.MeasuredWatts += device.MeasuredWatts
+		report.DrawWatts += device.DrawWatts
+		if device.Source == models.PowerSourceUnknown {
+			report.UnknownDevices++
+		}
+		report.Devices = append(report.Devices, device)
+	}
+
+	if report.CapacityWatts > 0 {
+		headroom := report.CapacityWatts - report.DrawWatts
+		utilization := float64(report.DrawWatts) / float64(report.CapacityWatts)
+		report.HeadroomWatts = &headroom
+		report.Utilization = &utilization
+		report.OverThreshold = utilization > report.Threshold
+		report.OverCapacity = headroom < 0
+	}
+
+	return report, nil
+}
+
+// descendantPowerDraw sums the draw of every device installed (directly or nested) in a chassis
+func descendantPowerDraw(devices store.DeviceStore, parentID int) (int, error) {
+	children, err := childDevices(devices, parentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum child device power: %w", err)
+	}
+
+	var draw int
+	var sum func(children []models.Device)
+	sum = func(children []models.Device) {
+		for _, child := range children {
+			childDraw, _ := devicePowerDraw(child.NameplateWatts, child.MeasuredWatts)
+			draw += childDraw
+			sum(child.Children)
+		}
+	}
+	sum(children)
+	return draw, nil
+}
+
+// rackPowerBudgetCheck returns the placement check that a device drawing drawWatts keeps a rack
+// within its power capacity. The capacity is read here; the draw of the other devices is summed
+// in the device write transaction, so concurrent writes cannot both pass. Racks without a capacity
+// are not enforced.
+func rackPowerBudgetCheck(racks store.RackStore, rackID, drawWatts int) (store.PlacementCheck, error) {
+	if drawWatts <= 0 {
+		return nil, nil
+	}
+
+	rack, err := racks.GetRack(rackID)
+	if err != nil {
+		return nil, err
+	}
+	if rack.PowerCapacityWatts <= 0 {
+		return nil, nil
+	}
+
+	return func(rackDevices []models.Device) error {
+		var currentDraw int
+		for _, device := range rackDevices {
+			draw, _ := devicePowerDraw(device.NameplateWatts, device.MeasuredWatts)
+			currentDraw += draw
+		}
+		if currentDraw+drawWatts > rack.PowerCapacityWatts {
+			return fmt.Errorf("device would exceed rack power budget (%d W in use + %d W > %d W capacity)", currentDraw, drawWatts, rack.PowerCapacityWatts)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// newStrictDeviceService returns a device service enforcing power budgets and a rack with a
+// 1000 W capacity
+func newStrictDeviceService(t *testing.T) (*store.Store, *DeviceService, *models.Rack) {
+	t.Helper()
+	t.Setenv("RACK_POWER_STRICT", "true")
+	s := store.NewMemoryStore()
+	rack := &models.Rack{Name: "A", SizeU: 42, PowerCapacityWatts: 1000}
+	if err := s.Racks.CreateRack(rack); err != nil {
+		t.Fatalf("CreateRack: %v", err)
+	}
+	return s, NewDeviceService(s.Devices, s.Racks, s.DeviceTypes, s.Interfaces, s.IPAM), rack
+}
+
+func createPoweredDevice(service *DeviceService, rackID int, name string, positionU, watts int) (*models.Device, error) {
+	return service.CreateDevice(models.CreateDeviceRequest{
+		RackID:         rackID,
+		Name:           name,
+		Type:           models.DeviceTypeServer,
+		PositionU:      positionU,
+		SizeU:          1,
+		NameplateWatts: watts,
+	})
+}
+
+func TestStrictPowerBudget(t *testing.T) {
+	_, service, rack := newStrictDeviceService(t)
+
+	if _, err := createPoweredDevice(service, rack.ID, "a", 10, 600); err != nil {
+		t.Fatalf("CreateDevice within budget: %v", err)
+	}
+	if _, err := createPoweredDevice(service, rack.ID, "b", 11, 500); err == nil || !strings.Contains(err.Error(), "power budget") {
+		t.Errorf("CreateDevice over budget: err = %v, want a power budget error", err)
+	}
+	b, err := createPoweredDevice(service, rack.ID, "b", 11, 400)
+	if err != nil {
+		t.Fatalf("CreateDevice up to the budget: %v", err)
+	}
+
+	more := 500
+	if _, err := service.UpdateDevice(b.ID, models.UpdateDeviceRequest{NameplateWatts: &more}); err == nil || !strings.Contains(err.Error(), "power budget") {
+		t.Errorf("UpdateDevice over budget: err = %v, want a power budget error", err)
+	}
+	less := 100
+	if _, err := service.UpdateDevice(b.ID, models.UpdateDeviceRequest{NameplateWatts: &less}); err != nil {
+		t.Errorf("UpdateDevice lowering the draw: %v", err)
+	}
+}
+
+// barrierDeviceStore holds every CreateDevice until all the expected callers have arrived, so
+// concurrent creates finish their checks outside the store before any of them writes
+type barrierDeviceStore struct {
+	store.DeviceStore
+	arrived sync.WaitGroup
+}
+
+func (b *barrierDeviceStore) CreateDevice(device *models.Device, interfaces []*models.DeviceInterface, check store.PlacementCheck) error {
+	b.arrived.Done()
+	b.arrived.Wait()
+	return b.DeviceStore.CreateDevice(device, interfaces, check)
+}
+
+func TestStrictPowerBudgetConcurrentCreates(t *testing.T) {
+	s, _, rack := newStrictDeviceService(t)
+
+	// Each device fits alone, but any two exceed the budget
+	const attempts = 8
+	barrier := &barrierDeviceStore{DeviceStore: s.Devices}
+	barrier.arrived.Add(attempts)
+	service := NewDeviceService(barrier, s.Racks, s.DeviceTypes, s.Interfaces, s.IPAM)
+
+	var wg sync.WaitGroup
+	errs := make([]error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = createPoweredDevice(service, rack.ID, "server", 1+i, 600)
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !strings.Contains(err.Error(), "power budget"):
+			t.Errorf("CreateDevice: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d concurrent creates succeeded, want 1", created)
+	}
+
+	devices, err := s.Devices.ListDevices(store.DeviceFilter{RackID: &rack.ID})
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Errorf("%d devices in the rack, want 1", len(devices))
+	}
+}
+
+func TestGetRackPower(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := &models.Rack{Name: "A", SizeU: 42, PowerCapacityWatts: 1000}
+	if err := s.Racks.CreateRack(rack); err != nil {
+		t.Fatalf("CreateRack: %v", err)
+	}
+	measured := newDevice(rack.ID, "measured", 10, 1)
+	measured.NameplateWatts, measured.MeasuredWatts = 800, 450
+	mustCreateDevice(t, s, measured)
+	nameplate := newDevice(rack.ID, "nameplate", 20, 1)
+	nameplate.NameplateWatts = 400
+	mustCreateDevice(t, s, nameplate)
+	mustCreateDevice(t, s, newDevice(rack.ID, "unknown", 30, 1))
+
+	threshold := 0.8
+	report, err := NewPowerService(s.Racks, s.Devices).GetRackPower(rack.ID, &threshold)
+	if err != nil {
+		t.Fatalf("GetRackPower: %v", err)
+	}
+	if report.DrawWatts != 850 || report.NameplateWatts != 1200 || report.MeasuredWatts != 450 || report.UnknownDevices != 1 {
+		t.Errorf("GetRackPower = draw %d, nameplate %d, measured %d, unknown %d; want 850, 1200, 450, 1",
+			report.DrawWatts, report.NameplateWatts, report.MeasuredWatts, report.UnknownDevices)
+	}
+	if report.HeadroomWatts == nil || *report.HeadroomWatts != 150 || !report.OverThreshold || report.OverCapacity {
+		t.Errorf("GetRackPower headroom = %v, over threshold %v, over capacity %v; want 150, true, false",
+			report.HeadroomWatts, report.OverThreshold, report.OverCapacity)
+	}
+}
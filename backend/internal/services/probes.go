@@ -0,0 +1,523 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// defaultProbeTimeout applies when a probe doesn't configure timeout_ms
+const defaultProbeTimeout = 5 * time.Second
+
+// defaultTCPPorts are tried when a device has an IP address but no configured probes
+var defaultTCPPorts = []int{80, 443, 22, 8080, 8443, 3389}
+
+// ProbeResult is the outcome of a single probe
+type ProbeResult struct {
+	Name    string                 `json:"name,omitempty"`
+	Type    models.HealthProbeType `json:"type"`
+	Status  models.DeviceStatus    `json:"status"`
+	Latency int64                  `json:"latency_ms,omitempty"`
+	Message string                 `json:"message"`
+}
+
+// Prober checks one aspect of a device's health
+type Prober interface {
+	Probe(ctx context.Context, device *models.Device) ProbeResult
+}
+
+// NewProber builds the prober for a configured probe, validating its settings
+func NewProber(probe models.HealthProbe) (Prober, error) {
+	config := probe.Config
+	timeout := defaultProbeTimeout
+	if config.TimeoutMs > 0 {
+		timeout = time.Duration(config.TimeoutMs) * time.Millisecond
+	}
+
+	switch probe.Type {
+	case models.HealthProbeTypeTCP:
+		if len(config.Ports) == 0 {
+			return nil, fmt.Errorf("tcp probe requires at least one port")
+		}
+		for _, port := range config.Ports {
+			if port < 1 || port > 65535 {
+				return nil, fmt.Errorf("invalid tcp port %d", port)
+			}
+		}
+		return &TCPProber{Host: config.Host, Ports: config.Ports, RequireAll: true, Timeout: timeout}, nil
+
+	case models.HealthProbeTypeHTTP:
+		method := strings.ToUpper(config.Method)
+		if method == "" {
+			method = http.MethodGet
+		}
+		for _, status := range config.ExpectedStatus {
+			if status < 100 || status > 599 {
+				return nil, fmt.Errorf("invalid expected status %d", status)
+			}
+		}
+		return &HTTPProber{
+			URL:                config.URL,
+			Method:             method,
+			RequestHeaders:     config.RequestHeaders,
+			ExpectedStatus:     config.ExpectedStatus,
+			BodyContains:       config.BodyContains,
+			ExpectedHeaders:    config.ExpectedHeaders,
+			InsecureSkipVerify: config.InsecureSkipVerify,
+			Timeout:            timeout,
+		}, nil
+
+	case models.HealthProbeTypeTLS:
+		port := config.Port
+		if port == 0 {
+			port = 443
+		}
+		if port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid tls port %d", port)
+		}
+		warnDays, criticalDays := config.WarnDays, config.CriticalDays
+		if warnDays == 0 {
+			warnDays = 30
+		}
+		if criticalDays == 0 {
+			criticalDays = 7
+		}
+		if criticalDays > warnDays {
+			return nil, fmt.Errorf("critical_days must not exceed warn_days")
+		}
+		return &TLSProber{
+			Host:         config.Host,
+			Port:         port,
+			ServerName:   config.ServerName,
+			WarnDays:     warnDays,
+			CriticalDays: criticalDays,
+			Timeout:      timeout,
+		}, nil
+
+	case models.HealthProbeTypeDNS:
+		if config.QueryName == "" {
+			return nil, fmt.Errorf("dns probe requires query_name")
+		}
+		recordType := strings.ToUpper(config.RecordType)
+		if recordType == "" {
+			recordType = "A"
+		}
+		switch recordType {
+		case "A", "AAAA", "CNAME", "MX", "TXT":
+		default:
+			return nil, fmt.Errorf("unsupported dns record type %q", config.RecordType)
+		}
+		return &DNSProber{
+			QueryName:  config.QueryName,
+			RecordType: recordType,
+			Expected:   config.Expected,
+			Resolver:   config.Resolver,
+			Timeout:    timeout,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown probe type %q", probe.Type)
+}
+
+// TCPProber checks that TCP ports accept connections
+type TCPProber struct {
+	Host  string
+	Ports []int
+	// RequireAll reports a warning when only some ports are open; otherwise one open port is enough
+	RequireAll bool
+	Timeout    time.Duration
+}
+
+// Probe implements Prober
+func (p *TCPProber) Probe(ctx context.Context, device *models.Device) ProbeResult {
+	result := ProbeResult{Type: models.HealthProbeTypeTCP}
+
+	host := p.Host
+	if host == "" {
+		host = device.IPAddress
+	}
+	if host == "" {
+		result.Status = models.DeviceStatusUnknown
+		result.Message = "No host configured (set host or the device IP address)"
+		return result
+	}
+
+	dialer := &net.Dialer{Timeout: p.Timeout}
+	var open, closed []string
+	for _, port := range p.Ports {
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			closed = append(closed, strconv.Itoa(port))
+			continue
+		}
+		conn.Close()
+		if result.Latency == 0 {
+			result.Latency = time.Since(start).Milliseconds()
+		}
+		open = append(open, strconv.Itoa(port))
+		if !p.RequireAll {
+			break
+		}
+	}
+
+	switch {
+	case len(open) == 0:
+		result.Status = models.DeviceStatusOffline
+		result.Message = fmt.Sprintf("%s unreachable on ports %s", host, strings.Join(closed, ", "))
+	case len(closed) > 0 && p.RequireAll:
+		result.Status = models.DeviceStatusWarning
+		result.Message = fmt.Sprintf("%s open on %s, closed on %s", host, strings.Join(open, ", "), strings.Join(closed, ", "))
+	default:
+		result.Status = models.DeviceStatusOnline
+		result.Message = fmt.Sprintf("%s open on %s (%dms)", host, strings.Join(open, ", "), result.Latency)
+	}
+
+	return result
+}
+
+// HTTPProber sends an HTTP request and asserts on the response
+type HTTPProber struct {
+	URL                string
+	Method             string
+	RequestHeaders     map[string]string
+	ExpectedStatus     []int
+	BodyContains       string
+	ExpectedHeaders    map[string]string
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+}
+
+// maxProbeBodySize caps how much of a response body is read for body_contains
+const maxProbeBodySize = 1 << 20
+
+// Probe implements Prober; unreachable hosts and 5xx responses are offline, other failed assertions are warnings
+func (p *HTTPProber) Probe(ctx context.Context, device *models.Device) ProbeResult {
+	result := ProbeResult{Type: models.HealthProbeTypeHTTP}
+
+	url := p.URL
+	if url == "" {
+		url = device.HealthCheckURL
+	}
+	if url == "" {
+		result.Status = models.DeviceStatusUnknown
+		result.Message = "No URL configured (set url or the device health check URL)"
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, p.Method, url, nil)
+	if err != nil {
+		result.Status = models.DeviceStatusOffline
+		result.Message = fmt.Sprintf("HTTP check failed: %v", err)
+		return result
+	}
+	for key, value := range p.RequestHeaders {
+		req.Header.Set(key, value)
+	}
+
+	client := &http.Client{Timeout: p.Timeout}
+	if p.InsecureSkipVerify {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Status = models.DeviceStatusOffline
+		result.Message = fmt.Sprintf("HTTP check failed: %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	result.Latency = time.Since(start).Milliseconds()
+
+	if !p.statusExpected(resp.StatusCode) {
+		result.Status = models.DeviceStatusWarning
+		if resp.StatusCode >= 500 {
+			result.Status = models.DeviceStatusOffline
+		}
+		result.Message = fmt.Sprintf("HTTP check failed: unexpected status %d", resp.StatusCode)
+		return result
+	}
+
+	for key, expected := range p.ExpectedHeaders {
+		if actual := resp.Header.Get(key); !strings.Contains(actual, expected) {
+			result.Status = models.DeviceStatusWarning
+			result.Message = fmt.Sprintf("HTTP check failed: header %s is %q, expected %q", key, actual, expected)
+			return result
+		}
+	}
+
+	if p.BodyContains != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodySize))
+		if err != nil {
+			result.Status = models.DeviceStatusWarning
+			result.Message = fmt.Sprintf("HTTP check failed: reading body: %v", err)
+			return result
+		}
+		if !strings.Contains(string(body), p.BodyContains) {
+			result.Status = models.DeviceStatusWarning
+			result.Message = fmt.Sprintf("HTTP check failed: body does not contain %q", p.BodyContains)
+			return result
+		}
+	}
+
+	result.Status = models.DeviceStatusOnline
+	result.Message = fmt.Sprintf("HTTP check successful (%d, %dms)", resp.StatusCode, result.Latency)
+	return result
+}
+
+// statusExpected reports whether a status code passes; any 2xx passes when no codes are configured
+func (p *HTTPProber) statusExpected(code int) bool {
+	if len(p.ExpectedStatus) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, expected := range p.ExpectedStatus {
+		if code == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// TLSProber performs a TLS handshake and checks certificate expiry, hostname and chain
+type TLSProber struct {
+	Host         string
+	Port         int
+	ServerName   string
+	WarnDays     int
+	CriticalDays int
+	Timeout      time.Duration
+}
+
+// Probe implements Prober; certificates expiring within CriticalDays are offline, within WarnDays a warning
+func (p *TLSProber) Probe(ctx context.Context, device *models.Device) ProbeResult {
+	result := ProbeResult{Type: models.HealthProbeTypeTLS}
+
+	host := p.Host
+	if host == "" {
+		host = device.IPAddress
+	}
+	if host == "" {
+		result.Status = models.DeviceStatusUnknown
+		result.Message = "No host configured (set host or the device IP address)"
+		return result
+	}
+
+	serverName := p.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: p.Timeout},
+		// Skip verification so expiry can be read from any certificate; the chain and hostname are checked below
+		Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+	}
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(p.Port)))
+	if err != nil {
+		result.Status = models.DeviceStatusOffline
+		result.Message = fmt.Sprintf("TLS handshake failed: %v", err)
+		return result
+	}
+	defer conn.Close()
+	result.Latency = time.Since(start).Milliseconds()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		result.Status = models.DeviceStatusWarning
+		result.Message = "TLS handshake returned no certificates"
+		return result
+	}
+
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, chainErr := leaf.Verify(x509.VerifyOptions{Intermediates: intermediates})
+
+	remaining := time.Until(leaf.NotAfter)
+	days := int(remaining.Hours() / 24)
+
+	switch {
+	case remaining <= 0:
+		result.Status = models.DeviceStatusOffline
+		result.Message = fmt.Sprintf("Certificate for %s expired on %s", serverName, leaf.NotAfter.Format("2006-01-02"))
+	case days < p.CriticalDays:
+		result.Status = models.DeviceStatusOffline
+		result.Message = fmt.Sprintf("Certificate for %s expires in %d days", serverName, days)
+	case days < p.WarnDays:
+		result.Status = models.DeviceStatusWarning
+		result.Message = fmt.Sprintf("Certificate for %s expires in %d days", serverName, days)
+	case leaf.VerifyHostname(serverName) != nil:
+		result.Status = models.DeviceStatusWarning
+		result.Message = fmt.Sprintf("Certificate does not match %s", serverName)
+	case chainErr != nil:
+		result.Status = models.DeviceStatusWarning
+		result.Message = fmt.Sprintf("Certificate for %s is not trusted: %v", serverName, chainErr)
+	default:
+		result.Status = models.DeviceStatusOnline
+		result.Message = fmt.Sprintf("TLS handshake successful, certificate valid for %d days (%dms)", days, result.Latency)
+	}
+
+	return result
+}
+
+// DNSProber resolves a name and optionally checks the answers
+type DNSProber struct {
+	QueryName  string
+	RecordType string
+	Expected   []string
+	Resolver   string
+	Timeout    time.Duration
+}
+
+// Probe implements Prober; resolution failures are offline and missing expected answers a warning
+func (p *DNSProber) Probe(ctx context.Context, device *models.Device) ProbeResult {
+	result := ProbeResult{Type: models.HealthProbeTypeDNS}
+
+	resolver := net.DefaultResolver
+	if p.Resolver != "" {
+		address := p.Resolver
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				dialer := net.Dialer{Timeout: p.Timeout}
+				return dialer.DialContext(ctx, network, address)
+			},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	answers, err := p.lookup(ctx, resolver)
+	if err != nil {
+		result.Status = models.DeviceStatusOffline
+		result.Message = fmt.Sprintf("DNS %s lookup for %s failed: %v", p.RecordType, p.QueryName, err)
+		return result
+	}
+	result.Latency = time.Since(start).Milliseconds()
+
+	found := make(map[string]bool, len(answers))
+	for _, answer := range answers {
+		found[strings.TrimSuffix(strings.ToLower(answer), ".")] = true
+	}
+	for _, expected := range p.Expected {
+		if !found[strings.TrimSuffix(strings.ToLower(expected), ".")] {
+			result.Status = models.DeviceStatusWarning
+			result.Message = fmt.Sprintf("DNS %s lookup for %s returned %s, expected %s",
+				p.RecordType, p.QueryName, strings.Join(answers, ", "), expected)
+			return result
+		}
+	}
+
+	result.Status = models.DeviceStatusOnline
+	result.Message = fmt.Sprintf("DNS %s lookup for %s returned %s (%dms)", p.RecordType, p.QueryName, strings.Join(answers, ", "), result.Latency)
+	return result
+}
+
+// lookup resolves the configured record type into answer strings
+func (p *DNSProber) lookup(ctx context.Context, resolver *net.Resolver) ([]string, error) {
+	var answers []string
+
+	switch p.RecordType {
+	case "A", "AAAA":
+		addrs, err := resolver.LookupIPAddr(ctx, p.QueryName)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if (addr.IP.To4() != nil) == (p.RecordType == "A") {
+				answers = append(answers, addr.IP.String())
+			}
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, p.QueryName)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case "MX":
+		records, err := resolver.LookupMX(ctx, p.QueryName)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			answers = append(answers, record.Host)
+		}
+	case "TXT":
+		records, err := resolver.LookupTXT(ctx, p.QueryName)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+	}
+
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("no %s records", p.RecordType)
+	}
+	return answers, nil
+}
+
+// statusSeverity orders statuses from healthy to failed for combining probe results
+var statusSeverity = map[models.DeviceStatus]int{
+	models.DeviceStatusOnline:  0,
+	models.DeviceStatusUnknown: 1,
+	models.DeviceStatusWarning: 2,
+	models.DeviceStatusOffline: 3,
+}
+
+// CombineProbeResults reduces probe results to a single device status using the device's mode
+func CombineProbeResults(mode models.HealthCheckMode, results []ProbeResult) models.DeviceStatus {
+	if len(results) == 0 {
+		return models.DeviceStatusUnknown
+	}
+
+	best, worst := results[0].Status, results[0].Status
+	online := 0
+	for _, result := range results {
+		if statusSeverity[result.Status] < statusSeverity[best] {
+			best = result.Status
+		}
+		if statusSeverity[result.Status] > statusSeverity[worst] {
+			worst = result.Status
+		}
+		if result.Status == models.DeviceStatusOnline {
+			online++
+		}
+	}
+
+	switch mode {
+	case models.HealthCheckModeAny:
+		return best
+	case models.HealthCheckModeMajority:
+		if online*2 > len(results) {
+			return models.DeviceStatusOnline
+		}
+		if best == models.DeviceStatusOnline || best == models.DeviceStatusWarning {
+			return models.DeviceStatusWarning
+		}
+		return worst
+	default:
+		return worst
+	}
+}
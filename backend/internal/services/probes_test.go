@@ -0,0 +1,220 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func TestCombineProbeResults(t *testing.T) {
+	online, warning, offline, unknown := models.DeviceStatusOnline, models.DeviceStatusWarning, models.DeviceStatusOffline, models.DeviceStatusUnknown
+	results := func(statuses ...models.DeviceStatus) []ProbeResult {
+		var probes []ProbeResult
+		for _, status := range statuses {
+			probes = append(probes, ProbeResult{Status: status})
+		}
+		return probes
+	}
+
+	tests := []struct {
+		name    string
+		mode    models.HealthCheckMode
+		results []ProbeResult
+		want    models.DeviceStatus
+	}{
+		{"no probes", models.HealthCheckModeAll, nil, unknown},
+		{"all passing", models.HealthCheckModeAll, results(online, online), online},
+		{"all takes the worst", models.HealthCheckModeAll, results(online, warning, offline), offline},
+		{"all with an unknown", models.HealthCheckModeAll, results(online, unknown), unknown},
+		{"unset mode is all", "", results(online, warning), warning},
+		{"any takes the best", models.HealthCheckModeAny, results(offline, warning, online), online},
+		{"any all failing", models.HealthCheckModeAny, results(offline, offline), offline},
+		{"majority passing", models.HealthCheckModeMajority, results(online, online, offline), online},
+		{"majority on a tie", models.HealthCheckModeMajority, results(online, offline), warning},
+		{"majority with a warning", models.HealthCheckModeMajority, results(warning, offline, offline), warning},
+		{"majority all failing", models.HealthCheckModeMajority, results(offline, unknown), offline},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CombineProbeResults(tt.mode, tt.results); got != tt.want {
+				t.Errorf("CombineProbeResults(%s) = %s, want %s", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProberValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		probe   models.HealthProbe
+		wantErr string
+	}{
+		{"tcp without ports", models.HealthProbe{Type: models.HealthProbeTypeTCP}, "at least one port"},
+		{"tcp port out of range", models.HealthProbe{Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{70000}}}, "invalid tcp port"},
+		{"http bad expected status", models.HealthProbe{Type: models.HealthProbeTypeHTTP, Config: models.HealthProbeConfig{ExpectedStatus: []int{42}}}, "invalid expected status"},
+		{"tls critical after warn", models.HealthProbe{Type: models.HealthProbeTypeTLS, Config: models.HealthProbeConfig{WarnDays: 5, CriticalDays: 10}}, "critical_days"},
+		{"dns without name", models.HealthProbe{Type: models.HealthProbeTypeDNS}, "query_name"},
+		{"dns bad record type", models.HealthProbe{Type: models.HealthProbeTypeDNS, Config: models.HealthProbeConfig{QueryName: "example.com", RecordType: "SRV"}}, "unsupported dns record type"},
+		{"unknown type", models.HealthProbe{Type: "icmp"}, "unknown probe type"},
+		{"valid tcp", models.HealthProbe{Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{22}}}, ""},
+		{"valid tls defaults", models.HealthProbe{Type: models.HealthProbeTypeTLS}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProber(tt.probe)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("NewProber: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewProber: err = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// localPorts returns a port accepting connections on 127.0.0.1 and one refusing them
+func localPorts(t *testing.T) (open, closed int) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	refused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closed = refused.Addr().(*net.TCPAddr).Port
+	refused.Close()
+	return listener.Addr().(*net.TCPAddr).Port, closed
+}
+
+func TestCheckDeviceHealthCombinesProbes(t *testing.T) {
+	open, closed := localPorts(t)
+
+	tests := []struct {
+		mode models.HealthCheckMode
+		want models.DeviceStatus
+	}{
+		{models.HealthCheckModeAll, models.DeviceStatusOffline},
+		{models.HealthCheckModeAny, models.DeviceStatusOnline},
+		{models.HealthCheckModeMajority, models.DeviceStatusWarning},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			s := store.NewMemoryStore()
+			rack := mustCreateRack(t, s, "A")
+			device := newDevice(rack.ID, "web-1", 10, 1)
+			device.IPAddress = "127.0.0.1"
+			device.HealthCheckMode = tt.mode
+			mustCreateDevice(t, s, device)
+			for _, probe := range []*models.HealthProbe{
+				{DeviceID: device.ID, Name: "up", Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{open}}, Enabled: true},
+				{DeviceID: device.ID, Name: "down", Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{closed}}, Enabled: true},
+				{DeviceID: device.ID, Name: "off", Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{closed}}},
+			} {
+				if err := s.HealthProbes.CreateHealthProbe(probe); err != nil {
+					t.Fatalf("CreateHealthProbe(%s): %v", probe.Name, err)
+				}
+			}
+
+			service := NewHealthService(s.Devices, s.HealthProbes, s.HealthChecks)
+			result, err := service.CheckDeviceHealth(device.ID)
+			if err != nil {
+				t.Fatalf("CheckDeviceHealth: %v", err)
+			}
+			if result.Status != tt.want {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.want, result.Message)
+			}
+
+			// Disabled probes don't run; the others are reported by name in probe order
+			var names []string
+			for _, probe := range result.Probes {
+				names = append(names, probe.Name+"="+string(probe.Status))
+			}
+			if got, want := strings.Join(names, ","), "up=online,down=offline"; got != want {
+				t.Errorf("probes = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestCheckDeviceHealthReportsInvalidProbes(t *testing.T) {
+	open, _ := localPorts(t)
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 1)
+	device.IPAddress = "127.0.0.1"
+	mustCreateDevice(t, s, device)
+	for _, probe := range []*models.HealthProbe{
+		{DeviceID: device.ID, Name: "up", Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{open}}, Enabled: true},
+		{DeviceID: device.ID, Name: "broken", Type: models.HealthProbeTypeDNS, Enabled: true},
+	} {
+		if err := s.HealthProbes.CreateHealthProbe(probe); err != nil {
+			t.Fatalf("CreateHealthProbe(%s): %v", probe.Name, err)
+		}
+	}
+
+	result, err := NewHealthService(s.Devices, s.HealthProbes, s.HealthChecks).CheckDeviceHealth(device.ID)
+	if err != nil {
+		t.Fatalf("CheckDeviceHealth: %v", err)
+	}
+	// A probe that can't run is unknown, which an all-mode device can't call online
+	if result.Status != models.DeviceStatusUnknown {
+		t.Errorf("status = %s, want unknown", result.Status)
+	}
+	if len(result.Probes) != 2 || result.Probes[0].Name != "broken" || !strings.Contains(result.Probes[0].Message, "Invalid probe configuration") {
+		t.Errorf("probes = %+v, want the invalid probe reported first", result.Probes)
+	}
+}
+
+func TestCheckDeviceHealthWithoutProbes(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	unconfigured := mustCreateDevice(t, s, newDevice(rack.ID, "blank", 10, 1))
+	service := NewHealthService(s.Devices, s.HealthProbes, s.HealthChecks)
+
+	result, err := service.CheckDeviceHealth(unconfigured.ID)
+	if err != nil {
+		t.Fatalf("CheckDeviceHealth: %v", err)
+	}
+	if result.Status != models.DeviceStatusUnknown || len(result.Probes) != 0 {
+		t.Errorf("device without IP or URL = %+v, want unknown without probes", result)
+	}
+
+	// Without probes the defaults run in any mode, whatever the device's mode: the health check
+	// URL passing is enough even if none of the default ports are open
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	device := newDevice(rack.ID, "web-1", 20, 1)
+	device.IPAddress = "127.0.0.1"
+	device.HealthCheckURL = server.URL
+	device.HealthCheckMode = models.HealthCheckModeAll
+	mustCreateDevice(t, s, device)
+
+	result, err = service.CheckDeviceHealth(device.ID)
+	if err != nil {
+		t.Fatalf("CheckDeviceHealth: %v", err)
+	}
+	if result.Status != models.DeviceStatusOnline || len(result.Probes) != 2 {
+		t.Errorf("device with the default checks = %+v, want online from the HTTP and TCP checks", result)
+	}
+}
@@ -1,162 +1,108 @@
 package services
 
 import (
-	"database/sql"
 	"fmt"
 
-	"rackview/internal/database"
 	"rackview/internal/models"
+	"rackview/internal/store"
 )
 
 // RackService handles rack-related business logic
-type RackService struct{}
+type RackService struct {
+	racks     store.RackStore
+	devices   store.DeviceStore
+	locations *LocationService
+}
 
 // NewRackService creates a new rack service
-func NewRackService() *RackService {
-	return &RackService{}
+func NewRackService(racks store.RackStore, devices store.DeviceStore, locations store.LocationStore) *RackService {
+	return &RackService{racks: racks, devices: devices, locations: NewLocationService(locations, racks, devices)}
 }
 
-// GetAllRacks retrieves all racks
-func (s *RackService) GetAllRacks() ([]models.Rack, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, name, description, size_u, created_at, updated_at
-		FROM racks
-		ORDER BY id
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query racks: %w", err)
-	}
-	defer rows.Close()
-
-	var racks []models.Rack
-	for rows.Next() {
-		var rack models.Rack
-		if err := rows.Scan(&rack.ID, &rack.Name, &rack.Description, &rack.SizeU, &rack.CreatedAt, &rack.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan rack: %w", err)
-		}
-		racks = append(racks, rack)
-	}
-
-	return racks, nil
+// GetAllRacks retrieves all racks, optionally filtered by site, room or row
+func (s *RackService) GetAllRacks(filter models.RackFilter) ([]models.Rack, error) {
+	return s.racks.ListRacks(filter)
 }
 
 // GetRackByID retrieves a rack by ID with its devices
 func (s *RackService) GetRackByID(id int) (*models.Rack, error) {
-	var rack models.Rack
-	err := database.DB.QueryRow(`
-		SELECT id, name, description, size_u, created_at, updated_at
-		FROM racks
-		WHERE id = $1
-	`, id).Scan(&rack.ID, &rack.Name, &rack.Description, &rack.SizeU, &rack.CreatedAt, &rack.UpdatedAt)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("rack not found")
-	}
+	rack, err := s.racks.GetRack(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query rack: %w", err)
+		return nil, err
 	}
 
 	// Load devices for this rack
-	devices, err := NewDeviceService().GetDevicesByRackID(id)
+	devices, err := s.devices.ListDevices(store.DeviceFilter{RackID: &id})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load devices: %w", err)
 	}
 	rack.Devices = devices
+	rack.Elevation = buildElevation(devices)
 
-	return &rack, nil
+	return rack, nil
 }
 
 // CreateRack creates a new rack
 func (s *RackService) CreateRack(req models.CreateRackRequest) (*models.Rack, error) {
-	var rack models.Rack
-	err := database.DB.QueryRow(`
-		INSERT INTO racks (name, description, size_u)
-		VALUES ($1, $2, $3)
-		RETURNING id, name, description, size_u, created_at, updated_at
-	`, req.Name, req.Description, req.SizeU).Scan(
-		&rack.ID, &rack.Name, &rack.Description, &rack.SizeU, &rack.CreatedAt, &rack.UpdatedAt,
-	)
-
+	siteID, roomID, rowID, err := s.locations.ResolveLocation(req.SiteID, req.RoomID, req.RowID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create rack: %w", err)
+		return nil, err
+	}
+
+	rack := &models.Rack{
+		Name:               req.Name,
+		Description:        req.Description,
+		SizeU:              req.SizeU,
+		SiteID:             siteID,
+		RoomID:             roomID,
+		RowID:              rowID,
+		PowerCapacityWatts: req.PowerCapacityWatts,
+	}
+	if err := s.racks.CreateRack(rack); err != nil {
+		return nil, err
 	}
 
-	return &rack, nil
+	return rack, nil
 }
 
 // UpdateRack updates an existing rack
 func (s *RackService) UpdateRack(id int, req models.UpdateRackRequest) (*models.Rack, error) {
-	// Build dynamic update query
-	updates := []string{}
-	args := []interface{}{}
-	argPos := 1
+	if req.Name == "" && req.Description == "" && req.SizeU == nil && req.PowerCapacityWatts == nil &&
+		req.SiteID == nil && req.RoomID == nil && req.RowID == nil {
+		return s.GetRackByID(id)
+	}
+
+	rack, err := s.racks.GetRack(id)
+	if err != nil {
+		return nil, err
+	}
 
 	if req.Name != "" {
-		updates = append(updates, fmt.Sprintf("name = $%d", argPos))
-		args = append(args, req.Name)
-		argPos++
+		rack.Name = req.Name
 	}
 	if req.Description != "" {
-		updates = append(updates, fmt.Sprintf("description = $%d", argPos))
-		args = append(args, req.Description)
-		argPos++
+		rack.Description = req.Description
 	}
 	if req.SizeU != nil {
-		updates = append(updates, fmt.Sprintf("size_u = $%d", argPos))
-		args = append(args, *req.SizeU)
-		argPos++
+		rack.SizeU = *req.SizeU
 	}
-
-	if len(updates) == 0 {
-		return s.GetRackByID(id)
+	if req.PowerCapacityWatts != nil {
+		rack.PowerCapacityWatts = *req.PowerCapacityWatts
 	}
-
-	args = append(args, id)
-	setClause := ""
-	for i, update := range updates {
-		if i > 0 {
-			setClause += ", "
+	if req.SiteID != nil || req.RoomID != nil || req.RowID != nil {
+		if rack.SiteID, rack.RoomID, rack.RowID, err = s.locations.ResolveLocation(req.SiteID, req.RoomID, req.RowID); err != nil {
+			return nil, err
 		}
-		setClause += update
 	}
 
-	query := fmt.Sprintf(`
-		UPDATE racks
-		SET %s
-		WHERE id = $%d
-		RETURNING id, name, description, size_u, created_at, updated_at
-	`, setClause, argPos)
-
-	var rack models.Rack
-	err := database.DB.QueryRow(query, args...).Scan(
-		&rack.ID, &rack.Name, &rack.Description, &rack.SizeU, &rack.CreatedAt, &rack.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("rack not found")
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to update rack: %w", err)
+	if err := s.racks.UpdateRack(rack); err != nil {
+		return nil, err
 	}
 
-	return &rack, nil
+	return rack, nil
 }
 
 // DeleteRack deletes a rack
 func (s *RackService) DeleteRack(id int) error {
-	result, err := database.DB.Exec("DELETE FROM racks WHERE id = $1", id)
-	if err != nil {
-		return fmt.Errorf("failed to delete rack: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("rack not found")
-	}
-
-	return nil
+	return s.racks.DeleteRack(id)
 }
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rackview/internal/models"
+)
+
+// edgeLabel describes a connection by its ports and speed, e.g. "eth0 - Gi1/0/1, 10G"
+func edgeLabel(edge models.TopologyEdge) string {
+	parts := []string{}
+	switch {
+	case edge.SourcePort != "" || edge.TargetPort != "":
+		parts = append(parts, strings.TrimSpace(edge.SourcePort+" - "+edge.TargetPort))
+	case edge.PortInfo != "":
+		parts = append(parts, edge.PortInfo)
+	}
+	if edge.Speed != "" {
+		parts = append(parts, edge.Speed)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RenderTopologyDOT renders a topology as an undirected Graphviz graph with one cluster per rack
+func RenderTopologyDOT(topology *models.Topology) string {
+	nodes := topologyNodesByID(topology)
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+
+	var b strings.Builder
+	b.WriteString("graph rackview {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, rack := range topology.Racks {
+		fmt.Fprintf(&b, "  subgraph cluster_rack_%d {\n", rack.ID)
+		fmt.Fprintf(&b, "    label=%s;\n", quote(rack.Name))
+		for _, id := range rack.DeviceIDs {
+			node := nodes[id]
+			fmt.Fprintf(&b, "    d%d [label=%s, type=%s, status=%s];\n", node.ID, quote(node.Name), quote(string(node.Type)), quote(string(node.Status)))
+		}
+		b.WriteString("  }\n")
+	}
+	for _, edge := range topology.Edges {
+		fmt.Fprintf(&b, "  d%d -- d%d [label=%s", edge.Source, edge.Target, quote(edgeLabel(edge)))
+		if edge.ConnectionType != "" {
+			fmt.Fprintf(&b, ", connection_type=%s", quote(edge.ConnectionType))
+		}
+		b.WriteString("];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderTopologyMermaid renders a topology as a Mermaid flowchart with one subgraph per rack
+func RenderTopologyMermaid(topology *models.Topology) string {
+	nodes := topologyNodesByID(topology)
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+	}
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, rack := range topology.Racks {
+		fmt.Fprintf(&b, "  subgraph rack_%d[%s]\n", rack.ID, quote(rack.Name))
+		for _, id := range rack.DeviceIDs {
+			fmt.Fprintf(&b, "    d%d[%s]\n", id, quote(nodes[id].Name))
+		}
+		b.WriteString("  end\n")
+	}
+	for _, edge := range topology.Edges {
+		if label := edgeLabel(edge); label != "" {
+			fmt.Fprintf(&b, "  d%d ---|%s| d%d\n", edge.Source, quote(label), edge.Target)
+		} else {
+			fmt.Fprintf(&b, "  d%d --- d%d\n", edge.Source, edge.Target)
+		}
+	}
+	return b.String()
+}
+
+// GraphML document structure; racks are nodes with a nested graph of their devices
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID    string        `xml:"id,attr"`
+	Data  []graphMLData `xml:"data"`
+	Graph *graphMLGraph `xml:"graph,omitempty"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// RenderTopologyGraphML renders a topology as GraphML with one nested graph per rack
+func RenderTopologyGraphML(topology *models.Topology) (string, error) {
+	nodes := topologyNodesByID(topology)
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "kind", For: "node", AttrName: "kind", AttrType: "string"},
+			{ID: "type", For: "node", AttrName: "type", AttrType: "string"},
+			{ID: "status", For: "node", AttrName: "status", AttrType: "string"},
+			{ID: "label", For: "edge", AttrName: "label", AttrType: "string"},
+			{ID: "connection_type", For: "edge", AttrName: "connection_type", AttrType: "string"},
+			{ID: "speed", For: "edge", AttrName: "speed", AttrType: "string"},
+			{ID: "source_port", For: "edge", AttrName: "source_port", AttrType: "string"},
+			{ID: "target_port", For: "edge", AttrName: "target_port", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "rackview", EdgeDefault: "undirected"},
+	}
+
+	for _, rack := range topology.Racks {
+		rackID := "rack" + strconv.Itoa(rack.ID)
+		rackGraph := &graphMLGraph{ID: rackID + ":", EdgeDefault: "undirected"}
+		for _, id := range rack.DeviceIDs {
+			node := nodes[id]
+			rackGraph.Nodes = append(rackGraph.Nodes, graphMLNode{
+				ID: "d" + strconv.Itoa(node.ID),
+				Data: []graphMLData{
+					{Key: "name", Value: node.Name},
+					{Key: "kind", Value: "device"},
+					{Key: "type", Value: string(node.Type)},
+					{Key: "status", Value: string(node.Status)},
+				},
+			})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:    rackID,
+			Data:  []graphMLData{{Key: "name", Value: rack.Name}, {Key: "kind", Value: "rack"}},
+			Graph: rackGraph,
+		})
+	}
+
+	for _, edge := range topology.Edges {
+		data := []graphMLData{{Key: "label", Value: edgeLabel(edge)}}
+		for _, field := range []graphMLData{
+			{Key: "connection_type", Value: edge.ConnectionType},
+			{Key: "speed", Value: edge.Speed},
+			{Key: "source_port", Value: edge.SourcePort},
+			{Key: "target_port", Value: edge.TargetPort},
+		} {
+			if field.Value != "" {
+				data = append(data, field)
+			}
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     "c" + strconv.Itoa(edge.ID),
+			Source: "d" + strconv.Itoa(edge.Source),
+			Target: "d" + strconv.Itoa(edge.Target),
+			Data:   data,
+		})
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode GraphML: %w", err)
+	}
+	return xml.Header + string(out) + "\n", nil
+}
+
+// topologyNodesByID indexes the nodes of a topology
+func topologyNodesByID(topology *models.Topology) map[int]models.TopologyNode {
+	nodes := make(map[int]models.TopologyNode, len(topology.Nodes))
+	for _, node := range topology.Nodes {
+		nodes[node.ID] = node
+	}
+	return nodes
+}
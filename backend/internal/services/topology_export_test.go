@@ -0,0 +1,177 @@
+package services
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rackview/internal/models"
+)
+
+// exportTopology is two racks of devices with names that need escaping in every format
+func exportTopology() *models.Topology {
+	return &models.Topology{
+		Racks: []models.TopologyRack{
+			{ID: 1, Name: `Row "A"`, DeviceIDs: []int{10, 11}},
+			{ID: 2, Name: "B & C", DeviceIDs: []int{12}},
+		},
+		Nodes: []models.TopologyNode{
+			{ID: 10, Name: "core-1", Type: models.DeviceTypeNetwork, Status: models.DeviceStatusOnline, RackID: 1, RackName: `Row "A"`},
+			{ID: 11, Name: "web\n<1>", Type: models.DeviceTypeServer, Status: models.DeviceStatusOffline, RackID: 1, RackName: `Row "A"`},
+			{ID: 12, Name: `db\1`, Type: models.DeviceTypeServer, Status: models.DeviceStatusWarning, RackID: 2, RackName: "B & C"},
+		},
+		Edges: []models.TopologyEdge{
+			{ID: 100, Source: 11, Target: 10, SourcePort: "eth0", TargetPort: "swp1", ConnectionType: "Ethernet", Speed: "10G"},
+			{ID: 101, Source: 12, Target: 10, PortInfo: "port 2"},
+			{ID: 102, Source: 12, Target: 11},
+		},
+	}
+}
+
+func TestEdgeLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		edge models.TopologyEdge
+		want string
+	}{
+		{"ports and speed", models.TopologyEdge{SourcePort: "eth0", TargetPort: "swp1", Speed: "10G", PortInfo: "ignored"}, "eth0 - swp1, 10G"},
+		{"source port only", models.TopologyEdge{SourcePort: "eth0"}, "eth0 -"},
+		{"target port only", models.TopologyEdge{TargetPort: "swp1"}, "- swp1"},
+		{"port info", models.TopologyEdge{PortInfo: "port 2", Speed: "1G"}, "port 2, 1G"},
+		{"speed only", models.TopologyEdge{Speed: "1G"}, "1G"},
+		{"nothing", models.TopologyEdge{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := edgeLabel(tt.edge); got != tt.want {
+				t.Errorf("edgeLabel = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTopologyDOT(t *testing.T) {
+	want := `graph rackview {
+  node [shape=box];
+  subgraph cluster_rack_1 {
+    label="Row \"A\"";
+    d10 [label="core-1", type="network", status="online"];
+    d11 [label="web\n<1>", type="server", status="offline"];
+  }
+  subgraph cluster_rack_2 {
+    label="B & C";
+    d12 [label="db\\1", type="server", status="warning"];
+  }
+  d11 -- d10 [label="eth0 - swp1, 10G", connection_type="Ethernet"];
+  d12 -- d10 [label="port 2"];
+  d12 -- d11 [label=""];
+}
+`
+	if got := RenderTopologyDOT(exportTopology()); got != want {
+		t.Errorf("RenderTopologyDOT =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTopologyMermaid(t *testing.T) {
+	want := `graph LR
+  subgraph rack_1["Row #quot;A#quot;"]
+    d10["core-1"]
+    d11["web <1>"]
+  end
+  subgraph rack_2["B & C"]
+    d12["db\1"]
+  end
+  d11 ---|"eth0 - swp1, 10G"| d10
+  d12 ---|"port 2"| d10
+  d12 --- d11
+`
+	if got := RenderTopologyMermaid(exportTopology()); got != want {
+		t.Errorf("RenderTopologyMermaid =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// graphMLValues returns a node or edge's data as a key to value map
+func graphMLValues(data []graphMLData) map[string]string {
+	values := map[string]string{}
+	for _, d := range data {
+		values[d.Key] = d.Value
+	}
+	return values
+}
+
+func TestRenderTopologyGraphML(t *testing.T) {
+	out, err := RenderTopologyGraphML(exportTopology())
+	if err != nil {
+		t.Fatalf("RenderTopologyGraphML: %v", err)
+	}
+	if !strings.HasPrefix(out, xml.Header+"<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">") {
+		t.Errorf("document starts %q, want an XML header and the GraphML namespace", out[:80])
+	}
+
+	// The output parses back into the same structure, with names unescaped
+	var doc graphML
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output does not parse: %v\n%s", err, out)
+	}
+	if doc.Graph.ID != "rackview" || doc.Graph.EdgeDefault != "undirected" || len(doc.Keys) != 9 {
+		t.Errorf("graph = %s (%s) with %d keys, want rackview, undirected with 9 keys", doc.Graph.ID, doc.Graph.EdgeDefault, len(doc.Keys))
+	}
+
+	racks := map[string][]string{}
+	for _, rack := range doc.Graph.Nodes {
+		if values := graphMLValues(rack.Data); values["kind"] != "rack" {
+			t.Errorf("top-level node %s is a %q, want a rack", rack.ID, values["kind"])
+		}
+		if rack.Graph == nil || rack.Graph.ID != rack.ID+":" {
+			t.Fatalf("rack %s has nested graph %+v, want one named %s:", rack.ID, rack.Graph, rack.ID)
+		}
+		for _, device := range rack.Graph.Nodes {
+			values := graphMLValues(device.Data)
+			racks[rack.ID] = append(racks[rack.ID], device.ID+" "+values["name"]+" "+values["type"]+" "+values["status"])
+		}
+	}
+	wantRacks := map[string][]string{
+		"rack1": {"d10 core-1 network online", "d11 web\n<1> server offline"},
+		"rack2": {`d12 db\1 server warning`},
+	}
+	if !reflect.DeepEqual(racks, wantRacks) {
+		t.Errorf("racks = %q, want %q", racks, wantRacks)
+	}
+
+	// Edges are on the top-level graph and only carry the fields that are set
+	var edges []map[string]string
+	for _, edge := range doc.Graph.Edges {
+		values := graphMLValues(edge.Data)
+		values["id"], values["endpoints"] = edge.ID, edge.Source+"-"+edge.Target
+		edges = append(edges, values)
+	}
+	wantEdges := []map[string]string{
+		{"id": "c100", "endpoints": "d11-d10", "label": "eth0 - swp1, 10G", "connection_type": "Ethernet", "speed": "10G", "source_port": "eth0", "target_port": "swp1"},
+		{"id": "c101", "endpoints": "d12-d10", "label": "port 2"},
+		{"id": "c102", "endpoints": "d12-d11", "label": ""},
+	}
+	if !reflect.DeepEqual(edges, wantEdges) {
+		t.Errorf("edges = %v, want %v", edges, wantEdges)
+	}
+}
+
+func TestTopologyJSON(t *testing.T) {
+	topology := exportTopology()
+	topology.Racks = topology.Racks[1:]
+	topology.Nodes = topology.Nodes[2:]
+	topology.Edges = []models.TopologyEdge{{ID: 101, Source: 12, Target: 10, PortInfo: "port 2"}}
+
+	out, err := json.Marshal(topology)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	// Unset edge fields are left out, and HTML characters are escaped as gin does
+	want := `{"racks":[{"id":2,"name":"B \u0026 C","device_ids":[12]}],` +
+		`"nodes":[{"id":12,"name":"db\\1","type":"server","status":"warning","rack_id":2,"rack_name":"B \u0026 C"}],` +
+		`"edges":[{"id":101,"source":12,"target":10,"port_info":"port 2"}]}`
+	if string(out) != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", out, want)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"sort"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// GetTopology builds the device graph, grouped by rack, for the devices and connections
+// matching the filter
+func (s *NetworkService) GetTopology(filter models.TopologyFilter) (*models.Topology, error) {
+	racks, err := s.racks.ListRacks(models.RackFilter{})
+	if err != nil {
+		return nil, err
+	}
+	rackNames := map[int]string{}
+	for _, rack := range racks {
+		rackNames[rack.ID] = rack.Name
+	}
+
+	rackIDs := map[int]bool{}
+	for _, rackID := range filter.RackIDs {
+		rackIDs[rackID] = true
+	}
+	deviceTypes := map[models.DeviceType]bool{}
+	for _, deviceType := range filter.DeviceTypes {
+		deviceTypes[deviceType] = true
+	}
+
+	devices, err := s.devices.ListDevices(store.DeviceFilter{})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(devices, func(i, j int) bool {
+		if devices[i].RackID != devices[j].RackID {
+			return devices[i].RackID < devices[j].RackID
+		}
+		return devices[i].ID < devices[j].ID
+	})
+
+	topology := &models.Topology{
+		Racks: []models.TopologyRack{},
+		Nodes: []models.TopologyNode{},
+		Edges: []models.TopologyEdge{},
+	}
+	included := map[int]bool{}
+	for _, device := range devices {
+		if len(rackIDs) > 0 && !rackIDs[device.RackID] {
+			continue
+		}
+		if len(deviceTypes) > 0 && !deviceTypes[device.Type] {
+			continue
+		}
+		node := models.TopologyNode{
+			ID:       device.ID,
+			Name:     device.Name,
+			Type:     device.Type,
+			Status:   device.Status,
+			RackID:   device.RackID,
+			RackName: rackNames[device.RackID],
+		}
+		// Devices are ordered by rack, so a new rack starts a new group
+		if n := len(topology.Racks); n == 0 || topology.Racks[n-1].ID != node.RackID {
+			topology.Racks = append(topology.Racks, models.TopologyRack{ID: node.RackID, Name: node.RackName})
+		}
+		rack := &topology.Racks[len(topology.Racks)-1]
+		rack.DeviceIDs = append(rack.DeviceIDs, node.ID)
+
+		topology.Nodes = append(topology.Nodes, node)
+		included[node.ID] = true
+	}
+
+	connections, err := s.connections.ListConnections(store.ConnectionFilter{})
+	if err != nil {
+		return nil, err
+	}
+	interfaces, err := s.interfaces.ListInterfaces(store.InterfaceFilter{})
+	if err != nil {
+		return nil, err
+	}
+	interfaceNames := map[int]string{}
+	for _, iface := range interfaces {
+		interfaceNames[iface.ID] = iface.Name
+	}
+	interfaceName := func(id *int) string {
+		if id == nil {
+			return ""
+		}
+		return interfaceNames[*id]
+	}
+
+	for _, conn := range connections {
+		if filter.ConnectionType != "" && conn.ConnectionType != filter.ConnectionType {
+			continue
+		}
+		if !included[conn.SourceDeviceID] || !included[conn.TargetDeviceID] {
+			continue
+		}
+		topology.Edges = append(topology.Edges, models.TopologyEdge{
+			ID:             conn.ID,
+			Source:         conn.SourceDeviceID,
+			Target:         conn.TargetDeviceID,
+			SourcePort:     interfaceName(conn.SourceInterfaceID),
+			TargetPort:     interfaceName(conn.TargetInterfaceID),
+			ConnectionType: conn.ConnectionType,
+			Speed:          conn.Speed,
+			PortInfo:       conn.PortInfo,
+		})
+	}
+
+	return topology, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// maxTraceHops bounds a trace in case pairing data forms a cycle the visited set misses
+const maxTraceHops = 64
+
+// TraceInterface follows the cable on an interface through patch panels to the far end
+func (s *NetworkService) TraceInterface(interfaceID int) (*models.CablePath, error) {
+	iface, err := s.interfaces.GetInterface(interfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	origin, err := s.traceEndpoint(iface.DeviceID, iface)
+	if err != nil {
+		return nil, err
+	}
+
+	path := &models.CablePath{Origin: origin, Hops: []models.TraceHop{}}
+	if iface.ConnectionID == nil {
+		path.Status = models.TraceStatusUnconnected
+		path.Message = fmt.Sprintf("interface %s on %s has no cable", iface.Name, origin.DeviceName)
+		return path, nil
+	}
+
+	conn, err := s.connections.GetConnection(*iface.ConnectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.followPath(path, conn, origin); err != nil {
+		return nil, err
+	}
+
+	return path, nil
+}
+
+// TraceDevice traces every cable leaving a device, one path per connection
+func (s *NetworkService) TraceDevice(deviceID int) ([]models.CablePath, error) {
+	if _, err := s.devices.GetDevice(deviceID); err != nil {
+		return nil, err
+	}
+
+	connections, err := s.connections.ListConnections(store.ConnectionFilter{DeviceID: &deviceID})
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []models.CablePath{}
+	for i := range connections {
+		conn := &connections[i]
+
+		// Start from this device's end; a connection looping back to the device starts at its source
+		deviceEnd, interfaceID := conn.SourceDeviceID, conn.SourceInterfaceID
+		if conn.SourceDeviceID != deviceID {
+			deviceEnd, interfaceID = conn.TargetDeviceID, conn.TargetInterfaceID
+		}
+
+		var iface *models.DeviceInterface
+		if interfaceID != nil {
+			if iface, err = s.interfaces.GetInterface(*interfaceID); err != nil {
+				return nil, err
+			}
+		}
+		origin, err := s.traceEndpoint(deviceEnd, iface)
+		if err != nil {
+			return nil, err
+		}
+
+		path := models.CablePath{Origin: origin, Hops: []models.TraceHop{}}
+		if err := s.followPath(&path, conn, origin); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// followPath appends hops starting with conn leaving from, passing through paired patch panel
+// ports until the path reaches a port that is not paired, a panel port without a cable or a loop
+func (s *NetworkService) followPath(path *models.CablePath, conn *models.NetworkConnection, from models.TraceEndpoint) error {
+	visited := map[int]bool{}
+	if from.InterfaceID != nil {
+		visited[*from.InterfaceID] = true
+	}
+
+	for len(path.Hops) < maxTraceHops {
+		// The far end is the side of the connection that is not the port we left from
+		farDeviceID, farInterfaceID := conn.TargetDeviceID, conn.TargetInterfaceID
+		if from.InterfaceID != nil && conn.TargetInterfaceID != nil && *conn.TargetInterfaceID == *from.InterfaceID {
+			farDeviceID, farInterfaceID = conn.SourceDeviceID, conn.SourceInterfaceID
+		} else if from.InterfaceID == nil && conn.TargetDeviceID == from.DeviceID && conn.SourceDeviceID != from.DeviceID {
+			farDeviceID, farInterfaceID = conn.SourceDeviceID, conn.SourceInterfaceID
+		}
+
+		var far *models.DeviceInterface
+		if farInterfaceID != nil {
+			iface, err := s.interfaces.GetInterface(*farInterfaceID)
+			if err != nil {
+				return err
+			}
+			far = iface
+		}
+		to, err := s.traceEndpoint(farDeviceID, far)
+		if err != nil {
+			return err
+		}
+
+		hop := models.TraceHop{
+			ConnectionID:   conn.ID,
+			ConnectionType: conn.ConnectionType,
+			PortInfo:       conn.PortInfo,
+			Speed:          conn.Speed,
+			From:           from,
+			To:             to,
+		}
+
+		if far != nil && visited[far.ID] {
+			path.Hops = append(path.Hops, hop)
+			path.Status = models.TraceStatusLoop
+			path.Message = fmt.Sprintf("path returns to interface %s on %s", to.InterfaceName, to.DeviceName)
+			return nil
+		}
+
+		// A port without a pair is the far end of the path
+		pairedID := pairedPort(far)
+		if pairedID == nil {
+			path.Hops = append(path.Hops, hop)
+			path.Destination = &to
+			path.Status = models.TraceStatusComplete
+			return nil
+		}
+		visited[far.ID] = true
+
+		paired, err := s.interfaces.GetInterface(*pairedID)
+		if err != nil {
+			return err
+		}
+		through, err := s.traceEndpoint(paired.DeviceID, paired)
+		if err != nil {
+			return err
+		}
+		hop.PassThrough = &through
+		path.Hops = append(path.Hops, hop)
+
+		if visited[paired.ID] {
+			path.Status = models.TraceStatusLoop
+			path.Message = fmt.Sprintf("path returns to interface %s on %s", through.InterfaceName, through.DeviceName)
+			return nil
+		}
+		visited[paired.ID] = true
+
+		if paired.ConnectionID == nil {
+			path.Status = models.TraceStatusBroken
+			path.Message = fmt.Sprintf("patch panel port %s on %s has no cable", through.InterfaceName, through.DeviceName)
+			return nil
+		}
+
+		next, err := s.connections.GetConnection(*paired.ConnectionID)
+		if err != nil {
+			return err
+		}
+		conn, from = next, through
+	}
+
+	path.Status = models.TraceStatusLoop
+	path.Message = fmt.Sprintf("path exceeds %d hops", maxTraceHops)
+	return nil
+}
+
+// pairedPort returns the port on the other side of a patch panel port, or nil if iface is not paired
+func pairedPort(iface *models.DeviceInterface) *int {
+	if iface == nil {
+		return nil
+	}
+	if iface.RearPortID != nil {
+		return iface.RearPortID
+	}
+	return iface.FrontPortID
+}
+
+// traceEndpoint describes a device port, or the device itself when iface is nil
+func (s *NetworkService) traceEndpoint(deviceID int, iface *models.DeviceInterface) (models.TraceEndpoint, error) {
+	endpoint := models.TraceEndpoint{DeviceID: deviceID}
+	device, err := s.devices.GetDevice(deviceID)
+	if err != nil {
+		return endpoint, err
+	}
+	endpoint.DeviceName = device.Name
+
+	if iface != nil {
+		endpoint.InterfaceID = &iface.ID
+		endpoint.InterfaceName = iface.Name
+	}
+	return endpoint, nil
+}
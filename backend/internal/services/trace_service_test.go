@@ -0,0 +1,219 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// mustCreateFrontPort creates a patch panel front port paired with rear
+func mustCreateFrontPort(t *testing.T, s *store.Store, rear *models.DeviceInterface, name string) *models.DeviceInterface {
+	t.Helper()
+	front := &models.DeviceInterface{DeviceID: rear.DeviceID, Name: name, Type: "ethernet", Enabled: true, RearPortID: &rear.ID}
+	if err := s.Interfaces.CreateInterface(front); err != nil {
+		t.Fatalf("CreateInterface(%s): %v", name, err)
+	}
+	return front
+}
+
+// hopNames describes each hop as "from>to" or "from>to|through" by interface name
+func hopNames(path *models.CablePath) []string {
+	names := []string{}
+	for _, hop := range path.Hops {
+		name := hop.From.InterfaceName + ">" + hop.To.InterfaceName
+		if hop.PassThrough != nil {
+			name += "|" + hop.PassThrough.InterfaceName
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestTraceInterface(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	server := mustCreateDevice(t, s, newDevice(rack.ID, "server", 10, 1))
+	switch1 := mustCreateDevice(t, s, newDevice(rack.ID, "switch", 40, 1))
+	panelA := mustCreateDevice(t, s, newDevice(rack.ID, "panel-a", 30, 1))
+	panelB := mustCreateDevice(t, s, newDevice(rack.ID, "panel-b", 31, 1))
+
+	eth0 := mustCreateInterface(t, s, server.ID, "eth0")
+	eth1 := mustCreateInterface(t, s, server.ID, "eth1")
+	eth2 := mustCreateInterface(t, s, server.ID, "eth2")
+	swp1 := mustCreateInterface(t, s, switch1.ID, "swp1")
+	rearA1 := mustCreateInterface(t, s, panelA.ID, "rear-a1")
+	frontA1 := mustCreateFrontPort(t, s, rearA1, "front-a1")
+	rearB1 := mustCreateInterface(t, s, panelB.ID, "rear-b1")
+	frontB1 := mustCreateFrontPort(t, s, rearB1, "front-b1")
+	rearA2 := mustCreateInterface(t, s, panelA.ID, "rear-a2")
+	frontA2 := mustCreateFrontPort(t, s, rearA2, "front-a2")
+
+	// eth0 reaches the switch through both panels; eth1 ends at a panel port with no cable beyond it
+	mustConnect(t, s, server.ID, eth0, panelA.ID, frontA1)
+	trunk := mustConnect(t, s, panelA.ID, rearA1, panelB.ID, rearB1)
+	mustConnect(t, s, switch1.ID, swp1, panelB.ID, frontB1)
+	mustConnect(t, s, server.ID, eth1, panelA.ID, frontA2)
+
+	service := newNetworkService(s)
+	tests := []struct {
+		name        string
+		start       *models.DeviceInterface
+		wantStatus  models.TraceStatus
+		wantHops    []string
+		wantDest    string
+		wantMessage string
+	}{
+		{"through two panels", eth0, models.TraceStatusComplete,
+			[]string{"eth0>front-a1|rear-a1", "rear-a1>rear-b1|front-b1", "front-b1>swp1"}, "swp1", ""},
+		// Tracing the other way follows the same cables back
+		{"from the far end", swp1, models.TraceStatusComplete,
+			[]string{"swp1>front-b1|rear-b1", "rear-b1>rear-a1|front-a1", "front-a1>eth0"}, "eth0", ""},
+		{"from a panel port", rearA1, models.TraceStatusComplete,
+			[]string{"rear-a1>rear-b1|front-b1", "front-b1>swp1"}, "swp1", ""},
+		{"broken at a panel", eth1, models.TraceStatusBroken,
+			[]string{"eth1>front-a2|rear-a2"}, "", "patch panel port rear-a2 on panel-a has no cable"},
+		{"no cable", eth2, models.TraceStatusUnconnected, []string{}, "", "interface eth2 on server has no cable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := service.TraceInterface(tt.start.ID)
+			if err != nil {
+				t.Fatalf("TraceInterface: %v", err)
+			}
+			if path.Origin.InterfaceName != tt.start.Name {
+				t.Errorf("origin = %+v, want %s", path.Origin, tt.start.Name)
+			}
+			if path.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", path.Status, tt.wantStatus, path.Message)
+			}
+			if got := hopNames(path); !reflect.DeepEqual(got, tt.wantHops) {
+				t.Errorf("hops = %v, want %v", got, tt.wantHops)
+			}
+			if tt.wantDest == "" && path.Destination != nil {
+				t.Errorf("destination = %+v, want none", path.Destination)
+			}
+			if tt.wantDest != "" && (path.Destination == nil || path.Destination.InterfaceName != tt.wantDest) {
+				t.Errorf("destination = %+v, want %s", path.Destination, tt.wantDest)
+			}
+			if path.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", path.Message, tt.wantMessage)
+			}
+		})
+	}
+
+	if path, _ := service.TraceInterface(eth0.ID); path.Hops[1].ConnectionID != trunk.ID {
+		t.Errorf("second hop = connection %d, want the trunk %d", path.Hops[1].ConnectionID, trunk.ID)
+	}
+	if _, err := service.TraceInterface(frontA2.ID + 1000); err == nil {
+		t.Errorf("TraceInterface of a missing interface succeeded")
+	}
+}
+
+func TestTraceInterfaceLoop(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	panelA := mustCreateDevice(t, s, newDevice(rack.ID, "panel-a", 30, 1))
+	panelB := mustCreateDevice(t, s, newDevice(rack.ID, "panel-b", 31, 1))
+
+	// Panel A's rear goes to panel B, whose front is patched straight back into panel A's front
+	rearA := mustCreateInterface(t, s, panelA.ID, "rear-a")
+	frontA := mustCreateFrontPort(t, s, rearA, "front-a")
+	rearB := mustCreateInterface(t, s, panelB.ID, "rear-b")
+	frontB := mustCreateFrontPort(t, s, rearB, "front-b")
+	mustConnect(t, s, panelA.ID, rearA, panelB.ID, rearB)
+	mustConnect(t, s, panelB.ID, frontB, panelA.ID, frontA)
+
+	service := newNetworkService(s)
+	for _, start := range []*models.DeviceInterface{rearA, frontA, rearB, frontB} {
+		t.Run(start.Name, func(t *testing.T) {
+			path, err := service.TraceInterface(start.ID)
+			if err != nil {
+				t.Fatalf("TraceInterface: %v", err)
+			}
+			if path.Status != models.TraceStatusLoop || path.Destination != nil {
+				t.Fatalf("trace = %s to %+v, want a loop without a destination", path.Status, path.Destination)
+			}
+			// The trace stops at the second cable, on reaching the port it started from
+			if len(path.Hops) != 2 {
+				t.Errorf("hops = %v, want 2", hopNames(path))
+			}
+			if want := "path returns to interface " + start.Name; !strings.HasPrefix(path.Message, want) {
+				t.Errorf("message = %q, want it to start with %q", path.Message, want)
+			}
+		})
+	}
+}
+
+func TestTraceDevice(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	server := mustCreateDevice(t, s, newDevice(rack.ID, "server", 10, 1))
+	peer := mustCreateDevice(t, s, newDevice(rack.ID, "peer", 11, 1))
+	panel := mustCreateDevice(t, s, newDevice(rack.ID, "panel", 30, 1))
+	eth0 := mustCreateInterface(t, s, server.ID, "eth0")
+	eth1 := mustCreateInterface(t, s, server.ID, "eth1")
+	rear := mustCreateInterface(t, s, panel.ID, "rear1")
+	front := mustCreateFrontPort(t, s, rear, "front1")
+
+	// Two of the server's ports are patched to each other through the panel, and a device-level
+	// link goes to a peer
+	out := mustConnect(t, s, server.ID, eth0, panel.ID, front)
+	back := mustConnect(t, s, panel.ID, rear, server.ID, eth1)
+	link := mustConnect(t, s, peer.ID, nil, server.ID, nil)
+
+	service := newNetworkService(s)
+	paths, err := service.TraceDevice(server.ID)
+	if err != nil {
+		t.Fatalf("TraceDevice: %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("paths = %d, want one per connection", len(paths))
+	}
+
+	byConnection := map[int]models.CablePath{}
+	for _, path := range paths {
+		if path.Status != models.TraceStatusComplete || path.Destination == nil {
+			t.Errorf("path from %+v = %s (%s), want complete", path.Origin, path.Status, path.Message)
+			continue
+		}
+		byConnection[path.Hops[0].ConnectionID] = path
+	}
+
+	// Each cable is traced from the server's end, whichever side of the connection that is
+	tests := []struct {
+		name     string
+		conn     *models.NetworkConnection
+		wantHops []string
+		wantDest string
+	}{
+		{"server is the source", out, []string{"eth0>front1|rear1", "rear1>eth1"}, "eth1"},
+		{"server is the target", back, []string{"eth1>rear1|front1", "front1>eth0"}, "eth0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := byConnection[tt.conn.ID]
+			if !ok {
+				t.Fatalf("no path for connection %d", tt.conn.ID)
+			}
+			if got := hopNames(&path); !reflect.DeepEqual(got, tt.wantHops) {
+				t.Errorf("hops = %v, want %v", got, tt.wantHops)
+			}
+			if path.Destination.DeviceID != server.ID || path.Destination.InterfaceName != tt.wantDest {
+				t.Errorf("destination = %+v, want %s on the server", path.Destination, tt.wantDest)
+			}
+		})
+	}
+
+	// A connection without interfaces starts at the device and ends at the device on the other side
+	path := byConnection[link.ID]
+	if path.Origin.DeviceID != server.ID || path.Origin.InterfaceID != nil || path.Destination == nil || path.Destination.DeviceID != peer.ID {
+		t.Errorf("device link path = %+v to %+v, want server to peer", path.Origin, path.Destination)
+	}
+
+	if _, err := service.TraceDevice(server.ID + 1000); err == nil {
+		t.Errorf("TraceDevice of a missing device succeeded")
+	}
+}
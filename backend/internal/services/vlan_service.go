@@ -0,0 +1,388 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// VLANService handles VLAN group, VLAN and tagging business logic
+type VLANService struct {
+	vlans     store.VLANStore
+	network   *NetworkService
+	locations *LocationService
+}
+
+// NewVLANService creates a new VLAN service
+func NewVLANService(vlans store.VLANStore, network *NetworkService, locations *LocationService) *VLANService {
+	return &VLANService{vlans: vlans, network: network, locations: locations}
+}
+
+// VLAN groups
+
+// GetAllVLANGroups retrieves all VLAN groups
+func (s *VLANService) GetAllVLANGroups() ([]models.VLANGroup, error) {
+	groups, err := s.vlans.ListVLANGroups()
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = []models.VLANGroup{}
+	}
+	return groups, nil
+}
+
+// GetVLANGroupByID retrieves a VLAN group by ID
+func (s *VLANService) GetVLANGroupByID(id int) (*models.VLANGroup, error) {
+	return s.vlans.GetVLANGroup(id)
+}
+
+// vlanGroupNameError names the group in a store error for a name that is already in use
+func vlanGroupNameError(err error, name string) error {
+	if errors.Is(err, store.ErrVLANGroupExists) {
+		return fmt.Errorf("vlan group %s already exists", name)
+	}
+	return err
+}
+
+// CreateVLANGroup creates a new VLAN group
+func (s *VLANService) CreateVLANGroup(req models.CreateVLANGroupRequest) (*models.VLANGroup, error) {
+	siteID := positiveOrNil(req.SiteID)
+	if siteID != nil {
+		if _, err := s.locations.GetSiteByID(*siteID); err != nil {
+			return nil, err
+		}
+	}
+
+	group := &models.VLANGroup{Name: req.Name, SiteID: siteID, Description: req.Description}
+	if err := s.vlans.CreateVLANGroup(group); err != nil {
+		return nil, vlanGroupNameError(err, req.Name)
+	}
+	return group, nil
+}
+
+// UpdateVLANGroup updates a VLAN group; a site change moves its VLANs with it
+func (s *VLANService) UpdateVLANGroup(id int, req models.UpdateVLANGroupRequest) (*models.VLANGroup, error) {
+	group, err := s.vlans.GetVLANGroup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			return nil, fmt.Errorf("vlan group name cannot be empty")
+		}
+		group.Name = *req.Name
+	}
+	if req.SiteID != nil {
+		siteID := positiveOrNil(req.SiteID)
+		if siteID != nil {
+			if _, err := s.locations.GetSiteByID(*siteID); err != nil {
+				return nil, err
+			}
+		}
+		group.SiteID = siteID
+	}
+	if req.Description != nil {
+		group.Description = *req.Description
+	}
+
+	if err := s.vlans.UpdateVLANGroup(group); err != nil {
+		if errors.Is(err, store.ErrVLANExists) {
+			return nil, fmt.Errorf("the group's VLANs would repeat VIDs at the new site")
+		}
+		return nil, vlanGroupNameError(err, group.Name)
+	}
+	return group, nil
+}
+
+// DeleteVLANGroup deletes a VLAN group and its VLANs
+func (s *VLANService) DeleteVLANGroup(id int) error {
+	return s.vlans.DeleteVLANGroup(id)
+}
+
+// VLANs
+
+// GetAllVLANs retrieves all VLANs, optionally in one group or site
+func (s *VLANService) GetAllVLANs(filter models.VLANFilter) ([]models.VLAN, error) {
+	vlans, err := s.vlans.ListVLANs(filter)
+	if err != nil {
+		return nil, err
+	}
+	if vlans == nil {
+		vlans = []models.VLAN{}
+	}
+	return vlans, nil
+}
+
+// GetVLANByID retrieves a VLAN by ID
+func (s *VLANService) GetVLANByID(id int) (*models.VLAN, error) {
+	return s.vlans.GetVLAN(id)
+}
+
+// vlanScopeError names the VID in a store error for a VID that is already in use in its scope
+func vlanScopeError(err error, vid int) error {
+	if errors.Is(err, store.ErrVLANExists) {
+		return fmt.Errorf("VLAN %d already exists in this scope", vid)
+	}
+	return err
+}
+
+// CreateVLAN creates a new VLAN; a grouped VLAN takes its group's site
+func (s *VLANService) CreateVLAN(req models.CreateVLANRequest) (*models.VLAN, error) {
+	groupID := positiveOrNil(req.GroupID)
+	siteID := positiveOrNil(req.SiteID)
+	if groupID != nil {
+		group, err := s.GetVLANGroupByID(*groupID)
+		if err != nil {
+			return nil, err
+		}
+		if siteID != nil && (group.SiteID == nil || *group.SiteID != *siteID) {
+			return nil, fmt.Errorf("vlan group %s is not scoped to site %d", group.Name, *siteID)
+		}
+		siteID = group.SiteID
+	} else if siteID != nil {
+		if _, err := s.locations.GetSiteByID(*siteID); err != nil {
+			return nil, err
+		}
+	}
+
+	vlan := &models.VLAN{VID: req.VID, Name: req.Name, GroupID: groupID, SiteID: siteID, Description: req.Description}
+	if err := s.vlans.CreateVLAN(vlan); err != nil {
+		return nil, vlanScopeError(err, req.VID)
+	}
+	return vlan, nil
+}
+
+// UpdateVLAN updates a VLAN
+func (s *VLANService) UpdateVLAN(id int, req models.UpdateVLANRequest) (*models.VLAN, error) {
+	vlan, err := s.vlans.GetVLAN(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.VID != nil {
+		vlan.VID = *req.VID
+	}
+	if req.Name != nil {
+		if *req.Name == "" {
+			return nil, fmt.Errorf("vlan name cannot be empty")
+		}
+		vlan.Name = *req.Name
+	}
+	if req.Description != nil {
+		vlan.Description = *req.Description
+	}
+
+	if err := s.vlans.UpdateVLAN(vlan); err != nil {
+		return nil, vlanScopeError(err, vlan.VID)
+	}
+	return vlan, nil
+}
+
+// DeleteVLAN deletes a VLAN and removes it from all tagging
+func (s *VLANService) DeleteVLAN(id int) error {
+	return s.vlans.DeleteVLAN(id)
+}
+
+// Tagging
+
+// checkTagging validates a tagging request against the VLAN scopes of the devices involved
+func (s *VLANService) checkTagging(req models.SetVLANTaggingRequest, deviceIDs ...int) error {
+	untaggedVLANID := positiveOrNil(req.UntaggedVLANID)
+	switch req.VLANMode {
+	case "":
+		if untaggedVLANID != nil || len(req.TaggedVLANIDs) > 0 {
+			return fmt.Errorf("vlan_mode is required to set VLANs")
+		}
+		return nil
+	case models.VLANModeAccess:
+		if untaggedVLANID == nil {
+			return fmt.Errorf("access mode requires untagged_vlan_id")
+		}
+		if len(req.TaggedVLANIDs) > 0 {
+			return fmt.Errorf("access mode cannot carry tagged VLANs")
+		}
+	}
+
+	vlanIDs := req.TaggedVLANIDs
+	if untaggedVLANID != nil {
+		vlanIDs = append([]int{*untaggedVLANID}, vlanIDs...)
+	}
+	for _, vlanID := range vlanIDs {
+		vlan, err := s.GetVLANByID(vlanID)
+		if err != nil {
+			return fmt.Errorf("vlan %d not found", vlanID)
+		}
+		if vlan.SiteID == nil {
+			continue
+		}
+		for _, deviceID := range deviceIDs {
+			device, err := s.network.devices.GetDevice(deviceID)
+			if err != nil {
+				return err
+			}
+			rack, err := s.network.racks.GetRack(device.RackID)
+			if err != nil {
+				return err
+			}
+			if rack.SiteID == nil || *rack.SiteID != *vlan.SiteID {
+				return fmt.Errorf("VLAN %d (%s) is scoped to site %d, which does not contain %s", vlan.VID, vlan.Name, *vlan.SiteID, device.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// vlanTagging returns the tagging a request sets
+func vlanTagging(req models.SetVLANTaggingRequest) models.VLANTagging {
+	return models.VLANTagging{
+		VLANMode:       req.VLANMode,
+		UntaggedVLANID: positiveOrNil(req.UntaggedVLANID),
+		TaggedVLANIDs:  req.TaggedVLANIDs,
+	}
+}
+
+// SetInterfaceVLANs replaces the VLAN tagging of a device's interface
+func (s *VLANService) SetInterfaceVLANs(deviceID, interfaceID int, req models.SetVLANTaggingRequest) (*models.DeviceInterface, error) {
+	interfaceService := NewInterfaceService(s.network.interfaces, s.network.devices)
+	if _, err := interfaceService.GetDeviceInterface(deviceID, interfaceID); err != nil {
+		return nil, err
+	}
+	if err := s.checkTagging(req, deviceID); err != nil {
+		return nil, err
+	}
+	if err := s.vlans.SetInterfaceVLANs(interfaceID, vlanTagging(req)); err != nil {
+		return nil, err
+	}
+	return interfaceService.GetDeviceInterface(deviceID, interfaceID)
+}
+
+// SetConnectionVLANs replaces the VLAN tagging of a connection
+func (s *VLANService) SetConnectionVLANs(connectionID int, req models.SetVLANTaggingRequest) (*models.NetworkConnection, error) {
+	conn, err := s.network.connections.GetConnection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTagging(req, conn.SourceDeviceID, conn.TargetDeviceID); err != nil {
+		return nil, err
+	}
+	if err := s.vlans.SetConnectionVLANs(connectionID, vlanTagging(req)); err != nil {
+		return nil, err
+	}
+	return s.network.GetConnectionByID(connectionID)
+}
+
+// GetVLANDevices lists the devices on a VLAN, grouped into the segments connected by links
+// carrying it, optionally only the segment of one device.
+//
+// A link carries the VLAN when its connection tagging does; an untagged connection carries it
+// when every tagged interface at its ends does and at least one end is tagged. A device is on
+// the VLAN when one of its interfaces or links carries it.
+func (s *VLANService) GetVLANDevices(vlanID int, deviceID *int) (*models.VLANReachability, error) {
+	vlan, err := s.GetVLANByID(vlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	topology, err := s.network.GetTopology(models.TopologyFilter{})
+	if err != nil {
+		return nil, err
+	}
+	nodes := topologyNodesByID(topology)
+
+	interfaces, err := s.network.interfaces.ListInterfaces(store.InterfaceFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[int]bool{}
+	interfaceTagging := map[int]models.VLANTagging{}
+	for _, iface := range interfaces {
+		if iface.VLANMode == "" {
+			continue
+		}
+		interfaceTagging[iface.ID] = iface.VLANTagging
+		if iface.Carries(vlanID) {
+			members[iface.DeviceID] = true
+		}
+	}
+
+	connections, err := s.network.connections.ListConnections(store.ConnectionFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var links []models.TopologyEdge
+	for _, conn := range connections {
+		if linkCarries(conn, interfaceTagging, vlanID) {
+			links = append(links, models.TopologyEdge{ID: conn.ID, Source: conn.SourceDeviceID, Target: conn.TargetDeviceID})
+			members[conn.SourceDeviceID] = true
+			members[conn.TargetDeviceID] = true
+		}
+	}
+
+	// Segments are the components of the graph of member devices and carrying links
+	excluded := map[int]bool{}
+	for _, node := range topology.Nodes {
+		if !members[node.ID] {
+			excluded[node.ID] = true
+		}
+	}
+	g := newNetworkGraph(&models.Topology{Nodes: topology.Nodes, Edges: links}, excluded)
+
+	reachability := &models.VLANReachability{
+		VLAN:     *vlan,
+		Devices:  []models.TopologyNode{},
+		Segments: []models.VLANSegment{},
+	}
+	for _, component := range g.components(0, 0) {
+		if deviceID != nil && !containsID(component, *deviceID) {
+			continue
+		}
+		segment := models.VLANSegment{Devices: make([]models.TopologyNode, 0, len(component))}
+		for _, id := range component {
+			segment.Devices = append(segment.Devices, nodes[id])
+		}
+		reachability.Segments = append(reachability.Segments, segment)
+		reachability.Devices = append(reachability.Devices, segment.Devices...)
+	}
+
+	return reachability, nil
+}
+
+// linkCarries reports whether a connection carries a VLAN; see GetVLANDevices
+func linkCarries(conn models.NetworkConnection, interfaceTagging map[int]models.VLANTagging, vlanID int) bool {
+	if conn.VLANMode != "" {
+		return conn.Carries(vlanID)
+	}
+
+	tagged := false
+	for _, interfaceID := range []*int{conn.SourceInterfaceID, conn.TargetInterfaceID} {
+		if interfaceID == nil {
+			continue
+		}
+		tagging, ok := interfaceTagging[*interfaceID]
+		if !ok {
+			continue
+		}
+		if !tagging.Carries(vlanID) {
+			return false
+		}
+		tagged = true
+	}
+	return tagged
+}
+
+// containsID reports whether ids contains id
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func TestLinkCarries(t *testing.T) {
+	const vlan10, vlan20 = 10, 20
+	access := func(vlanID int) models.VLANTagging {
+		return models.VLANTagging{VLANMode: models.VLANModeAccess, UntaggedVLANID: &vlanID}
+	}
+	trunk := func(vlanIDs ...int) models.VLANTagging {
+		return models.VLANTagging{VLANMode: models.VLANModeTrunk, TaggedVLANIDs: vlanIDs}
+	}
+	// Interface 1 is the source end of every connection and interface 2 the target end
+	source, target := 1, 2
+	link := func(tagging models.VLANTagging) models.NetworkConnection {
+		return models.NetworkConnection{SourceInterfaceID: &source, TargetInterfaceID: &target, VLANTagging: tagging}
+	}
+
+	tests := []struct {
+		name       string
+		conn       models.NetworkConnection
+		interfaces map[int]models.VLANTagging
+		want       bool
+	}{
+		{"untagged everywhere", link(models.VLANTagging{}), nil, false},
+		{"device-level connection", models.NetworkConnection{}, nil, false},
+		{"connection tagged", link(access(vlan10)), nil, true},
+		{"connection tagged with another vlan", link(access(vlan20)), nil, false},
+		// The connection's own tagging wins over its ends
+		{"connection overrides interfaces", link(trunk(vlan20)), map[int]models.VLANTagging{1: access(vlan10), 2: access(vlan10)}, false},
+		{"one tagged end", link(models.VLANTagging{}), map[int]models.VLANTagging{1: access(vlan10)}, true},
+		{"both ends tagged", link(models.VLANTagging{}), map[int]models.VLANTagging{1: access(vlan10), 2: trunk(vlan10, vlan20)}, true},
+		{"ends disagree", link(models.VLANTagging{}), map[int]models.VLANTagging{1: access(vlan10), 2: trunk(vlan20)}, false},
+		{"trunk allowing every vlan", link(models.VLANTagging{}), map[int]models.VLANTagging{2: trunk()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linkCarries(tt.conn, tt.interfaces, vlan10); got != tt.want {
+				t.Errorf("linkCarries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// segmentIDs returns the device IDs of each segment, sorted within the segment
+func segmentIDs(reachability *models.VLANReachability) [][]int {
+	segments := [][]int{}
+	for _, segment := range reachability.Segments {
+		ids := nodeIDs(segment.Devices)
+		sort.Ints(ids)
+		segments = append(segments, ids)
+	}
+	return segments
+}
+
+func TestGetVLANDevices(t *testing.T) {
+	s := store.NewMemoryStore()
+	rack := mustCreateRack(t, s, "A")
+	network := newNetworkService(s)
+	service := NewVLANService(s.VLANs, network, NewLocationService(s.Locations, s.Racks, s.Devices))
+
+	vlan10, err := service.CreateVLAN(models.CreateVLANRequest{VID: 10, Name: "servers"})
+	if err != nil {
+		t.Fatalf("CreateVLAN: %v", err)
+	}
+	vlan20, err := service.CreateVLAN(models.CreateVLANRequest{VID: 20, Name: "storage"})
+	if err != nil {
+		t.Fatalf("CreateVLAN: %v", err)
+	}
+	access10 := models.SetVLANTaggingRequest{VLANMode: models.VLANModeAccess, UntaggedVLANID: &vlan10.ID}
+	trunk20 := models.SetVLANTaggingRequest{VLANMode: models.VLANModeTrunk, TaggedVLANIDs: []int{vlan20.ID}}
+
+	sw1 := mustCreateDevice(t, s, newDevice(rack.ID, "sw-1", 40, 1))
+	sw2 := mustCreateDevice(t, s, newDevice(rack.ID, "sw-2", 39, 1))
+	sw3 := mustCreateDevice(t, s, newDevice(rack.ID, "sw-3", 38, 1))
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 11, 1))
+	c := mustCreateDevice(t, s, newDevice(rack.ID, "c", 12, 1))
+	lone := mustCreateDevice(t, s, newDevice(rack.ID, "lone", 13, 1))
+	other := mustCreateDevice(t, s, newDevice(rack.ID, "other", 14, 1))
+
+	setInterface := func(device *models.Device, name string, req models.SetVLANTaggingRequest) *models.DeviceInterface {
+		iface := mustCreateInterface(t, s, device.ID, name)
+		if _, err := service.SetInterfaceVLANs(device.ID, iface.ID, req); err != nil {
+			t.Fatalf("SetInterfaceVLANs(%s %s): %v", device.Name, name, err)
+		}
+		return iface
+	}
+	setConnection := func(conn *models.NetworkConnection, req models.SetVLANTaggingRequest) {
+		if _, err := service.SetConnectionVLANs(conn.ID, req); err != nil {
+			t.Fatalf("SetConnectionVLANs(%d): %v", conn.ID, err)
+		}
+	}
+
+	// sw-1 and sw-2 are joined by a trunk carrying every VLAN, but the sw-2 to sw-3 trunk only
+	// carries VLAN 20, which splits VLAN 10 in two
+	setConnection(mustConnect(t, s, sw1.ID, nil, sw2.ID, nil), models.SetVLANTaggingRequest{VLANMode: models.VLANModeTrunk})
+	setConnection(mustConnect(t, s, sw2.ID, nil, sw3.ID, nil), trunk20)
+	// a and c are on VLAN 10 through their interfaces, b through its connection
+	mustConnect(t, s, a.ID, setInterface(a, "eth0", access10), sw1.ID, nil)
+	setConnection(mustConnect(t, s, b.ID, nil, sw2.ID, nil), access10)
+	mustConnect(t, s, c.ID, setInterface(c, "eth0", access10), sw3.ID, nil)
+	// lone is on VLAN 10 with no cable, and other is cabled without any VLANs
+	setInterface(lone, "eth0", access10)
+	mustConnect(t, s, other.ID, nil, sw1.ID, nil)
+
+	tests := []struct {
+		name     string
+		vlanID   int
+		deviceID *int
+		want     [][]int
+	}{
+		{"vlan 10", vlan10.ID, nil, [][]int{{sw1.ID, sw2.ID, a.ID, b.ID}, {sw3.ID, c.ID}, {lone.ID}}},
+		{"vlan 10 segment of c", vlan10.ID, &c.ID, [][]int{{sw3.ID, c.ID}}},
+		{"vlan 10 segment of a device off the vlan", vlan10.ID, &other.ID, [][]int{}},
+		{"vlan 20", vlan20.ID, nil, [][]int{{sw1.ID, sw2.ID, sw3.ID}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reachability, err := service.GetVLANDevices(tt.vlanID, tt.deviceID)
+			if err != nil {
+				t.Fatalf("GetVLANDevices: %v", err)
+			}
+			if got := segmentIDs(reachability); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segments = %v, want %v", got, tt.want)
+			}
+			// Devices lists every device of the segments returned
+			devices := 0
+			for _, segment := range reachability.Segments {
+				devices += len(segment.Devices)
+			}
+			if len(reachability.Devices) != devices {
+				t.Errorf("devices = %d, want the %d in the segments", len(reachability.Devices), devices)
+			}
+		})
+	}
+
+	if _, err := service.GetVLANDevices(vlan20.ID+1000, nil); err == nil {
+		t.Errorf("GetVLANDevices of a missing VLAN succeeded")
+	}
+}
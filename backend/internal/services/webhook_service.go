@@ -0,0 +1,135 @@
+package services
+
+import (
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// WebhookService handles webhook subscription business logic
+type WebhookService struct {
+	webhooks store.WebhookStore
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhooks store.WebhookStore) *WebhookService {
+	return &WebhookService{webhooks: webhooks}
+}
+
+// GetAllWebhooks retrieves all webhooks
+func (s *WebhookService) GetAllWebhooks() ([]models.Webhook, error) {
+	webhooks, err := s.webhooks.ListWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+	return webhooks, nil
+}
+
+// GetWebhookByID retrieves a webhook by ID
+func (s *WebhookService) GetWebhookByID(id int) (*models.Webhook, error) {
+	return s.webhooks.GetWebhook(id)
+}
+
+// CreateWebhook registers a new webhook
+func (s *WebhookService) CreateWebhook(req models.CreateWebhookRequest) (*models.Webhook, error) {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	webhook := &models.Webhook{
+		Name:     req.Name,
+		URL:      req.URL,
+		Secret:   req.Secret,
+		Statuses: req.Statuses,
+		Enabled:  enabled,
+	}
+	if err := s.webhooks.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook updates an existing webhook
+func (s *WebhookService) UpdateWebhook(id int, req models.UpdateWebhookRequest) (*models.Webhook, error) {
+	webhook, err := s.webhooks.GetWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		webhook.Name = *req.Name
+	}
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+	if req.Statuses != nil {
+		webhook.Statuses = req.Statuses
+	}
+	if req.Enabled != nil {
+		webhook.Enabled = *req.Enabled
+	}
+
+	if err := s.webhooks.UpdateWebhook(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook deletes a webhook and its delivery log
+func (s *WebhookService) DeleteWebhook(id int) error {
+	return s.webhooks.DeleteWebhook(id)
+}
+
+// getWebhooksForStatus retrieves the enabled webhooks subscribed to transitions into status
+func (s *WebhookService) getWebhooksForStatus(status models.DeviceStatus) ([]models.Webhook, error) {
+	webhooks, err := s.GetAllWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []models.Webhook
+	for _, webhook := range webhooks {
+		if !webhook.Enabled {
+			continue
+		}
+		if len(webhook.Statuses) == 0 {
+			matching = append(matching, webhook)
+			continue
+		}
+		for _, subscribed := range webhook.Statuses {
+			if subscribed == status {
+				matching = append(matching, webhook)
+				break
+			}
+		}
+	}
+
+	return matching, nil
+}
+
+// GetDeliveries retrieves the most recent delivery attempts for a webhook
+func (s *WebhookService) GetDeliveries(webhookID, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.GetWebhookByID(webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhooks.ListWebhookDeliveries(webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+// recordDelivery logs a delivery attempt
+func (s *WebhookService) recordDelivery(delivery models.WebhookDelivery) error {
+	return s.webhooks.RecordWebhookDelivery(&delivery)
+}
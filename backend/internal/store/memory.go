@@ -0,0 +1,680 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// NewMemoryStore creates stores that keep everything in memory. They follow the foreign keys,
+// cascades, unique constraints and device overlap constraint of the Postgres schema.
+func NewMemoryStore() *Store {
+	m := &memoryStore{
+		racks:        map[int]*models.Rack{},
+		devices:      map[int]*models.Device{},
+		connections:  map[int]*models.NetworkConnection{},
+		interfaces:   map[int]*models.DeviceInterface{},
+		cables:       map[int]*models.Cable{},
+		deviceTypes:  map[int]*models.DeviceTypeTemplate{},
+		sites:        map[int]*models.Site{},
+		rooms:        map[int]*models.Room{},
+		rows:         map[int]*models.Row{},
+		prefixes:     map[int]*models.IPPrefix{},
+		addresses:    map[int]*models.IPAddress{},
+		healthProbes: map[int]*models.HealthProbe{},
+		healthChecks: map[int64]*models.HealthCheck{},
+		vlanGroups:   map[int]*models.VLANGroup{},
+		vlans:        map[int]*models.VLAN{},
+		webhooks:     map[int]*models.Webhook{},
+		deliveries:   map[int64]*models.WebhookDelivery{},
+		alertStates:  map[int]*models.AlertState{},
+	}
+	return &Store{
+		Racks:        m,
+		Devices:      m,
+		Connections:  m,
+		Interfaces:   m,
+		Cables:       m,
+		DeviceTypes:  m,
+		Locations:    m,
+		IPAM:         m,
+		HealthProbes: m,
+		HealthChecks: m,
+		VLANs:        m,
+		Webhooks:     m,
+		AlertStates:  m,
+	}
+}
+
+// memoryStore implements every store interface over maps guarded by one lock
+type memoryStore struct {
+	mu           sync.RWMutex
+	racks        map[int]*models.Rack
+	devices      map[int]*models.Device
+	connections  map[int]*models.NetworkConnection
+	interfaces   map[int]*models.DeviceInterface
+	cables       map[int]*models.Cable
+	deviceTypes  map[int]*models.DeviceTypeTemplate
+	sites        map[int]*models.Site
+	rooms        map[int]*models.Room
+	rows         map[int]*models.Row
+	prefixes     map[int]*models.IPPrefix
+	addresses    map[int]*models.IPAddress
+	healthProbes map[int]*models.HealthProbe
+	healthChecks map[int64]*models.HealthCheck
+	vlanGroups   map[int]*models.VLANGroup
+	vlans        map[int]*models.VLAN
+	webhooks     map[int]*models.Webhook
+	deliveries   map[int64]*models.WebhookDelivery
+	alertStates  map[int]*models.AlertState
+	lastID       int
+}
+
+// nextID returns a new ID; IDs are unique across tables, which callers must not rely on
+func (m *memoryStore) nextID() int {
+	m.lastID++
+	return m.lastID
+}
+
+func copyIntPtr(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// copyRack returns a copy of a stored rack that shares no memory with it
+func copyRack(rack *models.Rack) models.Rack {
+	c := *rack
+	c.SiteID = copyIntPtr(rack.SiteID)
+	c.RoomID = copyIntPtr(rack.RoomID)
+	c.RowID = copyIntPtr(rack.RowID)
+	c.Devices = nil
+	c.Elevation = nil
+	return c
+}
+
+// copyDevice returns a copy of a stored device that shares no memory with it
+func copyDevice(device *models.Device) models.Device {
+	c := *device
+	c.ParentDeviceID = copyIntPtr(device.ParentDeviceID)
+	c.DeviceTypeID = copyIntPtr(device.DeviceTypeID)
+	c.Specs = make(map[string]string, len(device.Specs))
+	for k, v := range device.Specs {
+		c.Specs[k] = v
+	}
+	c.Faces = DeviceFaces(&c)
+	c.Children = nil
+	return c
+}
+
+// copyConnection returns a copy of a stored connection that shares no memory with it
+func copyConnection(conn *models.NetworkConnection) models.NetworkConnection {
+	c := *conn
+	c.SourceInterfaceID = copyIntPtr(conn.SourceInterfaceID)
+	c.TargetInterfaceID = copyIntPtr(conn.TargetInterfaceID)
+	c.CableID = copyIntPtr(conn.CableID)
+	c.UntaggedVLANID = copyIntPtr(conn.UntaggedVLANID)
+	c.TaggedVLANIDs = append([]int(nil), conn.TaggedVLANIDs...)
+	c.SourceDevice, c.TargetDevice = nil, nil
+	c.SourceInterface, c.TargetInterface = nil, nil
+	c.Cable = nil
+	return c
+}
+
+// Racks
+
+func (m *memoryStore) ListRacks(filter models.RackFilter) ([]models.Rack, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matches := func(filter, value *int) bool {
+		return filter == nil || (value != nil && *value == *filter)
+	}
+	var racks []models.Rack
+	for _, rack := range m.racks {
+		if matches(filter.SiteID, rack.SiteID) && matches(filter.RoomID, rack.RoomID) && matches(filter.RowID, rack.RowID) {
+			racks = append(racks, copyRack(rack))
+		}
+	}
+	sort.Slice(racks, func(i, j int) bool { return racks[i].ID < racks[j].ID })
+	return racks, nil
+}
+
+func (m *memoryStore) GetRack(id int) (*models.Rack, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rack, ok := m.racks[id]
+	if !ok {
+		return nil, ErrRackNotFound
+	}
+	c := copyRack(rack)
+	return &c, nil
+}
+
+// checkRackReferences enforces the rack foreign keys of the Postgres schema
+func (m *memoryStore) checkRackReferences(rack *models.Rack) error {
+	if rack.SiteID != nil {
+		if _, ok := m.sites[*rack.SiteID]; !ok {
+			return fmt.Errorf("site %d does not exist", *rack.SiteID)
+		}
+	}
+	if rack.RoomID != nil {
+		if _, ok := m.rooms[*rack.RoomID]; !ok {
+			return fmt.Errorf("room %d does not exist", *rack.RoomID)
+		}
+	}
+	if rack.RowID != nil {
+		if _, ok := m.rows[*rack.RowID]; !ok {
+			return fmt.Errorf("row %d does not exist", *rack.RowID)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateRack(rack *models.Rack) error {
+	if rack.SizeU < 1 {
+		return fmt.Errorf("failed to create rack: size_u must be positive")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkRackReferences(rack); err != nil {
+		return fmt.Errorf("failed to create rack: %w", err)
+	}
+
+	now := time.Now()
+	rack.ID = m.nextID()
+	rack.CreatedAt, rack.UpdatedAt = now, now
+	stored := copyRack(rack)
+	m.racks[rack.ID] = &stored
+	*rack = copyRack(&stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateRack(rack *models.Rack) error {
+	if rack.SizeU < 1 {
+		return fmt.Errorf("failed to update rack: size_u must be positive")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.racks[rack.ID]
+	if !ok {
+		return ErrRackNotFound
+	}
+	if err := m.checkRackReferences(rack); err != nil {
+		return fmt.Errorf("failed to update rack: %w", err)
+	}
+	rack.CreatedAt = current.CreatedAt
+	rack.UpdatedAt = time.Now()
+	stored := copyRack(rack)
+	m.racks[rack.ID] = &stored
+	*rack = copyRack(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteRack(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.racks[id]; !ok {
+		return ErrRackNotFound
+	}
+	delete(m.racks, id)
+	for _, device := range m.devices {
+		if device.RackID == id {
+			m.deleteDevice(device.ID)
+		}
+	}
+	return nil
+}
+
+// Devices
+
+func (m *memoryStore) ListDevices(filter DeviceFilter) ([]models.Device, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var devices []models.Device
+	for _, device := range m.devices {
+		if filter.RackID != nil && device.RackID != *filter.RackID {
+			continue
+		}
+		if filter.ParentDeviceID != nil && (device.ParentDeviceID == nil || *device.ParentDeviceID != *filter.ParentDeviceID) {
+			continue
+		}
+		if filter.IPAddress != "" && device.IPAddress != filter.IPAddress {
+			continue
+		}
+		devices = append(devices, copyDevice(device))
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		a, b := devices[i], devices[j]
+		if filter.ParentDeviceID != nil {
+			if a.Bay != b.Bay {
+				return a.Bay < b.Bay
+			}
+			return a.ID < b.ID
+		}
+		if a.RackID != b.RackID {
+			return a.RackID < b.RackID
+		}
+		if a.PositionU != b.PositionU {
+			return a.PositionU > b.PositionU
+		}
+		return a.ID < b.ID
+	})
+	return devices, nil
+}
+
+func (m *memoryStore) GetDevice(id int) (*models.Device, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	device, ok := m.devices[id]
+	if !ok {
+		return nil, ErrDeviceNotFound
+	}
+	c := copyDevice(device)
+	return &c, nil
+}
+
+// checkDeviceReferences enforces the device foreign keys of the Postgres schema
+func (m *memoryStore) checkDeviceReferences(device *models.Device) error {
+	if _, ok := m.racks[device.RackID]; !ok {
+		return fmt.Errorf("rack %d does not exist", device.RackID)
+	}
+	if device.ParentDeviceID != nil {
+		if _, ok := m.devices[*device.ParentDeviceID]; !ok {
+			return fmt.Errorf("parent device %d does not exist", *device.ParentDeviceID)
+		}
+	}
+	if device.DeviceTypeID != nil {
+		if _, ok := m.deviceTypes[*device.DeviceTypeID]; !ok {
+			return fmt.Errorf("device type %d does not exist", *device.DeviceTypeID)
+		}
+	}
+	return nil
+}
+
+// checkOverlap enforces the devices_no_overlap constraint of the Postgres schema
+func (m *memoryStore) checkOverlap(device *models.Device) error {
+	faces := DeviceFaces(device)
+	bottomU := device.PositionU - device.SizeU + 1
+	for _, other := range m.devices {
+		if other.ID == device.ID || other.RackID != device.RackID {
+			continue
+		}
+		if other.PositionU-other.SizeU+1 > device.PositionU || bottomU > other.PositionU {
+			continue
+		}
+		for _, face := range DeviceFaces(other) {
+			for _, f := range faces {
+				if face == f {
+					return ErrDeviceOverlap
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkDeviceIPAddress enforces the idx_devices_ip_address index of the Postgres schema
+func (m *memoryStore) checkDeviceIPAddress(device *models.Device) error {
+	if device.IPAddress == "" {
+		return nil
+	}
+	for _, other := range m.devices {
+		if other.ID != device.ID && other.IPAddress == device.IPAddress {
+			return ErrIPAddressInUse
+		}
+	}
+	return nil
+}
+
+// checkPlacement runs a placement check against the other devices in the device's rack
+func (m *memoryStore) checkPlacement(device *models.Device, check PlacementCheck) error {
+	if check == nil {
+		return nil
+	}
+
+	var rackDevices []models.Device
+	for _, other := range m.devices {
+		if other.RackID == device.RackID && other.ID != device.ID {
+			rackDevices = append(rackDevices, copyDevice(other))
+		}
+	}
+	sort.Slice(rackDevices, func(i, j int) bool {
+		a, b := rackDevices[i], rackDevices[j]
+		if a.PositionU != b.PositionU {
+			return a.PositionU > b.PositionU
+		}
+		return a.ID < b.ID
+	})
+	return check(rackDevices)
+}
+
+func (m *memoryStore) CreateDevice(device *models.Device, interfaces []*models.DeviceInterface, check PlacementCheck) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkDeviceReferences(device); err != nil {
+		return fmt.Errorf("failed to create device: %w", err)
+	}
+	if err := m.checkPlacement(device, check); err != nil {
+		return err
+	}
+	if err := m.checkOverlap(device); err != nil {
+		return err
+	}
+	if err := m.checkDeviceIPAddress(device); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	device.ID = m.nextID()
+	device.CreatedAt, device.UpdatedAt = now, now
+	stored := copyDevice(device)
+	m.devices[device.ID] = &stored
+
+	// Interfaces are checked against the new device; a failure takes the device back out
+	var created []int
+	for _, iface := range interfaces {
+		iface.DeviceID = device.ID
+		if err := m.checkInterface(iface); err != nil {
+			for _, id := range created {
+				delete(m.interfaces, id)
+			}
+			delete(m.devices, device.ID)
+			return fmt.Errorf("failed to create interface %s: %w", iface.Name, err)
+		}
+		m.insertInterface(iface)
+		created = append(created, iface.ID)
+	}
+
+	*device = copyDevice(&stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateDevice(device *models.Device, check PlacementCheck) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.devices[device.ID]
+	if !ok {
+		return ErrDeviceNotFound
+	}
+	if err := m.checkDeviceReferences(device); err != nil {
+		return fmt.Errorf("failed to update device: %w", err)
+	}
+	if err := m.checkPlacement(device, check); err != nil {
+		return err
+	}
+	if err := m.checkOverlap(device); err != nil {
+		return err
+	}
+	if err := m.checkDeviceIPAddress(device); err != nil {
+		return err
+	}
+
+	stored := copyDevice(device)
+	if device.Specs == nil {
+		stored.Specs = current.Specs
+	}
+	stored.CreatedAt = current.CreatedAt
+	stored.UpdatedAt = time.Now()
+	m.devices[device.ID] = &stored
+	m.moveInstalledDevices(device.ID, device.RackID, stored.UpdatedAt)
+	*device = copyDevice(&stored)
+	return nil
+}
+
+// moveInstalledDevices moves the devices installed in a chassis, directly or nested, to its rack
+func (m *memoryStore) moveInstalledDevices(parentID, rackID int, now time.Time) {
+	for _, child := range m.devices {
+		if child.ParentDeviceID == nil || *child.ParentDeviceID != parentID {
+			continue
+		}
+		if child.RackID != rackID {
+			child.RackID = rackID
+			child.UpdatedAt = now
+		}
+		m.moveInstalledDevices(child.ID, rackID, now)
+	}
+}
+
+func (m *memoryStore) UpdateDeviceStatus(id int, status models.DeviceStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	device, ok := m.devices[id]
+	if !ok {
+		return ErrDeviceNotFound
+	}
+	device.Status = status
+	device.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *memoryStore) DeleteDevice(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.devices[id]; !ok {
+		return ErrDeviceNotFound
+	}
+	m.deleteDevice(id)
+	return nil
+}
+
+// deleteDevice removes a device and the devices installed in it, with their connections,
+// interfaces, addresses, health probes, health checks and alert state, and unlinks the webhook
+// deliveries about them
+func (m *memoryStore) deleteDevice(id int) {
+	if _, ok := m.devices[id]; !ok {
+		return
+	}
+	delete(m.devices, id)
+	for _, conn := range m.connections {
+		if conn.SourceDeviceID == id || conn.TargetDeviceID == id {
+			delete(m.connections, conn.ID)
+		}
+	}
+	for _, iface := range m.interfaces {
+		if iface.DeviceID == id {
+			m.deleteInterface(iface.ID)
+		}
+	}
+	for _, address := range m.addresses {
+		if address.DeviceID == id {
+			delete(m.addresses, address.ID)
+		}
+	}
+	for _, probe := range m.healthProbes {
+		if probe.DeviceID == id {
+			delete(m.healthProbes, probe.ID)
+		}
+	}
+	for _, check := range m.healthChecks {
+		if check.DeviceID == id {
+			delete(m.healthChecks, check.ID)
+		}
+	}
+	delete(m.alertStates, id)
+	for _, delivery := range m.deliveries {
+		if delivery.DeviceID != nil && *delivery.DeviceID == id {
+			delivery.DeviceID = nil
+		}
+	}
+	for _, child := range m.devices {
+		if child.ParentDeviceID != nil && *child.ParentDeviceID == id {
+			m.deleteDevice(child.ID)
+		}
+	}
+}
+
+// Connections
+
+func (m *memoryStore) ListConnections(filter ConnectionFilter) ([]models.NetworkConnection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var connections []models.NetworkConnection
+	for _, conn := range m.connections {
+		if filter.DeviceID != nil && conn.SourceDeviceID != *filter.DeviceID && conn.TargetDeviceID != *filter.DeviceID {
+			continue
+		}
+		connections = append(connections, copyConnection(conn))
+	}
+	sort.Slice(connections, func(i, j int) bool { return connections[i].ID < connections[j].ID })
+	return connections, nil
+}
+
+func (m *memoryStore) GetConnection(id int) (*models.NetworkConnection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	conn, ok := m.connections[id]
+	if !ok {
+		return nil, ErrConnectionNotFound
+	}
+	c := copyConnection(conn)
+	return &c, nil
+}
+
+// checkConnectionReferences enforces the connection foreign keys, the unique interface and cable
+// indexes and the one-connection-per-interface trigger of the Postgres schema
+func (m *memoryStore) checkConnectionReferences(conn *models.NetworkConnection) error {
+	interfaceIDs := []int{}
+	for _, id := range []*int{conn.SourceInterfaceID, conn.TargetInterfaceID} {
+		if id == nil {
+			continue
+		}
+		if _, ok := m.interfaces[*id]; !ok {
+			return fmt.Errorf("interface %d does not exist", *id)
+		}
+		interfaceIDs = append(interfaceIDs, *id)
+	}
+	if len(interfaceIDs) == 2 && interfaceIDs[0] == interfaceIDs[1] {
+		return fmt.Errorf("interface %d already carries a cable", interfaceIDs[0])
+	}
+	for _, id := range interfaceIDs {
+		if other := m.interfaceConnection(id); other != nil && *other != conn.ID {
+			return fmt.Errorf("interface %d already carries a cable", id)
+		}
+	}
+
+	if conn.CableID != nil {
+		if _, ok := m.cables[*conn.CableID]; !ok {
+			return fmt.Errorf("cable %d does not exist", *conn.CableID)
+		}
+		if other := m.cableConnection(*conn.CableID); other != nil && *other != conn.ID {
+			return fmt.Errorf("cable %d already makes connection %d", *conn.CableID, *other)
+		}
+	}
+	return nil
+}
+
+// interfaceConnection returns the ID of the connection on an interface, if any
+func (m *memoryStore) interfaceConnection(interfaceID int) *int {
+	for _, conn := range m.connections {
+		if (conn.SourceInterfaceID != nil && *conn.SourceInterfaceID == interfaceID) ||
+			(conn.TargetInterfaceID != nil && *conn.TargetInterfaceID == interfaceID) {
+			id := conn.ID
+			return &id
+		}
+	}
+	return nil
+}
+
+// cableConnection returns the ID of the connection made with a cable, if any
+func (m *memoryStore) cableConnection(cableID int) *int {
+	for _, conn := range m.connections {
+		if conn.CableID != nil && *conn.CableID == cableID {
+			id := conn.ID
+			return &id
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateConnection(conn *models.NetworkConnection, cable *models.Cable) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, deviceID := range []int{conn.SourceDeviceID, conn.TargetDeviceID} {
+		if _, ok := m.devices[deviceID]; !ok {
+			return fmt.Errorf("failed to create connection: device %d does not exist", deviceID)
+		}
+	}
+	if conn.SourceDeviceID == conn.TargetDeviceID {
+		return fmt.Errorf("failed to create connection: source and target device must differ")
+	}
+	// Nothing is written until both the cable and the connection are known to be valid
+	if cable != nil {
+		if err := m.checkCableLabel(cable); err != nil {
+			return err
+		}
+		conn.CableID = nil
+	}
+	if err := m.checkConnectionReferences(conn); err != nil {
+		return fmt.Errorf("failed to create connection: %w", err)
+	}
+
+	if cable != nil {
+		m.insertCable(cable)
+		conn.CableID = &cable.ID
+	}
+	conn.ID = m.nextID()
+	conn.CreatedAt = time.Now()
+	// Tagging is only written through the VLANStore
+	conn.VLANTagging = models.VLANTagging{}
+	stored := copyConnection(conn)
+	m.connections[conn.ID] = &stored
+	*conn = copyConnection(&stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateConnection(conn *models.NetworkConnection) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.connections[conn.ID]
+	if !ok {
+		return ErrConnectionNotFound
+	}
+	if err := m.checkConnectionReferences(conn); err != nil {
+		return fmt.Errorf("failed to update connection: %w", err)
+	}
+
+	stored := copyConnection(current)
+	stored.SourceInterfaceID = copyIntPtr(conn.SourceInterfaceID)
+	stored.TargetInterfaceID = copyIntPtr(conn.TargetInterfaceID)
+	stored.CableID = copyIntPtr(conn.CableID)
+	stored.ConnectionType = conn.ConnectionType
+	stored.PortInfo = conn.PortInfo
+	stored.Speed = conn.Speed
+	m.connections[conn.ID] = &stored
+	*conn = copyConnection(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteConnection(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.connections[id]; !ok {
+		return ErrConnectionNotFound
+	}
+	delete(m.connections, id)
+	return nil
+}
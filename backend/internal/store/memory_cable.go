@@ -0,0 +1,137 @@
+package store
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// copyCable returns a copy of a stored cable with its connection and ends looked up
+func (m *memoryStore) copyCable(cable *models.Cable) models.Cable {
+	c := *cable
+	if cable.LengthM != nil {
+		length := *cable.LengthM
+		c.LengthM = &length
+	}
+	c.ConnectionID = m.cableConnection(cable.ID)
+	c.AEnd, c.BEnd = "", ""
+	if c.ConnectionID != nil {
+		conn := m.connections[*c.ConnectionID]
+		c.AEnd = m.cableEnd(conn.SourceDeviceID, conn.SourceInterfaceID)
+		c.BEnd = m.cableEnd(conn.TargetDeviceID, conn.TargetInterfaceID)
+	}
+	c.PrintableLabel = ""
+	return c
+}
+
+// cableEnd describes one end of a connection, e.g. "web-01 eth0"
+func (m *memoryStore) cableEnd(deviceID int, interfaceID *int) string {
+	end := m.devices[deviceID].Name
+	if interfaceID != nil {
+		end += " " + m.interfaces[*interfaceID].Name
+	}
+	return strings.TrimSpace(end)
+}
+
+func (m *memoryStore) ListCables(filter models.CableFilter) ([]models.Cable, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	label := strings.ToLower(filter.Label)
+	var cables []models.Cable
+	for _, cable := range m.cables {
+		if label != "" && !strings.Contains(strings.ToLower(cable.Label), label) {
+			continue
+		}
+		if filter.Media != "" && cable.Media != filter.Media {
+			continue
+		}
+		if filter.Status != "" && cable.Status != filter.Status {
+			continue
+		}
+		cables = append(cables, m.copyCable(cable))
+	}
+	sort.Slice(cables, func(i, j int) bool { return cables[i].Label < cables[j].Label })
+	return cables, nil
+}
+
+func (m *memoryStore) GetCable(id int) (*models.Cable, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cable, ok := m.cables[id]
+	if !ok {
+		return nil, ErrCableNotFound
+	}
+	c := m.copyCable(cable)
+	return &c, nil
+}
+
+// checkCableLabel enforces the cables_label_key constraint of the Postgres schema
+func (m *memoryStore) checkCableLabel(cable *models.Cable) error {
+	for _, other := range m.cables {
+		if other.ID != cable.ID && other.Label == cable.Label {
+			return ErrCableLabelInUse
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateCable(cable *models.Cable) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkCableLabel(cable); err != nil {
+		return err
+	}
+	m.insertCable(cable)
+	return nil
+}
+
+// insertCable stores a new cable that has passed checkCableLabel
+func (m *memoryStore) insertCable(cable *models.Cable) {
+	now := time.Now()
+	cable.ID = m.nextID()
+	cable.CreatedAt, cable.UpdatedAt = now, now
+	stored := m.copyCable(cable)
+	m.cables[cable.ID] = &stored
+	*cable = m.copyCable(&stored)
+}
+
+func (m *memoryStore) UpdateCable(cable *models.Cable) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.cables[cable.ID]
+	if !ok {
+		return ErrCableNotFound
+	}
+	if err := m.checkCableLabel(cable); err != nil {
+		return err
+	}
+
+	stored := m.copyCable(cable)
+	stored.CreatedAt = current.CreatedAt
+	stored.UpdatedAt = time.Now()
+	m.cables[cable.ID] = &stored
+	*cable = m.copyCable(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteCable(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.cables[id]; !ok {
+		return ErrCableNotFound
+	}
+	delete(m.cables, id)
+	for _, conn := range m.connections {
+		if conn.CableID != nil && *conn.CableID == id {
+			conn.CableID = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// copyDeviceType returns a copy of a stored device type that shares no memory with it
+func copyDeviceType(deviceType *models.DeviceTypeTemplate) models.DeviceTypeTemplate {
+	c := *deviceType
+	c.Specs = make(map[string]string, len(deviceType.Specs))
+	for k, v := range deviceType.Specs {
+		c.Specs[k] = v
+	}
+	c.Ports = append([]models.PortTemplate{}, deviceType.Ports...)
+	return c
+}
+
+func (m *memoryStore) ListDeviceTypes() ([]models.DeviceTypeTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var deviceTypes []models.DeviceTypeTemplate
+	for _, deviceType := range m.deviceTypes {
+		deviceTypes = append(deviceTypes, copyDeviceType(deviceType))
+	}
+	sort.Slice(deviceTypes, func(i, j int) bool {
+		a, b := deviceTypes[i], deviceTypes[j]
+		if a.Manufacturer != b.Manufacturer {
+			return a.Manufacturer < b.Manufacturer
+		}
+		return a.Model < b.Model
+	})
+	return deviceTypes, nil
+}
+
+func (m *memoryStore) GetDeviceType(id int) (*models.DeviceTypeTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	deviceType, ok := m.deviceTypes[id]
+	if !ok {
+		return nil, ErrDeviceTypeNotFound
+	}
+	c := copyDeviceType(deviceType)
+	return &c, nil
+}
+
+// checkDeviceTypeModel enforces the unique manufacturer and model of the Postgres schema
+func (m *memoryStore) checkDeviceTypeModel(deviceType *models.DeviceTypeTemplate) error {
+	for _, other := range m.deviceTypes {
+		if other.ID != deviceType.ID && other.Manufacturer == deviceType.Manufacturer && other.Model == deviceType.Model {
+			return fmt.Errorf("device type %s %s already exists", deviceType.Manufacturer, deviceType.Model)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateDeviceType(deviceType *models.DeviceTypeTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkDeviceTypeModel(deviceType); err != nil {
+		return fmt.Errorf("failed to create device type: %w", err)
+	}
+
+	now := time.Now()
+	deviceType.ID = m.nextID()
+	deviceType.CreatedAt, deviceType.UpdatedAt = now, now
+	stored := copyDeviceType(deviceType)
+	m.deviceTypes[deviceType.ID] = &stored
+	*deviceType = copyDeviceType(&stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateDeviceType(deviceType *models.DeviceTypeTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.deviceTypes[deviceType.ID]
+	if !ok {
+		return ErrDeviceTypeNotFound
+	}
+	if err := m.checkDeviceTypeModel(deviceType); err != nil {
+		return fmt.Errorf("failed to update device type: %w", err)
+	}
+
+	stored := copyDeviceType(deviceType)
+	stored.CreatedAt = current.CreatedAt
+	stored.UpdatedAt = time.Now()
+	m.deviceTypes[deviceType.ID] = &stored
+	*deviceType = copyDeviceType(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteDeviceType(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.deviceTypes[id]; !ok {
+		return ErrDeviceTypeNotFound
+	}
+	delete(m.deviceTypes, id)
+	for _, device := range m.devices {
+		if device.DeviceTypeID != nil && *device.DeviceTypeID == id {
+			device.DeviceTypeID = nil
+		}
+	}
+	return nil
+}
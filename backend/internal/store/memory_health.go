@@ -0,0 +1,251 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"rackview/internal/models"
+)
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// copyHealthProbe returns a copy of a stored probe that shares no memory with it
+func copyHealthProbe(probe *models.HealthProbe) models.HealthProbe {
+	c := *probe
+	c.Config.Ports = append([]int(nil), probe.Config.Ports...)
+	c.Config.RequestHeaders = copyStringMap(probe.Config.RequestHeaders)
+	c.Config.ExpectedStatus = append([]int(nil), probe.Config.ExpectedStatus...)
+	c.Config.ExpectedHeaders = copyStringMap(probe.Config.ExpectedHeaders)
+	c.Config.Expected = append([]string(nil), probe.Config.Expected...)
+	return c
+}
+
+// Probes
+
+func (m *memoryStore) ListHealthProbes(filter HealthProbeFilter) ([]models.HealthProbe, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var probes []models.HealthProbe
+	for _, probe := range m.healthProbes {
+		if filter.DeviceID != nil && probe.DeviceID != *filter.DeviceID {
+			continue
+		}
+		if filter.EnabledOnly && !probe.Enabled {
+			continue
+		}
+		probes = append(probes, copyHealthProbe(probe))
+	}
+	sort.Slice(probes, func(i, j int) bool { return probes[i].ID < probes[j].ID })
+	return probes, nil
+}
+
+func (m *memoryStore) GetHealthProbe(id int) (*models.HealthProbe, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	probe, ok := m.healthProbes[id]
+	if !ok {
+		return nil, ErrHealthProbeNotFound
+	}
+	c := copyHealthProbe(probe)
+	return &c, nil
+}
+
+func (m *memoryStore) CreateHealthProbe(probe *models.HealthProbe) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.devices[probe.DeviceID]; !ok {
+		return fmt.Errorf("failed to create health probe: device %d does not exist", probe.DeviceID)
+	}
+
+	now := time.Now()
+	probe.ID = m.nextID()
+	probe.CreatedAt, probe.UpdatedAt = now, now
+	stored := copyHealthProbe(probe)
+	m.healthProbes[probe.ID] = &stored
+	*probe = copyHealthProbe(&stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateHealthProbe(probe *models.HealthProbe) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.healthProbes[probe.ID]
+	if !ok {
+		return ErrHealthProbeNotFound
+	}
+
+	stored := copyHealthProbe(current)
+	stored.Name = probe.Name
+	stored.Enabled = probe.Enabled
+	stored.Config = copyHealthProbe(probe).Config
+	stored.UpdatedAt = time.Now()
+	m.healthProbes[probe.ID] = &stored
+	*probe = copyHealthProbe(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteHealthProbe(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.healthProbes[id]; !ok {
+		return ErrHealthProbeNotFound
+	}
+	delete(m.healthProbes, id)
+	return nil
+}
+
+// Checks
+
+func (m *memoryStore) RecordHealthCheck(check *models.HealthCheck) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.devices[check.DeviceID]; !ok {
+		return fmt.Errorf("failed to record health check: device %d does not exist", check.DeviceID)
+	}
+
+	check.ID = int64(m.nextID())
+	check.CheckedAt = check.CheckedAt.UTC()
+	stored := *check
+	if check.Latency != nil {
+		latency := *check.Latency
+		stored.Latency = &latency
+	}
+	m.healthChecks[check.ID] = &stored
+	return nil
+}
+
+// deviceHealthChecks returns a device's checks in a time range, oldest first
+func (m *memoryStore) deviceHealthChecks(deviceID int, from, to time.Time) []models.HealthCheck {
+	var checks []models.HealthCheck
+	for _, check := range m.healthChecks {
+		if check.DeviceID != deviceID || check.CheckedAt.Before(from) || !check.CheckedAt.Before(to) {
+			continue
+		}
+		c := *check
+		if check.Latency != nil {
+			latency := *check.Latency
+			c.Latency = &latency
+		}
+		checks = append(checks, c)
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		if !checks[i].CheckedAt.Equal(checks[j].CheckedAt) {
+			return checks[i].CheckedAt.Before(checks[j].CheckedAt)
+		}
+		return checks[i].ID < checks[j].ID
+	})
+	return checks
+}
+
+func (m *memoryStore) ListHealthChecks(deviceID int, from, to time.Time, limit int) ([]models.HealthCheck, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	checks := m.deviceHealthChecks(deviceID, from, to)
+	if len(checks) > limit {
+		checks = checks[:limit]
+	}
+	return checks, nil
+}
+
+func (m *memoryStore) SummarizeHealthChecks(deviceID int, from, to time.Time, bucketSeconds int) ([]models.HealthHistoryBucket, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var buckets []models.HealthHistoryBucket
+	var latencyTotal, latencyCount int64
+	index := int64(-1)
+	for _, check := range m.deviceHealthChecks(deviceID, from, to) {
+		checkIndex := check.CheckedAt.Unix() / int64(bucketSeconds)
+		if checkIndex != index || len(buckets) == 0 {
+			index = checkIndex
+			latencyTotal, latencyCount = 0, 0
+			buckets = append(buckets, models.HealthHistoryBucket{Start: time.Unix(index*int64(bucketSeconds), 0).UTC()})
+		}
+		bucket := &buckets[len(buckets)-1]
+
+		bucket.Checks++
+		switch check.Status {
+		case models.DeviceStatusOnline:
+			bucket.Online++
+		case models.DeviceStatusOffline:
+			bucket.Offline++
+		case models.DeviceStatusWarning:
+			bucket.Warning++
+		case models.DeviceStatusUnknown:
+			bucket.Unknown++
+		}
+
+		if check.Latency == nil {
+			continue
+		}
+		latency := *check.Latency
+		latencyTotal += latency
+		latencyCount++
+		avg := float64(latencyTotal) / float64(latencyCount)
+		bucket.AvgLatency = &avg
+		if bucket.MinLatency == nil || latency < *bucket.MinLatency {
+			bucket.MinLatency = &latency
+		}
+		if bucket.MaxLatency == nil || latency > *bucket.MaxLatency {
+			max := latency
+			bucket.MaxLatency = &max
+		}
+	}
+	return buckets, nil
+}
+
+func (m *memoryStore) PruneHealthChecks(before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var removed int64
+	for id, check := range m.healthChecks {
+		if check.CheckedAt.Before(before) {
+			delete(m.healthChecks, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (m *memoryStore) LatestHealthChecks() ([]models.HealthCheck, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	latest := map[int]*models.HealthCheck{}
+	for _, check := range m.healthChecks {
+		current, ok := latest[check.DeviceID]
+		if !ok || check.CheckedAt.After(current.CheckedAt) || (check.CheckedAt.Equal(current.CheckedAt) && check.ID > current.ID) {
+			latest[check.DeviceID] = check
+		}
+	}
+
+	var checks []models.HealthCheck
+	for _, check := range latest {
+		c := *check
+		if check.Latency != nil {
+			latency := *check.Latency
+			c.Latency = &latency
+		}
+		checks = append(checks, c)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].DeviceID < checks[j].DeviceID })
+	return checks, nil
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// copyInterface returns a copy of a stored interface with its front port and connection looked up
+func (m *memoryStore) copyInterface(iface *models.DeviceInterface) models.DeviceInterface {
+	c := *iface
+	c.RearPortID = copyIntPtr(iface.RearPortID)
+	c.UntaggedVLANID = copyIntPtr(iface.UntaggedVLANID)
+	c.TaggedVLANIDs = append([]int(nil), iface.TaggedVLANIDs...)
+	c.FrontPortID = nil
+	for _, other := range m.interfaces {
+		if other.RearPortID != nil && *other.RearPortID == iface.ID {
+			id := other.ID
+			c.FrontPortID = &id
+		}
+	}
+	c.ConnectionID = m.interfaceConnection(iface.ID)
+	return c
+}
+
+func (m *memoryStore) ListInterfaces(filter InterfaceFilter) ([]models.DeviceInterface, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var interfaces []models.DeviceInterface
+	for _, iface := range m.interfaces {
+		if filter.DeviceID != nil && iface.DeviceID != *filter.DeviceID {
+			continue
+		}
+		if filter.RackID != nil && m.devices[iface.DeviceID].RackID != *filter.RackID {
+			continue
+		}
+		interfaces = append(interfaces, m.copyInterface(iface))
+	}
+
+	sort.Slice(interfaces, func(i, j int) bool {
+		a, b := interfaces[i], interfaces[j]
+		if a.DeviceID != b.DeviceID {
+			return a.DeviceID < b.DeviceID
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	return interfaces, nil
+}
+
+func (m *memoryStore) GetInterface(id int) (*models.DeviceInterface, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	iface, ok := m.interfaces[id]
+	if !ok {
+		return nil, ErrInterfaceNotFound
+	}
+	c := m.copyInterface(iface)
+	return &c, nil
+}
+
+// checkInterface enforces the interface foreign keys, the unique name per device, the unique rear
+// port index and the rear port check of the Postgres schema
+func (m *memoryStore) checkInterface(iface *models.DeviceInterface) error {
+	if _, ok := m.devices[iface.DeviceID]; !ok {
+		return fmt.Errorf("device %d does not exist", iface.DeviceID)
+	}
+	for _, other := range m.interfaces {
+		if other.ID != iface.ID && other.DeviceID == iface.DeviceID && other.Name == iface.Name {
+			return fmt.Errorf("interface %s already exists on device %d", iface.Name, iface.DeviceID)
+		}
+	}
+	if iface.RearPortID != nil {
+		if *iface.RearPortID == iface.ID {
+			return fmt.Errorf("interface %d cannot be its own rear port", iface.ID)
+		}
+		if _, ok := m.interfaces[*iface.RearPortID]; !ok {
+			return fmt.Errorf("interface %d does not exist", *iface.RearPortID)
+		}
+		for _, other := range m.interfaces {
+			if other.ID != iface.ID && other.RearPortID != nil && *other.RearPortID == *iface.RearPortID {
+				return fmt.Errorf("rear port %d is already paired with interface %d", *iface.RearPortID, other.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateInterface(iface *models.DeviceInterface) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkInterface(iface); err != nil {
+		return fmt.Errorf("failed to create interface: %w", err)
+	}
+	m.insertInterface(iface)
+	return nil
+}
+
+// insertInterface stores a new interface that has passed checkInterface
+func (m *memoryStore) insertInterface(iface *models.DeviceInterface) {
+	now := time.Now()
+	iface.ID = m.nextID()
+	iface.CreatedAt, iface.UpdatedAt = now, now
+	// Tagging is only written through the VLANStore
+	iface.VLANTagging = models.VLANTagging{}
+	stored := m.copyInterface(iface)
+	m.interfaces[iface.ID] = &stored
+	*iface = m.copyInterface(&stored)
+}
+
+func (m *memoryStore) UpdateInterface(iface *models.DeviceInterface) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.interfaces[iface.ID]
+	if !ok {
+		return ErrInterfaceNotFound
+	}
+	iface.DeviceID = current.DeviceID
+	if err := m.checkInterface(iface); err != nil {
+		return fmt.Errorf("failed to update interface: %w", err)
+	}
+
+	stored := m.copyInterface(current)
+	stored.Name = iface.Name
+	stored.Type = iface.Type
+	stored.Speed = iface.Speed
+	stored.MACAddress = iface.MACAddress
+	stored.Enabled = iface.Enabled
+	stored.Description = iface.Description
+	stored.RearPortID = copyIntPtr(iface.RearPortID)
+	stored.UpdatedAt = time.Now()
+	m.interfaces[iface.ID] = &stored
+	*iface = m.copyInterface(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteInterface(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.interfaces[id]; !ok {
+		return ErrInterfaceNotFound
+	}
+	m.deleteInterface(id)
+	return nil
+}
+
+// deleteInterface removes an interface and its addresses, detaching the connection on it and
+// unpairing its front port
+func (m *memoryStore) deleteInterface(id int) {
+	delete(m.interfaces, id)
+	for _, conn := range m.connections {
+		if conn.SourceInterfaceID != nil && *conn.SourceInterfaceID == id {
+			conn.SourceInterfaceID = nil
+		}
+		if conn.TargetInterfaceID != nil && *conn.TargetInterfaceID == id {
+			conn.TargetInterfaceID = nil
+		}
+	}
+	for _, other := range m.interfaces {
+		if other.RearPortID != nil && *other.RearPortID == id {
+			other.RearPortID = nil
+		}
+	}
+	for _, address := range m.addresses {
+		if address.InterfaceID != nil && *address.InterfaceID == id {
+			delete(m.addresses, address.ID)
+		}
+	}
+}
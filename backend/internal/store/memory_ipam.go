@@ -0,0 +1,231 @@
+package store
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	"rackview/internal/models"
+)
+
+func copyIPAddress(address *models.IPAddress) models.IPAddress {
+	c := *address
+	c.InterfaceID = copyIntPtr(address.InterfaceID)
+	c.PrefixID = copyIntPtr(address.PrefixID)
+	return c
+}
+
+// prefixRecords returns the stored prefixes for mostSpecificPrefix
+func (m *memoryStore) prefixRecords() []prefixRecord {
+	var records []prefixRecord
+	for _, prefix := range m.prefixes {
+		value, _ := netip.ParsePrefix(prefix.Prefix)
+		records = append(records, prefixRecord{id: prefix.ID, prefix: value})
+	}
+	return records
+}
+
+// reassignPrefixes points every address at the most specific prefix containing it
+func (m *memoryStore) reassignPrefixes() {
+	prefixes := m.prefixRecords()
+	for _, address := range m.addresses {
+		address.PrefixID = mostSpecificPrefix(prefixes, address.Address)
+	}
+}
+
+// Prefixes
+
+func (m *memoryStore) ListPrefixes() ([]models.IPPrefix, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var prefixes []models.IPPrefix
+	for _, prefix := range m.prefixes {
+		prefixes = append(prefixes, *prefix)
+	}
+	sortPrefixes(prefixes)
+	return prefixes, nil
+}
+
+func (m *memoryStore) GetPrefix(id int) (*models.IPPrefix, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	prefix, ok := m.prefixes[id]
+	if !ok {
+		return nil, ErrPrefixNotFound
+	}
+	c := *prefix
+	return &c, nil
+}
+
+// checkPrefix enforces the CIDR type and the ip_prefixes_prefix_key constraint of the Postgres schema
+func (m *memoryStore) checkPrefix(prefix *models.IPPrefix) error {
+	value, err := netip.ParsePrefix(prefix.Prefix)
+	if err != nil || value.Masked() != value {
+		return fmt.Errorf("failed to write prefix: invalid cidr value %q", prefix.Prefix)
+	}
+	for _, other := range m.prefixes {
+		if otherValue, _ := netip.ParsePrefix(other.Prefix); other.ID != prefix.ID && otherValue == value {
+			return ErrPrefixExists
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreatePrefix(prefix *models.IPPrefix) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkPrefix(prefix); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	prefix.ID = m.nextID()
+	prefix.CreatedAt, prefix.UpdatedAt = now, now
+	prefix.Used, prefix.Utilization = 0, 0
+	stored := *prefix
+	m.prefixes[prefix.ID] = &stored
+	m.reassignPrefixes()
+	return nil
+}
+
+func (m *memoryStore) UpdatePrefix(prefix *models.IPPrefix) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.prefixes[prefix.ID]
+	if !ok {
+		return ErrPrefixNotFound
+	}
+	if err := m.checkPrefix(prefix); err != nil {
+		return err
+	}
+
+	prefix.CreatedAt = current.CreatedAt
+	prefix.UpdatedAt = time.Now()
+	prefix.Used, prefix.Utilization = 0, 0
+	stored := *prefix
+	m.prefixes[prefix.ID] = &stored
+	m.reassignPrefixes()
+	return nil
+}
+
+func (m *memoryStore) DeletePrefix(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.prefixes[id]; !ok {
+		return ErrPrefixNotFound
+	}
+	delete(m.prefixes, id)
+	m.reassignPrefixes()
+	return nil
+}
+
+// Addresses
+
+func (m *memoryStore) ListIPAddresses(filter models.IPAddressFilter) ([]models.IPAddress, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var addresses []models.IPAddress
+	for _, address := range m.addresses {
+		if filter.DeviceID != nil && address.DeviceID != *filter.DeviceID {
+			continue
+		}
+		if filter.PrefixID != nil && (address.PrefixID == nil || *address.PrefixID != *filter.PrefixID) {
+			continue
+		}
+		if filter.Address != "" && address.Address != filter.Address {
+			continue
+		}
+		addresses = append(addresses, copyIPAddress(address))
+	}
+	sortIPAddresses(addresses)
+	return addresses, nil
+}
+
+func (m *memoryStore) GetIPAddress(id int) (*models.IPAddress, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	address, ok := m.addresses[id]
+	if !ok {
+		return nil, ErrIPAddressNotFound
+	}
+	c := copyIPAddress(address)
+	return &c, nil
+}
+
+// checkIPAddress enforces the INET type, the address foreign keys and the idx_ip_addresses_address
+// index of the Postgres schema
+func (m *memoryStore) checkIPAddress(address *models.IPAddress) error {
+	if _, err := netip.ParseAddr(address.Address); err != nil {
+		return fmt.Errorf("failed to write ip address: invalid inet value %q", address.Address)
+	}
+	if _, ok := m.devices[address.DeviceID]; !ok {
+		return fmt.Errorf("failed to write ip address: device %d does not exist", address.DeviceID)
+	}
+	if address.InterfaceID != nil {
+		if _, ok := m.interfaces[*address.InterfaceID]; !ok {
+			return fmt.Errorf("failed to write ip address: interface %d does not exist", *address.InterfaceID)
+		}
+	}
+	for _, other := range m.addresses {
+		if other.ID != address.ID && other.Address == address.Address {
+			return ErrIPAddressInUse
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateIPAddress(address *models.IPAddress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkIPAddress(address); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	address.ID = m.nextID()
+	address.PrefixID = mostSpecificPrefix(m.prefixRecords(), address.Address)
+	address.CreatedAt, address.UpdatedAt = now, now
+	stored := copyIPAddress(address)
+	m.addresses[address.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) UpdateIPAddress(address *models.IPAddress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.addresses[address.ID]
+	if !ok {
+		return ErrIPAddressNotFound
+	}
+	address.DeviceID = current.DeviceID
+	if err := m.checkIPAddress(address); err != nil {
+		return err
+	}
+
+	address.PrefixID = mostSpecificPrefix(m.prefixRecords(), address.Address)
+	address.CreatedAt = current.CreatedAt
+	address.UpdatedAt = time.Now()
+	stored := copyIPAddress(address)
+	m.addresses[address.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) DeleteIPAddress(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.addresses[id]; !ok {
+		return ErrIPAddressNotFound
+	}
+	delete(m.addresses, id)
+	return nil
+}
@@ -0,0 +1,340 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// Sites
+
+func (m *memoryStore) ListSites() ([]models.Site, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sites []models.Site
+	for _, site := range m.sites {
+		sites = append(sites, *site)
+	}
+	sort.Slice(sites, func(i, j int) bool { return sites[i].Name < sites[j].Name })
+	return sites, nil
+}
+
+func (m *memoryStore) GetSite(id int) (*models.Site, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	site, ok := m.sites[id]
+	if !ok {
+		return nil, ErrSiteNotFound
+	}
+	c := *site
+	return &c, nil
+}
+
+// checkSiteName enforces the unique site name of the Postgres schema
+func (m *memoryStore) checkSiteName(site *models.Site) error {
+	for _, other := range m.sites {
+		if other.ID != site.ID && other.Name == site.Name {
+			return fmt.Errorf("site %s already exists", site.Name)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateSite(site *models.Site) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkSiteName(site); err != nil {
+		return fmt.Errorf("failed to create site: %w", err)
+	}
+
+	now := time.Now()
+	site.ID = m.nextID()
+	site.CreatedAt, site.UpdatedAt = now, now
+	stored := *site
+	m.sites[site.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) UpdateSite(site *models.Site) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.sites[site.ID]
+	if !ok {
+		return ErrSiteNotFound
+	}
+	if err := m.checkSiteName(site); err != nil {
+		return fmt.Errorf("failed to update site: %w", err)
+	}
+
+	site.CreatedAt = current.CreatedAt
+	site.UpdatedAt = time.Now()
+	stored := *site
+	m.sites[site.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) DeleteSite(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sites[id]; !ok {
+		return ErrSiteNotFound
+	}
+	delete(m.sites, id)
+	for _, room := range m.rooms {
+		if room.SiteID == id {
+			m.deleteRoom(room.ID)
+		}
+	}
+	for _, rack := range m.racks {
+		if rack.SiteID != nil && *rack.SiteID == id {
+			rack.SiteID = nil
+		}
+	}
+	for _, group := range m.vlanGroups {
+		if group.SiteID != nil && *group.SiteID == id {
+			m.deleteVLANGroup(group.ID)
+		}
+	}
+	for _, vlan := range m.vlans {
+		if vlan.SiteID != nil && *vlan.SiteID == id {
+			m.deleteVLAN(vlan.ID)
+		}
+	}
+	return nil
+}
+
+// Rooms
+
+func (m *memoryStore) ListRooms(siteID *int) ([]models.Room, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var rooms []models.Room
+	for _, room := range m.rooms {
+		if siteID == nil || room.SiteID == *siteID {
+			rooms = append(rooms, *room)
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		if rooms[i].SiteID != rooms[j].SiteID {
+			return rooms[i].SiteID < rooms[j].SiteID
+		}
+		return rooms[i].Name < rooms[j].Name
+	})
+	return rooms, nil
+}
+
+func (m *memoryStore) GetRoom(id int) (*models.Room, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	room, ok := m.rooms[id]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+	c := *room
+	return &c, nil
+}
+
+// checkRoom enforces the room foreign key and the unique room name per site of the Postgres schema
+func (m *memoryStore) checkRoom(room *models.Room) error {
+	if _, ok := m.sites[room.SiteID]; !ok {
+		return fmt.Errorf("site %d does not exist", room.SiteID)
+	}
+	for _, other := range m.rooms {
+		if other.ID != room.ID && other.SiteID == room.SiteID && other.Name == room.Name {
+			return fmt.Errorf("room %s already exists in site %d", room.Name, room.SiteID)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateRoom(room *models.Room) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkRoom(room); err != nil {
+		return fmt.Errorf("failed to create room: %w", err)
+	}
+
+	now := time.Now()
+	room.ID = m.nextID()
+	room.CreatedAt, room.UpdatedAt = now, now
+	stored := *room
+	m.rooms[room.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) UpdateRoom(room *models.Room) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.rooms[room.ID]
+	if !ok {
+		return ErrRoomNotFound
+	}
+	if err := m.checkRoom(room); err != nil {
+		return fmt.Errorf("failed to update room: %w", err)
+	}
+
+	now := time.Now()
+	room.CreatedAt = current.CreatedAt
+	room.UpdatedAt = now
+	stored := *room
+	m.rooms[room.ID] = &stored
+	for _, rack := range m.racks {
+		if rack.RoomID != nil && *rack.RoomID == room.ID {
+			siteID := room.SiteID
+			rack.SiteID = &siteID
+			rack.UpdatedAt = now
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) DeleteRoom(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.rooms[id]; !ok {
+		return ErrRoomNotFound
+	}
+	m.deleteRoom(id)
+	return nil
+}
+
+// deleteRoom removes a room and its rows; its racks lose the room and row
+func (m *memoryStore) deleteRoom(id int) {
+	delete(m.rooms, id)
+	for _, row := range m.rows {
+		if row.RoomID == id {
+			m.deleteRow(row.ID)
+		}
+	}
+	for _, rack := range m.racks {
+		if rack.RoomID != nil && *rack.RoomID == id {
+			rack.RoomID = nil
+		}
+	}
+}
+
+// Rows
+
+func (m *memoryStore) ListRows(filter RowFilter) ([]models.Row, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var rows []models.Row
+	for _, row := range m.rows {
+		if filter.RoomID != nil && row.RoomID != *filter.RoomID {
+			continue
+		}
+		if filter.SiteID != nil && m.rooms[row.RoomID].SiteID != *filter.SiteID {
+			continue
+		}
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].RoomID != rows[j].RoomID {
+			return rows[i].RoomID < rows[j].RoomID
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows, nil
+}
+
+func (m *memoryStore) GetRow(id int) (*models.Row, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, ErrRowNotFound
+	}
+	c := *row
+	return &c, nil
+}
+
+// checkRow enforces the row foreign key and the unique row name per room of the Postgres schema
+func (m *memoryStore) checkRow(row *models.Row) error {
+	if _, ok := m.rooms[row.RoomID]; !ok {
+		return fmt.Errorf("room %d does not exist", row.RoomID)
+	}
+	for _, other := range m.rows {
+		if other.ID != row.ID && other.RoomID == row.RoomID && other.Name == row.Name {
+			return fmt.Errorf("row %s already exists in room %d", row.Name, row.RoomID)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateRow(row *models.Row) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkRow(row); err != nil {
+		return fmt.Errorf("failed to create row: %w", err)
+	}
+
+	now := time.Now()
+	row.ID = m.nextID()
+	row.CreatedAt, row.UpdatedAt = now, now
+	stored := *row
+	m.rows[row.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) UpdateRow(row *models.Row) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.rows[row.ID]
+	if !ok {
+		return ErrRowNotFound
+	}
+	if err := m.checkRow(row); err != nil {
+		return fmt.Errorf("failed to update row: %w", err)
+	}
+
+	now := time.Now()
+	row.CreatedAt = current.CreatedAt
+	row.UpdatedAt = now
+	stored := *row
+	m.rows[row.ID] = &stored
+	for _, rack := range m.racks {
+		if rack.RowID != nil && *rack.RowID == row.ID {
+			roomID, siteID := row.RoomID, m.rooms[row.RoomID].SiteID
+			rack.RoomID, rack.SiteID = &roomID, &siteID
+			rack.UpdatedAt = now
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) DeleteRow(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.rows[id]; !ok {
+		return ErrRowNotFound
+	}
+	m.deleteRow(id)
+	return nil
+}
+
+// deleteRow removes a row; its racks lose the row
+func (m *memoryStore) deleteRow(id int) {
+	delete(m.rows, id)
+	for _, rack := range m.racks {
+		if rack.RowID != nil && *rack.RowID == id {
+			rack.RowID = nil
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package store_test
+
+import (
+	"testing"
+
+	"rackview/internal/store"
+	"rackview/internal/store/storetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) *store.Store { return store.NewMemoryStore() })
+}
@@ -0,0 +1,368 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// copyVLANGroup returns a copy of a stored group that shares no memory with it
+func copyVLANGroup(group *models.VLANGroup) models.VLANGroup {
+	c := *group
+	c.SiteID = copyIntPtr(group.SiteID)
+	return c
+}
+
+// copyVLAN returns a copy of a stored VLAN that shares no memory with it
+func copyVLAN(vlan *models.VLAN) models.VLAN {
+	c := *vlan
+	c.GroupID = copyIntPtr(vlan.GroupID)
+	c.SiteID = copyIntPtr(vlan.SiteID)
+	return c
+}
+
+// sameID reports whether two optional IDs are both unset or equal
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// VLAN groups
+
+func (m *memoryStore) ListVLANGroups() ([]models.VLANGroup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var groups []models.VLANGroup
+	for _, group := range m.vlanGroups {
+		groups = append(groups, copyVLANGroup(group))
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+func (m *memoryStore) GetVLANGroup(id int) (*models.VLANGroup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	group, ok := m.vlanGroups[id]
+	if !ok {
+		return nil, ErrVLANGroupNotFound
+	}
+	c := copyVLANGroup(group)
+	return &c, nil
+}
+
+// checkVLANGroup enforces the site foreign key and unique name of the Postgres schema
+func (m *memoryStore) checkVLANGroup(group *models.VLANGroup) error {
+	if group.SiteID != nil {
+		if _, ok := m.sites[*group.SiteID]; !ok {
+			return fmt.Errorf("site %d does not exist", *group.SiteID)
+		}
+	}
+	for _, other := range m.vlanGroups {
+		if other.ID != group.ID && other.Name == group.Name {
+			return ErrVLANGroupExists
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateVLANGroup(group *models.VLANGroup) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkVLANGroup(group); err != nil {
+		if err == ErrVLANGroupExists {
+			return err
+		}
+		return fmt.Errorf("failed to create vlan group: %w", err)
+	}
+
+	now := time.Now()
+	group.ID = m.nextID()
+	group.CreatedAt, group.UpdatedAt = now, now
+	stored := copyVLANGroup(group)
+	m.vlanGroups[group.ID] = &stored
+	*group = copyVLANGroup(&stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateVLANGroup(group *models.VLANGroup) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.vlanGroups[group.ID]
+	if !ok {
+		return ErrVLANGroupNotFound
+	}
+	if err := m.checkVLANGroup(group); err != nil {
+		if err == ErrVLANGroupExists {
+			return err
+		}
+		return fmt.Errorf("failed to update vlan group: %w", err)
+	}
+
+	// The group's VLANs move with it; nothing is written unless they all fit at the new site
+	var moved []models.VLAN
+	for _, vlan := range m.vlans {
+		if vlan.GroupID != nil && *vlan.GroupID == group.ID {
+			c := copyVLAN(vlan)
+			c.SiteID = copyIntPtr(group.SiteID)
+			moved = append(moved, c)
+		}
+	}
+	for i := range moved {
+		if err := m.checkVLANScope(&moved[i]); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	group.CreatedAt = current.CreatedAt
+	group.UpdatedAt = now
+	stored := copyVLANGroup(group)
+	m.vlanGroups[group.ID] = &stored
+	for i := range moved {
+		vlan := m.vlans[moved[i].ID]
+		if !sameID(vlan.SiteID, moved[i].SiteID) {
+			vlan.SiteID = moved[i].SiteID
+			vlan.UpdatedAt = now
+		}
+	}
+	*group = copyVLANGroup(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteVLANGroup(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.vlanGroups[id]; !ok {
+		return ErrVLANGroupNotFound
+	}
+	m.deleteVLANGroup(id)
+	return nil
+}
+
+// deleteVLANGroup removes a group and its VLANs
+func (m *memoryStore) deleteVLANGroup(id int) {
+	delete(m.vlanGroups, id)
+	for _, vlan := range m.vlans {
+		if vlan.GroupID != nil && *vlan.GroupID == id {
+			m.deleteVLAN(vlan.ID)
+		}
+	}
+}
+
+// VLANs
+
+func (m *memoryStore) ListVLANs(filter models.VLANFilter) ([]models.VLAN, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var vlans []models.VLAN
+	for _, vlan := range m.vlans {
+		if filter.GroupID != nil && (vlan.GroupID == nil || *vlan.GroupID != *filter.GroupID) {
+			continue
+		}
+		if filter.SiteID != nil && (vlan.SiteID == nil || *vlan.SiteID != *filter.SiteID) {
+			continue
+		}
+		vlans = append(vlans, copyVLAN(vlan))
+	}
+	sort.Slice(vlans, func(i, j int) bool {
+		if vlans[i].VID != vlans[j].VID {
+			return vlans[i].VID < vlans[j].VID
+		}
+		return vlans[i].ID < vlans[j].ID
+	})
+	return vlans, nil
+}
+
+func (m *memoryStore) GetVLAN(id int) (*models.VLAN, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	vlan, ok := m.vlans[id]
+	if !ok {
+		return nil, ErrVLANNotFound
+	}
+	c := copyVLAN(vlan)
+	return &c, nil
+}
+
+// checkVLANScope enforces the VID range check and the idx_vlans_scope_vid index of the Postgres
+// schema
+func (m *memoryStore) checkVLANScope(vlan *models.VLAN) error {
+	if vlan.VID < 1 || vlan.VID > 4094 {
+		return fmt.Errorf("vid %d is out of range", vlan.VID)
+	}
+	for _, other := range m.vlans {
+		if other.ID != vlan.ID && other.VID == vlan.VID && sameID(other.GroupID, vlan.GroupID) && sameID(other.SiteID, vlan.SiteID) {
+			return ErrVLANExists
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) CreateVLAN(vlan *models.VLAN) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// A grouped VLAN takes its group's site, whatever SiteID says
+	if vlan.GroupID != nil {
+		group, ok := m.vlanGroups[*vlan.GroupID]
+		if !ok {
+			return fmt.Errorf("failed to create vlan: vlan group %d does not exist", *vlan.GroupID)
+		}
+		vlan.SiteID = copyIntPtr(group.SiteID)
+	} else if vlan.SiteID != nil {
+		if _, ok := m.sites[*vlan.SiteID]; !ok {
+			return fmt.Errorf("failed to create vlan: site %d does not exist", *vlan.SiteID)
+		}
+	}
+	if err := m.checkVLANScope(vlan); err != nil {
+		if err == ErrVLANExists {
+			return err
+		}
+		return fmt.Errorf("failed to create vlan: %w", err)
+	}
+
+	now := time.Now()
+	vlan.ID = m.nextID()
+	vlan.CreatedAt, vlan.UpdatedAt = now, now
+	stored := copyVLAN(vlan)
+	m.vlans[vlan.ID] = &stored
+	*vlan = copyVLAN(&stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateVLAN(vlan *models.VLAN) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.vlans[vlan.ID]
+	if !ok {
+		return ErrVLANNotFound
+	}
+
+	stored := copyVLAN(current)
+	stored.VID = vlan.VID
+	stored.Name = vlan.Name
+	stored.Description = vlan.Description
+	if err := m.checkVLANScope(&stored); err != nil {
+		if err == ErrVLANExists {
+			return err
+		}
+		return fmt.Errorf("failed to update vlan: %w", err)
+	}
+	stored.UpdatedAt = time.Now()
+	m.vlans[vlan.ID] = &stored
+	*vlan = copyVLAN(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteVLAN(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.vlans[id]; !ok {
+		return ErrVLANNotFound
+	}
+	m.deleteVLAN(id)
+	return nil
+}
+
+// deleteVLAN removes a VLAN and takes it out of the tagging of every interface and connection
+func (m *memoryStore) deleteVLAN(id int) {
+	delete(m.vlans, id)
+	for _, iface := range m.interfaces {
+		iface.VLANTagging = withoutVLAN(iface.VLANTagging, id)
+	}
+	for _, conn := range m.connections {
+		conn.VLANTagging = withoutVLAN(conn.VLANTagging, id)
+	}
+}
+
+// withoutVLAN returns a tagging with a VLAN removed from it
+func withoutVLAN(tagging models.VLANTagging, vlanID int) models.VLANTagging {
+	if tagging.UntaggedVLANID != nil && *tagging.UntaggedVLANID == vlanID {
+		tagging.UntaggedVLANID = nil
+	}
+	var tagged []int
+	for _, id := range tagging.TaggedVLANIDs {
+		if id != vlanID {
+			tagged = append(tagged, id)
+		}
+	}
+	tagging.TaggedVLANIDs = tagged
+	return tagging
+}
+
+// Tagging
+
+// checkTagging enforces the VLAN foreign keys and mode check of the Postgres schema and returns
+// the tagging as it reads back: tagged VLANs sorted, without repeats
+func (m *memoryStore) checkTagging(tagging models.VLANTagging) (models.VLANTagging, error) {
+	switch tagging.VLANMode {
+	case "", models.VLANModeAccess, models.VLANModeTrunk:
+	default:
+		return tagging, fmt.Errorf("invalid vlan mode %s", tagging.VLANMode)
+	}
+
+	stored := models.VLANTagging{VLANMode: tagging.VLANMode, UntaggedVLANID: copyIntPtr(tagging.UntaggedVLANID)}
+	if stored.UntaggedVLANID != nil {
+		if _, ok := m.vlans[*stored.UntaggedVLANID]; !ok {
+			return tagging, fmt.Errorf("vlan %d does not exist", *stored.UntaggedVLANID)
+		}
+	}
+	seen := map[int]bool{}
+	for _, id := range tagging.TaggedVLANIDs {
+		if _, ok := m.vlans[id]; !ok {
+			return tagging, fmt.Errorf("vlan %d does not exist", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			stored.TaggedVLANIDs = append(stored.TaggedVLANIDs, id)
+		}
+	}
+	sort.Ints(stored.TaggedVLANIDs)
+	return stored, nil
+}
+
+func (m *memoryStore) SetInterfaceVLANs(interfaceID int, tagging models.VLANTagging) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	iface, ok := m.interfaces[interfaceID]
+	if !ok {
+		return ErrInterfaceNotFound
+	}
+	stored, err := m.checkTagging(tagging)
+	if err != nil {
+		return fmt.Errorf("failed to set vlan tagging: %w", err)
+	}
+	iface.VLANTagging = stored
+	return nil
+}
+
+func (m *memoryStore) SetConnectionVLANs(connectionID int, tagging models.VLANTagging) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conn, ok := m.connections[connectionID]
+	if !ok {
+		return ErrConnectionNotFound
+	}
+	stored, err := m.checkTagging(tagging)
+	if err != nil {
+		return fmt.Errorf("failed to set vlan tagging: %w", err)
+	}
+	conn.VLANTagging = stored
+	return nil
+}
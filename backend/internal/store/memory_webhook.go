@@ -0,0 +1,172 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// copyWebhook returns a copy of a stored webhook that shares no memory with it
+func copyWebhook(webhook *models.Webhook) models.Webhook {
+	c := *webhook
+	c.Statuses = append([]models.DeviceStatus{}, webhook.Statuses...)
+	c.HasSecret = c.Secret != ""
+	return c
+}
+
+// copyWebhookDelivery returns a copy of a stored delivery that shares no memory with it
+func copyWebhookDelivery(delivery *models.WebhookDelivery) models.WebhookDelivery {
+	c := *delivery
+	c.DeviceID = copyIntPtr(delivery.DeviceID)
+	c.StatusCode = copyIntPtr(delivery.StatusCode)
+	c.Payload = append(json.RawMessage(nil), delivery.Payload...)
+	return c
+}
+
+// Webhooks
+
+func (m *memoryStore) ListWebhooks() ([]models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var webhooks []models.Webhook
+	for _, webhook := range m.webhooks {
+		webhooks = append(webhooks, copyWebhook(webhook))
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+func (m *memoryStore) GetWebhook(id int) (*models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	webhook, ok := m.webhooks[id]
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+	c := copyWebhook(webhook)
+	return &c, nil
+}
+
+func (m *memoryStore) CreateWebhook(webhook *models.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	webhook.ID = m.nextID()
+	webhook.CreatedAt, webhook.UpdatedAt = now, now
+	stored := copyWebhook(webhook)
+	m.webhooks[webhook.ID] = &stored
+	*webhook = copyWebhook(&stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateWebhook(webhook *models.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.webhooks[webhook.ID]
+	if !ok {
+		return ErrWebhookNotFound
+	}
+
+	webhook.CreatedAt = current.CreatedAt
+	webhook.UpdatedAt = time.Now()
+	stored := copyWebhook(webhook)
+	m.webhooks[webhook.ID] = &stored
+	*webhook = copyWebhook(&stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteWebhook(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.webhooks[id]; !ok {
+		return ErrWebhookNotFound
+	}
+	delete(m.webhooks, id)
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == id {
+			delete(m.deliveries, delivery.ID)
+		}
+	}
+	return nil
+}
+
+// Deliveries
+
+func (m *memoryStore) RecordWebhookDelivery(delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.webhooks[delivery.WebhookID]; !ok {
+		return fmt.Errorf("failed to record webhook delivery: webhook %d does not exist", delivery.WebhookID)
+	}
+	if delivery.DeviceID != nil {
+		if _, ok := m.devices[*delivery.DeviceID]; !ok {
+			return fmt.Errorf("failed to record webhook delivery: device %d does not exist", *delivery.DeviceID)
+		}
+	}
+
+	delivery.ID = int64(m.nextID())
+	delivery.CreatedAt = time.Now()
+	stored := copyWebhookDelivery(delivery)
+	m.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) ListWebhookDeliveries(webhookID, limit int) ([]models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, copyWebhookDelivery(delivery))
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return deliveries[i].ID > deliveries[j].ID
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// Alert state
+
+func (m *memoryStore) AdvanceAlertState(deviceID int, advance func(state *models.AlertState)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	device, ok := m.devices[deviceID]
+	if !ok {
+		return ErrDeviceNotFound
+	}
+	current, ok := m.alertStates[deviceID]
+	if !ok {
+		// The device's stored status is the baseline the first time it is observed
+		current = &models.AlertState{DeviceID: deviceID, AlertStatus: device.Status, ChangedAt: time.Now()}
+		m.alertStates[deviceID] = current
+	}
+
+	state := *current
+	advance(&state)
+	state.DeviceID = deviceID
+	if state.AlertStatus != current.AlertStatus {
+		state.ChangedAt = time.Now()
+	} else {
+		state.ChangedAt = current.ChangedAt
+	}
+	*current = state
+	return nil
+}
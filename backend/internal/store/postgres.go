@@ -0,0 +1,87 @@
+package store
+
+import (
+	"database/sql"
+)
+
+// NewPostgresStore creates the SQL-backed stores over an open database. The queries are written
+// for Postgres; on SQLite the database package's driver translates them.
+func NewPostgresStore(db *sql.DB) *Store {
+	return &Store{
+		Racks:        &postgresRackStore{db: db},
+		Devices:      &postgresDeviceStore{db: db},
+		Connections:  &postgresConnectionStore{db: db},
+		Interfaces:   &postgresInterfaceStore{db: db},
+		Cables:       &postgresCableStore{db: db},
+		DeviceTypes:  &postgresDeviceTypeStore{db: db},
+		Locations:    &postgresLocationStore{db: db},
+		IPAM:         &postgresIPAMStore{db: db},
+		HealthProbes: &postgresHealthProbeStore{db: db},
+		HealthChecks: &postgresHealthCheckStore{db: db},
+		VLANs:        &postgresVLANStore{db: db},
+		Webhooks:     &postgresWebhookStore{db: db},
+		AlertStates:  &postgresAlertStateStore{db: db},
+	}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// nullIntPtr converts a nullable integer column to *int
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
+
+// intSlice converts the elements of an INTEGER[] column, leaving nil for an empty array
+func intSlice(values []int64) []int {
+	var ints []int
+	for _, v := range values {
+		ints = append(ints, int(v))
+	}
+	return ints
+}
+
+// nullablePositive maps zero to NULL for optional numeric columns
+func nullablePositive(value int) interface{} {
+	if value <= 0 {
+		return nil
+	}
+	return value
+}
+
+// nullableString maps an empty string to NULL for optional text columns
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// deleteRow deletes one row by ID, returning notFound when there is none
+func deleteRow(db *sql.DB, query string, id int, notFound error) error {
+	result, err := db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
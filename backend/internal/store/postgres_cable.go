@@ -0,0 +1,146 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"rackview/internal/database"
+	"rackview/internal/models"
+)
+
+// cableSelect selects cables with the ports at either end of their connection
+const cableSelect = `
+	SELECT c.id, c.label, COALESCE(c.media, ''), c.length_m, COALESCE(c.color, ''), c.status, COALESCE(c.description, ''),
+		nc.id,
+		COALESCE(TRIM(sd.name || ' ' || COALESCE(si.name, '')), ''),
+		COALESCE(TRIM(td.name || ' ' || COALESCE(ti.name, '')), ''),
+		c.created_at, c.updated_at
+	FROM cables c
+	LEFT JOIN network_connections nc ON nc.cable_id = c.id
+	LEFT JOIN devices sd ON sd.id = nc.source_device_id
+	LEFT JOIN device_interfaces si ON si.id = nc.source_interface_id
+	LEFT JOIN devices td ON td.id = nc.target_device_id
+	LEFT JOIN device_interfaces ti ON ti.id = nc.target_interface_id
+`
+
+// scanCable scans a row selected with cableSelect into cable
+func scanCable(row rowScanner, cable *models.Cable) error {
+	var length sql.NullFloat64
+	var connectionID sql.NullInt64
+	if err := row.Scan(
+		&cable.ID, &cable.Label, &cable.Media, &length, &cable.Color, &cable.Status, &cable.Description,
+		&connectionID, &cable.AEnd, &cable.BEnd,
+		&cable.CreatedAt, &cable.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	cable.LengthM = nil
+	if length.Valid {
+		cable.LengthM = &length.Float64
+	}
+	cable.ConnectionID = nullIntPtr(connectionID)
+	return nil
+}
+
+// postgresCableStore is the CableStore over the cables table
+type postgresCableStore struct {
+	db *sql.DB
+}
+
+func (s *postgresCableStore) ListCables(filter models.CableFilter) ([]models.Cable, error) {
+	rows, err := s.db.Query(cableSelect+`
+		WHERE ($1 = '' OR c.label ILIKE '%' || $1 || '%')
+		AND ($2 = '' OR c.media = $2)
+		AND ($3 = '' OR c.status = $3)
+		ORDER BY c.label
+	`, filter.Label, string(filter.Media), string(filter.Status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cables: %w", err)
+	}
+	defer rows.Close()
+
+	var cables []models.Cable
+	for rows.Next() {
+		var cable models.Cable
+		if err := scanCable(rows, &cable); err != nil {
+			return nil, fmt.Errorf("failed to scan cable: %w", err)
+		}
+		cables = append(cables, cable)
+	}
+
+	return cables, rows.Err()
+}
+
+func (s *postgresCableStore) GetCable(id int) (*models.Cable, error) {
+	var cable models.Cable
+	err := scanCable(s.db.QueryRow(cableSelect+"WHERE c.id = $1", id), &cable)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrCableNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cable: %w", err)
+	}
+
+	return &cable, nil
+}
+
+func (s *postgresCableStore) CreateCable(cable *models.Cable) error {
+	return insertCable(s.db, cable)
+}
+
+// insertCable inserts a cable through db, which may be a transaction
+func insertCable(db queryRower, cable *models.Cable) error {
+	err := db.QueryRow(`
+		INSERT INTO cables (label, media, length_m, color, status, description)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`, cable.Label, nullableString(string(cable.Media)), cable.LengthM, nullableString(cable.Color), cable.Status,
+		nullableString(cable.Description)).Scan(&cable.ID, &cable.CreatedAt, &cable.UpdatedAt)
+	if err != nil {
+		if database.IsConstraintViolation(err, "cables_label_key") {
+			return ErrCableLabelInUse
+		}
+		return fmt.Errorf("failed to create cable: %w", err)
+	}
+
+	cable.ConnectionID = nil
+	cable.AEnd, cable.BEnd = "", ""
+	return nil
+}
+
+func (s *postgresCableStore) UpdateCable(cable *models.Cable) error {
+	var id int
+	err := s.db.QueryRow(`
+		UPDATE cables
+		SET label = $1, media = $2, length_m = $3, color = $4, status = $5, description = $6
+		WHERE id = $7
+		RETURNING id
+	`, cable.Label, nullableString(string(cable.Media)), cable.LengthM, nullableString(cable.Color), cable.Status,
+		nullableString(cable.Description), cable.ID).Scan(&id)
+
+	if err == sql.ErrNoRows {
+		return ErrCableNotFound
+	}
+	if err != nil {
+		if database.IsConstraintViolation(err, "cables_label_key") {
+			return ErrCableLabelInUse
+		}
+		return fmt.Errorf("failed to update cable: %w", err)
+	}
+
+	if err := scanCable(s.db.QueryRow(cableSelect+"WHERE c.id = $1", id), cable); err != nil {
+		return fmt.Errorf("failed to query cable: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresCableStore) DeleteCable(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM cables WHERE id = $1", id, ErrCableNotFound); err != nil {
+		if err == ErrCableNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete cable: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"rackview/internal/models"
+)
+
+// connectionColumns is the column list shared by every connection SELECT and RETURNING clause
+const connectionColumns = `id, source_device_id, target_device_id, source_interface_id, target_interface_id, cable_id,
+	COALESCE(vlan_mode, ''), untagged_vlan_id,
+	ARRAY(SELECT cv.vlan_id FROM connection_vlans cv WHERE cv.connection_id = network_connections.id ORDER BY cv.vlan_id),
+	COALESCE(connection_type, ''), COALESCE(port_info, ''), COALESCE(speed, ''), created_at`
+
+// scanConnection scans a row selected with connectionColumns into conn
+func scanConnection(row rowScanner, conn *models.NetworkConnection) error {
+	var sourceInterfaceID, targetInterfaceID, cableID, untaggedVLANID sql.NullInt64
+	var taggedVLANIDs []int64
+	if err := row.Scan(
+		&conn.ID, &conn.SourceDeviceID, &conn.TargetDeviceID, &sourceInterfaceID, &targetInterfaceID, &cableID,
+		&conn.VLANMode, &untaggedVLANID, pq.Array(&taggedVLANIDs),
+		&conn.ConnectionType, &conn.PortInfo, &conn.Speed, &conn.CreatedAt,
+	); err != nil {
+		return err
+	}
+	conn.SourceInterfaceID = nullIntPtr(sourceInterfaceID)
+	conn.TargetInterfaceID = nullIntPtr(targetInterfaceID)
+	conn.CableID = nullIntPtr(cableID)
+	conn.UntaggedVLANID = nullIntPtr(untaggedVLANID)
+	conn.TaggedVLANIDs = intSlice(taggedVLANIDs)
+	return nil
+}
+
+// postgresConnectionStore is the ConnectionStore over the network_connections table
+type postgresConnectionStore struct {
+	db *sql.DB
+}
+
+func (s *postgresConnectionStore) ListConnections(filter ConnectionFilter) ([]models.NetworkConnection, error) {
+	rows, err := s.db.Query(`
+		SELECT `+connectionColumns+`
+		FROM network_connections
+		WHERE ($1::INTEGER IS NULL OR source_device_id = $1 OR target_device_id = $1)
+		ORDER BY id
+	`, filter.DeviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connections: %w", err)
+	}
+	defer rows.Close()
+
+	var connections []models.NetworkConnection
+	for rows.Next() {
+		var conn models.NetworkConnection
+		if err := scanConnection(rows, &conn); err != nil {
+			return nil, fmt.Errorf("failed to scan connection: %w", err)
+		}
+		connections = append(connections, conn)
+	}
+
+	return connections, rows.Err()
+}
+
+func (s *postgresConnectionStore) GetConnection(id int) (*models.NetworkConnection, error) {
+	var conn models.NetworkConnection
+	err := scanConnection(s.db.QueryRow(`
+		SELECT `+connectionColumns+`
+		FROM network_connections
+		WHERE id = $1
+	`, id), &conn)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrConnectionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection: %w", err)
+	}
+
+	return &conn, nil
+}
+
+func (s *postgresConnectionStore) CreateConnection(conn *models.NetworkConnection, cable *models.Cable) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if cable != nil {
+		if err := insertCable(tx, cable); err != nil {
+			return err
+		}
+		conn.CableID = &cable.ID
+	}
+
+	err = scanConnection(tx.QueryRow(`
+		INSERT INTO network_connections (source_device_id, target_device_id, source_interface_id, target_interface_id, cable_id, connection_type, port_info, speed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+connectionColumns+`
+	`, conn.SourceDeviceID, conn.TargetDeviceID, conn.SourceInterfaceID, conn.TargetInterfaceID, conn.CableID,
+		conn.ConnectionType, conn.PortInfo, conn.Speed), conn)
+	if err != nil {
+		return fmt.Errorf("failed to create connection: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit connection: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresConnectionStore) UpdateConnection(conn *models.NetworkConnection) error {
+	err := scanConnection(s.db.QueryRow(`
+		UPDATE network_connections
+		SET connection_type = $1, port_info = $2, speed = $3, source_interface_id = $4, target_interface_id = $5, cable_id = $6
+		WHERE id = $7
+		RETURNING `+connectionColumns+`
+	`, conn.ConnectionType, conn.PortInfo, conn.Speed, conn.SourceInterfaceID, conn.TargetInterfaceID, conn.CableID, conn.ID), conn)
+
+	if err == sql.ErrNoRows {
+		return ErrConnectionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update connection: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresConnectionStore) DeleteConnection(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM network_connections WHERE id = $1", id, ErrConnectionNotFound); err != nil {
+		if err == ErrConnectionNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,375 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"rackview/internal/database"
+	"rackview/internal/models"
+)
+
+// deviceOverlapConstraint keeps devices in a rack from sharing a U on a common face
+const deviceOverlapConstraint = "devices_no_overlap"
+
+// deviceIPAddressIndex keeps a non-empty ip_address to one device
+const deviceIPAddressIndex = "idx_devices_ip_address"
+
+// deviceColumns is the column list shared by every device SELECT and RETURNING clause
+const deviceColumns = "id, rack_id, name, icon, type, position_u, size_u, mount_face, depth, zero_u_side, zero_u_slot, parent_device_id, bay, bay_count, status, model, device_type_id, ip_address, health_check_url, health_check_interval, health_check_mode, nameplate_watts, measured_watts, psu_count, psu_redundancy, created_at, updated_at"
+
+// scanDevice scans a row selected with deviceColumns into device
+func scanDevice(row rowScanner, device *models.Device) error {
+	var ipAddress, healthCheckURL, zeroUSide, zeroUSlot, model sql.NullString
+	var parentDeviceID, bay, bayCount, deviceTypeID sql.NullInt64
+	var healthCheckInterval, nameplateWatts, measuredWatts, psuCount sql.NullInt64
+	if err := row.Scan(
+		&device.ID, &device.RackID, &device.Name, &device.Icon, &device.Type,
+		&device.PositionU, &device.SizeU, &device.MountFace, &device.Depth, &zeroUSide, &zeroUSlot,
+		&parentDeviceID, &bay, &bayCount, &device.Status, &model, &deviceTypeID,
+		&ipAddress, &healthCheckURL, &healthCheckInterval, &device.HealthCheckMode,
+		&nameplateWatts, &measuredWatts, &psuCount, &device.PSURedundancy,
+		&device.CreatedAt, &device.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	// Convert nullable columns to zero values
+	device.Model = model.String
+	device.IPAddress = ipAddress.String
+	device.HealthCheckURL = healthCheckURL.String
+	device.HealthCheckInterval = int(healthCheckInterval.Int64)
+	device.NameplateWatts = int(nameplateWatts.Int64)
+	device.MeasuredWatts = int(measuredWatts.Int64)
+	device.PSUCount = int(psuCount.Int64)
+	device.ZeroUSide = models.ZeroUSide(zeroUSide.String)
+	device.ZeroUSlot = zeroUSlot.String
+	device.ParentDeviceID = nullIntPtr(parentDeviceID)
+	device.Bay = int(bay.Int64)
+	device.BayCount = int(bayCount.Int64)
+	device.DeviceTypeID = nullIntPtr(deviceTypeID)
+	device.Faces = DeviceFaces(device)
+	return nil
+}
+
+// DeviceFaces returns the rack faces a device occupies; zero-U and chassis bay devices occupy none
+func DeviceFaces(device *models.Device) []models.MountFace {
+	switch {
+	case device.ZeroUSide != "" || device.ParentDeviceID != nil:
+		return []models.MountFace{}
+	case device.Depth == models.DeviceDepthHalf:
+		return []models.MountFace{device.MountFace}
+	}
+	return []models.MountFace{models.MountFaceFront, models.MountFaceRear}
+}
+
+// postgresDeviceStore is the DeviceStore over the devices and device_specs tables
+type postgresDeviceStore struct {
+	db *sql.DB
+}
+
+func (s *postgresDeviceStore) ListDevices(filter DeviceFilter) ([]models.Device, error) {
+	orderBy := "rack_id, position_u DESC, id"
+	if filter.ParentDeviceID != nil {
+		orderBy = "bay, id"
+	}
+	rows, err := s.db.Query(`
+		SELECT `+deviceColumns+`
+		FROM devices
+		WHERE ($1::INTEGER IS NULL OR rack_id = $1)
+		AND ($2::INTEGER IS NULL OR parent_device_id = $2)
+		AND ($3 = '' OR ip_address = $3)
+		ORDER BY `+orderBy, filter.RackID, filter.ParentDeviceID, filter.IPAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query devices: %w", err)
+	}
+
+	var devices []models.Device
+	for rows.Next() {
+		var device models.Device
+		if err := scanDevice(rows, &device); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query devices: %w", err)
+	}
+
+	if err := s.loadSpecs(devices); err != nil {
+		return nil, fmt.Errorf("failed to load specs: %w", err)
+	}
+	return devices, nil
+}
+
+// loadSpecs fills in the specs of devices with one query
+func (s *postgresDeviceStore) loadSpecs(devices []models.Device) error {
+	if len(devices) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(devices))
+	byID := make(map[int]*models.Device, len(devices))
+	for i := range devices {
+		ids[i] = int64(devices[i].ID)
+		devices[i].Specs = map[string]string{}
+		byID[devices[i].ID] = &devices[i]
+	}
+
+	rows, err := s.db.Query(`
+		SELECT device_id, spec_key, COALESCE(spec_value, '')
+		FROM device_specs
+		WHERE device_id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deviceID int
+		var key, value string
+		if err := rows.Scan(&deviceID, &key, &value); err != nil {
+			return err
+		}
+		byID[deviceID].Specs[key] = value
+	}
+
+	return rows.Err()
+}
+
+func (s *postgresDeviceStore) GetDevice(id int) (*models.Device, error) {
+	var device models.Device
+	err := scanDevice(s.db.QueryRow(`
+		SELECT `+deviceColumns+`
+		FROM devices
+		WHERE id = $1
+	`, id), &device)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrDeviceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query device: %w", err)
+	}
+
+	devices := []models.Device{device}
+	if err := s.loadSpecs(devices); err != nil {
+		return nil, fmt.Errorf("failed to load specs: %w", err)
+	}
+	return &devices[0], nil
+}
+
+// deviceValues returns the stored fields of a device in the column order of INSERT and UPDATE
+func deviceValues(device *models.Device) []interface{} {
+	return []interface{}{
+		device.RackID, device.Name, device.Icon, device.Type, device.PositionU, device.SizeU, device.MountFace, device.Depth,
+		nullableString(string(device.ZeroUSide)), nullableString(device.ZeroUSlot), device.ParentDeviceID,
+		nullablePositive(device.Bay), nullablePositive(device.BayCount), device.Status, device.Model, device.DeviceTypeID,
+		device.IPAddress, device.HealthCheckURL, nullablePositive(device.HealthCheckInterval), device.HealthCheckMode,
+		nullablePositive(device.NameplateWatts), nullablePositive(device.MeasuredWatts), nullablePositive(device.PSUCount), device.PSURedundancy,
+	}
+}
+
+// lockRack locks a rack row for the rest of the transaction, so device writes to the rack run one
+// at a time, and runs the placement check against the other devices in the rack. On SQLite the
+// transaction already holds the database write lock.
+func lockRack(tx *sql.Tx, rackID, deviceID int, check PlacementCheck) error {
+	var id int
+	err := tx.QueryRow("SELECT id FROM racks WHERE id = $1 FOR NO KEY UPDATE", rackID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrRackNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock rack: %w", err)
+	}
+	if check == nil {
+		return nil
+	}
+
+	rows, err := tx.Query(`
+		SELECT `+deviceColumns+`
+		FROM devices
+		WHERE rack_id = $1 AND id != $2
+		ORDER BY position_u DESC, id
+	`, rackID, deviceID)
+	if err != nil {
+		return fmt.Errorf("failed to query rack devices: %w", err)
+	}
+	var devices []models.Device
+	for rows.Next() {
+		var device models.Device
+		if err := scanDevice(rows, &device); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query rack devices: %w", err)
+	}
+
+	return check(devices)
+}
+
+func (s *postgresDeviceStore) CreateDevice(device *models.Device, interfaces []*models.DeviceInterface, check PlacementCheck) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockRack(tx, device.RackID, 0, check); err != nil {
+		return err
+	}
+
+	specs := device.Specs
+	err = scanDevice(tx.QueryRow(`
+		INSERT INTO devices (rack_id, name, icon, type, position_u, size_u, mount_face, depth, zero_u_side, zero_u_slot, parent_device_id, bay, bay_count,
+			status, model, device_type_id, ip_address, health_check_url, health_check_interval, health_check_mode, nameplate_watts, measured_watts, psu_count, psu_redundancy)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		RETURNING `+deviceColumns+`
+	`, deviceValues(device)...), device)
+	if database.IsConstraintViolation(err, deviceOverlapConstraint) {
+		return ErrDeviceOverlap
+	}
+	if database.IsConstraintViolation(err, deviceIPAddressIndex) {
+		return ErrIPAddressInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create device: %w", err)
+	}
+
+	if err := setSpecs(tx, device.ID, specs); err != nil {
+		return fmt.Errorf("failed to set specs: %w", err)
+	}
+	for _, iface := range interfaces {
+		iface.DeviceID = device.ID
+		if err := insertInterface(tx, iface); err != nil {
+			return fmt.Errorf("failed to create interface %s: %w", iface.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit device: %w", err)
+	}
+
+	device.Specs = specs
+	if device.Specs == nil {
+		device.Specs = map[string]string{}
+	}
+	return nil
+}
+
+func (s *postgresDeviceStore) UpdateDevice(device *models.Device, check PlacementCheck) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockRack(tx, device.RackID, device.ID, check); err != nil {
+		return err
+	}
+
+	specs := device.Specs
+	err = scanDevice(tx.QueryRow(`
+		UPDATE devices
+		SET rack_id = $1, name = $2, icon = $3, type = $4, position_u = $5, size_u = $6, mount_face = $7, depth = $8,
+			zero_u_side = $9, zero_u_slot = $10, parent_device_id = $11, bay = $12, bay_count = $13,
+			status = $14, model = $15, device_type_id = $16, ip_address = $17, health_check_url = $18,
+			health_check_interval = $19, health_check_mode = $20, nameplate_watts = $21, measured_watts = $22,
+			psu_count = $23, psu_redundancy = $24
+		WHERE id = $25
+		RETURNING `+deviceColumns+`
+	`, append(deviceValues(device), device.ID)...), device)
+
+	if err == sql.ErrNoRows {
+		return ErrDeviceNotFound
+	}
+	if database.IsConstraintViolation(err, deviceOverlapConstraint) {
+		return ErrDeviceOverlap
+	}
+	if database.IsConstraintViolation(err, deviceIPAddressIndex) {
+		return ErrIPAddressInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update device: %w", err)
+	}
+
+	// Devices installed in a chassis, directly or nested, follow it to its rack
+	_, err = tx.Exec(`
+		WITH RECURSIVE installed AS (
+			SELECT id FROM devices WHERE parent_device_id = $1
+			UNION ALL
+			SELECT d.id FROM devices d JOIN installed ON d.parent_device_id = installed.id
+		)
+		UPDATE devices SET rack_id = $2
+		WHERE id IN (SELECT id FROM installed) AND rack_id != $2
+	`, device.ID, device.RackID)
+	if err != nil {
+		return fmt.Errorf("failed to move installed devices: %w", err)
+	}
+
+	if specs != nil {
+		if _, err := tx.Exec("DELETE FROM device_specs WHERE device_id = $1", device.ID); err != nil {
+			return fmt.Errorf("failed to update specs: %w", err)
+		}
+		if err := setSpecs(tx, device.ID, specs); err != nil {
+			return fmt.Errorf("failed to update specs: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit device: %w", err)
+	}
+
+	if specs == nil {
+		devices := []models.Device{*device}
+		if err := s.loadSpecs(devices); err != nil {
+			return fmt.Errorf("failed to reload specs: %w", err)
+		}
+		specs = devices[0].Specs
+	}
+	device.Specs = specs
+	return nil
+}
+
+// setSpecs inserts the specs of a device
+func setSpecs(tx *sql.Tx, deviceID int, specs map[string]string) error {
+	for key, value := range specs {
+		_, err := tx.Exec(`
+			INSERT INTO device_specs (device_id, spec_key, spec_value)
+			VALUES ($1, $2, $3)
+		`, deviceID, key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *postgresDeviceStore) UpdateDeviceStatus(id int, status models.DeviceStatus) error {
+	result, err := s.db.Exec("UPDATE devices SET status = $1 WHERE id = $2", status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update device status: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+func (s *postgresDeviceStore) DeleteDevice(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM devices WHERE id = $1", id, ErrDeviceNotFound); err != nil {
+		if err == ErrDeviceNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"rackview/internal/models"
+)
+
+// deviceTypeColumns is the column list shared by every device type SELECT and RETURNING clause
+const deviceTypeColumns = "id, manufacturer, model, type, size_u, COALESCE(icon, ''), specs, nameplate_watts, psu_count, psu_redundancy, bay_count, ports, created_at, updated_at"
+
+// scanDeviceType scans a row selected with deviceTypeColumns into deviceType
+func scanDeviceType(row rowScanner, deviceType *models.DeviceTypeTemplate) error {
+	var specs, ports []byte
+	var nameplateWatts, psuCount, bayCount sql.NullInt64
+	if err := row.Scan(
+		&deviceType.ID, &deviceType.Manufacturer, &deviceType.Model, &deviceType.Type, &deviceType.SizeU,
+		&deviceType.Icon, &specs, &nameplateWatts, &psuCount, &deviceType.PSURedundancy, &bayCount,
+		&ports, &deviceType.CreatedAt, &deviceType.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	deviceType.NameplateWatts = int(nameplateWatts.Int64)
+	deviceType.PSUCount = int(psuCount.Int64)
+	deviceType.BayCount = int(bayCount.Int64)
+	deviceType.Specs, deviceType.Ports = nil, nil
+	if err := json.Unmarshal(specs, &deviceType.Specs); err != nil {
+		return fmt.Errorf("failed to decode device type specs: %w", err)
+	}
+	if err := json.Unmarshal(ports, &deviceType.Ports); err != nil {
+		return fmt.Errorf("failed to decode device type ports: %w", err)
+	}
+	return nil
+}
+
+// encodeDeviceTypeTemplates encodes specs and ports for their JSONB columns
+func encodeDeviceTypeTemplates(specs map[string]string, ports []models.PortTemplate) ([]byte, []byte, error) {
+	if specs == nil {
+		specs = map[string]string{}
+	}
+	if ports == nil {
+		ports = []models.PortTemplate{}
+	}
+
+	encodedSpecs, err := json.Marshal(specs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode specs: %w", err)
+	}
+	encodedPorts, err := json.Marshal(ports)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode ports: %w", err)
+	}
+
+	return encodedSpecs, encodedPorts, nil
+}
+
+// postgresDeviceTypeStore is the DeviceTypeStore over the device_types table
+type postgresDeviceTypeStore struct {
+	db *sql.DB
+}
+
+func (s *postgresDeviceTypeStore) ListDeviceTypes() ([]models.DeviceTypeTemplate, error) {
+	rows, err := s.db.Query(`
+		SELECT ` + deviceTypeColumns + `
+		FROM device_types
+		ORDER BY manufacturer, model
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query device types: %w", err)
+	}
+	defer rows.Close()
+
+	var deviceTypes []models.DeviceTypeTemplate
+	for rows.Next() {
+		var deviceType models.DeviceTypeTemplate
+		if err := scanDeviceType(rows, &deviceType); err != nil {
+			return nil, fmt.Errorf("failed to scan device type: %w", err)
+		}
+		deviceTypes = append(deviceTypes, deviceType)
+	}
+
+	return deviceTypes, rows.Err()
+}
+
+func (s *postgresDeviceTypeStore) GetDeviceType(id int) (*models.DeviceTypeTemplate, error) {
+	var deviceType models.DeviceTypeTemplate
+	err := scanDeviceType(s.db.QueryRow(`
+		SELECT `+deviceTypeColumns+`
+		FROM device_types
+		WHERE id = $1
+	`, id), &deviceType)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrDeviceTypeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query device type: %w", err)
+	}
+
+	return &deviceType, nil
+}
+
+func (s *postgresDeviceTypeStore) CreateDeviceType(deviceType *models.DeviceTypeTemplate) error {
+	specs, ports, err := encodeDeviceTypeTemplates(deviceType.Specs, deviceType.Ports)
+	if err != nil {
+		return err
+	}
+
+	err = scanDeviceType(s.db.QueryRow(`
+		INSERT INTO device_types (manufacturer, model, type, size_u, icon, specs, nameplate_watts, psu_count, psu_redundancy, bay_count, ports)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING `+deviceTypeColumns+`
+	`, deviceType.Manufacturer, deviceType.Model, deviceType.Type, deviceType.SizeU, nullableString(deviceType.Icon), specs,
+		nullablePositive(deviceType.NameplateWatts), nullablePositive(deviceType.PSUCount), deviceType.PSURedundancy,
+		nullablePositive(deviceType.BayCount), ports), deviceType)
+	if err != nil {
+		return fmt.Errorf("failed to create device type: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresDeviceTypeStore) UpdateDeviceType(deviceType *models.DeviceTypeTemplate) error {
+	specs, ports, err := encodeDeviceTypeTemplates(deviceType.Specs, deviceType.Ports)
+	if err != nil {
+		return err
+	}
+
+	err = scanDeviceType(s.db.QueryRow(`
+		UPDATE device_types
+		SET manufacturer = $1, model = $2, type = $3, size_u = $4, icon = $5, specs = $6,
+			nameplate_watts = $7, psu_count = $8, psu_redundancy = $9, bay_count = $10, ports = $11
+		WHERE id = $12
+		RETURNING `+deviceTypeColumns+`
+	`, deviceType.Manufacturer, deviceType.Model, deviceType.Type, deviceType.SizeU, nullableString(deviceType.Icon), specs,
+		nullablePositive(deviceType.NameplateWatts), nullablePositive(deviceType.PSUCount), deviceType.PSURedundancy,
+		nullablePositive(deviceType.BayCount), ports, deviceType.ID), deviceType)
+
+	if err == sql.ErrNoRows {
+		return ErrDeviceTypeNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update device type: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresDeviceTypeStore) DeleteDeviceType(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM device_types WHERE id = $1", id, ErrDeviceTypeNotFound); err != nil {
+		if err == ErrDeviceTypeNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete device type: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"rackview/internal/models"
+)
+
+// healthProbeColumns is the column list shared by every probe SELECT and RETURNING clause
+const healthProbeColumns = "id, device_id, COALESCE(name, ''), type, config, enabled, created_at, updated_at"
+
+// scanHealthProbe scans a row selected with healthProbeColumns into probe
+func scanHealthProbe(row rowScanner, probe *models.HealthProbe) error {
+	var config []byte
+	if err := row.Scan(
+		&probe.ID, &probe.DeviceID, &probe.Name, &probe.Type, &config,
+		&probe.Enabled, &probe.CreatedAt, &probe.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	probe.Config = models.HealthProbeConfig{}
+	if err := json.Unmarshal(config, &probe.Config); err != nil {
+		return fmt.Errorf("failed to decode probe config: %w", err)
+	}
+	return nil
+}
+
+// postgresHealthProbeStore is the HealthProbeStore over the device_health_probes table
+type postgresHealthProbeStore struct {
+	db *sql.DB
+}
+
+func (s *postgresHealthProbeStore) ListHealthProbes(filter HealthProbeFilter) ([]models.HealthProbe, error) {
+	rows, err := s.db.Query(`
+		SELECT `+healthProbeColumns+`
+		FROM device_health_probes
+		WHERE ($1::INTEGER IS NULL OR device_id = $1)
+		AND (enabled OR NOT $2)
+		ORDER BY id
+	`, filter.DeviceID, filter.EnabledOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query health probes: %w", err)
+	}
+	defer rows.Close()
+
+	var probes []models.HealthProbe
+	for rows.Next() {
+		var probe models.HealthProbe
+		if err := scanHealthProbe(rows, &probe); err != nil {
+			return nil, fmt.Errorf("failed to scan health probe: %w", err)
+		}
+		probes = append(probes, probe)
+	}
+
+	return probes, rows.Err()
+}
+
+func (s *postgresHealthProbeStore) GetHealthProbe(id int) (*models.HealthProbe, error) {
+	var probe models.HealthProbe
+	err := scanHealthProbe(s.db.QueryRow(`
+		SELECT `+healthProbeColumns+`
+		FROM device_health_probes
+		WHERE id = $1
+	`, id), &probe)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrHealthProbeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query health probe: %w", err)
+	}
+
+	return &probe, nil
+}
+
+func (s *postgresHealthProbeStore) CreateHealthProbe(probe *models.HealthProbe) error {
+	config, err := json.Marshal(probe.Config)
+	if err != nil {
+		return fmt.Errorf("failed to encode probe config: %w", err)
+	}
+
+	err = scanHealthProbe(s.db.QueryRow(`
+		INSERT INTO device_health_probes (device_id, name, type, config, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+healthProbeColumns+`
+	`, probe.DeviceID, probe.Name, probe.Type, config, probe.Enabled), probe)
+	if err != nil {
+		return fmt.Errorf("failed to create health probe: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresHealthProbeStore) UpdateHealthProbe(probe *models.HealthProbe) error {
+	config, err := json.Marshal(probe.Config)
+	if err != nil {
+		return fmt.Errorf("failed to encode probe config: %w", err)
+	}
+
+	err = scanHealthProbe(s.db.QueryRow(`
+		UPDATE device_health_probes
+		SET name = $1, config = $2, enabled = $3
+		WHERE id = $4
+		RETURNING `+healthProbeColumns+`
+	`, probe.Name, config, probe.Enabled, probe.ID), probe)
+
+	if err == sql.ErrNoRows {
+		return ErrHealthProbeNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update health probe: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresHealthProbeStore) DeleteHealthProbe(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM device_health_probes WHERE id = $1", id, ErrHealthProbeNotFound); err != nil {
+		if err == ErrHealthProbeNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete health probe: %w", err)
+	}
+	return nil
+}
+
+// postgresHealthCheckStore is the HealthCheckStore over the health_checks table
+type postgresHealthCheckStore struct {
+	db *sql.DB
+}
+
+func (s *postgresHealthCheckStore) RecordHealthCheck(check *models.HealthCheck) error {
+	err := s.db.QueryRow(`
+		INSERT INTO health_checks (device_id, status, latency_ms, message, checked_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, check.DeviceID, check.Status, check.Latency, check.Message, check.CheckedAt.UTC()).Scan(&check.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record health check: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresHealthCheckStore) ListHealthChecks(deviceID int, from, to time.Time, limit int) ([]models.HealthCheck, error) {
+	rows, err := s.db.Query(`
+		SELECT id, device_id, status, latency_ms, COALESCE(message, ''), checked_at
+		FROM health_checks
+		WHERE device_id = $1 AND checked_at >= $2 AND checked_at < $3
+		ORDER BY checked_at
+		LIMIT $4
+	`, deviceID, from.UTC(), to.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query health history: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []models.HealthCheck
+	for rows.Next() {
+		var check models.HealthCheck
+		var latency sql.NullInt64
+		if err := rows.Scan(&check.ID, &check.DeviceID, &check.Status, &latency, &check.Message, &check.CheckedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan health check: %w", err)
+		}
+		if latency.Valid {
+			check.Latency = &latency.Int64
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, rows.Err()
+}
+
+func (s *postgresHealthCheckStore) SummarizeHealthChecks(deviceID int, from, to time.Time, bucketSeconds int) ([]models.HealthHistoryBucket, error) {
+	rows, err := s.db.Query(`
+		SELECT
+			FLOOR(EXTRACT(EPOCH FROM checked_at) / $4)::BIGINT AS bucket,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'online'),
+			COUNT(*) FILTER (WHERE status = 'offline'),
+			COUNT(*) FILTER (WHERE status = 'warning'),
+			COUNT(*) FILTER (WHERE status = 'unknown'),
+			AVG(latency_ms)::DOUBLE PRECISION,
+			MIN(latency_ms),
+			MAX(latency_ms)
+		FROM health_checks
+		WHERE device_id = $1 AND checked_at >= $2 AND checked_at < $3
+		GROUP BY bucket
+		ORDER BY bucket
+	`, deviceID, from.UTC(), to.UTC(), bucketSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query health history: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.HealthHistoryBucket
+	for rows.Next() {
+		var bucket models.HealthHistoryBucket
+		var index int64
+		var avgLatency sql.NullFloat64
+		var minLatency, maxLatency sql.NullInt64
+		if err := rows.Scan(
+			&index, &bucket.Checks, &bucket.Online, &bucket.Offline, &bucket.Warning, &bucket.Unknown,
+			&avgLatency, &minLatency, &maxLatency,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan health history bucket: %w", err)
+		}
+
+		bucket.Start = time.Unix(index*int64(bucketSeconds), 0).UTC()
+		if avgLatency.Valid {
+			bucket.AvgLatency = &avgLatency.Float64
+		}
+		if minLatency.Valid {
+			bucket.MinLatency = &minLatency.Int64
+		}
+		if maxLatency.Valid {
+			bucket.MaxLatency = &maxLatency.Int64
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
+func (s *postgresHealthCheckStore) PruneHealthChecks(before time.Time) (int64, error) {
+	result, err := s.db.Exec("DELETE FROM health_checks WHERE checked_at < $1", before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune health history: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (s *postgresHealthCheckStore) LatestHealthChecks() ([]models.HealthCheck, error) {
+	rows, err := s.db.Query(`
+		SELECT hc.id, hc.device_id, hc.status, hc.latency_ms, COALESCE(hc.message, ''), hc.checked_at
+		FROM devices d
+		JOIN health_checks hc ON hc.id = (
+			SELECT id
+			FROM health_checks
+			WHERE device_id = d.id
+			ORDER BY checked_at DESC, id DESC
+			LIMIT 1
+		)
+		ORDER BY d.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest health checks: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []models.HealthCheck
+	for rows.Next() {
+		var check models.HealthCheck
+		var latency sql.NullInt64
+		if err := rows.Scan(&check.ID, &check.DeviceID, &check.Status, &latency, &check.Message, &check.CheckedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan health check: %w", err)
+		}
+		if latency.Valid {
+			check.Latency = &latency.Int64
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, rows.Err()
+}
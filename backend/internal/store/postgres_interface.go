@@ -0,0 +1,135 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"rackview/internal/models"
+)
+
+// interfaceColumns is the column list shared by every interface SELECT and RETURNING clause.
+// The front port of a rear port and the connection ID are looked up.
+const interfaceColumns = `id, device_id, name, COALESCE(type, ''), COALESCE(speed, ''), COALESCE(mac_address, ''),
+	enabled, COALESCE(description, ''), rear_port_id,
+	COALESCE(vlan_mode, ''), untagged_vlan_id,
+	ARRAY(SELECT iv.vlan_id FROM interface_vlans iv WHERE iv.interface_id = device_interfaces.id ORDER BY iv.vlan_id),
+	(SELECT fp.id FROM device_interfaces fp WHERE fp.rear_port_id = device_interfaces.id),
+	(SELECT nc.id FROM network_connections nc
+	 WHERE nc.source_interface_id = device_interfaces.id OR nc.target_interface_id = device_interfaces.id
+	 LIMIT 1),
+	created_at, updated_at`
+
+// scanInterface scans a row selected with interfaceColumns into iface
+func scanInterface(row rowScanner, iface *models.DeviceInterface) error {
+	var rearPortID, frontPortID, connectionID, untaggedVLANID sql.NullInt64
+	var taggedVLANIDs []int64
+	if err := row.Scan(
+		&iface.ID, &iface.DeviceID, &iface.Name, &iface.Type, &iface.Speed, &iface.MACAddress,
+		&iface.Enabled, &iface.Description, &rearPortID,
+		&iface.VLANMode, &untaggedVLANID, pq.Array(&taggedVLANIDs),
+		&frontPortID, &connectionID,
+		&iface.CreatedAt, &iface.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	iface.RearPortID = nullIntPtr(rearPortID)
+	iface.FrontPortID = nullIntPtr(frontPortID)
+	iface.ConnectionID = nullIntPtr(connectionID)
+	iface.UntaggedVLANID = nullIntPtr(untaggedVLANID)
+	iface.TaggedVLANIDs = intSlice(taggedVLANIDs)
+	return nil
+}
+
+// postgresInterfaceStore is the InterfaceStore over the device_interfaces table
+type postgresInterfaceStore struct {
+	db *sql.DB
+}
+
+func (s *postgresInterfaceStore) ListInterfaces(filter InterfaceFilter) ([]models.DeviceInterface, error) {
+	rows, err := s.db.Query(`
+		SELECT `+interfaceColumns+`
+		FROM device_interfaces
+		WHERE ($1::INTEGER IS NULL OR device_id = $1)
+		AND ($2::INTEGER IS NULL OR device_id IN (SELECT id FROM devices WHERE rack_id = $2))
+		ORDER BY device_id, name, id
+	`, filter.DeviceID, filter.RackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query interfaces: %w", err)
+	}
+	defer rows.Close()
+
+	var interfaces []models.DeviceInterface
+	for rows.Next() {
+		var iface models.DeviceInterface
+		if err := scanInterface(rows, &iface); err != nil {
+			return nil, fmt.Errorf("failed to scan interface: %w", err)
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces, rows.Err()
+}
+
+func (s *postgresInterfaceStore) GetInterface(id int) (*models.DeviceInterface, error) {
+	var iface models.DeviceInterface
+	err := scanInterface(s.db.QueryRow(`
+		SELECT `+interfaceColumns+`
+		FROM device_interfaces
+		WHERE id = $1
+	`, id), &iface)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrInterfaceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query interface: %w", err)
+	}
+
+	return &iface, nil
+}
+
+func (s *postgresInterfaceStore) CreateInterface(iface *models.DeviceInterface) error {
+	if err := insertInterface(s.db, iface); err != nil {
+		return fmt.Errorf("failed to create interface: %w", err)
+	}
+	return nil
+}
+
+// insertInterface inserts an interface through db, which may be a transaction
+func insertInterface(db queryRower, iface *models.DeviceInterface) error {
+	return scanInterface(db.QueryRow(`
+		INSERT INTO device_interfaces (device_id, name, type, speed, mac_address, enabled, description, rear_port_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+interfaceColumns+`
+	`, iface.DeviceID, iface.Name, nullableString(iface.Type), nullableString(iface.Speed), nullableString(iface.MACAddress),
+		iface.Enabled, nullableString(iface.Description), iface.RearPortID), iface)
+}
+
+func (s *postgresInterfaceStore) UpdateInterface(iface *models.DeviceInterface) error {
+	err := scanInterface(s.db.QueryRow(`
+		UPDATE device_interfaces
+		SET name = $1, type = $2, speed = $3, mac_address = $4, enabled = $5, description = $6, rear_port_id = $7
+		WHERE id = $8
+		RETURNING `+interfaceColumns+`
+	`, iface.Name, nullableString(iface.Type), nullableString(iface.Speed), nullableString(iface.MACAddress),
+		iface.Enabled, nullableString(iface.Description), iface.RearPortID, iface.ID), iface)
+
+	if err == sql.ErrNoRows {
+		return ErrInterfaceNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update interface: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresInterfaceStore) DeleteInterface(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM device_interfaces WHERE id = $1", id, ErrInterfaceNotFound); err != nil {
+		if err == ErrInterfaceNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete interface: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,383 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"rackview/internal/database"
+	"rackview/internal/models"
+)
+
+// ipPrefixColumns is the column list shared by every prefix SELECT and RETURNING clause
+const ipPrefixColumns = "id, prefix::TEXT, COALESCE(description, ''), created_at, updated_at"
+
+// scanIPPrefix scans a row selected with ipPrefixColumns into prefix
+func scanIPPrefix(row rowScanner, prefix *models.IPPrefix) error {
+	return row.Scan(&prefix.ID, &prefix.Prefix, &prefix.Description, &prefix.CreatedAt, &prefix.UpdatedAt)
+}
+
+// ipAddressColumns is the column list shared by every address SELECT and RETURNING clause
+const ipAddressColumns = "id, host(address), device_id, interface_id, prefix_id, COALESCE(description, ''), created_at, updated_at"
+
+// scanIPAddress scans a row selected with ipAddressColumns into address
+func scanIPAddress(row rowScanner, address *models.IPAddress) error {
+	var interfaceID, prefixID sql.NullInt64
+	if err := row.Scan(
+		&address.ID, &address.Address, &address.DeviceID, &interfaceID, &prefixID,
+		&address.Description, &address.CreatedAt, &address.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	address.InterfaceID = nullIntPtr(interfaceID)
+	address.PrefixID = nullIntPtr(prefixID)
+	return nil
+}
+
+// sortPrefixes orders prefixes numerically, whether or not the database has a CIDR type
+func sortPrefixes(prefixes []models.IPPrefix) {
+	sort.Slice(prefixes, func(i, j int) bool {
+		a, _ := netip.ParsePrefix(prefixes[i].Prefix)
+		b, _ := netip.ParsePrefix(prefixes[j].Prefix)
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c < 0
+		}
+		return a.Bits() < b.Bits()
+	})
+}
+
+// sortIPAddresses orders addresses numerically, whether or not the database has an INET type
+func sortIPAddresses(addresses []models.IPAddress) {
+	sort.Slice(addresses, func(i, j int) bool {
+		a, _ := netip.ParseAddr(addresses[i].Address)
+		b, _ := netip.ParseAddr(addresses[j].Address)
+		return a.Less(b)
+	})
+}
+
+// prefixRecord is a stored prefix with its ID
+type prefixRecord struct {
+	id     int
+	prefix netip.Prefix
+}
+
+// mostSpecificPrefix returns the ID of the longest prefix containing address, or nil if none
+// does or the address does not parse
+func mostSpecificPrefix(prefixes []prefixRecord, address string) *int {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return nil
+	}
+	var best *prefixRecord
+	for i := range prefixes {
+		if prefixes[i].prefix.Contains(addr) && (best == nil || prefixes[i].prefix.Bits() > best.prefix.Bits()) {
+			best = &prefixes[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	id := best.id
+	return &id
+}
+
+// postgresIPAMStore is the IPAMStore over the ip_prefixes and ip_addresses tables
+type postgresIPAMStore struct {
+	db *sql.DB
+}
+
+// loadPrefixes loads every prefix inside a transaction
+func loadPrefixes(tx *sql.Tx) ([]prefixRecord, error) {
+	rows, err := tx.Query("SELECT id, prefix::TEXT FROM ip_prefixes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prefixes: %w", err)
+	}
+	defer rows.Close()
+
+	var prefixes []prefixRecord
+	for rows.Next() {
+		var record prefixRecord
+		var value string
+		if err := rows.Scan(&record.id, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan prefix: %w", err)
+		}
+		if record.prefix, err = netip.ParsePrefix(value); err != nil {
+			return nil, fmt.Errorf("failed to parse prefix %s: %w", value, err)
+		}
+		prefixes = append(prefixes, record)
+	}
+	return prefixes, rows.Err()
+}
+
+// reassignPrefixes points every address at the most specific prefix containing it
+func reassignPrefixes(tx *sql.Tx) error {
+	prefixes, err := loadPrefixes(tx)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query("SELECT id, host(address), prefix_id FROM ip_addresses")
+	if err != nil {
+		return fmt.Errorf("failed to query ip addresses: %w", err)
+	}
+	changed := map[int]*int{}
+	for rows.Next() {
+		var id int
+		var address string
+		var current sql.NullInt64
+		if err := rows.Scan(&id, &address, &current); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan ip address: %w", err)
+		}
+		prefixID := mostSpecificPrefix(prefixes, address)
+		var want sql.NullInt64
+		if prefixID != nil {
+			want = sql.NullInt64{Int64: int64(*prefixID), Valid: true}
+		}
+		if want != current {
+			changed[id] = prefixID
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query ip addresses: %w", err)
+	}
+
+	for id, prefixID := range changed {
+		if _, err := tx.Exec("UPDATE ip_addresses SET prefix_id = $1 WHERE id = $2", prefixID, id); err != nil {
+			return fmt.Errorf("failed to reassign address prefixes: %w", err)
+		}
+	}
+	return nil
+}
+
+// Prefixes
+
+func (s *postgresIPAMStore) ListPrefixes() ([]models.IPPrefix, error) {
+	rows, err := s.db.Query(`
+		SELECT ` + ipPrefixColumns + `
+		FROM ip_prefixes
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prefixes: %w", err)
+	}
+	defer rows.Close()
+
+	var prefixes []models.IPPrefix
+	for rows.Next() {
+		var prefix models.IPPrefix
+		if err := scanIPPrefix(rows, &prefix); err != nil {
+			return nil, fmt.Errorf("failed to scan prefix: %w", err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query prefixes: %w", err)
+	}
+
+	sortPrefixes(prefixes)
+	return prefixes, nil
+}
+
+func (s *postgresIPAMStore) GetPrefix(id int) (*models.IPPrefix, error) {
+	var prefix models.IPPrefix
+	err := scanIPPrefix(s.db.QueryRow(`
+		SELECT `+ipPrefixColumns+`
+		FROM ip_prefixes
+		WHERE id = $1
+	`, id), &prefix)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrPrefixNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prefix: %w", err)
+	}
+
+	return &prefix, nil
+}
+
+func (s *postgresIPAMStore) CreatePrefix(prefix *models.IPPrefix) error {
+	return s.writePrefix(prefix, `
+		INSERT INTO ip_prefixes (prefix, description)
+		VALUES ($1, $2)
+		RETURNING `+ipPrefixColumns,
+		prefix.Prefix, nullableString(prefix.Description))
+}
+
+func (s *postgresIPAMStore) UpdatePrefix(prefix *models.IPPrefix) error {
+	return s.writePrefix(prefix, `
+		UPDATE ip_prefixes
+		SET prefix = $1, description = $2
+		WHERE id = $3
+		RETURNING `+ipPrefixColumns,
+		prefix.Prefix, nullableString(prefix.Description), prefix.ID)
+}
+
+// writePrefix runs a prefix INSERT or UPDATE and reassigns addresses in one transaction
+func (s *postgresIPAMStore) writePrefix(prefix *models.IPPrefix, query string, args ...interface{}) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = scanIPPrefix(tx.QueryRow(query, args...), prefix)
+	if err == sql.ErrNoRows {
+		return ErrPrefixNotFound
+	}
+	if err != nil {
+		if database.IsConstraintViolation(err, "ip_prefixes_prefix_key") {
+			return ErrPrefixExists
+		}
+		return fmt.Errorf("failed to write prefix: %w", err)
+	}
+
+	if err := reassignPrefixes(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit prefix: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresIPAMStore) DeletePrefix(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM ip_prefixes WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete prefix: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete prefix: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPrefixNotFound
+	}
+
+	if err := reassignPrefixes(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit prefix: %w", err)
+	}
+	return nil
+}
+
+// Addresses
+
+func (s *postgresIPAMStore) ListIPAddresses(filter models.IPAddressFilter) ([]models.IPAddress, error) {
+	rows, err := s.db.Query(`
+		SELECT `+ipAddressColumns+`
+		FROM ip_addresses
+		WHERE ($1::INTEGER IS NULL OR device_id = $1)
+		AND ($2::INTEGER IS NULL OR prefix_id = $2)
+		AND ($3 = '' OR host(address) = $3)
+	`, filter.DeviceID, filter.PrefixID, filter.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ip addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []models.IPAddress
+	for rows.Next() {
+		var address models.IPAddress
+		if err := scanIPAddress(rows, &address); err != nil {
+			return nil, fmt.Errorf("failed to scan ip address: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query ip addresses: %w", err)
+	}
+
+	sortIPAddresses(addresses)
+	return addresses, nil
+}
+
+func (s *postgresIPAMStore) GetIPAddress(id int) (*models.IPAddress, error) {
+	var address models.IPAddress
+	err := scanIPAddress(s.db.QueryRow(`
+		SELECT `+ipAddressColumns+`
+		FROM ip_addresses
+		WHERE id = $1
+	`, id), &address)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrIPAddressNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ip address: %w", err)
+	}
+
+	return &address, nil
+}
+
+func (s *postgresIPAMStore) CreateIPAddress(address *models.IPAddress) error {
+	return s.writeIPAddress(address, func(tx *sql.Tx, prefixID *int) error {
+		return scanIPAddress(tx.QueryRow(`
+			INSERT INTO ip_addresses (address, device_id, interface_id, prefix_id, description)
+			VALUES ($1::INET, $2, $3, $4, $5)
+			RETURNING `+ipAddressColumns+`
+		`, address.Address, address.DeviceID, address.InterfaceID, prefixID, nullableString(address.Description)), address)
+	})
+}
+
+func (s *postgresIPAMStore) UpdateIPAddress(address *models.IPAddress) error {
+	return s.writeIPAddress(address, func(tx *sql.Tx, prefixID *int) error {
+		return scanIPAddress(tx.QueryRow(`
+			UPDATE ip_addresses
+			SET address = $1::INET, interface_id = $2, description = $3, prefix_id = $4
+			WHERE id = $5
+			RETURNING `+ipAddressColumns+`
+		`, address.Address, address.InterfaceID, nullableString(address.Description), prefixID, address.ID), address)
+	})
+}
+
+// writeIPAddress runs an address write with the most specific prefix of the address, read in the
+// same transaction
+func (s *postgresIPAMStore) writeIPAddress(address *models.IPAddress, write func(tx *sql.Tx, prefixID *int) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	prefixes, err := loadPrefixes(tx)
+	if err != nil {
+		return err
+	}
+
+	err = write(tx, mostSpecificPrefix(prefixes, address.Address))
+	if err == sql.ErrNoRows {
+		return ErrIPAddressNotFound
+	}
+	if err != nil {
+		if database.IsConstraintViolation(err, "idx_ip_addresses_address") {
+			return ErrIPAddressInUse
+		}
+		return fmt.Errorf("failed to write ip address: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ip address: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresIPAMStore) DeleteIPAddress(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM ip_addresses WHERE id = $1", id, ErrIPAddressNotFound); err != nil {
+		if err == ErrIPAddressNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete ip address: %w", err)
+	}
+	return nil
+}
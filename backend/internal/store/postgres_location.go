@@ -0,0 +1,288 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"rackview/internal/models"
+)
+
+const siteColumns = "id, name, COALESCE(description, ''), COALESCE(address, ''), created_at, updated_at"
+
+func scanSite(row rowScanner, site *models.Site) error {
+	return row.Scan(&site.ID, &site.Name, &site.Description, &site.Address, &site.CreatedAt, &site.UpdatedAt)
+}
+
+const roomColumns = "id, site_id, name, COALESCE(description, ''), created_at, updated_at"
+
+func scanRoom(row rowScanner, room *models.Room) error {
+	return row.Scan(&room.ID, &room.SiteID, &room.Name, &room.Description, &room.CreatedAt, &room.UpdatedAt)
+}
+
+const rowColumns = "id, room_id, name, COALESCE(description, ''), created_at, updated_at"
+
+func scanRow(row rowScanner, r *models.Row) error {
+	return row.Scan(&r.ID, &r.RoomID, &r.Name, &r.Description, &r.CreatedAt, &r.UpdatedAt)
+}
+
+// postgresLocationStore is the LocationStore over the sites, rooms and rack_rows tables
+type postgresLocationStore struct {
+	db *sql.DB
+}
+
+// Sites
+
+func (s *postgresLocationStore) ListSites() ([]models.Site, error) {
+	rows, err := s.db.Query("SELECT " + siteColumns + " FROM sites ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sites: %w", err)
+	}
+	defer rows.Close()
+
+	var sites []models.Site
+	for rows.Next() {
+		var site models.Site
+		if err := scanSite(rows, &site); err != nil {
+			return nil, fmt.Errorf("failed to scan site: %w", err)
+		}
+		sites = append(sites, site)
+	}
+
+	return sites, rows.Err()
+}
+
+func (s *postgresLocationStore) GetSite(id int) (*models.Site, error) {
+	var site models.Site
+	err := scanSite(s.db.QueryRow("SELECT "+siteColumns+" FROM sites WHERE id = $1", id), &site)
+	if err == sql.ErrNoRows {
+		return nil, ErrSiteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query site: %w", err)
+	}
+	return &site, nil
+}
+
+func (s *postgresLocationStore) CreateSite(site *models.Site) error {
+	err := scanSite(s.db.QueryRow(`
+		INSERT INTO sites (name, description, address)
+		VALUES ($1, $2, $3)
+		RETURNING `+siteColumns,
+		site.Name, site.Description, site.Address), site)
+	if err != nil {
+		return fmt.Errorf("failed to create site: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresLocationStore) UpdateSite(site *models.Site) error {
+	err := scanSite(s.db.QueryRow(`
+		UPDATE sites
+		SET name = $1, description = $2, address = $3
+		WHERE id = $4
+		RETURNING `+siteColumns,
+		site.Name, site.Description, site.Address, site.ID), site)
+	if err == sql.ErrNoRows {
+		return ErrSiteNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update site: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresLocationStore) DeleteSite(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM sites WHERE id = $1", id, ErrSiteNotFound); err != nil {
+		if err == ErrSiteNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete site: %w", err)
+	}
+	return nil
+}
+
+// Rooms
+
+func (s *postgresLocationStore) ListRooms(siteID *int) ([]models.Room, error) {
+	rows, err := s.db.Query(`
+		SELECT `+roomColumns+`
+		FROM rooms
+		WHERE ($1::INTEGER IS NULL OR site_id = $1)
+		ORDER BY site_id, name
+	`, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rooms: %w", err)
+	}
+	defer rows.Close()
+
+	var rooms []models.Room
+	for rows.Next() {
+		var room models.Room
+		if err := scanRoom(rows, &room); err != nil {
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+		rooms = append(rooms, room)
+	}
+
+	return rooms, rows.Err()
+}
+
+func (s *postgresLocationStore) GetRoom(id int) (*models.Room, error) {
+	var room models.Room
+	err := scanRoom(s.db.QueryRow("SELECT "+roomColumns+" FROM rooms WHERE id = $1", id), &room)
+	if err == sql.ErrNoRows {
+		return nil, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query room: %w", err)
+	}
+	return &room, nil
+}
+
+func (s *postgresLocationStore) CreateRoom(room *models.Room) error {
+	err := scanRoom(s.db.QueryRow(`
+		INSERT INTO rooms (site_id, name, description)
+		VALUES ($1, $2, $3)
+		RETURNING `+roomColumns,
+		room.SiteID, room.Name, room.Description), room)
+	if err != nil {
+		return fmt.Errorf("failed to create room: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresLocationStore) UpdateRoom(room *models.Room) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = scanRoom(tx.QueryRow(`
+		UPDATE rooms
+		SET site_id = $1, name = $2, description = $3
+		WHERE id = $4
+		RETURNING `+roomColumns,
+		room.SiteID, room.Name, room.Description, room.ID), room)
+	if err == sql.ErrNoRows {
+		return ErrRoomNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update room: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE racks SET site_id = $1 WHERE room_id = $2", room.SiteID, room.ID); err != nil {
+		return fmt.Errorf("failed to update rack locations: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit room update: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresLocationStore) DeleteRoom(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM rooms WHERE id = $1", id, ErrRoomNotFound); err != nil {
+		if err == ErrRoomNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+	return nil
+}
+
+// Rows
+
+func (s *postgresLocationStore) ListRows(filter RowFilter) ([]models.Row, error) {
+	rows, err := s.db.Query(`
+		SELECT `+rowColumns+`
+		FROM rack_rows
+		WHERE ($1::INTEGER IS NULL OR room_id = $1)
+		AND ($2::INTEGER IS NULL OR room_id IN (SELECT id FROM rooms WHERE site_id = $2))
+		ORDER BY room_id, name
+	`, filter.RoomID, filter.SiteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Row
+	for rows.Next() {
+		var row models.Row
+		if err := scanRow(rows, &row); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *postgresLocationStore) GetRow(id int) (*models.Row, error) {
+	var row models.Row
+	err := scanRow(s.db.QueryRow("SELECT "+rowColumns+" FROM rack_rows WHERE id = $1", id), &row)
+	if err == sql.ErrNoRows {
+		return nil, ErrRowNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query row: %w", err)
+	}
+	return &row, nil
+}
+
+func (s *postgresLocationStore) CreateRow(row *models.Row) error {
+	err := scanRow(s.db.QueryRow(`
+		INSERT INTO rack_rows (room_id, name, description)
+		VALUES ($1, $2, $3)
+		RETURNING `+rowColumns,
+		row.RoomID, row.Name, row.Description), row)
+	if err != nil {
+		return fmt.Errorf("failed to create row: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresLocationStore) UpdateRow(row *models.Row) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = scanRow(tx.QueryRow(`
+		UPDATE rack_rows
+		SET room_id = $1, name = $2, description = $3
+		WHERE id = $4
+		RETURNING `+rowColumns,
+		row.RoomID, row.Name, row.Description, row.ID), row)
+	if err == sql.ErrNoRows {
+		return ErrRowNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update row: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE racks
+		SET room_id = $1, site_id = (SELECT site_id FROM rooms WHERE id = $1)
+		WHERE row_id = $2
+	`, row.RoomID, row.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update rack locations: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit row update: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresLocationStore) DeleteRow(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM rack_rows WHERE id = $1", id, ErrRowNotFound); err != nil {
+		if err == ErrRowNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete row: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"rackview/internal/models"
+)
+
+// rackColumns is the column list shared by every rack SELECT and RETURNING clause
+const rackColumns = "id, name, COALESCE(description, ''), size_u, site_id, room_id, row_id, power_capacity_watts, created_at, updated_at"
+
+// scanRack scans a row selected with rackColumns into rack
+func scanRack(row rowScanner, rack *models.Rack) error {
+	var siteID, roomID, rowID, powerCapacity sql.NullInt64
+	if err := row.Scan(
+		&rack.ID, &rack.Name, &rack.Description, &rack.SizeU,
+		&siteID, &roomID, &rowID, &powerCapacity,
+		&rack.CreatedAt, &rack.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	rack.SiteID = nullIntPtr(siteID)
+	rack.RoomID = nullIntPtr(roomID)
+	rack.RowID = nullIntPtr(rowID)
+	rack.PowerCapacityWatts = int(powerCapacity.Int64)
+	return nil
+}
+
+// postgresRackStore is the RackStore over the racks table
+type postgresRackStore struct {
+	db *sql.DB
+}
+
+func (s *postgresRackStore) ListRacks(filter models.RackFilter) ([]models.Rack, error) {
+	rows, err := s.db.Query(`
+		SELECT `+rackColumns+`
+		FROM racks
+		WHERE ($1::INTEGER IS NULL OR site_id = $1)
+		AND ($2::INTEGER IS NULL OR room_id = $2)
+		AND ($3::INTEGER IS NULL OR row_id = $3)
+		ORDER BY id
+	`, filter.SiteID, filter.RoomID, filter.RowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query racks: %w", err)
+	}
+	defer rows.Close()
+
+	var racks []models.Rack
+	for rows.Next() {
+		var rack models.Rack
+		if err := scanRack(rows, &rack); err != nil {
+			return nil, fmt.Errorf("failed to scan rack: %w", err)
+		}
+		racks = append(racks, rack)
+	}
+
+	return racks, rows.Err()
+}
+
+func (s *postgresRackStore) GetRack(id int) (*models.Rack, error) {
+	var rack models.Rack
+	err := scanRack(s.db.QueryRow(`
+		SELECT `+rackColumns+`
+		FROM racks
+		WHERE id = $1
+	`, id), &rack)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrRackNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rack: %w", err)
+	}
+
+	return &rack, nil
+}
+
+func (s *postgresRackStore) CreateRack(rack *models.Rack) error {
+	err := scanRack(s.db.QueryRow(`
+		INSERT INTO racks (name, description, size_u, site_id, room_id, row_id, power_capacity_watts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+rackColumns+`
+	`, rack.Name, rack.Description, rack.SizeU, rack.SiteID, rack.RoomID, rack.RowID, nullablePositive(rack.PowerCapacityWatts)), rack)
+	if err != nil {
+		return fmt.Errorf("failed to create rack: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresRackStore) UpdateRack(rack *models.Rack) error {
+	err := scanRack(s.db.QueryRow(`
+		UPDATE racks
+		SET name = $1, description = $2, size_u = $3, site_id = $4, room_id = $5, row_id = $6, power_capacity_watts = $7
+		WHERE id = $8
+		RETURNING `+rackColumns+`
+	`, rack.Name, rack.Description, rack.SizeU, rack.SiteID, rack.RoomID, rack.RowID, nullablePositive(rack.PowerCapacityWatts), rack.ID), rack)
+
+	if err == sql.ErrNoRows {
+		return ErrRackNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update rack: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresRackStore) DeleteRack(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM racks WHERE id = $1", id, ErrRackNotFound); err != nil {
+		if err == ErrRackNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete rack: %w", err)
+	}
+	return nil
+}
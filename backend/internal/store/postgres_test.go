@@ -0,0 +1,73 @@
+package store_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"rackview/internal/database"
+	"rackview/internal/store"
+	"rackview/internal/store/storetest"
+)
+
+// postgresDSNEnv names the database the Postgres conformance run migrates and empties between
+// subtests; point it at a scratch database
+const postgresDSNEnv = "STORETEST_POSTGRES_DSN"
+
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		t.Fatalf("ping database: %v", err)
+	}
+
+	database.DB, database.CurrentDialect = db, database.Postgres
+	defer func() { database.DB = nil }()
+	if err := database.RunMigrations(); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	storetest.Run(t, func(t *testing.T) *store.Store {
+		if err := truncateTables(db); err != nil {
+			t.Fatalf("empty database: %v", err)
+		}
+		return store.NewPostgresStore(db)
+	})
+}
+
+// truncateTables empties every table but the migration history
+func truncateTables(db *sql.DB) error {
+	rows, err := db.Query(`SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return err
+		}
+		tables = append(tables, fmt.Sprintf("%q", table))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+
+	_, err = db.Exec("TRUNCATE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE")
+	return err
+}
@@ -0,0 +1,273 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"rackview/internal/database"
+	"rackview/internal/models"
+)
+
+const (
+	// vlanGroupNameKey is the unique constraint on VLAN group names
+	vlanGroupNameKey = "vlan_groups_name_key"
+	// vlanScopeIndex is the unique index on the VID of a VLAN within its scope
+	vlanScopeIndex = "idx_vlans_scope_vid"
+)
+
+const vlanGroupColumns = "id, name, site_id, COALESCE(description, ''), created_at, updated_at"
+
+func scanVLANGroup(row rowScanner, group *models.VLANGroup) error {
+	var siteID sql.NullInt64
+	if err := row.Scan(&group.ID, &group.Name, &siteID, &group.Description, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return err
+	}
+	group.SiteID = nullIntPtr(siteID)
+	return nil
+}
+
+const vlanColumns = "id, vid, name, group_id, site_id, COALESCE(description, ''), created_at, updated_at"
+
+func scanVLAN(row rowScanner, vlan *models.VLAN) error {
+	var groupID, siteID sql.NullInt64
+	if err := row.Scan(
+		&vlan.ID, &vlan.VID, &vlan.Name, &groupID, &siteID,
+		&vlan.Description, &vlan.CreatedAt, &vlan.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	vlan.GroupID = nullIntPtr(groupID)
+	vlan.SiteID = nullIntPtr(siteID)
+	return nil
+}
+
+// postgresVLANStore is the VLANStore over the vlan_groups and vlans tables and the tagging columns
+// and join tables of interfaces and connections
+type postgresVLANStore struct {
+	db *sql.DB
+}
+
+// VLAN groups
+
+func (s *postgresVLANStore) ListVLANGroups() ([]models.VLANGroup, error) {
+	rows, err := s.db.Query("SELECT " + vlanGroupColumns + " FROM vlan_groups ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vlan groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []models.VLANGroup
+	for rows.Next() {
+		var group models.VLANGroup
+		if err := scanVLANGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("failed to scan vlan group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+func (s *postgresVLANStore) GetVLANGroup(id int) (*models.VLANGroup, error) {
+	var group models.VLANGroup
+	err := scanVLANGroup(s.db.QueryRow("SELECT "+vlanGroupColumns+" FROM vlan_groups WHERE id = $1", id), &group)
+	if err == sql.ErrNoRows {
+		return nil, ErrVLANGroupNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vlan group: %w", err)
+	}
+	return &group, nil
+}
+
+func (s *postgresVLANStore) CreateVLANGroup(group *models.VLANGroup) error {
+	err := scanVLANGroup(s.db.QueryRow(`
+		INSERT INTO vlan_groups (name, site_id, description)
+		VALUES ($1, $2, $3)
+		RETURNING `+vlanGroupColumns,
+		group.Name, group.SiteID, nullableString(group.Description)), group)
+	if err != nil {
+		if database.IsConstraintViolation(err, vlanGroupNameKey) {
+			return ErrVLANGroupExists
+		}
+		return fmt.Errorf("failed to create vlan group: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresVLANStore) UpdateVLANGroup(group *models.VLANGroup) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = scanVLANGroup(tx.QueryRow(`
+		UPDATE vlan_groups
+		SET name = $1, site_id = $2, description = $3
+		WHERE id = $4
+		RETURNING `+vlanGroupColumns,
+		group.Name, group.SiteID, nullableString(group.Description), group.ID), group)
+	if err == sql.ErrNoRows {
+		return ErrVLANGroupNotFound
+	}
+	if err != nil {
+		if database.IsConstraintViolation(err, vlanGroupNameKey) {
+			return ErrVLANGroupExists
+		}
+		return fmt.Errorf("failed to update vlan group: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE vlans SET site_id = $1 WHERE group_id = $2", group.SiteID, group.ID); err != nil {
+		if database.IsConstraintViolation(err, vlanScopeIndex) {
+			return ErrVLANExists
+		}
+		return fmt.Errorf("failed to update vlan sites: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vlan group update: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresVLANStore) DeleteVLANGroup(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM vlan_groups WHERE id = $1", id, ErrVLANGroupNotFound); err != nil {
+		if err == ErrVLANGroupNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete vlan group: %w", err)
+	}
+	return nil
+}
+
+// VLANs
+
+func (s *postgresVLANStore) ListVLANs(filter models.VLANFilter) ([]models.VLAN, error) {
+	rows, err := s.db.Query(`
+		SELECT `+vlanColumns+`
+		FROM vlans
+		WHERE ($1::INTEGER IS NULL OR group_id = $1)
+		AND ($2::INTEGER IS NULL OR site_id = $2)
+		ORDER BY vid, id
+	`, filter.GroupID, filter.SiteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vlans: %w", err)
+	}
+	defer rows.Close()
+
+	var vlans []models.VLAN
+	for rows.Next() {
+		var vlan models.VLAN
+		if err := scanVLAN(rows, &vlan); err != nil {
+			return nil, fmt.Errorf("failed to scan vlan: %w", err)
+		}
+		vlans = append(vlans, vlan)
+	}
+
+	return vlans, rows.Err()
+}
+
+func (s *postgresVLANStore) GetVLAN(id int) (*models.VLAN, error) {
+	var vlan models.VLAN
+	err := scanVLAN(s.db.QueryRow("SELECT "+vlanColumns+" FROM vlans WHERE id = $1", id), &vlan)
+	if err == sql.ErrNoRows {
+		return nil, ErrVLANNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vlan: %w", err)
+	}
+	return &vlan, nil
+}
+
+func (s *postgresVLANStore) CreateVLAN(vlan *models.VLAN) error {
+	// A grouped VLAN takes its group's site, whatever SiteID says
+	err := scanVLAN(s.db.QueryRow(`
+		INSERT INTO vlans (vid, name, group_id, site_id, description)
+		VALUES ($1, $2, $3, CASE WHEN $3::INTEGER IS NULL THEN $4 ELSE (SELECT site_id FROM vlan_groups WHERE id = $3) END, $5)
+		RETURNING `+vlanColumns,
+		vlan.VID, vlan.Name, vlan.GroupID, vlan.SiteID, nullableString(vlan.Description)), vlan)
+	if err != nil {
+		if database.IsConstraintViolation(err, vlanScopeIndex) {
+			return ErrVLANExists
+		}
+		return fmt.Errorf("failed to create vlan: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresVLANStore) UpdateVLAN(vlan *models.VLAN) error {
+	err := scanVLAN(s.db.QueryRow(`
+		UPDATE vlans
+		SET vid = $1, name = $2, description = $3
+		WHERE id = $4
+		RETURNING `+vlanColumns,
+		vlan.VID, vlan.Name, nullableString(vlan.Description), vlan.ID), vlan)
+	if err == sql.ErrNoRows {
+		return ErrVLANNotFound
+	}
+	if err != nil {
+		if database.IsConstraintViolation(err, vlanScopeIndex) {
+			return ErrVLANExists
+		}
+		return fmt.Errorf("failed to update vlan: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresVLANStore) DeleteVLAN(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM vlans WHERE id = $1", id, ErrVLANNotFound); err != nil {
+		if err == ErrVLANNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete vlan: %w", err)
+	}
+	return nil
+}
+
+// Tagging
+
+func (s *postgresVLANStore) SetInterfaceVLANs(interfaceID int, tagging models.VLANTagging) error {
+	return s.setTagging("device_interfaces", "interface_vlans", "interface_id", interfaceID, tagging, ErrInterfaceNotFound)
+}
+
+func (s *postgresVLANStore) SetConnectionVLANs(connectionID int, tagging models.VLANTagging) error {
+	return s.setTagging("network_connections", "connection_vlans", "connection_id", connectionID, tagging, ErrConnectionNotFound)
+}
+
+// setTagging writes a tagging to table's vlan columns and replaces the rows of its VLAN join table
+func (s *postgresVLANStore) setTagging(table, joinTable, joinColumn string, id int, tagging models.VLANTagging, notFound error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(fmt.Sprintf("UPDATE %s SET vlan_mode = $1, untagged_vlan_id = $2 WHERE id = $3", table),
+		nullableString(string(tagging.VLANMode)), tagging.UntaggedVLANID, id)
+	if err != nil {
+		return fmt.Errorf("failed to set vlan mode: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = $1", joinTable, joinColumn), id); err != nil {
+		return fmt.Errorf("failed to clear tagged vlans: %w", err)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s, vlan_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", joinTable, joinColumn)
+	for _, vlanID := range tagging.TaggedVLANIDs {
+		if _, err := tx.Exec(insert, id, vlanID); err != nil {
+			return fmt.Errorf("failed to set tagged vlans: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vlan tagging: %w", err)
+	}
+	return nil
+}
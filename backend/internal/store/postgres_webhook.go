@@ -0,0 +1,226 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"rackview/internal/models"
+)
+
+// encodeStatuses stores a status filter as a comma-separated list
+func encodeStatuses(statuses []models.DeviceStatus) string {
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = string(status)
+	}
+	return strings.Join(parts, ",")
+}
+
+// decodeStatuses parses a comma-separated status filter
+func decodeStatuses(encoded string) []models.DeviceStatus {
+	statuses := []models.DeviceStatus{}
+	for _, part := range strings.Split(encoded, ",") {
+		if part != "" {
+			statuses = append(statuses, models.DeviceStatus(part))
+		}
+	}
+	return statuses
+}
+
+const webhookColumns = "id, name, url, COALESCE(secret, ''), statuses, enabled, created_at, updated_at"
+
+func scanWebhook(row rowScanner, webhook *models.Webhook) error {
+	var statuses string
+	if err := row.Scan(
+		&webhook.ID, &webhook.Name, &webhook.URL, &webhook.Secret, &statuses,
+		&webhook.Enabled, &webhook.CreatedAt, &webhook.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	webhook.HasSecret = webhook.Secret != ""
+	webhook.Statuses = decodeStatuses(statuses)
+	return nil
+}
+
+// postgresWebhookStore is the WebhookStore over the webhooks and webhook_deliveries tables
+type postgresWebhookStore struct {
+	db *sql.DB
+}
+
+func (s *postgresWebhookStore) ListWebhooks() ([]models.Webhook, error) {
+	rows, err := s.db.Query("SELECT " + webhookColumns + " FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []models.Webhook
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := scanWebhook(rows, &webhook); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (s *postgresWebhookStore) GetWebhook(id int) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := scanWebhook(s.db.QueryRow("SELECT "+webhookColumns+" FROM webhooks WHERE id = $1", id), &webhook)
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+func (s *postgresWebhookStore) CreateWebhook(webhook *models.Webhook) error {
+	err := scanWebhook(s.db.QueryRow(`
+		INSERT INTO webhooks (name, url, secret, statuses, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+webhookColumns,
+		webhook.Name, webhook.URL, nullableString(webhook.Secret), encodeStatuses(webhook.Statuses), webhook.Enabled), webhook)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresWebhookStore) UpdateWebhook(webhook *models.Webhook) error {
+	err := scanWebhook(s.db.QueryRow(`
+		UPDATE webhooks
+		SET name = $1, url = $2, secret = $3, statuses = $4, enabled = $5
+		WHERE id = $6
+		RETURNING `+webhookColumns,
+		webhook.Name, webhook.URL, nullableString(webhook.Secret), encodeStatuses(webhook.Statuses), webhook.Enabled,
+		webhook.ID), webhook)
+	if err == sql.ErrNoRows {
+		return ErrWebhookNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresWebhookStore) DeleteWebhook(id int) error {
+	if err := deleteRow(s.db, "DELETE FROM webhooks WHERE id = $1", id, ErrWebhookNotFound); err != nil {
+		if err == ErrWebhookNotFound {
+			return err
+		}
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresWebhookStore) RecordWebhookDelivery(delivery *models.WebhookDelivery) error {
+	err := s.db.QueryRow(`
+		INSERT INTO webhook_deliveries (webhook_id, event, device_id, payload, attempt, success, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, delivery.WebhookID, delivery.Event, delivery.DeviceID, []byte(delivery.Payload), delivery.Attempt,
+		delivery.Success, delivery.StatusCode, nullableString(delivery.Error), delivery.DurationMs,
+	).Scan(&delivery.ID, &delivery.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresWebhookStore) ListWebhookDeliveries(webhookID, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := s.db.Query(`
+		SELECT id, webhook_id, event, device_id, payload, attempt, success, status_code, COALESCE(error, ''), COALESCE(duration_ms, 0), created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var deviceID, statusCode sql.NullInt64
+		var payload []byte
+		if err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.Event, &deviceID, &payload, &delivery.Attempt,
+			&delivery.Success, &statusCode, &delivery.Error, &delivery.DurationMs, &delivery.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		delivery.Payload = payload
+		delivery.DeviceID = nullIntPtr(deviceID)
+		delivery.StatusCode = nullIntPtr(statusCode)
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// postgresAlertStateStore is the AlertStateStore over the device_alert_state table
+type postgresAlertStateStore struct {
+	db *sql.DB
+}
+
+func (s *postgresAlertStateStore) AdvanceAlertState(deviceID int, advance func(state *models.AlertState)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The device's stored status is the baseline the first time it is observed
+	_, err = tx.Exec(`
+		INSERT INTO device_alert_state (device_id, alert_status)
+		SELECT id, status FROM devices WHERE id = $1
+		ON CONFLICT (device_id) DO NOTHING
+	`, deviceID)
+	if err != nil {
+		return fmt.Errorf("failed to initialize alert state: %w", err)
+	}
+
+	state := models.AlertState{DeviceID: deviceID}
+	var candidate sql.NullString
+	var changedAt sql.NullTime
+	err = tx.QueryRow(`
+		SELECT alert_status, candidate_status, candidate_count, changed_at
+		FROM device_alert_state
+		WHERE device_id = $1
+		FOR UPDATE
+	`, deviceID).Scan(&state.AlertStatus, &candidate, &state.CandidateCount, &changedAt)
+	if err == sql.ErrNoRows {
+		return ErrDeviceNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load alert state: %w", err)
+	}
+	state.CandidateStatus = models.DeviceStatus(candidate.String)
+	state.ChangedAt = changedAt.Time
+
+	previous := state.AlertStatus
+	advance(&state)
+
+	_, err = tx.Exec(`
+		UPDATE device_alert_state
+		SET alert_status = $1, candidate_status = $2, candidate_count = $3,
+			changed_at = CASE WHEN $4 THEN CURRENT_TIMESTAMP ELSE changed_at END
+		WHERE device_id = $5
+	`, state.AlertStatus, nullableString(string(state.CandidateStatus)), state.CandidateCount,
+		state.AlertStatus != previous, deviceID)
+	if err != nil {
+		return fmt.Errorf("failed to update alert state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit alert state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"rackview/internal/database"
+	"rackview/internal/store"
+	"rackview/internal/store/storetest"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "rackview.db"))
+	if err := database.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer database.Close()
+	if err := database.RunMigrations(); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	db := database.DB
+	storetest.Run(t, func(t *testing.T) *store.Store {
+		if err := deleteTables(db); err != nil {
+			t.Fatalf("empty database: %v", err)
+		}
+		return store.NewPostgresStore(db)
+	})
+}
+
+// deleteTables empties every table but the migration history, including the seed data
+func deleteTables(db *sql.DB) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> 'schema_migrations'`)
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Foreign keys are switched off on this connection only, so tables can go in any order
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	for _, table := range tables {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM %q", table)); err != nil {
+			return err
+		}
+	}
+	_, err = conn.ExecContext(ctx, "DELETE FROM sqlite_sequence")
+	return err
+}
@@ -0,0 +1,365 @@
+// Package store defines the storage interfaces for the inventory (racks, devices, connections,
+// interfaces, cables, device types, locations, IPAM, VLANs, health checks, webhooks and alert
+// state), with a Postgres implementation and an in-memory implementation for tests and demos.
+//
+// Stores only persist records: validation such as rack fit, overlaps and bay occupancy stays in
+// the services, which hand placement rules to device writes as a PlacementCheck. The rules the
+// stores enforce themselves are the ones the schema enforces: foreign keys and their cascades,
+// unique names and addresses, and devices never overlapping. Both implementations must pass the
+// conformance suite in package storetest.
+package store
+
+import (
+	"errors"
+	"time"
+
+	"rackview/internal/models"
+)
+
+var (
+	// ErrRackNotFound is returned for a rack ID that does not exist
+	ErrRackNotFound = errors.New("rack not found")
+	// ErrDeviceNotFound is returned for a device ID that does not exist
+	ErrDeviceNotFound = errors.New("device not found")
+	// ErrConnectionNotFound is returned for a connection ID that does not exist
+	ErrConnectionNotFound = errors.New("connection not found")
+	// ErrDeviceOverlap is returned when a device would share a U on a common face with another
+	// device in its rack
+	ErrDeviceOverlap = errors.New("device overlaps with existing device")
+	// ErrInterfaceNotFound is returned for an interface ID that does not exist
+	ErrInterfaceNotFound = errors.New("interface not found")
+	// ErrCableNotFound is returned for a cable ID that does not exist
+	ErrCableNotFound = errors.New("cable not found")
+	// ErrCableLabelInUse is returned when a cable would repeat the label of another cable
+	ErrCableLabelInUse = errors.New("cable label is already in use")
+	// ErrDeviceTypeNotFound is returned for a device type ID that does not exist
+	ErrDeviceTypeNotFound = errors.New("device type not found")
+	// ErrSiteNotFound is returned for a site ID that does not exist
+	ErrSiteNotFound = errors.New("site not found")
+	// ErrRoomNotFound is returned for a room ID that does not exist
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrRowNotFound is returned for a row ID that does not exist
+	ErrRowNotFound = errors.New("row not found")
+	// ErrPrefixNotFound is returned for a prefix ID that does not exist
+	ErrPrefixNotFound = errors.New("prefix not found")
+	// ErrPrefixExists is returned when a prefix would repeat another prefix
+	ErrPrefixExists = errors.New("prefix already exists")
+	// ErrIPAddressNotFound is returned for an address assignment ID that does not exist
+	ErrIPAddressNotFound = errors.New("ip address not found")
+	// ErrIPAddressInUse is returned when an address assignment or a device ip_address would
+	// repeat another one of its kind
+	ErrIPAddressInUse = errors.New("ip address is already assigned")
+	// ErrHealthProbeNotFound is returned for a health probe ID that does not exist
+	ErrHealthProbeNotFound = errors.New("health probe not found")
+	// ErrVLANGroupNotFound is returned for a VLAN group ID that does not exist
+	ErrVLANGroupNotFound = errors.New("vlan group not found")
+	// ErrVLANGroupExists is returned when a VLAN group would repeat the name of another group
+	ErrVLANGroupExists = errors.New("vlan group already exists")
+	// ErrVLANNotFound is returned for a VLAN ID that does not exist
+	ErrVLANNotFound = errors.New("vlan not found")
+	// ErrVLANExists is returned when a VLAN would repeat the VID of another VLAN in its scope
+	ErrVLANExists = errors.New("vlan already exists in this scope")
+	// ErrWebhookNotFound is returned for a webhook ID that does not exist
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+// RackStore persists racks. Deleting a rack deletes its devices. A rack's site, room and row must
+// exist; consistency between the levels is up to the location service.
+type RackStore interface {
+	// ListRacks returns the racks matching the filter, ordered by ID
+	ListRacks(filter models.RackFilter) ([]models.Rack, error)
+	GetRack(id int) (*models.Rack, error)
+	// CreateRack inserts a rack and fills in its ID and timestamps
+	CreateRack(rack *models.Rack) error
+	// UpdateRack writes every stored field of an existing rack and refreshes its timestamps
+	UpdateRack(rack *models.Rack) error
+	DeleteRack(id int) error
+}
+
+// DeviceFilter represents the filters for listing devices
+type DeviceFilter struct {
+	RackID *int
+	// ParentDeviceID lists the devices installed in a chassis, ordered by bay
+	ParentDeviceID *int
+	// IPAddress lists the devices with this ip_address
+	IPAddress string
+}
+
+// PlacementCheck validates a device against the other devices in the rack it is written to. Device
+// writes call it before writing, while no other device write to that rack can run, so a placement
+// that passes cannot be taken concurrently. Its error is returned unchanged. It must not use the
+// store.
+type PlacementCheck func(rackDevices []models.Device) error
+
+// DeviceStore persists devices and their specs. Deleting a device deletes the devices installed
+// in it and its connections, interfaces, addresses, health probes, health checks and alert state;
+// moving a device to another rack moves the devices installed in it with it. Devices never
+// overlap: a write that would make a device share a U on a common face with another device in
+// its rack fails with ErrDeviceOverlap, whatever the placement check allowed. A non-empty
+// ip_address belongs to one device: a write repeating one fails with ErrIPAddressInUse.
+type DeviceStore interface {
+	// ListDevices returns the devices matching the filter with their specs, ordered by rack and
+	// then from the top of the rack down (or by bay for ParentDeviceID)
+	ListDevices(filter DeviceFilter) ([]models.Device, error)
+	// GetDevice returns a device with its specs; Children is not loaded
+	GetDevice(id int) (*models.Device, error)
+	// CreateDevice inserts a device with its specs and fills in its ID and timestamps. The
+	// interfaces, if any, are created on the device and filled in like CreateInterface. The
+	// placement check, if any, and the writes form one transaction.
+	CreateDevice(device *models.Device, interfaces []*models.DeviceInterface, check PlacementCheck) error
+	// UpdateDevice writes every stored field of an existing device. Specs are replaced when
+	// non-nil and left unchanged when nil. The placement check, if any, and the writes form one
+	// transaction.
+	UpdateDevice(device *models.Device, check PlacementCheck) error
+	// UpdateDeviceStatus sets only the status of a device
+	UpdateDeviceStatus(id int, status models.DeviceStatus) error
+	DeleteDevice(id int) error
+}
+
+// ConnectionFilter represents the filters for listing connections
+type ConnectionFilter struct {
+	// DeviceID lists the connections with the device at either end
+	DeviceID *int
+}
+
+// ConnectionStore persists network connections. An interface carries at most one connection and
+// a cable makes at most one. VLAN tagging is read with a connection but written through the
+// VLANStore.
+type ConnectionStore interface {
+	// ListConnections returns the connections matching the filter, ordered by ID
+	ListConnections(filter ConnectionFilter) ([]models.NetworkConnection, error)
+	GetConnection(id int) (*models.NetworkConnection, error)
+	// CreateConnection inserts a connection and fills in its ID and creation time. A non-nil
+	// cable is created in the same transaction, filled in like CreateCable, and the connection
+	// made with it instead of CableID.
+	CreateConnection(conn *models.NetworkConnection, cable *models.Cable) error
+	// UpdateConnection writes the interfaces, cable, type, port info and speed of a connection
+	UpdateConnection(conn *models.NetworkConnection) error
+	DeleteConnection(id int) error
+}
+
+// InterfaceFilter represents the filters for listing interfaces
+type InterfaceFilter struct {
+	DeviceID *int
+	// RackID lists the interfaces of the devices in a rack
+	RackID *int
+}
+
+// InterfaceStore persists device interfaces. The front port paired with a rear port and the
+// connection on an interface are looked up when it is read; VLAN tagging is read with it but
+// written through the VLANStore. Names are unique per device, and a rear port is paired
+// with at most one front port on the same device. Deleting an interface detaches the connection
+// on it, unpairs its front port and deletes its addresses.
+type InterfaceStore interface {
+	// ListInterfaces returns the interfaces matching the filter, ordered by device, name and ID
+	ListInterfaces(filter InterfaceFilter) ([]models.DeviceInterface, error)
+	GetInterface(id int) (*models.DeviceInterface, error)
+	// CreateInterface inserts an interface and fills in its ID and timestamps
+	CreateInterface(iface *models.DeviceInterface) error
+	// UpdateInterface writes the name, type, speed, MAC address, enabled flag, description and
+	// rear port of an existing interface
+	UpdateInterface(iface *models.DeviceInterface) error
+	DeleteInterface(id int) error
+}
+
+// CableStore persists cables. The connection made with a cable and the ports at either end of it
+// are looked up when it is read; PrintableLabel is left to the caller. Labels are unique: a write
+// repeating one fails with ErrCableLabelInUse. Deleting a cable keeps its connection without one.
+type CableStore interface {
+	// ListCables returns the cables matching the filter, ordered by label
+	ListCables(filter models.CableFilter) ([]models.Cable, error)
+	GetCable(id int) (*models.Cable, error)
+	// CreateCable inserts a cable and fills in its ID and timestamps
+	CreateCable(cable *models.Cable) error
+	// UpdateCable writes every stored field of an existing cable
+	UpdateCable(cable *models.Cable) error
+	DeleteCable(id int) error
+}
+
+// DeviceTypeStore persists the device type catalog. Manufacturer and model are unique together.
+// Deleting a device type keeps the devices created from it, unlinked.
+type DeviceTypeStore interface {
+	// ListDeviceTypes returns the catalog ordered by manufacturer and model
+	ListDeviceTypes() ([]models.DeviceTypeTemplate, error)
+	GetDeviceType(id int) (*models.DeviceTypeTemplate, error)
+	// CreateDeviceType inserts a device type and fills in its ID and timestamps
+	CreateDeviceType(deviceType *models.DeviceTypeTemplate) error
+	// UpdateDeviceType writes every stored field of an existing device type
+	UpdateDeviceType(deviceType *models.DeviceTypeTemplate) error
+	DeleteDeviceType(id int) error
+}
+
+// RowFilter represents the filters for listing rows
+type RowFilter struct {
+	RoomID *int
+	// SiteID lists the rows of every room in a site
+	SiteID *int
+}
+
+// LocationStore persists the site, room and row hierarchy above racks. Site names are unique, as
+// are room names within a site and row names within a room. Deleting a site deletes its rooms and
+// deleting a room its rows; racks lose the levels that were deleted. Moving a room to another site
+// or a row to another room moves their racks with them.
+type LocationStore interface {
+	// ListSites returns every site ordered by name
+	ListSites() ([]models.Site, error)
+	GetSite(id int) (*models.Site, error)
+	// CreateSite inserts a site and fills in its ID and timestamps
+	CreateSite(site *models.Site) error
+	// UpdateSite writes every stored field of an existing site
+	UpdateSite(site *models.Site) error
+	DeleteSite(id int) error
+
+	// ListRooms returns the rooms, optionally of one site, ordered by site and name
+	ListRooms(siteID *int) ([]models.Room, error)
+	GetRoom(id int) (*models.Room, error)
+	// CreateRoom inserts a room and fills in its ID and timestamps
+	CreateRoom(room *models.Room) error
+	// UpdateRoom writes every stored field of an existing room
+	UpdateRoom(room *models.Room) error
+	DeleteRoom(id int) error
+
+	// ListRows returns the rows matching the filter, ordered by room and name
+	ListRows(filter RowFilter) ([]models.Row, error)
+	GetRow(id int) (*models.Row, error)
+	// CreateRow inserts a row and fills in its ID and timestamps
+	CreateRow(row *models.Row) error
+	// UpdateRow writes every stored field of an existing row
+	UpdateRow(row *models.Row) error
+	DeleteRow(id int) error
+}
+
+// IPAMStore persists prefixes and address assignments. Prefixes and addresses are stored in
+// canonical form and are unique: a write repeating one fails with ErrPrefixExists or
+// ErrIPAddressInUse. An address belongs to the most specific prefix containing it, so PrefixID is
+// set by the store: address writes fill it in and prefix writes reassign the addresses they affect.
+type IPAMStore interface {
+	// ListPrefixes returns every prefix in numeric order; Used and Utilization are left to the caller
+	ListPrefixes() ([]models.IPPrefix, error)
+	GetPrefix(id int) (*models.IPPrefix, error)
+	// CreatePrefix inserts a prefix and fills in its ID and timestamps
+	CreatePrefix(prefix *models.IPPrefix) error
+	// UpdatePrefix writes the prefix and description of an existing prefix
+	UpdatePrefix(prefix *models.IPPrefix) error
+	DeletePrefix(id int) error
+
+	// ListIPAddresses returns the assignments matching the filter in numeric order
+	ListIPAddresses(filter models.IPAddressFilter) ([]models.IPAddress, error)
+	GetIPAddress(id int) (*models.IPAddress, error)
+	// CreateIPAddress inserts an assignment and fills in its ID, prefix and timestamps
+	CreateIPAddress(address *models.IPAddress) error
+	// UpdateIPAddress writes the address, interface and description of an existing assignment
+	UpdateIPAddress(address *models.IPAddress) error
+	DeleteIPAddress(id int) error
+}
+
+// HealthProbeFilter represents the filters for listing health probes
+type HealthProbeFilter struct {
+	DeviceID *int
+	// EnabledOnly leaves out disabled probes
+	EnabledOnly bool
+}
+
+// HealthProbeStore persists the configured health probes of devices
+type HealthProbeStore interface {
+	// ListHealthProbes returns the probes matching the filter, ordered by ID
+	ListHealthProbes(filter HealthProbeFilter) ([]models.HealthProbe, error)
+	GetHealthProbe(id int) (*models.HealthProbe, error)
+	// CreateHealthProbe inserts a probe and fills in its ID and timestamps
+	CreateHealthProbe(probe *models.HealthProbe) error
+	// UpdateHealthProbe writes the name, config and enabled flag of an existing probe
+	UpdateHealthProbe(probe *models.HealthProbe) error
+	DeleteHealthProbe(id int) error
+}
+
+// HealthCheckStore persists the health check history of devices. Time ranges include from and
+// exclude to.
+type HealthCheckStore interface {
+	// RecordHealthCheck inserts a check and fills in its ID
+	RecordHealthCheck(check *models.HealthCheck) error
+	// ListHealthChecks returns up to limit of a device's checks in a time range, oldest first
+	ListHealthChecks(deviceID int, from, to time.Time, limit int) ([]models.HealthCheck, error)
+	// SummarizeHealthChecks aggregates a device's checks in a time range into buckets of
+	// bucketSeconds aligned to the Unix epoch, oldest first. Buckets without checks are left out
+	// and Availability is left to the caller.
+	SummarizeHealthChecks(deviceID int, from, to time.Time, bucketSeconds int) ([]models.HealthHistoryBucket, error)
+	// PruneHealthChecks deletes the checks before a time and returns the number removed
+	PruneHealthChecks(before time.Time) (int64, error)
+	// LatestHealthChecks returns the most recent check of every device that has one, ordered by
+	// device
+	LatestHealthChecks() ([]models.HealthCheck, error)
+}
+
+// VLANStore persists VLAN groups, VLANs and the VLAN tagging of interfaces and connections. Group
+// names are unique, and a VID is unique within its group, or within its site (or globally) for
+// ungrouped VLANs: a write repeating one fails with ErrVLANGroupExists or ErrVLANExists. A grouped
+// VLAN is at its group's site. Deleting a site deletes its groups and VLANs, deleting a group its
+// VLANs, and deleting a VLAN takes it out of every tagging.
+type VLANStore interface {
+	// ListVLANGroups returns every group ordered by name
+	ListVLANGroups() ([]models.VLANGroup, error)
+	GetVLANGroup(id int) (*models.VLANGroup, error)
+	// CreateVLANGroup inserts a group and fills in its ID and timestamps
+	CreateVLANGroup(group *models.VLANGroup) error
+	// UpdateVLANGroup writes every stored field of an existing group and moves its VLANs to its site
+	UpdateVLANGroup(group *models.VLANGroup) error
+	DeleteVLANGroup(id int) error
+
+	// ListVLANs returns the VLANs matching the filter, ordered by VID and ID
+	ListVLANs(filter models.VLANFilter) ([]models.VLAN, error)
+	GetVLAN(id int) (*models.VLAN, error)
+	// CreateVLAN inserts a VLAN and fills in its ID and timestamps
+	CreateVLAN(vlan *models.VLAN) error
+	// UpdateVLAN writes the VID, name and description of an existing VLAN
+	UpdateVLAN(vlan *models.VLAN) error
+	DeleteVLAN(id int) error
+
+	// SetInterfaceVLANs replaces the tagging of an interface
+	SetInterfaceVLANs(interfaceID int, tagging models.VLANTagging) error
+	// SetConnectionVLANs replaces the tagging of a connection
+	SetConnectionVLANs(connectionID int, tagging models.VLANTagging) error
+}
+
+// WebhookStore persists webhooks and their delivery log. Deleting a webhook deletes its
+// deliveries; deleting a device keeps the deliveries about it without the device.
+type WebhookStore interface {
+	// ListWebhooks returns every webhook ordered by ID
+	ListWebhooks() ([]models.Webhook, error)
+	GetWebhook(id int) (*models.Webhook, error)
+	// CreateWebhook inserts a webhook and fills in its ID and timestamps
+	CreateWebhook(webhook *models.Webhook) error
+	// UpdateWebhook writes every stored field of an existing webhook
+	UpdateWebhook(webhook *models.Webhook) error
+	DeleteWebhook(id int) error
+
+	// RecordWebhookDelivery inserts a delivery attempt and fills in its ID and creation time
+	RecordWebhookDelivery(delivery *models.WebhookDelivery) error
+	// ListWebhookDeliveries returns up to limit of a webhook's deliveries, newest first
+	ListWebhookDeliveries(webhookID, limit int) ([]models.WebhookDelivery, error)
+}
+
+// AlertStateStore persists the debounce state of device status alerts. Deleting a device deletes
+// its state.
+type AlertStateStore interface {
+	// AdvanceAlertState calls advance with a device's state and writes back its changes, with no
+	// other advance of that device in between. A device seen for the first time starts from its
+	// stored status. ChangedAt is refreshed when AlertStatus changes. advance must not use the store.
+	AdvanceAlertState(deviceID int, advance func(state *models.AlertState)) error
+}
+
+// Store bundles the stores the services are built from
+type Store struct {
+	Racks        RackStore
+	Devices      DeviceStore
+	Connections  ConnectionStore
+	Interfaces   InterfaceStore
+	Cables       CableStore
+	DeviceTypes  DeviceTypeStore
+	Locations    LocationStore
+	IPAM         IPAMStore
+	HealthProbes HealthProbeStore
+	HealthChecks HealthCheckStore
+	VLANs        VLANStore
+	Webhooks     WebhookStore
+	AlertStates  AlertStateStore
+}
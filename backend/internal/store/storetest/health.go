@@ -0,0 +1,218 @@
+package storetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func mustRecordHealthCheck(t *testing.T, s *store.Store, deviceID int, status models.DeviceStatus, latency *int64, at time.Time) *models.HealthCheck {
+	t.Helper()
+	check := &models.HealthCheck{DeviceID: deviceID, Status: status, Latency: latency, CheckedAt: at}
+	if err := s.HealthChecks.RecordHealthCheck(check); err != nil {
+		t.Fatalf("RecordHealthCheck: %v", err)
+	}
+	return check
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func testHealthProbeCRUD(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "web-1", 10, 1))
+
+	probe := &models.HealthProbe{
+		DeviceID: device.ID,
+		Name:     "ssh",
+		Type:     models.HealthProbeTypeTCP,
+		Config:   models.HealthProbeConfig{Ports: []int{22, 443}, TimeoutMs: 500},
+		Enabled:  true,
+	}
+	if err := s.HealthProbes.CreateHealthProbe(probe); err != nil {
+		t.Fatalf("CreateHealthProbe: %v", err)
+	}
+	if probe.ID == 0 || probe.CreatedAt.IsZero() || probe.UpdatedAt.IsZero() {
+		t.Fatalf("CreateHealthProbe did not fill in ID and timestamps: %+v", probe)
+	}
+
+	got, err := s.HealthProbes.GetHealthProbe(probe.ID)
+	if err != nil {
+		t.Fatalf("GetHealthProbe: %v", err)
+	}
+	if got.DeviceID != device.ID || got.Name != "ssh" || got.Type != models.HealthProbeTypeTCP || !got.Enabled ||
+		!reflect.DeepEqual(got.Config, probe.Config) {
+		t.Errorf("GetHealthProbe = %+v, want the created probe", got)
+	}
+
+	got.Name = "ssh-only"
+	got.Config.Ports = []int{22}
+	got.Enabled = false
+	if err := s.HealthProbes.UpdateHealthProbe(got); err != nil {
+		t.Fatalf("UpdateHealthProbe: %v", err)
+	}
+	got, err = s.HealthProbes.GetHealthProbe(probe.ID)
+	if err != nil {
+		t.Fatalf("GetHealthProbe after update: %v", err)
+	}
+	if got.Name != "ssh-only" || got.Enabled || !reflect.DeepEqual(got.Config.Ports, []int{22}) {
+		t.Errorf("GetHealthProbe after update = %+v", got)
+	}
+
+	if err := s.HealthProbes.DeleteHealthProbe(probe.ID); err != nil {
+		t.Fatalf("DeleteHealthProbe: %v", err)
+	}
+	if _, err := s.HealthProbes.GetHealthProbe(probe.ID); !errors.Is(err, store.ErrHealthProbeNotFound) {
+		t.Errorf("GetHealthProbe after delete: err = %v, want ErrHealthProbeNotFound", err)
+	}
+	if err := s.HealthProbes.DeleteHealthProbe(probe.ID); !errors.Is(err, store.ErrHealthProbeNotFound) {
+		t.Errorf("DeleteHealthProbe twice: err = %v, want ErrHealthProbeNotFound", err)
+	}
+}
+
+func testHealthProbeFilter(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	var ids []int
+	for _, probe := range []*models.HealthProbe{
+		{DeviceID: a.ID, Name: "tcp", Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{22}}, Enabled: true},
+		{DeviceID: a.ID, Name: "off", Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{80}}},
+		{DeviceID: b.ID, Name: "tcp", Type: models.HealthProbeTypeTCP, Config: models.HealthProbeConfig{Ports: []int{22}}, Enabled: true},
+	} {
+		if err := s.HealthProbes.CreateHealthProbe(probe); err != nil {
+			t.Fatalf("CreateHealthProbe: %v", err)
+		}
+		ids = append(ids, probe.ID)
+	}
+
+	probeIDs := func(filter store.HealthProbeFilter) []int {
+		t.Helper()
+		probes, err := s.HealthProbes.ListHealthProbes(filter)
+		if err != nil {
+			t.Fatalf("ListHealthProbes: %v", err)
+		}
+		got := []int{}
+		for _, probe := range probes {
+			got = append(got, probe.ID)
+		}
+		return got
+	}
+	if got, want := probeIDs(store.HealthProbeFilter{DeviceID: &a.ID}), []int{ids[0], ids[1]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListHealthProbes by device = %v, want %v", got, want)
+	}
+	if got, want := probeIDs(store.HealthProbeFilter{EnabledOnly: true}), []int{ids[0], ids[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListHealthProbes enabled = %v, want %v", got, want)
+	}
+
+	// Deleting a device deletes its probes
+	if err := s.Devices.DeleteDevice(a.ID); err != nil {
+		t.Fatalf("DeleteDevice: %v", err)
+	}
+	if got, want := probeIDs(store.HealthProbeFilter{}), []int{ids[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListHealthProbes after device delete = %v, want %v", got, want)
+	}
+}
+
+func testHealthCheckHistory(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	first := mustRecordHealthCheck(t, s, a.ID, models.DeviceStatusOnline, int64Ptr(10), start)
+	if first.ID == 0 {
+		t.Fatalf("RecordHealthCheck did not fill in the ID")
+	}
+	mustRecordHealthCheck(t, s, a.ID, models.DeviceStatusOffline, nil, start.Add(2*time.Minute))
+	third := mustRecordHealthCheck(t, s, a.ID, models.DeviceStatusOnline, int64Ptr(30), start.Add(time.Minute))
+	mustRecordHealthCheck(t, s, a.ID, models.DeviceStatusOnline, int64Ptr(5), start.Add(time.Hour))
+	mustRecordHealthCheck(t, s, b.ID, models.DeviceStatusOnline, nil, start)
+
+	checks, err := s.HealthChecks.ListHealthChecks(a.ID, start, start.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ListHealthChecks: %v", err)
+	}
+	var statuses []models.DeviceStatus
+	for _, check := range checks {
+		statuses = append(statuses, check.Status)
+	}
+	want := []models.DeviceStatus{models.DeviceStatusOnline, models.DeviceStatusOnline, models.DeviceStatusOffline}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("ListHealthChecks statuses = %v, want %v (oldest first, end excluded)", statuses, want)
+	}
+	if len(checks) > 1 && (checks[1].ID != third.ID || checks[1].Latency == nil || *checks[1].Latency != 30) {
+		t.Errorf("ListHealthChecks[1] = %+v, want the check recorded at +1m", checks[1])
+	}
+
+	checks, err = s.HealthChecks.ListHealthChecks(a.ID, start, start.Add(2*time.Hour), 2)
+	if err != nil {
+		t.Fatalf("ListHealthChecks with limit: %v", err)
+	}
+	if len(checks) != 2 || !checks[0].CheckedAt.Equal(start) {
+		t.Errorf("ListHealthChecks with limit = %+v, want the two oldest", checks)
+	}
+}
+
+func testHealthCheckSummary(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mustRecordHealthCheck(t, s, device.ID, models.DeviceStatusOnline, int64Ptr(10), start)
+	mustRecordHealthCheck(t, s, device.ID, models.DeviceStatusOnline, int64Ptr(30), start.Add(10*time.Minute))
+	mustRecordHealthCheck(t, s, device.ID, models.DeviceStatusOffline, nil, start.Add(20*time.Minute))
+	// The next hour is empty; the one after has a single check
+	mustRecordHealthCheck(t, s, device.ID, models.DeviceStatusWarning, int64Ptr(50), start.Add(2*time.Hour+5*time.Minute))
+
+	buckets, err := s.HealthChecks.SummarizeHealthChecks(device.ID, start, start.Add(3*time.Hour), 3600)
+	if err != nil {
+		t.Fatalf("SummarizeHealthChecks: %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("SummarizeHealthChecks = %d buckets, want 2: %+v", len(buckets), buckets)
+	}
+
+	first := buckets[0]
+	if !first.Start.Equal(start) || first.Checks != 3 || first.Online != 2 || first.Offline != 1 {
+		t.Errorf("first bucket = %+v, want 3 checks from %v", first, start)
+	}
+	if first.AvgLatency == nil || *first.AvgLatency != 20 || first.MinLatency == nil || *first.MinLatency != 10 ||
+		first.MaxLatency == nil || *first.MaxLatency != 30 {
+		t.Errorf("first bucket latency = %v/%v/%v, want 20/10/30", first.AvgLatency, first.MinLatency, first.MaxLatency)
+	}
+
+	second := buckets[1]
+	if !second.Start.Equal(start.Add(2*time.Hour)) || second.Checks != 1 || second.Warning != 1 {
+		t.Errorf("second bucket = %+v, want 1 warning from %v", second, start.Add(2*time.Hour))
+	}
+}
+
+func testPruneHealthChecks(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		mustRecordHealthCheck(t, s, device.ID, models.DeviceStatusOnline, nil, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	removed, err := s.HealthChecks.PruneHealthChecks(start.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("PruneHealthChecks: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("PruneHealthChecks removed %d, want 2", removed)
+	}
+	checks, err := s.HealthChecks.ListHealthChecks(device.ID, start, start.Add(3*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ListHealthChecks: %v", err)
+	}
+	if len(checks) != 1 || !checks[0].CheckedAt.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("checks after prune = %+v, want the newest only", checks)
+	}
+}
@@ -0,0 +1,429 @@
+package storetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func mustCreateInterface(t *testing.T, s *store.Store, deviceID int, name string) *models.DeviceInterface {
+	t.Helper()
+	iface := &models.DeviceInterface{DeviceID: deviceID, Name: name, Type: "ethernet", Enabled: true}
+	if err := s.Interfaces.CreateInterface(iface); err != nil {
+		t.Fatalf("CreateInterface(%s): %v", name, err)
+	}
+	return iface
+}
+
+func interfaceNames(interfaces []models.DeviceInterface) []string {
+	names := []string{}
+	for _, iface := range interfaces {
+		names = append(names, iface.Name)
+	}
+	return names
+}
+
+func cableLabels(cables []models.Cable) []string {
+	labels := []string{}
+	for _, cable := range cables {
+		labels = append(labels, cable.Label)
+	}
+	return labels
+}
+
+func testInterfaceCRUD(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "web-1", 10, 1))
+
+	iface := &models.DeviceInterface{DeviceID: device.ID, Name: "eth0", Type: "ethernet", Speed: "10G", MACAddress: "aa:bb:cc:dd:ee:ff", Enabled: true}
+	if err := s.Interfaces.CreateInterface(iface); err != nil {
+		t.Fatalf("CreateInterface: %v", err)
+	}
+	if iface.ID == 0 || iface.CreatedAt.IsZero() || iface.UpdatedAt.IsZero() {
+		t.Fatalf("CreateInterface did not fill in ID and timestamps: %+v", iface)
+	}
+
+	got, err := s.Interfaces.GetInterface(iface.ID)
+	if err != nil {
+		t.Fatalf("GetInterface: %v", err)
+	}
+	if got.DeviceID != device.ID || got.Name != "eth0" || got.Speed != "10G" || got.MACAddress != "aa:bb:cc:dd:ee:ff" ||
+		!got.Enabled || got.RearPortID != nil || got.FrontPortID != nil || got.ConnectionID != nil {
+		t.Errorf("GetInterface = %+v, want the created interface", got)
+	}
+
+	got.Name = "eth1"
+	got.Enabled = false
+	got.Description = "uplink"
+	if err := s.Interfaces.UpdateInterface(got); err != nil {
+		t.Fatalf("UpdateInterface: %v", err)
+	}
+	got, err = s.Interfaces.GetInterface(iface.ID)
+	if err != nil {
+		t.Fatalf("GetInterface after update: %v", err)
+	}
+	if got.Name != "eth1" || got.Enabled || got.Description != "uplink" || got.DeviceID != device.ID {
+		t.Errorf("GetInterface after update = %+v", got)
+	}
+
+	if err := s.Interfaces.DeleteInterface(iface.ID); err != nil {
+		t.Fatalf("DeleteInterface: %v", err)
+	}
+	if _, err := s.Interfaces.GetInterface(iface.ID); !errors.Is(err, store.ErrInterfaceNotFound) {
+		t.Errorf("GetInterface after delete: err = %v, want ErrInterfaceNotFound", err)
+	}
+}
+
+func testInterfaceFilter(t *testing.T, s *store.Store) {
+	a := mustCreateRack(t, s, "A")
+	b := mustCreateRack(t, s, "B")
+	inA := mustCreateDevice(t, s, newDevice(a.ID, "in-a", 10, 1))
+	inB := mustCreateDevice(t, s, newDevice(b.ID, "in-b", 10, 1))
+	mustCreateInterface(t, s, inA.ID, "eth1")
+	mustCreateInterface(t, s, inA.ID, "eth0")
+	mustCreateInterface(t, s, inB.ID, "mgmt")
+
+	interfaces, err := s.Interfaces.ListInterfaces(store.InterfaceFilter{DeviceID: &inA.ID})
+	if err != nil {
+		t.Fatalf("ListInterfaces by device: %v", err)
+	}
+	if got, want := interfaceNames(interfaces), []string{"eth0", "eth1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListInterfaces by device = %v, want %v", got, want)
+	}
+
+	interfaces, err = s.Interfaces.ListInterfaces(store.InterfaceFilter{RackID: &b.ID})
+	if err != nil {
+		t.Fatalf("ListInterfaces by rack: %v", err)
+	}
+	if got, want := interfaceNames(interfaces), []string{"mgmt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListInterfaces by rack = %v, want %v", got, want)
+	}
+}
+
+func testInterfaceNotFound(t *testing.T, s *store.Store) {
+	missing := 1 << 30
+	if _, err := s.Interfaces.GetInterface(missing); !errors.Is(err, store.ErrInterfaceNotFound) {
+		t.Errorf("GetInterface: err = %v, want ErrInterfaceNotFound", err)
+	}
+	if err := s.Interfaces.UpdateInterface(&models.DeviceInterface{ID: missing, Name: "eth0"}); !errors.Is(err, store.ErrInterfaceNotFound) {
+		t.Errorf("UpdateInterface: err = %v, want ErrInterfaceNotFound", err)
+	}
+	if err := s.Interfaces.DeleteInterface(missing); !errors.Is(err, store.ErrInterfaceNotFound) {
+		t.Errorf("DeleteInterface: err = %v, want ErrInterfaceNotFound", err)
+	}
+}
+
+func testInterfaceNamesAreUnique(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	mustCreateInterface(t, s, a.ID, "eth0")
+
+	if err := s.Interfaces.CreateInterface(&models.DeviceInterface{DeviceID: a.ID, Name: "eth0"}); err == nil {
+		t.Errorf("CreateInterface with a repeated name succeeded")
+	}
+	// Names are only unique per device
+	mustCreateInterface(t, s, b.ID, "eth0")
+}
+
+func testRearPortPairing(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	panel := mustCreateDevice(t, s, newDevice(rack.ID, "panel", 10, 1))
+	rear := mustCreateInterface(t, s, panel.ID, "rear1")
+	front := &models.DeviceInterface{DeviceID: panel.ID, Name: "front1", RearPortID: &rear.ID}
+	if err := s.Interfaces.CreateInterface(front); err != nil {
+		t.Fatalf("CreateInterface front port: %v", err)
+	}
+
+	got, err := s.Interfaces.GetInterface(rear.ID)
+	if err != nil {
+		t.Fatalf("GetInterface rear port: %v", err)
+	}
+	if got.FrontPortID == nil || *got.FrontPortID != front.ID {
+		t.Errorf("rear port FrontPortID = %v, want %d", got.FrontPortID, front.ID)
+	}
+
+	if err := s.Interfaces.CreateInterface(&models.DeviceInterface{DeviceID: panel.ID, Name: "front2", RearPortID: &rear.ID}); err == nil {
+		t.Errorf("pairing a rear port twice succeeded")
+	}
+
+	// Deleting the rear port unpairs its front port
+	if err := s.Interfaces.DeleteInterface(rear.ID); err != nil {
+		t.Fatalf("DeleteInterface rear port: %v", err)
+	}
+	got, err = s.Interfaces.GetInterface(front.ID)
+	if err != nil {
+		t.Fatalf("GetInterface front port: %v", err)
+	}
+	if got.RearPortID != nil {
+		t.Errorf("front port RearPortID after rear port delete = %d, want nil", *got.RearPortID)
+	}
+}
+
+func testDeleteInterfaceDetaches(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	eth0 := mustCreateInterface(t, s, a.ID, "eth0")
+	conn := &models.NetworkConnection{SourceDeviceID: a.ID, TargetDeviceID: b.ID, SourceInterfaceID: &eth0.ID, ConnectionType: "Ethernet"}
+	if err := s.Connections.CreateConnection(conn, nil); err != nil {
+		t.Fatalf("CreateConnection: %v", err)
+	}
+	address := &models.IPAddress{Address: "10.0.0.1", DeviceID: a.ID, InterfaceID: &eth0.ID}
+	if err := s.IPAM.CreateIPAddress(address); err != nil {
+		t.Fatalf("CreateIPAddress: %v", err)
+	}
+
+	got, err := s.Interfaces.GetInterface(eth0.ID)
+	if err != nil {
+		t.Fatalf("GetInterface: %v", err)
+	}
+	if got.ConnectionID == nil || *got.ConnectionID != conn.ID {
+		t.Errorf("GetInterface ConnectionID = %v, want %d", got.ConnectionID, conn.ID)
+	}
+
+	if err := s.Interfaces.DeleteInterface(eth0.ID); err != nil {
+		t.Fatalf("DeleteInterface: %v", err)
+	}
+	gotConn, err := s.Connections.GetConnection(conn.ID)
+	if err != nil {
+		t.Fatalf("GetConnection after interface delete: %v", err)
+	}
+	if gotConn.SourceInterfaceID != nil {
+		t.Errorf("connection SourceInterfaceID after interface delete = %d, want nil", *gotConn.SourceInterfaceID)
+	}
+	if _, err := s.IPAM.GetIPAddress(address.ID); !errors.Is(err, store.ErrIPAddressNotFound) {
+		t.Errorf("address of a deleted interface: err = %v, want ErrIPAddressNotFound", err)
+	}
+}
+
+func testCableCRUD(t *testing.T, s *store.Store) {
+	length := 2.5
+	cable := &models.Cable{Label: "C-001", Media: models.CableMediaCat6, LengthM: &length, Color: "blue", Status: models.CableStatusInstalled}
+	if err := s.Cables.CreateCable(cable); err != nil {
+		t.Fatalf("CreateCable: %v", err)
+	}
+	if cable.ID == 0 || cable.CreatedAt.IsZero() || cable.UpdatedAt.IsZero() {
+		t.Fatalf("CreateCable did not fill in ID and timestamps: %+v", cable)
+	}
+
+	got, err := s.Cables.GetCable(cable.ID)
+	if err != nil {
+		t.Fatalf("GetCable: %v", err)
+	}
+	if got.Label != "C-001" || got.Media != models.CableMediaCat6 || got.LengthM == nil || *got.LengthM != 2.5 ||
+		got.Color != "blue" || got.Status != models.CableStatusInstalled || got.ConnectionID != nil {
+		t.Errorf("GetCable = %+v, want the created cable", got)
+	}
+
+	got.Label = "C-002"
+	got.LengthM = nil
+	got.Status = models.CableStatusDecommissioning
+	if err := s.Cables.UpdateCable(got); err != nil {
+		t.Fatalf("UpdateCable: %v", err)
+	}
+	got, err = s.Cables.GetCable(cable.ID)
+	if err != nil {
+		t.Fatalf("GetCable after update: %v", err)
+	}
+	if got.Label != "C-002" || got.LengthM != nil || got.Status != models.CableStatusDecommissioning {
+		t.Errorf("GetCable after update = %+v", got)
+	}
+
+	if err := s.Cables.DeleteCable(cable.ID); err != nil {
+		t.Fatalf("DeleteCable: %v", err)
+	}
+	if _, err := s.Cables.GetCable(cable.ID); !errors.Is(err, store.ErrCableNotFound) {
+		t.Errorf("GetCable after delete: err = %v, want ErrCableNotFound", err)
+	}
+	if err := s.Cables.DeleteCable(cable.ID); !errors.Is(err, store.ErrCableNotFound) {
+		t.Errorf("DeleteCable twice: err = %v, want ErrCableNotFound", err)
+	}
+}
+
+func testCableFilter(t *testing.T, s *store.Store) {
+	for _, cable := range []*models.Cable{
+		{Label: "B-1", Media: models.CableMediaOM4, Status: models.CableStatusPlanned},
+		{Label: "A-1", Media: models.CableMediaCat6, Status: models.CableStatusInstalled},
+		{Label: "A-2", Media: models.CableMediaOM4, Status: models.CableStatusInstalled},
+	} {
+		if err := s.Cables.CreateCable(cable); err != nil {
+			t.Fatalf("CreateCable(%s): %v", cable.Label, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter models.CableFilter
+		want   []string
+	}{
+		{"all", models.CableFilter{}, []string{"A-1", "A-2", "B-1"}},
+		{"media", models.CableFilter{Media: models.CableMediaOM4}, []string{"A-2", "B-1"}},
+		{"status", models.CableFilter{Status: models.CableStatusInstalled}, []string{"A-1", "A-2"}},
+		{"label", models.CableFilter{Label: "a-"}, []string{"A-1", "A-2"}},
+	}
+	for _, tt := range tests {
+		cables, err := s.Cables.ListCables(tt.filter)
+		if err != nil {
+			t.Fatalf("ListCables %s: %v", tt.name, err)
+		}
+		if got := cableLabels(cables); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ListCables %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testCableLabelsAreUnique(t *testing.T, s *store.Store) {
+	first := &models.Cable{Label: "C-001", Status: models.CableStatusPlanned}
+	second := &models.Cable{Label: "C-002", Status: models.CableStatusPlanned}
+	for _, cable := range []*models.Cable{first, second} {
+		if err := s.Cables.CreateCable(cable); err != nil {
+			t.Fatalf("CreateCable(%s): %v", cable.Label, err)
+		}
+	}
+
+	if err := s.Cables.CreateCable(&models.Cable{Label: "C-001", Status: models.CableStatusPlanned}); !errors.Is(err, store.ErrCableLabelInUse) {
+		t.Errorf("CreateCable with a repeated label: err = %v, want ErrCableLabelInUse", err)
+	}
+	second.Label = "C-001"
+	if err := s.Cables.UpdateCable(second); !errors.Is(err, store.ErrCableLabelInUse) {
+		t.Errorf("UpdateCable to a repeated label: err = %v, want ErrCableLabelInUse", err)
+	}
+}
+
+func testConnectionWithCable(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+
+	conn := &models.NetworkConnection{SourceDeviceID: a.ID, TargetDeviceID: b.ID, ConnectionType: "Ethernet"}
+	cable := &models.Cable{Label: "C-001", Media: models.CableMediaCat6, Status: models.CableStatusPlanned}
+	if err := s.Connections.CreateConnection(conn, cable); err != nil {
+		t.Fatalf("CreateConnection with cable: %v", err)
+	}
+	if cable.ID == 0 || cable.CreatedAt.IsZero() {
+		t.Fatalf("CreateConnection did not fill in the cable: %+v", cable)
+	}
+	if conn.CableID == nil || *conn.CableID != cable.ID {
+		t.Errorf("connection CableID = %v, want %d", conn.CableID, cable.ID)
+	}
+
+	got, err := s.Cables.GetCable(cable.ID)
+	if err != nil {
+		t.Fatalf("GetCable: %v", err)
+	}
+	if got.ConnectionID == nil || *got.ConnectionID != conn.ID {
+		t.Errorf("cable ConnectionID = %v, want %d", got.ConnectionID, conn.ID)
+	}
+
+	// Deleting the cable keeps the connection without one
+	if err := s.Cables.DeleteCable(cable.ID); err != nil {
+		t.Fatalf("DeleteCable: %v", err)
+	}
+	gotConn, err := s.Connections.GetConnection(conn.ID)
+	if err != nil {
+		t.Fatalf("GetConnection after cable delete: %v", err)
+	}
+	if gotConn.CableID != nil {
+		t.Errorf("connection CableID after cable delete = %d, want nil", *gotConn.CableID)
+	}
+}
+
+func testConnectionWithCableIsAtomic(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	if err := s.Cables.CreateCable(&models.Cable{Label: "C-001", Status: models.CableStatusPlanned}); err != nil {
+		t.Fatalf("CreateCable: %v", err)
+	}
+
+	// A repeated label fails the cable and with it the connection
+	conn := &models.NetworkConnection{SourceDeviceID: a.ID, TargetDeviceID: b.ID, ConnectionType: "Ethernet"}
+	if err := s.Connections.CreateConnection(conn, &models.Cable{Label: "C-001", Status: models.CableStatusPlanned}); !errors.Is(err, store.ErrCableLabelInUse) {
+		t.Errorf("CreateConnection with a repeated cable label: err = %v, want ErrCableLabelInUse", err)
+	}
+
+	// A missing device fails the connection and with it the cable
+	bad := &models.NetworkConnection{SourceDeviceID: a.ID, TargetDeviceID: 1 << 30, ConnectionType: "Ethernet"}
+	if err := s.Connections.CreateConnection(bad, &models.Cable{Label: "C-002", Status: models.CableStatusPlanned}); err == nil {
+		t.Errorf("CreateConnection to a missing device succeeded")
+	}
+
+	connections, err := s.Connections.ListConnections(store.ConnectionFilter{})
+	if err != nil {
+		t.Fatalf("ListConnections: %v", err)
+	}
+	if len(connections) != 0 {
+		t.Errorf("connections after failed creates = %v, want none", connectionIDs(connections))
+	}
+	cables, err := s.Cables.ListCables(models.CableFilter{})
+	if err != nil {
+		t.Fatalf("ListCables: %v", err)
+	}
+	if got, want := cableLabels(cables), []string{"C-001"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cables after failed creates = %v, want %v", got, want)
+	}
+}
+
+func testDeviceWithInterfaces(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 1)
+	interfaces := []*models.DeviceInterface{
+		{Name: "eth0", Type: "ethernet", Speed: "25G", Enabled: true},
+		{Name: "idrac", Enabled: true},
+	}
+	if err := s.Devices.CreateDevice(device, interfaces, nil); err != nil {
+		t.Fatalf("CreateDevice with interfaces: %v", err)
+	}
+	for _, iface := range interfaces {
+		if iface.ID == 0 || iface.DeviceID != device.ID || iface.CreatedAt.IsZero() {
+			t.Errorf("CreateDevice did not fill in interface %s: %+v", iface.Name, iface)
+		}
+	}
+
+	got, err := s.Interfaces.ListInterfaces(store.InterfaceFilter{DeviceID: &device.ID})
+	if err != nil {
+		t.Fatalf("ListInterfaces: %v", err)
+	}
+	if names, want := interfaceNames(got), []string{"eth0", "idrac"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("interfaces of the new device = %v, want %v", names, want)
+	}
+	if len(got) > 0 && (got[0].Speed != "25G" || !got[0].Enabled) {
+		t.Errorf("interface eth0 = %+v, want the created interface", got[0])
+	}
+}
+
+func testDeviceWithInterfacesIsAtomic(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	mustCreateDevice(t, s, newDevice(rack.ID, "taken", 10, 1))
+
+	// A repeated interface name fails the interfaces and with them the device
+	repeated := []*models.DeviceInterface{{Name: "eth0"}, {Name: "eth0"}}
+	if err := s.Devices.CreateDevice(newDevice(rack.ID, "repeated", 20, 1), repeated, nil); err == nil {
+		t.Errorf("CreateDevice with repeated interface names succeeded")
+	}
+
+	// An overlap fails the device and with it the interfaces
+	overlapping := []*models.DeviceInterface{{Name: "eth0"}}
+	if err := s.Devices.CreateDevice(newDevice(rack.ID, "overlapping", 10, 1), overlapping, nil); !errors.Is(err, store.ErrDeviceOverlap) {
+		t.Errorf("CreateDevice overlapping: err = %v, want ErrDeviceOverlap", err)
+	}
+
+	devices, err := s.Devices.ListDevices(store.DeviceFilter{RackID: &rack.ID})
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if got, want := deviceNames(devices), []string{"taken"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("devices after failed creates = %v, want %v", got, want)
+	}
+	interfaces, err := s.Interfaces.ListInterfaces(store.InterfaceFilter{RackID: &rack.ID})
+	if err != nil {
+		t.Fatalf("ListInterfaces: %v", err)
+	}
+	if len(interfaces) != 0 {
+		t.Errorf("interfaces after failed creates = %v, want none", interfaceNames(interfaces))
+	}
+}
@@ -0,0 +1,259 @@
+package storetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func mustCreatePrefix(t *testing.T, s *store.Store, cidr string) *models.IPPrefix {
+	t.Helper()
+	prefix := &models.IPPrefix{Prefix: cidr}
+	if err := s.IPAM.CreatePrefix(prefix); err != nil {
+		t.Fatalf("CreatePrefix(%s): %v", cidr, err)
+	}
+	return prefix
+}
+
+func mustCreateIPAddress(t *testing.T, s *store.Store, deviceID int, address string) *models.IPAddress {
+	t.Helper()
+	ip := &models.IPAddress{Address: address, DeviceID: deviceID}
+	if err := s.IPAM.CreateIPAddress(ip); err != nil {
+		t.Fatalf("CreateIPAddress(%s): %v", address, err)
+	}
+	return ip
+}
+
+func prefixIDOf(t *testing.T, s *store.Store, id int) *int {
+	t.Helper()
+	address, err := s.IPAM.GetIPAddress(id)
+	if err != nil {
+		t.Fatalf("GetIPAddress: %v", err)
+	}
+	return address.PrefixID
+}
+
+func testPrefixCRUD(t *testing.T, s *store.Store) {
+	prefix := &models.IPPrefix{Prefix: "10.0.0.0/24", Description: "servers"}
+	if err := s.IPAM.CreatePrefix(prefix); err != nil {
+		t.Fatalf("CreatePrefix: %v", err)
+	}
+	if prefix.ID == 0 || prefix.CreatedAt.IsZero() || prefix.UpdatedAt.IsZero() {
+		t.Fatalf("CreatePrefix did not fill in ID and timestamps: %+v", prefix)
+	}
+
+	got, err := s.IPAM.GetPrefix(prefix.ID)
+	if err != nil {
+		t.Fatalf("GetPrefix: %v", err)
+	}
+	if got.Prefix != "10.0.0.0/24" || got.Description != "servers" {
+		t.Errorf("GetPrefix = %+v, want the created prefix", got)
+	}
+
+	got.Description = "web servers"
+	if err := s.IPAM.UpdatePrefix(got); err != nil {
+		t.Fatalf("UpdatePrefix: %v", err)
+	}
+	if got, err = s.IPAM.GetPrefix(prefix.ID); err != nil || got.Description != "web servers" {
+		t.Errorf("GetPrefix after update = %+v, %v", got, err)
+	}
+
+	if err := s.IPAM.DeletePrefix(prefix.ID); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if _, err := s.IPAM.GetPrefix(prefix.ID); !errors.Is(err, store.ErrPrefixNotFound) {
+		t.Errorf("GetPrefix after delete: err = %v, want ErrPrefixNotFound", err)
+	}
+	if err := s.IPAM.DeletePrefix(prefix.ID); !errors.Is(err, store.ErrPrefixNotFound) {
+		t.Errorf("DeletePrefix twice: err = %v, want ErrPrefixNotFound", err)
+	}
+}
+
+func testPrefixOrderAndUniqueness(t *testing.T, s *store.Store) {
+	for _, cidr := range []string{"10.0.10.0/24", "10.0.2.0/24", "10.0.0.0/16"} {
+		mustCreatePrefix(t, s, cidr)
+	}
+	if err := s.IPAM.CreatePrefix(&models.IPPrefix{Prefix: "10.0.2.0/24"}); !errors.Is(err, store.ErrPrefixExists) {
+		t.Errorf("CreatePrefix with a repeated prefix: err = %v, want ErrPrefixExists", err)
+	}
+
+	prefixes, err := s.IPAM.ListPrefixes()
+	if err != nil {
+		t.Fatalf("ListPrefixes: %v", err)
+	}
+	var got []string
+	for _, prefix := range prefixes {
+		got = append(got, prefix.Prefix)
+	}
+	if want := []string{"10.0.0.0/16", "10.0.2.0/24", "10.0.10.0/24"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListPrefixes = %v, want %v", got, want)
+	}
+}
+
+func testIPAddressCRUD(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "web-1", 10, 1))
+	eth0 := mustCreateInterface(t, s, device.ID, "eth0")
+
+	address := &models.IPAddress{Address: "10.0.0.5", DeviceID: device.ID, InterfaceID: &eth0.ID, Description: "primary"}
+	if err := s.IPAM.CreateIPAddress(address); err != nil {
+		t.Fatalf("CreateIPAddress: %v", err)
+	}
+	if address.ID == 0 || address.CreatedAt.IsZero() || address.PrefixID != nil {
+		t.Fatalf("CreateIPAddress = %+v, want an ID, timestamps and no prefix", address)
+	}
+
+	got, err := s.IPAM.GetIPAddress(address.ID)
+	if err != nil {
+		t.Fatalf("GetIPAddress: %v", err)
+	}
+	if got.Address != "10.0.0.5" || got.DeviceID != device.ID || got.InterfaceID == nil || *got.InterfaceID != eth0.ID || got.Description != "primary" {
+		t.Errorf("GetIPAddress = %+v, want the created address", got)
+	}
+
+	got.Address = "10.0.0.6"
+	got.InterfaceID = nil
+	if err := s.IPAM.UpdateIPAddress(got); err != nil {
+		t.Fatalf("UpdateIPAddress: %v", err)
+	}
+	got, err = s.IPAM.GetIPAddress(address.ID)
+	if err != nil {
+		t.Fatalf("GetIPAddress after update: %v", err)
+	}
+	if got.Address != "10.0.0.6" || got.InterfaceID != nil || got.DeviceID != device.ID {
+		t.Errorf("GetIPAddress after update = %+v", got)
+	}
+
+	if err := s.IPAM.DeleteIPAddress(address.ID); err != nil {
+		t.Fatalf("DeleteIPAddress: %v", err)
+	}
+	if _, err := s.IPAM.GetIPAddress(address.ID); !errors.Is(err, store.ErrIPAddressNotFound) {
+		t.Errorf("GetIPAddress after delete: err = %v, want ErrIPAddressNotFound", err)
+	}
+}
+
+func testIPAddressFilterAndUniqueness(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	mustCreateIPAddress(t, s, a.ID, "10.0.0.10")
+	mustCreateIPAddress(t, s, a.ID, "10.0.0.9")
+	mustCreateIPAddress(t, s, b.ID, "10.0.1.1")
+
+	if err := s.IPAM.CreateIPAddress(&models.IPAddress{Address: "10.0.0.9", DeviceID: b.ID}); !errors.Is(err, store.ErrIPAddressInUse) {
+		t.Errorf("CreateIPAddress with a repeated address: err = %v, want ErrIPAddressInUse", err)
+	}
+
+	addressesOf := func(filter models.IPAddressFilter) []string {
+		t.Helper()
+		addresses, err := s.IPAM.ListIPAddresses(filter)
+		if err != nil {
+			t.Fatalf("ListIPAddresses: %v", err)
+		}
+		got := []string{}
+		for _, address := range addresses {
+			got = append(got, address.Address)
+		}
+		return got
+	}
+	if got, want := addressesOf(models.IPAddressFilter{DeviceID: &a.ID}), []string{"10.0.0.9", "10.0.0.10"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListIPAddresses by device = %v, want %v", got, want)
+	}
+	if got, want := addressesOf(models.IPAddressFilter{Address: "10.0.1.1"}), []string{"10.0.1.1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListIPAddresses by address = %v, want %v", got, want)
+	}
+}
+
+func testPrefixAssignment(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "web-1", 10, 1))
+	wide := mustCreatePrefix(t, s, "10.0.0.0/16")
+	address := mustCreateIPAddress(t, s, device.ID, "10.0.1.5")
+	if address.PrefixID == nil || *address.PrefixID != wide.ID {
+		t.Fatalf("CreateIPAddress PrefixID = %v, want %d", address.PrefixID, wide.ID)
+	}
+
+	// A more specific prefix takes the address over
+	narrow := mustCreatePrefix(t, s, "10.0.1.0/24")
+	if got := prefixIDOf(t, s, address.ID); got == nil || *got != narrow.ID {
+		t.Errorf("PrefixID after a narrower prefix is created = %v, want %d", got, narrow.ID)
+	}
+	addresses, err := s.IPAM.ListIPAddresses(models.IPAddressFilter{PrefixID: &narrow.ID})
+	if err != nil {
+		t.Fatalf("ListIPAddresses by prefix: %v", err)
+	}
+	if len(addresses) != 1 || addresses[0].ID != address.ID {
+		t.Errorf("ListIPAddresses by prefix = %+v, want the address", addresses)
+	}
+
+	// Moving the prefix away gives the address back
+	narrow.Prefix = "10.0.2.0/24"
+	if err := s.IPAM.UpdatePrefix(narrow); err != nil {
+		t.Fatalf("UpdatePrefix: %v", err)
+	}
+	if got := prefixIDOf(t, s, address.ID); got == nil || *got != wide.ID {
+		t.Errorf("PrefixID after the narrower prefix moves = %v, want %d", got, wide.ID)
+	}
+
+	// Deleting the most specific prefix hands the address to the next one
+	narrow.Prefix = "10.0.1.0/24"
+	if err := s.IPAM.UpdatePrefix(narrow); err != nil {
+		t.Fatalf("UpdatePrefix back: %v", err)
+	}
+	if err := s.IPAM.DeletePrefix(narrow.ID); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if got := prefixIDOf(t, s, address.ID); got == nil || *got != wide.ID {
+		t.Errorf("PrefixID after the narrower prefix is deleted = %v, want %d", got, wide.ID)
+	}
+
+	if err := s.IPAM.DeletePrefix(wide.ID); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if got := prefixIDOf(t, s, address.ID); got != nil {
+		t.Errorf("PrefixID after its prefix is deleted = %d, want nil", *got)
+	}
+}
+
+func testDeleteDeviceDeletesAddresses(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "web-1", 10, 1))
+	address := mustCreateIPAddress(t, s, device.ID, "10.0.0.5")
+
+	if err := s.Devices.DeleteDevice(device.ID); err != nil {
+		t.Fatalf("DeleteDevice: %v", err)
+	}
+	if _, err := s.IPAM.GetIPAddress(address.ID); !errors.Is(err, store.ErrIPAddressNotFound) {
+		t.Errorf("address of a deleted device: err = %v, want ErrIPAddressNotFound", err)
+	}
+}
+
+func testDeviceIPAddressesAreUnique(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := newDevice(rack.ID, "a", 10, 1)
+	a.IPAddress = "10.0.0.1"
+	mustCreateDevice(t, s, a)
+
+	b := newDevice(rack.ID, "b", 20, 1)
+	b.IPAddress = "10.0.0.1"
+	if err := s.Devices.CreateDevice(b, nil, nil); !errors.Is(err, store.ErrIPAddressInUse) {
+		t.Errorf("CreateDevice with a repeated ip address: err = %v, want ErrIPAddressInUse", err)
+	}
+
+	b.IPAddress = "10.0.0.2"
+	mustCreateDevice(t, s, b)
+	b.IPAddress = "10.0.0.1"
+	if err := s.Devices.UpdateDevice(b, nil); !errors.Is(err, store.ErrIPAddressInUse) {
+		t.Errorf("UpdateDevice to a repeated ip address: err = %v, want ErrIPAddressInUse", err)
+	}
+
+	// Any number of devices may have no address, and a device keeps its own
+	mustCreateDevice(t, s, newDevice(rack.ID, "c", 30, 1))
+	mustCreateDevice(t, s, newDevice(rack.ID, "d", 31, 1))
+	if err := s.Devices.UpdateDevice(a, nil); err != nil {
+		t.Errorf("UpdateDevice keeping its ip address: %v", err)
+	}
+}
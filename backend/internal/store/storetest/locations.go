@@ -0,0 +1,342 @@
+package storetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func mustCreateSite(t *testing.T, s *store.Store, name string) *models.Site {
+	t.Helper()
+	site := &models.Site{Name: name}
+	if err := s.Locations.CreateSite(site); err != nil {
+		t.Fatalf("CreateSite(%s): %v", name, err)
+	}
+	return site
+}
+
+func mustCreateRoom(t *testing.T, s *store.Store, siteID int, name string) *models.Room {
+	t.Helper()
+	room := &models.Room{SiteID: siteID, Name: name}
+	if err := s.Locations.CreateRoom(room); err != nil {
+		t.Fatalf("CreateRoom(%s): %v", name, err)
+	}
+	return room
+}
+
+func mustCreateRow(t *testing.T, s *store.Store, roomID int, name string) *models.Row {
+	t.Helper()
+	row := &models.Row{RoomID: roomID, Name: name}
+	if err := s.Locations.CreateRow(row); err != nil {
+		t.Fatalf("CreateRow(%s): %v", name, err)
+	}
+	return row
+}
+
+// mustCreateLocatedRack creates a rack in a row of a room of a site
+func mustCreateLocatedRack(t *testing.T, s *store.Store, name string, site *models.Site, room *models.Room, row *models.Row) *models.Rack {
+	t.Helper()
+	rack := &models.Rack{Name: name, SizeU: 42, SiteID: &site.ID, RoomID: &room.ID, RowID: &row.ID}
+	if err := s.Racks.CreateRack(rack); err != nil {
+		t.Fatalf("CreateRack(%s): %v", name, err)
+	}
+	return rack
+}
+
+func rowNames(rows []models.Row) []string {
+	names := []string{}
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+	return names
+}
+
+func testLocationCRUD(t *testing.T, s *store.Store) {
+	site := &models.Site{Name: "DC1", Description: "primary", Address: "1 Main St"}
+	if err := s.Locations.CreateSite(site); err != nil {
+		t.Fatalf("CreateSite: %v", err)
+	}
+	if site.ID == 0 || site.CreatedAt.IsZero() || site.UpdatedAt.IsZero() {
+		t.Fatalf("CreateSite did not fill in ID and timestamps: %+v", site)
+	}
+	room := mustCreateRoom(t, s, site.ID, "Hall A")
+	row := mustCreateRow(t, s, room.ID, "Row 1")
+
+	gotSite, err := s.Locations.GetSite(site.ID)
+	if err != nil {
+		t.Fatalf("GetSite: %v", err)
+	}
+	if gotSite.Name != "DC1" || gotSite.Description != "primary" || gotSite.Address != "1 Main St" {
+		t.Errorf("GetSite = %+v, want the created site", gotSite)
+	}
+	gotSite.Address = "2 Main St"
+	if err := s.Locations.UpdateSite(gotSite); err != nil {
+		t.Fatalf("UpdateSite: %v", err)
+	}
+	if gotSite, err = s.Locations.GetSite(site.ID); err != nil || gotSite.Address != "2 Main St" {
+		t.Errorf("GetSite after update = %+v, %v", gotSite, err)
+	}
+
+	gotRoom, err := s.Locations.GetRoom(room.ID)
+	if err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+	if gotRoom.SiteID != site.ID || gotRoom.Name != "Hall A" {
+		t.Errorf("GetRoom = %+v, want the created room", gotRoom)
+	}
+
+	gotRow, err := s.Locations.GetRow(row.ID)
+	if err != nil {
+		t.Fatalf("GetRow: %v", err)
+	}
+	if gotRow.RoomID != room.ID || gotRow.Name != "Row 1" {
+		t.Errorf("GetRow = %+v, want the created row", gotRow)
+	}
+
+	if err := s.Locations.DeleteRow(row.ID); err != nil {
+		t.Fatalf("DeleteRow: %v", err)
+	}
+	if _, err := s.Locations.GetRow(row.ID); !errors.Is(err, store.ErrRowNotFound) {
+		t.Errorf("GetRow after delete: err = %v, want ErrRowNotFound", err)
+	}
+}
+
+func testLocationNotFound(t *testing.T, s *store.Store) {
+	missing := 1 << 30
+	if _, err := s.Locations.GetSite(missing); !errors.Is(err, store.ErrSiteNotFound) {
+		t.Errorf("GetSite: err = %v, want ErrSiteNotFound", err)
+	}
+	if err := s.Locations.DeleteSite(missing); !errors.Is(err, store.ErrSiteNotFound) {
+		t.Errorf("DeleteSite: err = %v, want ErrSiteNotFound", err)
+	}
+	if _, err := s.Locations.GetRoom(missing); !errors.Is(err, store.ErrRoomNotFound) {
+		t.Errorf("GetRoom: err = %v, want ErrRoomNotFound", err)
+	}
+	if err := s.Locations.UpdateRoom(&models.Room{ID: missing, Name: "x"}); !errors.Is(err, store.ErrRoomNotFound) {
+		t.Errorf("UpdateRoom: err = %v, want ErrRoomNotFound", err)
+	}
+	if _, err := s.Locations.GetRow(missing); !errors.Is(err, store.ErrRowNotFound) {
+		t.Errorf("GetRow: err = %v, want ErrRowNotFound", err)
+	}
+	if err := s.Locations.DeleteRow(missing); !errors.Is(err, store.ErrRowNotFound) {
+		t.Errorf("DeleteRow: err = %v, want ErrRowNotFound", err)
+	}
+}
+
+func testLocationNamesAreUnique(t *testing.T, s *store.Store) {
+	dc1 := mustCreateSite(t, s, "DC1")
+	dc2 := mustCreateSite(t, s, "DC2")
+	mustCreateRoom(t, s, dc1.ID, "Hall A")
+
+	if err := s.Locations.CreateSite(&models.Site{Name: "DC1"}); err == nil {
+		t.Errorf("CreateSite with a repeated name succeeded")
+	}
+	if err := s.Locations.CreateRoom(&models.Room{SiteID: dc1.ID, Name: "Hall A"}); err == nil {
+		t.Errorf("CreateRoom with a repeated name in the site succeeded")
+	}
+	// Room names are only unique within a site
+	mustCreateRoom(t, s, dc2.ID, "Hall A")
+}
+
+func testRowFilter(t *testing.T, s *store.Store) {
+	dc1 := mustCreateSite(t, s, "DC1")
+	dc2 := mustCreateSite(t, s, "DC2")
+	hallA := mustCreateRoom(t, s, dc1.ID, "Hall A")
+	hallB := mustCreateRoom(t, s, dc1.ID, "Hall B")
+	other := mustCreateRoom(t, s, dc2.ID, "Hall A")
+	mustCreateRow(t, s, hallB.ID, "B2")
+	mustCreateRow(t, s, hallA.ID, "A1")
+	mustCreateRow(t, s, other.ID, "X1")
+
+	rows, err := s.Locations.ListRows(store.RowFilter{SiteID: &dc1.ID})
+	if err != nil {
+		t.Fatalf("ListRows by site: %v", err)
+	}
+	if got, want := rowNames(rows), []string{"A1", "B2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRows by site = %v, want %v", got, want)
+	}
+
+	rows, err = s.Locations.ListRows(store.RowFilter{RoomID: &hallB.ID})
+	if err != nil {
+		t.Fatalf("ListRows by room: %v", err)
+	}
+	if got, want := rowNames(rows), []string{"B2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRows by room = %v, want %v", got, want)
+	}
+}
+
+func testDeleteSiteCascades(t *testing.T, s *store.Store) {
+	site := mustCreateSite(t, s, "DC1")
+	room := mustCreateRoom(t, s, site.ID, "Hall A")
+	row := mustCreateRow(t, s, room.ID, "Row 1")
+	rack := mustCreateLocatedRack(t, s, "A", site, room, row)
+
+	if err := s.Locations.DeleteSite(site.ID); err != nil {
+		t.Fatalf("DeleteSite: %v", err)
+	}
+	if _, err := s.Locations.GetRoom(room.ID); !errors.Is(err, store.ErrRoomNotFound) {
+		t.Errorf("room of a deleted site: err = %v, want ErrRoomNotFound", err)
+	}
+	if _, err := s.Locations.GetRow(row.ID); !errors.Is(err, store.ErrRowNotFound) {
+		t.Errorf("row of a deleted site: err = %v, want ErrRowNotFound", err)
+	}
+	got, err := s.Racks.GetRack(rack.ID)
+	if err != nil {
+		t.Fatalf("rack of a deleted site: %v", err)
+	}
+	if got.SiteID != nil || got.RoomID != nil || got.RowID != nil {
+		t.Errorf("rack location after site delete = %v/%v/%v, want none", got.SiteID, got.RoomID, got.RowID)
+	}
+}
+
+func testDeleteRoomKeepsSite(t *testing.T, s *store.Store) {
+	site := mustCreateSite(t, s, "DC1")
+	room := mustCreateRoom(t, s, site.ID, "Hall A")
+	row := mustCreateRow(t, s, room.ID, "Row 1")
+	rack := mustCreateLocatedRack(t, s, "A", site, room, row)
+
+	if err := s.Locations.DeleteRoom(room.ID); err != nil {
+		t.Fatalf("DeleteRoom: %v", err)
+	}
+	if _, err := s.Locations.GetRow(row.ID); !errors.Is(err, store.ErrRowNotFound) {
+		t.Errorf("row of a deleted room: err = %v, want ErrRowNotFound", err)
+	}
+	got, err := s.Racks.GetRack(rack.ID)
+	if err != nil {
+		t.Fatalf("rack of a deleted room: %v", err)
+	}
+	if got.SiteID == nil || *got.SiteID != site.ID || got.RoomID != nil || got.RowID != nil {
+		t.Errorf("rack location after room delete = %v/%v/%v, want site %d only", got.SiteID, got.RoomID, got.RowID, site.ID)
+	}
+}
+
+func testMoveRoomMovesRacks(t *testing.T, s *store.Store) {
+	dc1 := mustCreateSite(t, s, "DC1")
+	dc2 := mustCreateSite(t, s, "DC2")
+	room := mustCreateRoom(t, s, dc1.ID, "Hall A")
+	row := mustCreateRow(t, s, room.ID, "Row 1")
+	rack := mustCreateLocatedRack(t, s, "A", dc1, room, row)
+
+	room.SiteID = dc2.ID
+	if err := s.Locations.UpdateRoom(room); err != nil {
+		t.Fatalf("UpdateRoom: %v", err)
+	}
+	got, err := s.Racks.GetRack(rack.ID)
+	if err != nil {
+		t.Fatalf("GetRack: %v", err)
+	}
+	if got.SiteID == nil || *got.SiteID != dc2.ID || got.RoomID == nil || *got.RoomID != room.ID {
+		t.Errorf("rack location after room move = %v/%v, want site %d room %d", got.SiteID, got.RoomID, dc2.ID, room.ID)
+	}
+
+	racks, err := s.Racks.ListRacks(models.RackFilter{SiteID: &dc1.ID})
+	if err != nil {
+		t.Fatalf("ListRacks: %v", err)
+	}
+	if len(racks) != 0 {
+		t.Errorf("racks left in the old site = %d, want none", len(racks))
+	}
+}
+
+func testDeviceTypeCRUD(t *testing.T, s *store.Store) {
+	deviceType := &models.DeviceTypeTemplate{
+		Manufacturer:  "Dell",
+		Model:         "R650",
+		Type:          models.DeviceTypeServer,
+		SizeU:         1,
+		Specs:         map[string]string{"cpu": "2x Xeon"},
+		PSUCount:      2,
+		PSURedundancy: models.PSURedundancyNone,
+		Ports:         []models.PortTemplate{{Name: "eth0", Type: "ethernet", Speed: "25G"}, {Name: "idrac"}},
+	}
+	if err := s.DeviceTypes.CreateDeviceType(deviceType); err != nil {
+		t.Fatalf("CreateDeviceType: %v", err)
+	}
+	if deviceType.ID == 0 || deviceType.CreatedAt.IsZero() || deviceType.UpdatedAt.IsZero() {
+		t.Fatalf("CreateDeviceType did not fill in ID and timestamps: %+v", deviceType)
+	}
+
+	got, err := s.DeviceTypes.GetDeviceType(deviceType.ID)
+	if err != nil {
+		t.Fatalf("GetDeviceType: %v", err)
+	}
+	if got.Manufacturer != "Dell" || got.Model != "R650" || got.SizeU != 1 || got.PSUCount != 2 ||
+		!reflect.DeepEqual(got.Specs, deviceType.Specs) || !reflect.DeepEqual(got.Ports, deviceType.Ports) {
+		t.Errorf("GetDeviceType = %+v, want the created device type", got)
+	}
+
+	got.Model = "R660"
+	got.Ports = []models.PortTemplate{{Name: "eth0"}}
+	if err := s.DeviceTypes.UpdateDeviceType(got); err != nil {
+		t.Fatalf("UpdateDeviceType: %v", err)
+	}
+	got, err = s.DeviceTypes.GetDeviceType(deviceType.ID)
+	if err != nil {
+		t.Fatalf("GetDeviceType after update: %v", err)
+	}
+	if got.Model != "R660" || !reflect.DeepEqual(got.Ports, []models.PortTemplate{{Name: "eth0"}}) {
+		t.Errorf("GetDeviceType after update = %+v", got)
+	}
+
+	if err := s.DeviceTypes.DeleteDeviceType(deviceType.ID); err != nil {
+		t.Fatalf("DeleteDeviceType: %v", err)
+	}
+	if _, err := s.DeviceTypes.GetDeviceType(deviceType.ID); !errors.Is(err, store.ErrDeviceTypeNotFound) {
+		t.Errorf("GetDeviceType after delete: err = %v, want ErrDeviceTypeNotFound", err)
+	}
+	if err := s.DeviceTypes.DeleteDeviceType(deviceType.ID); !errors.Is(err, store.ErrDeviceTypeNotFound) {
+		t.Errorf("DeleteDeviceType twice: err = %v, want ErrDeviceTypeNotFound", err)
+	}
+}
+
+func testDeviceTypeModelsAreUnique(t *testing.T, s *store.Store) {
+	for _, deviceType := range []*models.DeviceTypeTemplate{
+		{Manufacturer: "Dell", Model: "R650", Type: models.DeviceTypeServer, PSURedundancy: models.PSURedundancyNone},
+		{Manufacturer: "HPE", Model: "R650", Type: models.DeviceTypeServer, PSURedundancy: models.PSURedundancyNone},
+	} {
+		if err := s.DeviceTypes.CreateDeviceType(deviceType); err != nil {
+			t.Fatalf("CreateDeviceType(%s %s): %v", deviceType.Manufacturer, deviceType.Model, err)
+		}
+	}
+	repeated := &models.DeviceTypeTemplate{Manufacturer: "Dell", Model: "R650", Type: models.DeviceTypeServer, PSURedundancy: models.PSURedundancyNone}
+	if err := s.DeviceTypes.CreateDeviceType(repeated); err == nil {
+		t.Errorf("CreateDeviceType with a repeated manufacturer and model succeeded")
+	}
+
+	deviceTypes, err := s.DeviceTypes.ListDeviceTypes()
+	if err != nil {
+		t.Fatalf("ListDeviceTypes: %v", err)
+	}
+	var got []string
+	for _, deviceType := range deviceTypes {
+		got = append(got, deviceType.Manufacturer)
+	}
+	if want := []string{"Dell", "HPE"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDeviceTypes manufacturers = %v, want %v", got, want)
+	}
+}
+
+func testDeleteDeviceTypeUnlinksDevices(t *testing.T, s *store.Store) {
+	deviceType := &models.DeviceTypeTemplate{Manufacturer: "Dell", Model: "R650", Type: models.DeviceTypeServer, PSURedundancy: models.PSURedundancyNone}
+	if err := s.DeviceTypes.CreateDeviceType(deviceType); err != nil {
+		t.Fatalf("CreateDeviceType: %v", err)
+	}
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 1)
+	device.DeviceTypeID = &deviceType.ID
+	mustCreateDevice(t, s, device)
+
+	if err := s.DeviceTypes.DeleteDeviceType(deviceType.ID); err != nil {
+		t.Fatalf("DeleteDeviceType: %v", err)
+	}
+	got, err := s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("device of a deleted device type: %v", err)
+	}
+	if got.DeviceTypeID != nil {
+		t.Errorf("DeviceTypeID after device type delete = %d, want nil", *got.DeviceTypeID)
+	}
+}
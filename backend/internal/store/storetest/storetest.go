@@ -0,0 +1,823 @@
+// Package storetest is the conformance suite every store implementation must pass.
+//
+// An implementation's tests call Run with a function returning an empty store, e.g.
+//
+//	func TestMemoryStore(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) *store.Store { return store.NewMemoryStore() })
+//	}
+package storetest
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+// Factory returns an empty store for one subtest
+type Factory func(t *testing.T) *store.Store
+
+// Run runs the conformance suite, with a fresh store for every subtest
+func Run(t *testing.T, newStore Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s *store.Store)
+	}{
+		{"RackCRUD", testRackCRUD},
+		{"RackFilter", testRackFilter},
+		{"RackNotFound", testRackNotFound},
+		{"DeviceCRUD", testDeviceCRUD},
+		{"DeviceSpecs", testDeviceSpecs},
+		{"DeviceOrder", testDeviceOrder},
+		{"DeviceChildren", testDeviceChildren},
+		{"DeviceStatus", testDeviceStatus},
+		{"DeviceNotFound", testDeviceNotFound},
+		{"DeviceRequiresRack", testDeviceRequiresRack},
+		{"PlacementCheck", testPlacementCheck},
+		{"PlacementCheckIsExclusive", testPlacementCheckIsExclusive},
+		{"MoveChassisMovesChildren", testMoveChassisMovesChildren},
+		{"DeviceOverlap", testDeviceOverlap},
+		{"ConnectionCRUD", testConnectionCRUD},
+		{"ConnectionFilter", testConnectionFilter},
+		{"ConnectionNotFound", testConnectionNotFound},
+		{"ConnectionRequiresDevices", testConnectionRequiresDevices},
+		{"DeleteRackCascades", testDeleteRackCascades},
+		{"DeleteDeviceCascades", testDeleteDeviceCascades},
+		{"ReturnedValuesAreCopies", testReturnedValuesAreCopies},
+		{"InterfaceCRUD", testInterfaceCRUD},
+		{"InterfaceFilter", testInterfaceFilter},
+		{"InterfaceNotFound", testInterfaceNotFound},
+		{"InterfaceNamesAreUnique", testInterfaceNamesAreUnique},
+		{"RearPortPairing", testRearPortPairing},
+		{"DeleteInterfaceDetaches", testDeleteInterfaceDetaches},
+		{"DeviceWithInterfaces", testDeviceWithInterfaces},
+		{"DeviceWithInterfacesIsAtomic", testDeviceWithInterfacesIsAtomic},
+		{"CableCRUD", testCableCRUD},
+		{"CableFilter", testCableFilter},
+		{"CableLabelsAreUnique", testCableLabelsAreUnique},
+		{"ConnectionWithCable", testConnectionWithCable},
+		{"ConnectionWithCableIsAtomic", testConnectionWithCableIsAtomic},
+		{"DeviceTypeCRUD", testDeviceTypeCRUD},
+		{"DeviceTypeModelsAreUnique", testDeviceTypeModelsAreUnique},
+		{"DeleteDeviceTypeUnlinksDevices", testDeleteDeviceTypeUnlinksDevices},
+		{"LocationCRUD", testLocationCRUD},
+		{"LocationNotFound", testLocationNotFound},
+		{"LocationNamesAreUnique", testLocationNamesAreUnique},
+		{"RowFilter", testRowFilter},
+		{"DeleteSiteCascades", testDeleteSiteCascades},
+		{"DeleteRoomKeepsSite", testDeleteRoomKeepsSite},
+		{"MoveRoomMovesRacks", testMoveRoomMovesRacks},
+		{"PrefixCRUD", testPrefixCRUD},
+		{"PrefixOrderAndUniqueness", testPrefixOrderAndUniqueness},
+		{"IPAddressCRUD", testIPAddressCRUD},
+		{"IPAddressFilterAndUniqueness", testIPAddressFilterAndUniqueness},
+		{"PrefixAssignment", testPrefixAssignment},
+		{"DeleteDeviceDeletesAddresses", testDeleteDeviceDeletesAddresses},
+		{"DeviceIPAddressesAreUnique", testDeviceIPAddressesAreUnique},
+		{"HealthProbeCRUD", testHealthProbeCRUD},
+		{"HealthProbeFilter", testHealthProbeFilter},
+		{"HealthCheckHistory", testHealthCheckHistory},
+		{"HealthCheckSummary", testHealthCheckSummary},
+		{"PruneHealthChecks", testPruneHealthChecks},
+		{"LatestHealthChecks", testLatestHealthChecks},
+		{"VLANGroupCRUD", testVLANGroupCRUD},
+		{"VLANCRUD", testVLANCRUD},
+		{"VLANScopeIsUnique", testVLANScopeIsUnique},
+		{"MoveVLANGroupMovesVLANs", testMoveVLANGroupMovesVLANs},
+		{"DeleteVLANCascades", testDeleteVLANCascades},
+		{"VLANTagging", testVLANTagging},
+		{"WebhookCRUD", testWebhookCRUD},
+		{"WebhookDeliveries", testWebhookDeliveries},
+		{"AlertState", testAlertState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t))
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func mustCreateRack(t *testing.T, s *store.Store, name string) *models.Rack {
+	t.Helper()
+	rack := &models.Rack{Name: name, Description: "test rack", SizeU: 42}
+	if err := s.Racks.CreateRack(rack); err != nil {
+		t.Fatalf("CreateRack(%s): %v", name, err)
+	}
+	return rack
+}
+
+func newDevice(rackID int, name string, positionU, sizeU int) *models.Device {
+	return &models.Device{
+		RackID:          rackID,
+		Name:            name,
+		Icon:            "🖥️",
+		Type:            models.DeviceTypeServer,
+		PositionU:       positionU,
+		SizeU:           sizeU,
+		MountFace:       models.MountFaceFront,
+		Depth:           models.DeviceDepthFull,
+		Status:          models.DeviceStatusOnline,
+		HealthCheckMode: models.HealthCheckModeAll,
+		PSURedundancy:   models.PSURedundancyNone,
+	}
+}
+
+func mustCreateDevice(t *testing.T, s *store.Store, device *models.Device) *models.Device {
+	t.Helper()
+	if err := s.Devices.CreateDevice(device, nil, nil); err != nil {
+		t.Fatalf("CreateDevice(%s): %v", device.Name, err)
+	}
+	return device
+}
+
+func mustCreateConnection(t *testing.T, s *store.Store, sourceID, targetID int) *models.NetworkConnection {
+	t.Helper()
+	conn := &models.NetworkConnection{SourceDeviceID: sourceID, TargetDeviceID: targetID, ConnectionType: "Ethernet", PortInfo: "eth0"}
+	if err := s.Connections.CreateConnection(conn, nil); err != nil {
+		t.Fatalf("CreateConnection(%d, %d): %v", sourceID, targetID, err)
+	}
+	return conn
+}
+
+func deviceNames(devices []models.Device) []string {
+	names := []string{}
+	for _, device := range devices {
+		names = append(names, device.Name)
+	}
+	return names
+}
+
+func connectionIDs(connections []models.NetworkConnection) []int {
+	ids := []int{}
+	for _, conn := range connections {
+		ids = append(ids, conn.ID)
+	}
+	return ids
+}
+
+func testRackCRUD(t *testing.T, s *store.Store) {
+	rack := &models.Rack{Name: "A1", Description: "first", SizeU: 42, PowerCapacityWatts: 5000}
+	if err := s.Racks.CreateRack(rack); err != nil {
+		t.Fatalf("CreateRack: %v", err)
+	}
+	if rack.ID == 0 || rack.CreatedAt.IsZero() {
+		t.Fatalf("CreateRack did not fill in ID and timestamps: %+v", rack)
+	}
+
+	got, err := s.Racks.GetRack(rack.ID)
+	if err != nil {
+		t.Fatalf("GetRack: %v", err)
+	}
+	if got.Name != "A1" || got.Description != "first" || got.SizeU != 42 || got.PowerCapacityWatts != 5000 {
+		t.Errorf("GetRack = %+v, want the created rack", got)
+	}
+
+	got.Name = "A1-renamed"
+	got.SizeU = 48
+	got.PowerCapacityWatts = 0
+	if err := s.Racks.UpdateRack(got); err != nil {
+		t.Fatalf("UpdateRack: %v", err)
+	}
+	got, err = s.Racks.GetRack(rack.ID)
+	if err != nil {
+		t.Fatalf("GetRack after update: %v", err)
+	}
+	if got.Name != "A1-renamed" || got.SizeU != 48 || got.PowerCapacityWatts != 0 {
+		t.Errorf("GetRack after update = %+v", got)
+	}
+
+	if err := s.Racks.DeleteRack(rack.ID); err != nil {
+		t.Fatalf("DeleteRack: %v", err)
+	}
+	if _, err := s.Racks.GetRack(rack.ID); !errors.Is(err, store.ErrRackNotFound) {
+		t.Errorf("GetRack after delete: err = %v, want ErrRackNotFound", err)
+	}
+}
+
+func testRackFilter(t *testing.T, s *store.Store) {
+	a := mustCreateRack(t, s, "A")
+	b := mustCreateRack(t, s, "B")
+	mustCreateRack(t, s, "C")
+
+	racks, err := s.Racks.ListRacks(models.RackFilter{})
+	if err != nil {
+		t.Fatalf("ListRacks: %v", err)
+	}
+	if len(racks) != 3 || racks[0].ID != a.ID || racks[1].ID != b.ID {
+		t.Errorf("ListRacks = %+v, want 3 racks ordered by ID", racks)
+	}
+
+	// Racks without a location never match a location filter
+	racks, err = s.Racks.ListRacks(models.RackFilter{SiteID: intPtr(1 << 30)})
+	if err != nil {
+		t.Fatalf("ListRacks with filter: %v", err)
+	}
+	if len(racks) != 0 {
+		t.Errorf("ListRacks with unknown site = %+v, want none", racks)
+	}
+}
+
+func testRackNotFound(t *testing.T, s *store.Store) {
+	missing := 1 << 30
+	if _, err := s.Racks.GetRack(missing); !errors.Is(err, store.ErrRackNotFound) {
+		t.Errorf("GetRack: err = %v, want ErrRackNotFound", err)
+	}
+	if err := s.Racks.UpdateRack(&models.Rack{ID: missing, Name: "x", SizeU: 1}); !errors.Is(err, store.ErrRackNotFound) {
+		t.Errorf("UpdateRack: err = %v, want ErrRackNotFound", err)
+	}
+	if err := s.Racks.DeleteRack(missing); !errors.Is(err, store.ErrRackNotFound) {
+		t.Errorf("DeleteRack: err = %v, want ErrRackNotFound", err)
+	}
+}
+
+func testDeviceCRUD(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 2)
+	device.Model = "R650"
+	device.IPAddress = "10.0.0.10"
+	device.NameplateWatts = 750
+	mustCreateDevice(t, s, device)
+	if device.ID == 0 || device.CreatedAt.IsZero() {
+		t.Fatalf("CreateDevice did not fill in ID and timestamps: %+v", device)
+	}
+
+	got, err := s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if got.Name != "web-1" || got.RackID != rack.ID || got.PositionU != 10 || got.SizeU != 2 ||
+		got.Model != "R650" || got.IPAddress != "10.0.0.10" || got.NameplateWatts != 750 {
+		t.Errorf("GetDevice = %+v, want the created device", got)
+	}
+	if !reflect.DeepEqual(got.Faces, []models.MountFace{models.MountFaceFront, models.MountFaceRear}) {
+		t.Errorf("GetDevice faces = %v, want both faces for a full-depth device", got.Faces)
+	}
+
+	other := mustCreateRack(t, s, "B")
+	got.RackID = other.ID
+	got.PositionU = 20
+	got.Depth = models.DeviceDepthHalf
+	got.MountFace = models.MountFaceRear
+	got.NameplateWatts = 0
+	if err := s.Devices.UpdateDevice(got, nil); err != nil {
+		t.Fatalf("UpdateDevice: %v", err)
+	}
+	got, err = s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice after update: %v", err)
+	}
+	if got.RackID != other.ID || got.PositionU != 20 || got.NameplateWatts != 0 {
+		t.Errorf("GetDevice after update = %+v", got)
+	}
+	if !reflect.DeepEqual(got.Faces, []models.MountFace{models.MountFaceRear}) {
+		t.Errorf("GetDevice faces = %v, want the rear face for a half-depth rear device", got.Faces)
+	}
+
+	if err := s.Devices.DeleteDevice(device.ID); err != nil {
+		t.Fatalf("DeleteDevice: %v", err)
+	}
+	if _, err := s.Devices.GetDevice(device.ID); !errors.Is(err, store.ErrDeviceNotFound) {
+		t.Errorf("GetDevice after delete: err = %v, want ErrDeviceNotFound", err)
+	}
+}
+
+func testDeviceSpecs(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "db-1", 5, 1)
+	device.Specs = map[string]string{"cpu": "2x Xeon", "ram": "256GB"}
+	mustCreateDevice(t, s, device)
+
+	got, err := s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if !reflect.DeepEqual(got.Specs, map[string]string{"cpu": "2x Xeon", "ram": "256GB"}) {
+		t.Errorf("specs = %v, want the created specs", got.Specs)
+	}
+
+	// nil specs leave them unchanged
+	got.Specs = nil
+	got.Name = "db-1a"
+	if err := s.Devices.UpdateDevice(got, nil); err != nil {
+		t.Fatalf("UpdateDevice: %v", err)
+	}
+	if !reflect.DeepEqual(got.Specs, map[string]string{"cpu": "2x Xeon", "ram": "256GB"}) {
+		t.Errorf("specs after update without specs = %v, want them unchanged", got.Specs)
+	}
+
+	// non-nil specs replace them
+	got.Specs = map[string]string{"ram": "512GB"}
+	if err := s.Devices.UpdateDevice(got, nil); err != nil {
+		t.Fatalf("UpdateDevice: %v", err)
+	}
+	got, err = s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if !reflect.DeepEqual(got.Specs, map[string]string{"ram": "512GB"}) {
+		t.Errorf("specs after replace = %v, want only ram", got.Specs)
+	}
+
+	devices, err := s.Devices.ListDevices(store.DeviceFilter{RackID: &rack.ID})
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if len(devices) != 1 || !reflect.DeepEqual(devices[0].Specs, map[string]string{"ram": "512GB"}) {
+		t.Errorf("ListDevices = %+v, want the device with its specs", devices)
+	}
+}
+
+func testDeviceOrder(t *testing.T, s *store.Store) {
+	a := mustCreateRack(t, s, "A")
+	b := mustCreateRack(t, s, "B")
+	mustCreateDevice(t, s, newDevice(b.ID, "b-low", 1, 1))
+	mustCreateDevice(t, s, newDevice(a.ID, "a-low", 2, 1))
+	mustCreateDevice(t, s, newDevice(a.ID, "a-high", 40, 1))
+	mustCreateDevice(t, s, newDevice(b.ID, "b-high", 30, 1))
+
+	devices, err := s.Devices.ListDevices(store.DeviceFilter{})
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	want := []string{"a-high", "a-low", "b-high", "b-low"}
+	if got := deviceNames(devices); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDevices = %v, want %v", got, want)
+	}
+
+	devices, err = s.Devices.ListDevices(store.DeviceFilter{RackID: &b.ID})
+	if err != nil {
+		t.Fatalf("ListDevices by rack: %v", err)
+	}
+	want = []string{"b-high", "b-low"}
+	if got := deviceNames(devices); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDevices by rack = %v, want %v", got, want)
+	}
+}
+
+func testDeviceChildren(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	chassis := newDevice(rack.ID, "chassis", 10, 4)
+	chassis.BayCount = 4
+	mustCreateDevice(t, s, chassis)
+
+	for _, bay := range []int{3, 1} {
+		blade := newDevice(rack.ID, "blade-"+string(rune('0'+bay)), 0, 0)
+		blade.ParentDeviceID = &chassis.ID
+		blade.Bay = bay
+		mustCreateDevice(t, s, blade)
+		if len(blade.Faces) != 0 {
+			t.Errorf("bay device faces = %v, want none", blade.Faces)
+		}
+	}
+
+	children, err := s.Devices.ListDevices(store.DeviceFilter{ParentDeviceID: &chassis.ID})
+	if err != nil {
+		t.Fatalf("ListDevices by parent: %v", err)
+	}
+	want := []string{"blade-1", "blade-3"}
+	if got := deviceNames(children); !reflect.DeepEqual(got, want) {
+		t.Errorf("children = %v, want %v ordered by bay", got, want)
+	}
+	if children[0].ParentDeviceID == nil || *children[0].ParentDeviceID != chassis.ID || children[0].Bay != 1 {
+		t.Errorf("child = %+v, want parent %d bay 1", children[0], chassis.ID)
+	}
+
+	got, err := s.Devices.GetDevice(chassis.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if got.BayCount != 4 {
+		t.Errorf("bay_count = %d, want 4", got.BayCount)
+	}
+
+	orphan := newDevice(rack.ID, "orphan", 0, 0)
+	orphan.ParentDeviceID = intPtr(1 << 30)
+	orphan.Bay = 1
+	if err := s.Devices.CreateDevice(orphan, nil, nil); err == nil {
+		t.Errorf("CreateDevice with a missing parent succeeded")
+	}
+}
+
+func testDeviceStatus(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 1)
+	device.Specs = map[string]string{"cpu": "8"}
+	mustCreateDevice(t, s, device)
+
+	if err := s.Devices.UpdateDeviceStatus(device.ID, models.DeviceStatusOffline); err != nil {
+		t.Fatalf("UpdateDeviceStatus: %v", err)
+	}
+	got, err := s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if got.Status != models.DeviceStatusOffline || got.Name != "web-1" || got.Specs["cpu"] != "8" {
+		t.Errorf("GetDevice after status update = %+v, want only the status changed", got)
+	}
+	if err := s.Devices.UpdateDeviceStatus(1<<30, models.DeviceStatusOffline); !errors.Is(err, store.ErrDeviceNotFound) {
+		t.Errorf("UpdateDeviceStatus of a missing device: err = %v, want ErrDeviceNotFound", err)
+	}
+}
+
+func testDeviceNotFound(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	missing := 1 << 30
+	if _, err := s.Devices.GetDevice(missing); !errors.Is(err, store.ErrDeviceNotFound) {
+		t.Errorf("GetDevice: err = %v, want ErrDeviceNotFound", err)
+	}
+	device := newDevice(rack.ID, "ghost", 1, 1)
+	device.ID = missing
+	if err := s.Devices.UpdateDevice(device, nil); !errors.Is(err, store.ErrDeviceNotFound) {
+		t.Errorf("UpdateDevice: err = %v, want ErrDeviceNotFound", err)
+	}
+	if err := s.Devices.DeleteDevice(missing); !errors.Is(err, store.ErrDeviceNotFound) {
+		t.Errorf("DeleteDevice: err = %v, want ErrDeviceNotFound", err)
+	}
+}
+
+func testDeviceRequiresRack(t *testing.T, s *store.Store) {
+	if err := s.Devices.CreateDevice(newDevice(1<<30, "lost", 1, 1), nil, nil); err == nil {
+		t.Errorf("CreateDevice in a missing rack succeeded")
+	}
+
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "web-1", 1, 1))
+	device.RackID = 1 << 30
+	if err := s.Devices.UpdateDevice(device, nil); err == nil {
+		t.Errorf("UpdateDevice into a missing rack succeeded")
+	}
+}
+
+func testPlacementCheck(t *testing.T, s *store.Store) {
+	a := mustCreateRack(t, s, "A")
+	b := mustCreateRack(t, s, "B")
+	mustCreateDevice(t, s, newDevice(a.ID, "a-high", 40, 1))
+	mustCreateDevice(t, s, newDevice(a.ID, "a-low", 2, 1))
+	mustCreateDevice(t, s, newDevice(b.ID, "in-b", 10, 1))
+
+	// The check sees the other devices of the rack written to, top down
+	var seen []string
+	record := func(rackDevices []models.Device) error {
+		seen = deviceNames(rackDevices)
+		return nil
+	}
+	device := newDevice(a.ID, "new", 20, 1)
+	if err := s.Devices.CreateDevice(device, nil, record); err != nil {
+		t.Fatalf("CreateDevice: %v", err)
+	}
+	if want := []string{"a-high", "a-low"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("CreateDevice check saw %v, want %v", seen, want)
+	}
+	if err := s.Devices.UpdateDevice(device, record); err != nil {
+		t.Fatalf("UpdateDevice: %v", err)
+	}
+	if want := []string{"a-high", "a-low"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("UpdateDevice check saw %v, want %v without the device itself", seen, want)
+	}
+	device.RackID = b.ID
+	if err := s.Devices.UpdateDevice(device, record); err != nil {
+		t.Fatalf("UpdateDevice to another rack: %v", err)
+	}
+	if want := []string{"in-b"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("UpdateDevice check saw %v, want %v in the target rack", seen, want)
+	}
+
+	// A failing check writes nothing and its error is returned unchanged
+	errTaken := errors.New("taken")
+	reject := func([]models.Device) error { return errTaken }
+	rejected := newDevice(a.ID, "rejected", 30, 1)
+	rejected.Specs = map[string]string{"cpu": "8"}
+	if err := s.Devices.CreateDevice(rejected, nil, reject); !errors.Is(err, errTaken) {
+		t.Errorf("CreateDevice with a failing check: err = %v, want the check's error", err)
+	}
+	devices, err := s.Devices.ListDevices(store.DeviceFilter{RackID: &a.ID})
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if want := []string{"a-high", "a-low"}; !reflect.DeepEqual(deviceNames(devices), want) {
+		t.Errorf("devices after a rejected create = %v, want %v", deviceNames(devices), want)
+	}
+
+	device.Name = "renamed"
+	device.Specs = map[string]string{"ram": "64GB"}
+	if err := s.Devices.UpdateDevice(device, reject); !errors.Is(err, errTaken) {
+		t.Errorf("UpdateDevice with a failing check: err = %v, want the check's error", err)
+	}
+	got, err := s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if got.Name != "new" || len(got.Specs) != 0 {
+		t.Errorf("device after a rejected update = %+v, want it unchanged", got)
+	}
+}
+
+func testPlacementCheckIsExclusive(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+
+	// Every writer claims U10; the check must see the devices of writers that finished before it
+	claim := func(rackDevices []models.Device) error {
+		for _, device := range rackDevices {
+			if device.PositionU == 10 {
+				return errors.New("taken")
+			}
+		}
+		return nil
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.Devices.CreateDevice(newDevice(rack.ID, "claim", 10, 1), nil, claim)
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+		}
+	}
+	devices, err := s.Devices.ListDevices(store.DeviceFilter{RackID: &rack.ID})
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if created != 1 || len(devices) != 1 {
+		t.Errorf("%d concurrent creates succeeded and %d devices were stored, want 1 (errors %v)", created, len(devices), errs)
+	}
+}
+
+func testMoveChassisMovesChildren(t *testing.T, s *store.Store) {
+	a := mustCreateRack(t, s, "A")
+	b := mustCreateRack(t, s, "B")
+	chassis := newDevice(a.ID, "chassis", 10, 4)
+	chassis.BayCount = 2
+	mustCreateDevice(t, s, chassis)
+	blade := newDevice(a.ID, "blade", 0, 0)
+	blade.ParentDeviceID = &chassis.ID
+	blade.Bay = 1
+	blade.BayCount = 1
+	mustCreateDevice(t, s, blade)
+	card := newDevice(a.ID, "card", 0, 0)
+	card.ParentDeviceID = &blade.ID
+	card.Bay = 1
+	mustCreateDevice(t, s, card)
+
+	chassis.RackID = b.ID
+	if err := s.Devices.UpdateDevice(chassis, nil); err != nil {
+		t.Fatalf("UpdateDevice: %v", err)
+	}
+	devices, err := s.Devices.ListDevices(store.DeviceFilter{RackID: &b.ID})
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if got := deviceNames(devices); len(got) != 3 {
+		t.Errorf("devices in the new rack = %v, want the chassis with everything installed in it", got)
+	}
+}
+
+func testDeviceOverlap(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	other := mustCreateRack(t, s, "B")
+	server := mustCreateDevice(t, s, newDevice(rack.ID, "server", 10, 2))
+
+	// U10 and U9 are taken on both faces; the store rejects overlaps even without a check
+	if err := s.Devices.CreateDevice(newDevice(rack.ID, "below", 9, 1), nil, nil); !errors.Is(err, store.ErrDeviceOverlap) {
+		t.Errorf("CreateDevice over U9: err = %v, want ErrDeviceOverlap", err)
+	}
+	if err := s.Devices.CreateDevice(newDevice(rack.ID, "spanning", 12, 4), nil, nil); !errors.Is(err, store.ErrDeviceOverlap) {
+		t.Errorf("CreateDevice spanning U9-U12: err = %v, want ErrDeviceOverlap", err)
+	}
+	mustCreateDevice(t, s, newDevice(rack.ID, "above", 11, 1))
+	mustCreateDevice(t, s, newDevice(rack.ID, "under", 8, 1))
+	mustCreateDevice(t, s, newDevice(other.ID, "elsewhere", 10, 2))
+
+	// Half-depth devices share a U on opposite faces only
+	front := newDevice(rack.ID, "front", 20, 1)
+	front.Depth = models.DeviceDepthHalf
+	mustCreateDevice(t, s, front)
+	rear := newDevice(rack.ID, "rear", 20, 1)
+	rear.Depth = models.DeviceDepthHalf
+	rear.MountFace = models.MountFaceRear
+	mustCreateDevice(t, s, rear)
+	front2 := newDevice(rack.ID, "front-2", 20, 1)
+	front2.Depth = models.DeviceDepthHalf
+	if err := s.Devices.CreateDevice(front2, nil, nil); !errors.Is(err, store.ErrDeviceOverlap) {
+		t.Errorf("CreateDevice on a taken face: err = %v, want ErrDeviceOverlap", err)
+	}
+
+	// Zero-U and chassis bay devices take no U
+	pdu := newDevice(rack.ID, "pdu", 0, 0)
+	pdu.ZeroUSide = models.ZeroUSideLeft
+	mustCreateDevice(t, s, pdu)
+	chassis := newDevice(rack.ID, "chassis", 30, 2)
+	chassis.BayCount = 1
+	mustCreateDevice(t, s, chassis)
+	blade := newDevice(rack.ID, "blade", 0, 0)
+	blade.ParentDeviceID = &chassis.ID
+	blade.Bay = 1
+	mustCreateDevice(t, s, blade)
+
+	// Moving onto a taken U fails and leaves the device where it was
+	moved := *server
+	moved.PositionU = 21
+	if err := s.Devices.UpdateDevice(&moved, nil); !errors.Is(err, store.ErrDeviceOverlap) {
+		t.Errorf("UpdateDevice onto U20: err = %v, want ErrDeviceOverlap", err)
+	}
+	got, err := s.Devices.GetDevice(server.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if got.PositionU != 10 {
+		t.Errorf("position after a rejected move = %d, want 10", got.PositionU)
+	}
+
+	// A device does not overlap itself
+	got.Name = "server-renamed"
+	if err := s.Devices.UpdateDevice(got, nil); err != nil {
+		t.Errorf("UpdateDevice in place: %v", err)
+	}
+}
+
+func testConnectionCRUD(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+
+	conn := &models.NetworkConnection{SourceDeviceID: a.ID, TargetDeviceID: b.ID, ConnectionType: "Ethernet", PortInfo: "eth0", Speed: "10G"}
+	if err := s.Connections.CreateConnection(conn, nil); err != nil {
+		t.Fatalf("CreateConnection: %v", err)
+	}
+	if conn.ID == 0 || conn.CreatedAt.IsZero() {
+		t.Fatalf("CreateConnection did not fill in ID and creation time: %+v", conn)
+	}
+
+	got, err := s.Connections.GetConnection(conn.ID)
+	if err != nil {
+		t.Fatalf("GetConnection: %v", err)
+	}
+	if got.SourceDeviceID != a.ID || got.TargetDeviceID != b.ID || got.ConnectionType != "Ethernet" ||
+		got.PortInfo != "eth0" || got.Speed != "10G" || got.SourceInterfaceID != nil || got.CableID != nil {
+		t.Errorf("GetConnection = %+v, want the created connection", got)
+	}
+	if got.VLANMode != "" || got.TaggedVLANIDs != nil {
+		t.Errorf("GetConnection tagging = %+v, want none", got.VLANTagging)
+	}
+
+	got.ConnectionType = "Fiber"
+	got.PortInfo = "xe-0/0/1"
+	got.Speed = ""
+	if err := s.Connections.UpdateConnection(got); err != nil {
+		t.Fatalf("UpdateConnection: %v", err)
+	}
+	got, err = s.Connections.GetConnection(conn.ID)
+	if err != nil {
+		t.Fatalf("GetConnection after update: %v", err)
+	}
+	if got.ConnectionType != "Fiber" || got.PortInfo != "xe-0/0/1" || got.Speed != "" || got.SourceDeviceID != a.ID {
+		t.Errorf("GetConnection after update = %+v", got)
+	}
+
+	if err := s.Connections.DeleteConnection(conn.ID); err != nil {
+		t.Fatalf("DeleteConnection: %v", err)
+	}
+	if _, err := s.Connections.GetConnection(conn.ID); !errors.Is(err, store.ErrConnectionNotFound) {
+		t.Errorf("GetConnection after delete: err = %v, want ErrConnectionNotFound", err)
+	}
+}
+
+func testConnectionFilter(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	c := mustCreateDevice(t, s, newDevice(rack.ID, "c", 30, 1))
+	ab := mustCreateConnection(t, s, a.ID, b.ID)
+	bc := mustCreateConnection(t, s, b.ID, c.ID)
+	ca := mustCreateConnection(t, s, c.ID, a.ID)
+
+	connections, err := s.Connections.ListConnections(store.ConnectionFilter{})
+	if err != nil {
+		t.Fatalf("ListConnections: %v", err)
+	}
+	if got, want := connectionIDs(connections), []int{ab.ID, bc.ID, ca.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListConnections = %v, want %v", got, want)
+	}
+
+	// Both ends count
+	connections, err = s.Connections.ListConnections(store.ConnectionFilter{DeviceID: &a.ID})
+	if err != nil {
+		t.Fatalf("ListConnections by device: %v", err)
+	}
+	if got, want := connectionIDs(connections), []int{ab.ID, ca.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListConnections by device = %v, want %v", got, want)
+	}
+}
+
+func testConnectionNotFound(t *testing.T, s *store.Store) {
+	missing := 1 << 30
+	if _, err := s.Connections.GetConnection(missing); !errors.Is(err, store.ErrConnectionNotFound) {
+		t.Errorf("GetConnection: err = %v, want ErrConnectionNotFound", err)
+	}
+	if err := s.Connections.UpdateConnection(&models.NetworkConnection{ID: missing}); !errors.Is(err, store.ErrConnectionNotFound) {
+		t.Errorf("UpdateConnection: err = %v, want ErrConnectionNotFound", err)
+	}
+	if err := s.Connections.DeleteConnection(missing); !errors.Is(err, store.ErrConnectionNotFound) {
+		t.Errorf("DeleteConnection: err = %v, want ErrConnectionNotFound", err)
+	}
+}
+
+func testConnectionRequiresDevices(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+
+	if err := s.Connections.CreateConnection(&models.NetworkConnection{SourceDeviceID: a.ID, TargetDeviceID: 1 << 30}, nil); err == nil {
+		t.Errorf("CreateConnection to a missing device succeeded")
+	}
+	if err := s.Connections.CreateConnection(&models.NetworkConnection{SourceDeviceID: a.ID, TargetDeviceID: a.ID}, nil); err == nil {
+		t.Errorf("CreateConnection from a device to itself succeeded")
+	}
+}
+
+func testDeleteRackCascades(t *testing.T, s *store.Store) {
+	a := mustCreateRack(t, s, "A")
+	b := mustCreateRack(t, s, "B")
+	inA := mustCreateDevice(t, s, newDevice(a.ID, "in-a", 10, 1))
+	inB := mustCreateDevice(t, s, newDevice(b.ID, "in-b", 10, 1))
+	other := mustCreateDevice(t, s, newDevice(b.ID, "other", 20, 1))
+	mustCreateConnection(t, s, inA.ID, inB.ID)
+	kept := mustCreateConnection(t, s, inB.ID, other.ID)
+
+	if err := s.Racks.DeleteRack(a.ID); err != nil {
+		t.Fatalf("DeleteRack: %v", err)
+	}
+	if _, err := s.Devices.GetDevice(inA.ID); !errors.Is(err, store.ErrDeviceNotFound) {
+		t.Errorf("device in deleted rack: err = %v, want ErrDeviceNotFound", err)
+	}
+	connections, err := s.Connections.ListConnections(store.ConnectionFilter{})
+	if err != nil {
+		t.Fatalf("ListConnections: %v", err)
+	}
+	if got, want := connectionIDs(connections), []int{kept.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("connections after rack delete = %v, want %v", got, want)
+	}
+}
+
+func testDeleteDeviceCascades(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	chassis := newDevice(rack.ID, "chassis", 10, 4)
+	chassis.BayCount = 2
+	mustCreateDevice(t, s, chassis)
+	blade := newDevice(rack.ID, "blade", 0, 0)
+	blade.ParentDeviceID = &chassis.ID
+	blade.Bay = 1
+	mustCreateDevice(t, s, blade)
+	sw := mustCreateDevice(t, s, newDevice(rack.ID, "switch", 40, 1))
+	mustCreateConnection(t, s, blade.ID, sw.ID)
+
+	if err := s.Devices.DeleteDevice(chassis.ID); err != nil {
+		t.Fatalf("DeleteDevice: %v", err)
+	}
+	if _, err := s.Devices.GetDevice(blade.ID); !errors.Is(err, store.ErrDeviceNotFound) {
+		t.Errorf("device in deleted chassis: err = %v, want ErrDeviceNotFound", err)
+	}
+	connections, err := s.Connections.ListConnections(store.ConnectionFilter{DeviceID: &sw.ID})
+	if err != nil {
+		t.Fatalf("ListConnections: %v", err)
+	}
+	if len(connections) != 0 {
+		t.Errorf("connections of a deleted device's child = %v, want none", connectionIDs(connections))
+	}
+}
+
+func testReturnedValuesAreCopies(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 1)
+	device.Specs = map[string]string{"cpu": "8"}
+	mustCreateDevice(t, s, device)
+
+	got, err := s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	got.Name = "changed"
+	got.Specs["cpu"] = "changed"
+	device.Specs["cpu"] = "changed"
+
+	again, err := s.Devices.GetDevice(device.ID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if again.Name != "web-1" || again.Specs["cpu"] != "8" {
+		t.Errorf("GetDevice = %+v, want changes to returned values not to be stored", again)
+	}
+}
@@ -0,0 +1,327 @@
+package storetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func mustCreateVLANGroup(t *testing.T, s *store.Store, name string, siteID *int) *models.VLANGroup {
+	t.Helper()
+	group := &models.VLANGroup{Name: name, SiteID: siteID}
+	if err := s.VLANs.CreateVLANGroup(group); err != nil {
+		t.Fatalf("CreateVLANGroup(%s): %v", name, err)
+	}
+	return group
+}
+
+func mustCreateVLAN(t *testing.T, s *store.Store, vid int, groupID, siteID *int) *models.VLAN {
+	t.Helper()
+	vlan := &models.VLAN{VID: vid, Name: "vlan", GroupID: groupID, SiteID: siteID}
+	if err := s.VLANs.CreateVLAN(vlan); err != nil {
+		t.Fatalf("CreateVLAN(%d): %v", vid, err)
+	}
+	return vlan
+}
+
+func vlanIDs(vlans []models.VLAN) []int {
+	ids := []int{}
+	for _, vlan := range vlans {
+		ids = append(ids, vlan.ID)
+	}
+	return ids
+}
+
+func testVLANGroupCRUD(t *testing.T, s *store.Store) {
+	site := mustCreateSite(t, s, "DC1")
+	group := &models.VLANGroup{Name: "core", SiteID: &site.ID, Description: "core switching"}
+	if err := s.VLANs.CreateVLANGroup(group); err != nil {
+		t.Fatalf("CreateVLANGroup: %v", err)
+	}
+	if group.ID == 0 || group.CreatedAt.IsZero() || group.UpdatedAt.IsZero() {
+		t.Fatalf("CreateVLANGroup did not fill in ID and timestamps: %+v", group)
+	}
+	mustCreateVLANGroup(t, s, "access", nil)
+
+	got, err := s.VLANs.GetVLANGroup(group.ID)
+	if err != nil {
+		t.Fatalf("GetVLANGroup: %v", err)
+	}
+	if got.Name != "core" || got.SiteID == nil || *got.SiteID != site.ID || got.Description != "core switching" {
+		t.Errorf("GetVLANGroup = %+v, want the created group", got)
+	}
+
+	groups, err := s.VLANs.ListVLANGroups()
+	if err != nil {
+		t.Fatalf("ListVLANGroups: %v", err)
+	}
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	if want := []string{"access", "core"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListVLANGroups = %v, want %v", names, want)
+	}
+
+	got.Description = ""
+	got.SiteID = nil
+	if err := s.VLANs.UpdateVLANGroup(got); err != nil {
+		t.Fatalf("UpdateVLANGroup: %v", err)
+	}
+	if got, err = s.VLANs.GetVLANGroup(group.ID); err != nil || got.Description != "" || got.SiteID != nil {
+		t.Errorf("GetVLANGroup after update = %+v, %v", got, err)
+	}
+
+	dup := &models.VLANGroup{Name: "access"}
+	if err := s.VLANs.CreateVLANGroup(dup); !errors.Is(err, store.ErrVLANGroupExists) {
+		t.Errorf("CreateVLANGroup with a repeated name: err = %v, want ErrVLANGroupExists", err)
+	}
+	got.Name = "access"
+	if err := s.VLANs.UpdateVLANGroup(got); !errors.Is(err, store.ErrVLANGroupExists) {
+		t.Errorf("UpdateVLANGroup to a repeated name: err = %v, want ErrVLANGroupExists", err)
+	}
+
+	if err := s.VLANs.DeleteVLANGroup(group.ID); err != nil {
+		t.Fatalf("DeleteVLANGroup: %v", err)
+	}
+	if _, err := s.VLANs.GetVLANGroup(group.ID); !errors.Is(err, store.ErrVLANGroupNotFound) {
+		t.Errorf("GetVLANGroup after delete: err = %v, want ErrVLANGroupNotFound", err)
+	}
+	if err := s.VLANs.DeleteVLANGroup(group.ID); !errors.Is(err, store.ErrVLANGroupNotFound) {
+		t.Errorf("DeleteVLANGroup twice: err = %v, want ErrVLANGroupNotFound", err)
+	}
+	if err := s.VLANs.UpdateVLANGroup(&models.VLANGroup{ID: group.ID, Name: "gone"}); !errors.Is(err, store.ErrVLANGroupNotFound) {
+		t.Errorf("UpdateVLANGroup after delete: err = %v, want ErrVLANGroupNotFound", err)
+	}
+}
+
+func testVLANCRUD(t *testing.T, s *store.Store) {
+	site := mustCreateSite(t, s, "DC1")
+	group := mustCreateVLANGroup(t, s, "core", &site.ID)
+
+	// A grouped VLAN takes its group's site
+	vlan := &models.VLAN{VID: 100, Name: "servers", GroupID: &group.ID, Description: "server LAN"}
+	if err := s.VLANs.CreateVLAN(vlan); err != nil {
+		t.Fatalf("CreateVLAN: %v", err)
+	}
+	if vlan.ID == 0 || vlan.CreatedAt.IsZero() || vlan.UpdatedAt.IsZero() {
+		t.Fatalf("CreateVLAN did not fill in ID and timestamps: %+v", vlan)
+	}
+	if vlan.SiteID == nil || *vlan.SiteID != site.ID {
+		t.Errorf("CreateVLAN in a site's group: SiteID = %v, want %d", vlan.SiteID, site.ID)
+	}
+	siteVLAN := mustCreateVLAN(t, s, 10, nil, &site.ID)
+	globalVLAN := mustCreateVLAN(t, s, 10, nil, nil)
+
+	got, err := s.VLANs.GetVLAN(vlan.ID)
+	if err != nil {
+		t.Fatalf("GetVLAN: %v", err)
+	}
+	if got.VID != 100 || got.Name != "servers" || got.GroupID == nil || *got.GroupID != group.ID || got.Description != "server LAN" {
+		t.Errorf("GetVLAN = %+v, want the created VLAN", got)
+	}
+
+	list := func(filter models.VLANFilter) []int {
+		t.Helper()
+		vlans, err := s.VLANs.ListVLANs(filter)
+		if err != nil {
+			t.Fatalf("ListVLANs: %v", err)
+		}
+		return vlanIDs(vlans)
+	}
+	if got, want := list(models.VLANFilter{}), []int{siteVLAN.ID, globalVLAN.ID, vlan.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListVLANs = %v, want %v", got, want)
+	}
+	if got, want := list(models.VLANFilter{GroupID: &group.ID}), []int{vlan.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListVLANs by group = %v, want %v", got, want)
+	}
+	if got, want := list(models.VLANFilter{SiteID: &site.ID}), []int{siteVLAN.ID, vlan.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListVLANs by site = %v, want %v", got, want)
+	}
+
+	got.VID = 200
+	got.Name = "storage"
+	got.Description = ""
+	if err := s.VLANs.UpdateVLAN(got); err != nil {
+		t.Fatalf("UpdateVLAN: %v", err)
+	}
+	if got, err = s.VLANs.GetVLAN(vlan.ID); err != nil || got.VID != 200 || got.Name != "storage" || got.Description != "" {
+		t.Errorf("GetVLAN after update = %+v, %v", got, err)
+	}
+
+	if err := s.VLANs.DeleteVLAN(vlan.ID); err != nil {
+		t.Fatalf("DeleteVLAN: %v", err)
+	}
+	if _, err := s.VLANs.GetVLAN(vlan.ID); !errors.Is(err, store.ErrVLANNotFound) {
+		t.Errorf("GetVLAN after delete: err = %v, want ErrVLANNotFound", err)
+	}
+	if err := s.VLANs.DeleteVLAN(vlan.ID); !errors.Is(err, store.ErrVLANNotFound) {
+		t.Errorf("DeleteVLAN twice: err = %v, want ErrVLANNotFound", err)
+	}
+	if err := s.VLANs.UpdateVLAN(&models.VLAN{ID: vlan.ID, VID: 1, Name: "gone"}); !errors.Is(err, store.ErrVLANNotFound) {
+		t.Errorf("UpdateVLAN after delete: err = %v, want ErrVLANNotFound", err)
+	}
+}
+
+func testVLANScopeIsUnique(t *testing.T, s *store.Store) {
+	site := mustCreateSite(t, s, "DC1")
+	group := mustCreateVLANGroup(t, s, "core", nil)
+	mustCreateVLAN(t, s, 10, nil, nil)
+	mustCreateVLAN(t, s, 10, nil, &site.ID)
+	mustCreateVLAN(t, s, 10, &group.ID, nil)
+
+	for _, vlan := range []*models.VLAN{
+		{VID: 10, Name: "global"},
+		{VID: 10, Name: "site", SiteID: &site.ID},
+		{VID: 10, Name: "grouped", GroupID: &group.ID},
+	} {
+		if err := s.VLANs.CreateVLAN(vlan); !errors.Is(err, store.ErrVLANExists) {
+			t.Errorf("CreateVLAN(%s) repeating a VID: err = %v, want ErrVLANExists", vlan.Name, err)
+		}
+	}
+
+	other := mustCreateVLAN(t, s, 20, nil, nil)
+	other.VID = 10
+	if err := s.VLANs.UpdateVLAN(other); !errors.Is(err, store.ErrVLANExists) {
+		t.Errorf("UpdateVLAN repeating a VID: err = %v, want ErrVLANExists", err)
+	}
+}
+
+func testMoveVLANGroupMovesVLANs(t *testing.T, s *store.Store) {
+	a := mustCreateSite(t, s, "DC1")
+	b := mustCreateSite(t, s, "DC2")
+	group := mustCreateVLANGroup(t, s, "core", &a.ID)
+	vlan := mustCreateVLAN(t, s, 100, &group.ID, nil)
+
+	group.SiteID = &b.ID
+	if err := s.VLANs.UpdateVLANGroup(group); err != nil {
+		t.Fatalf("UpdateVLANGroup: %v", err)
+	}
+	got, err := s.VLANs.GetVLAN(vlan.ID)
+	if err != nil {
+		t.Fatalf("GetVLAN: %v", err)
+	}
+	if got.SiteID == nil || *got.SiteID != b.ID {
+		t.Errorf("VLAN site after moving its group = %v, want %d", got.SiteID, b.ID)
+	}
+
+	group.SiteID = nil
+	if err := s.VLANs.UpdateVLANGroup(group); err != nil {
+		t.Fatalf("UpdateVLANGroup to no site: %v", err)
+	}
+	if got, err = s.VLANs.GetVLAN(vlan.ID); err != nil || got.SiteID != nil {
+		t.Errorf("VLAN after unscoping its group = %+v, %v", got, err)
+	}
+}
+
+func testDeleteVLANCascades(t *testing.T, s *store.Store) {
+	site := mustCreateSite(t, s, "DC1")
+	siteGroup := mustCreateVLANGroup(t, s, "site", &site.ID)
+	group := mustCreateVLANGroup(t, s, "core", nil)
+	inSiteGroup := mustCreateVLAN(t, s, 10, &siteGroup.ID, nil)
+	atSite := mustCreateVLAN(t, s, 20, nil, &site.ID)
+	inGroup := mustCreateVLAN(t, s, 30, &group.ID, nil)
+	global := mustCreateVLAN(t, s, 40, nil, nil)
+
+	// Deleting a group deletes its VLANs
+	if err := s.VLANs.DeleteVLANGroup(group.ID); err != nil {
+		t.Fatalf("DeleteVLANGroup: %v", err)
+	}
+	if _, err := s.VLANs.GetVLAN(inGroup.ID); !errors.Is(err, store.ErrVLANNotFound) {
+		t.Errorf("GetVLAN in a deleted group: err = %v, want ErrVLANNotFound", err)
+	}
+
+	// Deleting a site deletes its groups and the VLANs scoped to it
+	if err := s.Locations.DeleteSite(site.ID); err != nil {
+		t.Fatalf("DeleteSite: %v", err)
+	}
+	if _, err := s.VLANs.GetVLANGroup(siteGroup.ID); !errors.Is(err, store.ErrVLANGroupNotFound) {
+		t.Errorf("GetVLANGroup at a deleted site: err = %v, want ErrVLANGroupNotFound", err)
+	}
+	for _, vlan := range []*models.VLAN{inSiteGroup, atSite} {
+		if _, err := s.VLANs.GetVLAN(vlan.ID); !errors.Is(err, store.ErrVLANNotFound) {
+			t.Errorf("GetVLAN(%d) at a deleted site: err = %v, want ErrVLANNotFound", vlan.VID, err)
+		}
+	}
+
+	vlans, err := s.VLANs.ListVLANs(models.VLANFilter{})
+	if err != nil {
+		t.Fatalf("ListVLANs: %v", err)
+	}
+	if got, want := vlanIDs(vlans), []int{global.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListVLANs after deletes = %v, want %v", got, want)
+	}
+}
+
+func testVLANTagging(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	iface := mustCreateInterface(t, s, a.ID, "eth0")
+	conn := mustCreateConnection(t, s, a.ID, b.ID)
+	native := mustCreateVLAN(t, s, 1, nil, nil)
+	v20 := mustCreateVLAN(t, s, 20, nil, nil)
+	v10 := mustCreateVLAN(t, s, 10, nil, nil)
+
+	// Tagged VLANs read back sorted by ID, without repeats
+	trunk := models.VLANTagging{VLANMode: models.VLANModeTrunk, UntaggedVLANID: &native.ID, TaggedVLANIDs: []int{v10.ID, v20.ID, v10.ID}}
+	want := models.VLANTagging{VLANMode: models.VLANModeTrunk, UntaggedVLANID: &native.ID, TaggedVLANIDs: []int{v20.ID, v10.ID}}
+	if err := s.VLANs.SetInterfaceVLANs(iface.ID, trunk); err != nil {
+		t.Fatalf("SetInterfaceVLANs: %v", err)
+	}
+	if err := s.VLANs.SetConnectionVLANs(conn.ID, trunk); err != nil {
+		t.Fatalf("SetConnectionVLANs: %v", err)
+	}
+	gotIface, err := s.Interfaces.GetInterface(iface.ID)
+	if err != nil {
+		t.Fatalf("GetInterface: %v", err)
+	}
+	if !reflect.DeepEqual(gotIface.VLANTagging, want) {
+		t.Errorf("interface tagging = %+v, want %+v", gotIface.VLANTagging, want)
+	}
+	gotConn, err := s.Connections.GetConnection(conn.ID)
+	if err != nil {
+		t.Fatalf("GetConnection: %v", err)
+	}
+	if !reflect.DeepEqual(gotConn.VLANTagging, want) {
+		t.Errorf("connection tagging = %+v, want %+v", gotConn.VLANTagging, want)
+	}
+
+	// Deleting a VLAN takes it out of every tagging
+	if err := s.VLANs.DeleteVLAN(v10.ID); err != nil {
+		t.Fatalf("DeleteVLAN: %v", err)
+	}
+	if err := s.VLANs.DeleteVLAN(native.ID); err != nil {
+		t.Fatalf("DeleteVLAN: %v", err)
+	}
+	want = models.VLANTagging{VLANMode: models.VLANModeTrunk, TaggedVLANIDs: []int{v20.ID}}
+	if gotIface, err = s.Interfaces.GetInterface(iface.ID); err != nil || !reflect.DeepEqual(gotIface.VLANTagging, want) {
+		t.Errorf("interface tagging after VLAN delete = %+v, %v; want %+v", gotIface.VLANTagging, err, want)
+	}
+	if gotConn, err = s.Connections.GetConnection(conn.ID); err != nil || !reflect.DeepEqual(gotConn.VLANTagging, want) {
+		t.Errorf("connection tagging after VLAN delete = %+v, %v; want %+v", gotConn.VLANTagging, err, want)
+	}
+
+	// An empty tagging clears it
+	if err := s.VLANs.SetInterfaceVLANs(iface.ID, models.VLANTagging{}); err != nil {
+		t.Fatalf("SetInterfaceVLANs to none: %v", err)
+	}
+	if gotIface, err = s.Interfaces.GetInterface(iface.ID); err != nil || !reflect.DeepEqual(gotIface.VLANTagging, models.VLANTagging{}) {
+		t.Errorf("interface tagging after clearing = %+v, %v", gotIface.VLANTagging, err)
+	}
+
+	access := models.VLANTagging{VLANMode: models.VLANModeAccess, UntaggedVLANID: &v20.ID}
+	if err := s.VLANs.SetInterfaceVLANs(iface.ID+1000, access); !errors.Is(err, store.ErrInterfaceNotFound) {
+		t.Errorf("SetInterfaceVLANs on a missing interface: err = %v, want ErrInterfaceNotFound", err)
+	}
+	if err := s.VLANs.SetConnectionVLANs(conn.ID+1000, access); !errors.Is(err, store.ErrConnectionNotFound) {
+		t.Errorf("SetConnectionVLANs on a missing connection: err = %v, want ErrConnectionNotFound", err)
+	}
+	missing := models.VLANTagging{VLANMode: models.VLANModeTrunk, TaggedVLANIDs: []int{v10.ID}}
+	if err := s.VLANs.SetInterfaceVLANs(iface.ID, missing); err == nil {
+		t.Errorf("SetInterfaceVLANs with a deleted VLAN succeeded")
+	}
+}
@@ -0,0 +1,270 @@
+package storetest
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"rackview/internal/models"
+	"rackview/internal/store"
+)
+
+func mustCreateWebhook(t *testing.T, s *store.Store, name string) *models.Webhook {
+	t.Helper()
+	webhook := &models.Webhook{Name: name, URL: "http://example.com/" + name, Statuses: []models.DeviceStatus{}, Enabled: true}
+	if err := s.Webhooks.CreateWebhook(webhook); err != nil {
+		t.Fatalf("CreateWebhook(%s): %v", name, err)
+	}
+	return webhook
+}
+
+func mustRecordWebhookDelivery(t *testing.T, s *store.Store, webhookID int, deviceID *int, attempt int) *models.WebhookDelivery {
+	t.Helper()
+	delivery := &models.WebhookDelivery{
+		WebhookID:  webhookID,
+		Event:      models.WebhookEventDeviceStatusChanged,
+		DeviceID:   deviceID,
+		Payload:    json.RawMessage(`{"event":"device.status_changed"}`),
+		Attempt:    attempt,
+		StatusCode: intPtr(500),
+		Error:      "server error",
+		DurationMs: 12,
+	}
+	if err := s.Webhooks.RecordWebhookDelivery(delivery); err != nil {
+		t.Fatalf("RecordWebhookDelivery: %v", err)
+	}
+	return delivery
+}
+
+func testWebhookCRUD(t *testing.T, s *store.Store) {
+	webhook := &models.Webhook{
+		Name:     "ops",
+		URL:      "http://example.com/hook",
+		Secret:   "s3cret",
+		Statuses: []models.DeviceStatus{models.DeviceStatusOffline, models.DeviceStatusWarning},
+		Enabled:  true,
+	}
+	if err := s.Webhooks.CreateWebhook(webhook); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	if webhook.ID == 0 || webhook.CreatedAt.IsZero() || webhook.UpdatedAt.IsZero() || !webhook.HasSecret {
+		t.Fatalf("CreateWebhook did not fill in ID, timestamps and HasSecret: %+v", webhook)
+	}
+	other := mustCreateWebhook(t, s, "other")
+
+	got, err := s.Webhooks.GetWebhook(webhook.ID)
+	if err != nil {
+		t.Fatalf("GetWebhook: %v", err)
+	}
+	if got.Name != "ops" || got.URL != "http://example.com/hook" || got.Secret != "s3cret" || !got.HasSecret || !got.Enabled ||
+		!reflect.DeepEqual(got.Statuses, webhook.Statuses) {
+		t.Errorf("GetWebhook = %+v, want the created webhook", got)
+	}
+
+	webhooks, err := s.Webhooks.ListWebhooks()
+	if err != nil {
+		t.Fatalf("ListWebhooks: %v", err)
+	}
+	var ids []int
+	for _, w := range webhooks {
+		ids = append(ids, w.ID)
+	}
+	if want := []int{webhook.ID, other.ID}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListWebhooks = %v, want %v", ids, want)
+	}
+
+	got.Secret = ""
+	got.Statuses = []models.DeviceStatus{}
+	got.Enabled = false
+	if err := s.Webhooks.UpdateWebhook(got); err != nil {
+		t.Fatalf("UpdateWebhook: %v", err)
+	}
+	got, err = s.Webhooks.GetWebhook(webhook.ID)
+	if err != nil {
+		t.Fatalf("GetWebhook after update: %v", err)
+	}
+	if got.Secret != "" || got.HasSecret || got.Enabled || len(got.Statuses) != 0 {
+		t.Errorf("GetWebhook after update = %+v", got)
+	}
+
+	if err := s.Webhooks.DeleteWebhook(webhook.ID); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	if _, err := s.Webhooks.GetWebhook(webhook.ID); !errors.Is(err, store.ErrWebhookNotFound) {
+		t.Errorf("GetWebhook after delete: err = %v, want ErrWebhookNotFound", err)
+	}
+	if err := s.Webhooks.DeleteWebhook(webhook.ID); !errors.Is(err, store.ErrWebhookNotFound) {
+		t.Errorf("DeleteWebhook twice: err = %v, want ErrWebhookNotFound", err)
+	}
+	if err := s.Webhooks.UpdateWebhook(&models.Webhook{ID: webhook.ID, Name: "gone", URL: "http://example.com"}); !errors.Is(err, store.ErrWebhookNotFound) {
+		t.Errorf("UpdateWebhook after delete: err = %v, want ErrWebhookNotFound", err)
+	}
+}
+
+func testWebhookDeliveries(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := mustCreateDevice(t, s, newDevice(rack.ID, "web-1", 10, 1))
+	webhook := mustCreateWebhook(t, s, "ops")
+	other := mustCreateWebhook(t, s, "other")
+
+	first := mustRecordWebhookDelivery(t, s, webhook.ID, &device.ID, 1)
+	if first.ID == 0 || first.CreatedAt.IsZero() {
+		t.Fatalf("RecordWebhookDelivery did not fill in ID and CreatedAt: %+v", first)
+	}
+	second := mustRecordWebhookDelivery(t, s, webhook.ID, &device.ID, 2)
+	third := mustRecordWebhookDelivery(t, s, webhook.ID, nil, 1)
+	mustRecordWebhookDelivery(t, s, other.ID, nil, 1)
+
+	deliveryIDs := func(webhookID, limit int) []int64 {
+		t.Helper()
+		deliveries, err := s.Webhooks.ListWebhookDeliveries(webhookID, limit)
+		if err != nil {
+			t.Fatalf("ListWebhookDeliveries: %v", err)
+		}
+		ids := []int64{}
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		return ids
+	}
+	if got, want := deliveryIDs(webhook.ID, 10), []int64{third.ID, second.ID, first.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListWebhookDeliveries = %v, want newest first %v", got, want)
+	}
+	if got, want := deliveryIDs(webhook.ID, 2), []int64{third.ID, second.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListWebhookDeliveries limited = %v, want %v", got, want)
+	}
+
+	deliveries, err := s.Webhooks.ListWebhookDeliveries(webhook.ID, 10)
+	if err != nil {
+		t.Fatalf("ListWebhookDeliveries: %v", err)
+	}
+	got := deliveries[2]
+	if got.WebhookID != webhook.ID || got.Event != models.WebhookEventDeviceStatusChanged || got.DeviceID == nil ||
+		*got.DeviceID != device.ID || got.Attempt != 1 || got.Success || got.StatusCode == nil || *got.StatusCode != 500 ||
+		got.Error != "server error" || got.DurationMs != 12 {
+		t.Errorf("recorded delivery = %+v", got)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(got.Payload, &payload); err != nil || payload["event"] != "device.status_changed" {
+		t.Errorf("recorded delivery payload = %s, %v", got.Payload, err)
+	}
+
+	// Deleting a device keeps its deliveries without the device
+	if err := s.Devices.DeleteDevice(device.ID); err != nil {
+		t.Fatalf("DeleteDevice: %v", err)
+	}
+	if deliveries, err = s.Webhooks.ListWebhookDeliveries(webhook.ID, 10); err != nil || len(deliveries) != 3 {
+		t.Fatalf("ListWebhookDeliveries after device delete = %+v, %v", deliveries, err)
+	}
+	for _, delivery := range deliveries {
+		if delivery.DeviceID != nil {
+			t.Errorf("delivery %d still names deleted device %d", delivery.ID, *delivery.DeviceID)
+		}
+	}
+
+	// Deleting a webhook deletes its deliveries
+	if err := s.Webhooks.DeleteWebhook(webhook.ID); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	if got := deliveryIDs(webhook.ID, 10); len(got) != 0 {
+		t.Errorf("ListWebhookDeliveries after webhook delete = %v, want none", got)
+	}
+	if got := deliveryIDs(other.ID, 10); len(got) != 1 {
+		t.Errorf("ListWebhookDeliveries of another webhook = %v, want one", got)
+	}
+}
+
+func testAlertState(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	device := newDevice(rack.ID, "web-1", 10, 1)
+	device.Status = models.DeviceStatusWarning
+	mustCreateDevice(t, s, device)
+
+	// The first observation starts from the device's stored status
+	var seen models.AlertState
+	if err := s.AlertStates.AdvanceAlertState(device.ID, func(state *models.AlertState) {
+		seen = *state
+		state.CandidateStatus = models.DeviceStatusOffline
+		state.CandidateCount = 1
+	}); err != nil {
+		t.Fatalf("AdvanceAlertState: %v", err)
+	}
+	if seen.DeviceID != device.ID || seen.AlertStatus != models.DeviceStatusWarning || seen.CandidateStatus != "" || seen.CandidateCount != 0 {
+		t.Errorf("initial alert state = %+v, want warning with no candidate", seen)
+	}
+	initialChange := seen.ChangedAt
+
+	if err := s.AlertStates.AdvanceAlertState(device.ID, func(state *models.AlertState) {
+		seen = *state
+	}); err != nil {
+		t.Fatalf("AdvanceAlertState: %v", err)
+	}
+	if seen.AlertStatus != models.DeviceStatusWarning || seen.CandidateStatus != models.DeviceStatusOffline || seen.CandidateCount != 1 {
+		t.Errorf("alert state after a candidate = %+v, want the stored candidate", seen)
+	}
+	if !seen.ChangedAt.Equal(initialChange) {
+		t.Errorf("ChangedAt moved without a status change: %v, want %v", seen.ChangedAt, initialChange)
+	}
+
+	// ChangedAt is refreshed when the alert status changes. SQLite keeps CURRENT_TIMESTAMP to the
+	// second, so wait out the current one.
+	time.Sleep(time.Until(initialChange.Truncate(time.Second).Add(1100 * time.Millisecond)))
+	if err := s.AlertStates.AdvanceAlertState(device.ID, func(state *models.AlertState) {
+		state.AlertStatus = models.DeviceStatusOffline
+		state.CandidateStatus = ""
+		state.CandidateCount = 0
+	}); err != nil {
+		t.Fatalf("AdvanceAlertState: %v", err)
+	}
+	if err := s.AlertStates.AdvanceAlertState(device.ID, func(state *models.AlertState) {
+		seen = *state
+	}); err != nil {
+		t.Fatalf("AdvanceAlertState: %v", err)
+	}
+	if seen.AlertStatus != models.DeviceStatusOffline || seen.CandidateStatus != "" || seen.CandidateCount != 0 {
+		t.Errorf("alert state after a transition = %+v, want offline with no candidate", seen)
+	}
+	if !seen.ChangedAt.After(initialChange) {
+		t.Errorf("ChangedAt after a transition = %v, want after %v", seen.ChangedAt, initialChange)
+	}
+
+	// Deleting a device deletes its alert state
+	if err := s.Devices.DeleteDevice(device.ID); err != nil {
+		t.Fatalf("DeleteDevice: %v", err)
+	}
+	called := false
+	err := s.AlertStates.AdvanceAlertState(device.ID, func(state *models.AlertState) { called = true })
+	if !errors.Is(err, store.ErrDeviceNotFound) || called {
+		t.Errorf("AdvanceAlertState for a deleted device: err = %v, called = %v; want ErrDeviceNotFound", err, called)
+	}
+}
+
+func testLatestHealthChecks(t *testing.T, s *store.Store) {
+	rack := mustCreateRack(t, s, "A")
+	a := mustCreateDevice(t, s, newDevice(rack.ID, "a", 10, 1))
+	b := mustCreateDevice(t, s, newDevice(rack.ID, "b", 20, 1))
+	mustCreateDevice(t, s, newDevice(rack.ID, "unchecked", 30, 1))
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	mustRecordHealthCheck(t, s, b.ID, models.DeviceStatusOnline, int64Ptr(5), base)
+	latestB := mustRecordHealthCheck(t, s, b.ID, models.DeviceStatusOffline, nil, base.Add(time.Minute))
+	latestA := mustRecordHealthCheck(t, s, a.ID, models.DeviceStatusWarning, int64Ptr(250), base.Add(2*time.Minute))
+	mustRecordHealthCheck(t, s, a.ID, models.DeviceStatusOnline, int64Ptr(3), base.Add(time.Second))
+
+	checks, err := s.HealthChecks.LatestHealthChecks()
+	if err != nil {
+		t.Fatalf("LatestHealthChecks: %v", err)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("LatestHealthChecks = %+v, want one check for each checked device", checks)
+	}
+	if got := checks[0]; got.ID != latestA.ID || got.DeviceID != a.ID || got.Status != models.DeviceStatusWarning ||
+		got.Latency == nil || *got.Latency != 250 {
+		t.Errorf("latest check of a = %+v, want %+v", got, latestA)
+	}
+	if got := checks[1]; got.ID != latestB.ID || got.DeviceID != b.ID || got.Status != models.DeviceStatusOffline || got.Latency != nil {
+		t.Errorf("latest check of b = %+v, want %+v", got, latestB)
+	}
+}
@@ -0,0 +1,7 @@
+-- Revert the initial schema
+DROP TABLE IF EXISTS network_connections;
+DROP TABLE IF EXISTS device_specs;
+DROP TABLE IF EXISTS devices;
+DROP TABLE IF EXISTS racks;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -0,0 +1 @@
+ALTER TABLE network_connections DROP COLUMN IF EXISTS speed;
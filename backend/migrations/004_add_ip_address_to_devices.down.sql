@@ -0,0 +1,3 @@
+ALTER TABLE devices
+DROP COLUMN IF EXISTS ip_address,
+DROP COLUMN IF EXISTS health_check_url;
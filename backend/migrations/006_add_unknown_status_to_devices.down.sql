@@ -0,0 +1,8 @@
+-- Devices without a known status are reported offline under the old constraint
+UPDATE devices SET status = 'offline' WHERE status = 'unknown';
+
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_status_check;
+ALTER TABLE devices ADD CONSTRAINT devices_status_check
+  CHECK (status IN ('online', 'offline', 'warning'));
+
+COMMENT ON COLUMN devices.status IS NULL;
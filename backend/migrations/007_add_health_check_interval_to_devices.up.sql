@@ -0,0 +1,6 @@
+-- Add per-device health check interval for the background scheduler
+ALTER TABLE devices
+ADD COLUMN IF NOT EXISTS health_check_interval INTEGER CHECK (health_check_interval IS NULL OR health_check_interval > 0);
+
+-- Add comment for documentation
+COMMENT ON COLUMN devices.health_check_interval IS 'Seconds between scheduled health checks (NULL uses the scheduler default)';
@@ -0,0 +1,17 @@
+-- Health check history (one row per manual or scheduled check)
+CREATE TABLE IF NOT EXISTS health_checks (
+    id BIGSERIAL PRIMARY KEY,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL CHECK (status IN ('online', 'offline', 'warning', 'unknown')),
+    latency_ms INTEGER,
+    message TEXT,
+    checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- History is always read per device and time range
+CREATE INDEX IF NOT EXISTS idx_health_checks_device_checked_at ON health_checks(device_id, checked_at DESC);
+CREATE INDEX IF NOT EXISTS idx_health_checks_checked_at ON health_checks(checked_at);
+
+-- Add comment for documentation
+COMMENT ON TABLE health_checks IS 'Health check results, pruned after the configured retention period';
+COMMENT ON COLUMN health_checks.checked_at IS 'UTC time the check completed';
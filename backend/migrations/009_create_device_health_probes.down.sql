@@ -0,0 +1,3 @@
+ALTER TABLE devices DROP COLUMN IF EXISTS health_check_mode;
+
+DROP TABLE IF EXISTS device_health_probes;
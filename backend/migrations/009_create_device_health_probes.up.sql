@@ -0,0 +1,25 @@
+-- Configurable health probes per device (replaces the fixed HTTP + common-port check when present)
+CREATE TABLE IF NOT EXISTS device_health_probes (
+    id SERIAL PRIMARY KEY,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    name VARCHAR(255),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('tcp', 'http', 'tls', 'dns')),
+    config JSONB NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_health_probes_device_id ON device_health_probes(device_id);
+
+DROP TRIGGER IF EXISTS update_device_health_probes_updated_at ON device_health_probes;
+CREATE TRIGGER update_device_health_probes_updated_at BEFORE UPDATE ON device_health_probes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Rule for combining probe results into the device status
+ALTER TABLE devices
+ADD COLUMN IF NOT EXISTS health_check_mode VARCHAR(20) NOT NULL DEFAULT 'all' CHECK (health_check_mode IN ('all', 'any', 'majority'));
+
+-- Add comment for documentation
+COMMENT ON COLUMN device_health_probes.config IS 'Type-specific probe settings (ports, URL and assertions, TLS thresholds, DNS query)';
+COMMENT ON COLUMN devices.health_check_mode IS 'How probe results combine: all (worst result wins), any (best result wins), majority';
@@ -0,0 +1,46 @@
+-- Webhook subscriptions for device status alerts
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(1000) NOT NULL,
+    secret VARCHAR(255),
+    statuses TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_webhooks_updated_at ON webhooks;
+CREATE TRIGGER update_webhooks_updated_at BEFORE UPDATE ON webhooks
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- One row per delivery attempt
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event VARCHAR(100) NOT NULL,
+    device_id INTEGER REFERENCES devices(id) ON DELETE SET NULL,
+    payload JSONB NOT NULL,
+    attempt INTEGER NOT NULL,
+    success BOOLEAN NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    duration_ms INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
+
+-- Debounce state for status transition alerts
+CREATE TABLE IF NOT EXISTS device_alert_state (
+    device_id INTEGER PRIMARY KEY REFERENCES devices(id) ON DELETE CASCADE,
+    alert_status VARCHAR(50) NOT NULL,
+    candidate_status VARCHAR(50),
+    candidate_count INTEGER NOT NULL DEFAULT 0,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add comment for documentation
+COMMENT ON COLUMN webhooks.statuses IS 'Comma-separated statuses that trigger this webhook (empty for all)';
+COMMENT ON COLUMN device_alert_state.alert_status IS 'Last status that was confirmed and alerted on';
+COMMENT ON COLUMN device_alert_state.candidate_count IS 'Consecutive checks that returned candidate_status';
@@ -0,0 +1,8 @@
+ALTER TABLE racks
+DROP COLUMN IF EXISTS row_id,
+DROP COLUMN IF EXISTS room_id,
+DROP COLUMN IF EXISTS site_id;
+
+DROP TABLE IF EXISTS rack_rows;
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS sites;
@@ -0,0 +1,55 @@
+-- Location hierarchy above racks: site > room > row
+
+CREATE TABLE IF NOT EXISTS sites (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    address TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id SERIAL PRIMARY KEY,
+    site_id INTEGER NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(site_id, name)
+);
+
+-- "rows" is an SQL keyword, so the table is rack_rows
+CREATE TABLE IF NOT EXISTS rack_rows (
+    id SERIAL PRIMARY KEY,
+    room_id INTEGER NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(room_id, name)
+);
+
+-- Racks reference their most specific location; parent IDs are kept in sync by the service
+ALTER TABLE racks
+ADD COLUMN IF NOT EXISTS site_id INTEGER REFERENCES sites(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS room_id INTEGER REFERENCES rooms(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS row_id INTEGER REFERENCES rack_rows(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_rooms_site_id ON rooms(site_id);
+CREATE INDEX IF NOT EXISTS idx_rack_rows_room_id ON rack_rows(room_id);
+CREATE INDEX IF NOT EXISTS idx_racks_site_id ON racks(site_id);
+CREATE INDEX IF NOT EXISTS idx_racks_room_id ON racks(room_id);
+CREATE INDEX IF NOT EXISTS idx_racks_row_id ON racks(row_id);
+
+DROP TRIGGER IF EXISTS update_sites_updated_at ON sites;
+CREATE TRIGGER update_sites_updated_at BEFORE UPDATE ON sites
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_rooms_updated_at ON rooms;
+CREATE TRIGGER update_rooms_updated_at BEFORE UPDATE ON rooms
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_rack_rows_updated_at ON rack_rows;
+CREATE TRIGGER update_rack_rows_updated_at BEFORE UPDATE ON rack_rows
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+ALTER TABLE racks DROP COLUMN IF EXISTS power_capacity_watts;
+
+ALTER TABLE devices
+DROP COLUMN IF EXISTS nameplate_watts,
+DROP COLUMN IF EXISTS measured_watts,
+DROP COLUMN IF EXISTS psu_count,
+DROP COLUMN IF EXISTS psu_redundancy;
@@ -0,0 +1,15 @@
+-- Per-device power draw and per-rack power capacity
+ALTER TABLE devices
+ADD COLUMN IF NOT EXISTS nameplate_watts INTEGER CHECK (nameplate_watts IS NULL OR nameplate_watts >= 0),
+ADD COLUMN IF NOT EXISTS measured_watts INTEGER CHECK (measured_watts IS NULL OR measured_watts >= 0),
+ADD COLUMN IF NOT EXISTS psu_count INTEGER CHECK (psu_count IS NULL OR psu_count > 0),
+ADD COLUMN IF NOT EXISTS psu_redundancy VARCHAR(10) NOT NULL DEFAULT 'none' CHECK (psu_redundancy IN ('none', 'n+1', '2n'));
+
+ALTER TABLE racks
+ADD COLUMN IF NOT EXISTS power_capacity_watts INTEGER CHECK (power_capacity_watts IS NULL OR power_capacity_watts > 0);
+
+-- Add comments for documentation
+COMMENT ON COLUMN devices.nameplate_watts IS 'Rated maximum power draw from the PSU nameplate';
+COMMENT ON COLUMN devices.measured_watts IS 'Observed power draw; used instead of nameplate_watts when set';
+COMMENT ON COLUMN devices.psu_redundancy IS 'PSU redundancy scheme: none, n+1 or 2n';
+COMMENT ON COLUMN racks.power_capacity_watts IS 'Usable power budget of the rack PDUs (NULL means untracked)';
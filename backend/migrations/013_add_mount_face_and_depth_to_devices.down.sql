@@ -0,0 +1,3 @@
+ALTER TABLE devices
+DROP COLUMN IF EXISTS mount_face,
+DROP COLUMN IF EXISTS depth;
@@ -0,0 +1,8 @@
+-- Mounting face and depth so half-depth devices can share a U on opposite rails
+ALTER TABLE devices
+ADD COLUMN IF NOT EXISTS mount_face VARCHAR(10) NOT NULL DEFAULT 'front' CHECK (mount_face IN ('front', 'rear')),
+ADD COLUMN IF NOT EXISTS depth VARCHAR(10) NOT NULL DEFAULT 'full' CHECK (depth IN ('full', 'half'));
+
+-- Add comments for documentation
+COMMENT ON COLUMN devices.mount_face IS 'Rails the device is mounted on: front or rear';
+COMMENT ON COLUMN devices.depth IS 'full occupies both faces of its U range; half only occupies its mounting face';
@@ -0,0 +1,12 @@
+-- Zero-U devices have no U range to fall back to, so they are removed
+DELETE FROM devices WHERE zero_u_side IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_devices_zero_u_slot;
+
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_placement_check;
+ALTER TABLE devices ADD CONSTRAINT devices_position_u_check CHECK (position_u > 0);
+ALTER TABLE devices ADD CONSTRAINT devices_size_u_check CHECK (size_u > 0);
+
+ALTER TABLE devices
+DROP COLUMN IF EXISTS zero_u_side,
+DROP COLUMN IF EXISTS zero_u_slot;
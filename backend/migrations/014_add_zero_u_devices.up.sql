@@ -0,0 +1,23 @@
+-- Zero-U devices (vertical PDUs, cable managers) mount on a side of the rack instead of a U range
+ALTER TABLE devices
+ADD COLUMN IF NOT EXISTS zero_u_side VARCHAR(10) CHECK (zero_u_side IN ('left', 'right')),
+ADD COLUMN IF NOT EXISTS zero_u_slot VARCHAR(1) CHECK (zero_u_slot IN ('A', 'B'));
+
+-- Zero-U devices have position_u = size_u = 0; every other device keeps a real U range
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_position_u_check;
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_size_u_check;
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_placement_check;
+ALTER TABLE devices ADD CONSTRAINT devices_placement_check
+  CHECK (
+    (zero_u_side IS NULL AND zero_u_slot IS NULL AND position_u > 0 AND size_u > 0) OR
+    (zero_u_side IS NOT NULL AND position_u = 0 AND size_u = 0)
+  );
+
+-- A side slot holds a single zero-U device
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_zero_u_slot
+  ON devices(rack_id, zero_u_side, zero_u_slot)
+  WHERE zero_u_slot IS NOT NULL;
+
+-- Add comments for documentation
+COMMENT ON COLUMN devices.zero_u_side IS 'Rack side of a zero-U device: left or right (NULL for U-mounted devices)';
+COMMENT ON COLUMN devices.zero_u_slot IS 'Optional slot on the zero-U side: A or B';
@@ -0,0 +1,16 @@
+-- Child devices have no rack placement of their own, so they are removed
+DELETE FROM devices WHERE parent_device_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_devices_parent_bay;
+
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_placement_check;
+ALTER TABLE devices ADD CONSTRAINT devices_placement_check
+  CHECK (
+    (zero_u_side IS NULL AND zero_u_slot IS NULL AND position_u > 0 AND size_u > 0) OR
+    (zero_u_side IS NOT NULL AND position_u = 0 AND size_u = 0)
+  );
+
+ALTER TABLE devices
+DROP COLUMN IF EXISTS parent_device_id,
+DROP COLUMN IF EXISTS bay,
+DROP COLUMN IF EXISTS bay_count;
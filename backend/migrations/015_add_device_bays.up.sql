@@ -0,0 +1,22 @@
+-- Modular devices: children (blades, line cards) installed into numbered bays of a parent chassis
+ALTER TABLE devices
+ADD COLUMN IF NOT EXISTS parent_device_id INTEGER REFERENCES devices(id) ON DELETE CASCADE,
+ADD COLUMN IF NOT EXISTS bay INTEGER CHECK (bay IS NULL OR bay > 0),
+ADD COLUMN IF NOT EXISTS bay_count INTEGER CHECK (bay_count IS NULL OR bay_count > 0);
+
+-- Child devices take neither U space nor a zero-U side; they live in their parent's bay
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_placement_check;
+ALTER TABLE devices ADD CONSTRAINT devices_placement_check
+  CHECK (
+    (parent_device_id IS NULL AND zero_u_side IS NULL AND zero_u_slot IS NULL AND position_u > 0 AND size_u > 0) OR
+    (parent_device_id IS NULL AND zero_u_side IS NOT NULL AND position_u = 0 AND size_u = 0) OR
+    (parent_device_id IS NOT NULL AND bay IS NOT NULL AND zero_u_side IS NULL AND position_u = 0 AND size_u = 0)
+  );
+
+-- A bay holds a single child device
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_parent_bay ON devices(parent_device_id, bay) WHERE parent_device_id IS NOT NULL;
+
+-- Add comments for documentation
+COMMENT ON COLUMN devices.parent_device_id IS 'Chassis this device is installed in (NULL for rack-mounted devices)';
+COMMENT ON COLUMN devices.bay IS 'Bay number within the parent chassis, starting at 1';
+COMMENT ON COLUMN devices.bay_count IS 'Number of bays this device provides for child devices';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_devices_device_type_id;
+ALTER TABLE devices DROP COLUMN IF EXISTS device_type_id;
+
+DROP TABLE IF EXISTS device_types;
@@ -0,0 +1,33 @@
+-- Device type catalog: reusable templates for devices of the same make and model
+CREATE TABLE IF NOT EXISTS device_types (
+    id SERIAL PRIMARY KEY,
+    manufacturer VARCHAR(255) NOT NULL,
+    model VARCHAR(255) NOT NULL,
+    type VARCHAR(50) NOT NULL CHECK (type IN ('server', 'network', 'storage')),
+    size_u INTEGER NOT NULL DEFAULT 1 CHECK (size_u >= 0),
+    icon VARCHAR(50),
+    specs JSONB NOT NULL DEFAULT '{}',
+    nameplate_watts INTEGER CHECK (nameplate_watts IS NULL OR nameplate_watts >= 0),
+    psu_count INTEGER CHECK (psu_count IS NULL OR psu_count > 0),
+    psu_redundancy VARCHAR(10) NOT NULL DEFAULT 'none' CHECK (psu_redundancy IN ('none', 'n+1', '2n')),
+    bay_count INTEGER CHECK (bay_count IS NULL OR bay_count > 0),
+    ports JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(manufacturer, model)
+);
+
+DROP TRIGGER IF EXISTS update_device_types_updated_at ON device_types;
+CREATE TRIGGER update_device_types_updated_at BEFORE UPDATE ON device_types
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Devices link to the catalog entry they were created from (or were matched to later)
+ALTER TABLE devices
+ADD COLUMN IF NOT EXISTS device_type_id INTEGER REFERENCES device_types(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_devices_device_type_id ON devices(device_type_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN device_types.size_u IS 'Default height in U (0 for zero-U or bay-mounted devices)';
+COMMENT ON COLUMN device_types.ports IS 'Port layout template: list of {name, type, speed}';
+COMMENT ON COLUMN devices.device_type_id IS 'Catalog entry the device is an instance of';
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS check_network_connections_interfaces ON network_connections;
+DROP FUNCTION IF EXISTS check_connection_interfaces();
+
+DROP INDEX IF EXISTS idx_network_connections_source_interface;
+DROP INDEX IF EXISTS idx_network_connections_target_interface;
+
+ALTER TABLE network_connections
+DROP COLUMN IF EXISTS source_interface_id,
+DROP COLUMN IF EXISTS target_interface_id;
+
+DROP TABLE IF EXISTS device_interfaces;
@@ -0,0 +1,66 @@
+-- Physical and logical ports of a device
+CREATE TABLE IF NOT EXISTS device_interfaces (
+    id SERIAL PRIMARY KEY,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(50),
+    speed VARCHAR(50),
+    mac_address VARCHAR(17),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(device_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_interfaces_device_id ON device_interfaces(device_id);
+
+DROP TRIGGER IF EXISTS update_device_interfaces_updated_at ON device_interfaces;
+CREATE TRIGGER update_device_interfaces_updated_at BEFORE UPDATE ON device_interfaces
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Connections can terminate on specific interfaces
+ALTER TABLE network_connections
+ADD COLUMN IF NOT EXISTS source_interface_id INTEGER REFERENCES device_interfaces(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS target_interface_id INTEGER REFERENCES device_interfaces(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_network_connections_source_interface
+    ON network_connections(source_interface_id) WHERE source_interface_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_network_connections_target_interface
+    ON network_connections(target_interface_id) WHERE target_interface_id IS NOT NULL;
+
+-- The unique indexes cover each column; this covers an interface appearing as the source of one
+-- connection and the target of another. The advisory lock serializes concurrent claims on an interface.
+CREATE OR REPLACE FUNCTION check_connection_interfaces()
+RETURNS TRIGGER AS $$
+DECLARE
+    iface INTEGER;
+BEGIN
+    IF NEW.source_interface_id IS NOT NULL AND NEW.source_interface_id = NEW.target_interface_id THEN
+        RAISE EXCEPTION 'a connection cannot start and end on the same interface'
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    FOREACH iface IN ARRAY ARRAY[NEW.source_interface_id, NEW.target_interface_id] LOOP
+        CONTINUE WHEN iface IS NULL;
+        PERFORM pg_advisory_xact_lock(hashtext('device_interfaces'), iface);
+        IF EXISTS (
+            SELECT 1 FROM network_connections
+            WHERE id != NEW.id AND (source_interface_id = iface OR target_interface_id = iface)
+        ) THEN
+            RAISE EXCEPTION 'interface % already carries a cable', iface
+                USING ERRCODE = 'unique_violation';
+        END IF;
+    END LOOP;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS check_network_connections_interfaces ON network_connections;
+CREATE TRIGGER check_network_connections_interfaces BEFORE INSERT OR UPDATE OF source_interface_id, target_interface_id ON network_connections
+    FOR EACH ROW EXECUTE FUNCTION check_connection_interfaces();
+
+-- Add comments for documentation
+COMMENT ON COLUMN network_connections.source_interface_id IS 'Interface on the source device; an interface carries at most one connection';
+COMMENT ON COLUMN network_connections.target_interface_id IS 'Interface on the target device; an interface carries at most one connection';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_device_interfaces_rear_port;
+ALTER TABLE device_interfaces DROP CONSTRAINT IF EXISTS device_interfaces_rear_port_check;
+ALTER TABLE device_interfaces DROP COLUMN IF EXISTS rear_port_id;
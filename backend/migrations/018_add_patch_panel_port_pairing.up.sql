@@ -0,0 +1,13 @@
+-- Patch panel front ports pass through to a rear port on the same panel
+ALTER TABLE device_interfaces
+ADD COLUMN IF NOT EXISTS rear_port_id INTEGER REFERENCES device_interfaces(id) ON DELETE SET NULL;
+
+ALTER TABLE device_interfaces DROP CONSTRAINT IF EXISTS device_interfaces_rear_port_check;
+ALTER TABLE device_interfaces ADD CONSTRAINT device_interfaces_rear_port_check CHECK (rear_port_id != id);
+
+-- A rear port pairs with at most one front port
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_interfaces_rear_port
+    ON device_interfaces(rear_port_id) WHERE rear_port_id IS NOT NULL;
+
+-- Add comments for documentation
+COMMENT ON COLUMN device_interfaces.rear_port_id IS 'Rear port paired with this front port; paired ports are passive pass-throughs for cable tracing';
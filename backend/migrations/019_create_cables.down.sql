@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_network_connections_cable;
+ALTER TABLE network_connections DROP COLUMN IF EXISTS cable_id;
+
+DROP TABLE IF EXISTS cables;
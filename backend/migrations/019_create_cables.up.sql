@@ -0,0 +1,29 @@
+-- Physical cable inventory
+CREATE TABLE IF NOT EXISTS cables (
+    id SERIAL PRIMARY KEY,
+    label VARCHAR(100) NOT NULL UNIQUE,
+    media VARCHAR(20) CHECK (media IN ('Cat5e', 'Cat6', 'Cat6a', 'OM3', 'OM4', 'OS2', 'DAC', 'AOC')),
+    length_m NUMERIC(6, 2) CHECK (length_m > 0),
+    color VARCHAR(30),
+    status VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'installed', 'decommissioning')),
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_cables_status ON cables(status);
+
+DROP TRIGGER IF EXISTS update_cables_updated_at ON cables;
+CREATE TRIGGER update_cables_updated_at BEFORE UPDATE ON cables
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- A connection is made with at most one cable, and a cable makes at most one connection
+ALTER TABLE network_connections
+ADD COLUMN IF NOT EXISTS cable_id INTEGER REFERENCES cables(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_network_connections_cable
+    ON network_connections(cable_id) WHERE cable_id IS NOT NULL;
+
+-- Add comments for documentation
+COMMENT ON COLUMN cables.length_m IS 'Cable length in meters';
+COMMENT ON COLUMN network_connections.cable_id IS 'Physical cable making this connection';
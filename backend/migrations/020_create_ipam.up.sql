@@ -0,0 +1,37 @@
+-- IP prefixes (subnets) managed by rackview
+CREATE TABLE IF NOT EXISTS ip_prefixes (
+    id SERIAL PRIMARY KEY,
+    prefix CIDR NOT NULL UNIQUE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_ip_prefixes_updated_at ON ip_prefixes;
+CREATE TRIGGER update_ip_prefixes_updated_at BEFORE UPDATE ON ip_prefixes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- IP addresses assigned to devices, optionally on a specific interface
+CREATE TABLE IF NOT EXISTS ip_addresses (
+    id SERIAL PRIMARY KEY,
+    address INET NOT NULL,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    interface_id INTEGER REFERENCES device_interfaces(id) ON DELETE CASCADE,
+    prefix_id INTEGER REFERENCES ip_prefixes(id) ON DELETE SET NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT ip_addresses_host_check CHECK (masklen(address) = CASE WHEN family(address) = 4 THEN 32 ELSE 128 END)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ip_addresses_address ON ip_addresses(address);
+CREATE INDEX IF NOT EXISTS idx_ip_addresses_device_id ON ip_addresses(device_id);
+CREATE INDEX IF NOT EXISTS idx_ip_addresses_prefix_id ON ip_addresses(prefix_id);
+
+DROP TRIGGER IF EXISTS update_ip_addresses_updated_at ON ip_addresses;
+CREATE TRIGGER update_ip_addresses_updated_at BEFORE UPDATE ON ip_addresses
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments for documentation
+COMMENT ON COLUMN ip_addresses.address IS 'Host address; an address is assigned at most once';
+COMMENT ON COLUMN ip_addresses.prefix_id IS 'Most specific prefix containing the address when it was assigned';
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS connection_vlans;
+DROP TABLE IF EXISTS interface_vlans;
+
+ALTER TABLE network_connections
+DROP COLUMN IF EXISTS vlan_mode,
+DROP COLUMN IF EXISTS untagged_vlan_id;
+
+ALTER TABLE device_interfaces
+DROP COLUMN IF EXISTS vlan_mode,
+DROP COLUMN IF EXISTS untagged_vlan_id;
+
+DROP TABLE IF EXISTS vlans;
+DROP TABLE IF EXISTS vlan_groups;
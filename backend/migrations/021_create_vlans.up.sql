@@ -0,0 +1,61 @@
+-- VLAN groups scope VIDs, optionally to a site
+CREATE TABLE IF NOT EXISTS vlan_groups (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    site_id INTEGER REFERENCES sites(id) ON DELETE CASCADE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_vlan_groups_updated_at ON vlan_groups;
+CREATE TRIGGER update_vlan_groups_updated_at BEFORE UPDATE ON vlan_groups
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS vlans (
+    id SERIAL PRIMARY KEY,
+    vid INTEGER NOT NULL CHECK (vid BETWEEN 1 AND 4094),
+    name VARCHAR(100) NOT NULL,
+    group_id INTEGER REFERENCES vlan_groups(id) ON DELETE CASCADE,
+    site_id INTEGER REFERENCES sites(id) ON DELETE CASCADE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A VID is unique within its group, or within its site (or globally) for ungrouped VLANs
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vlans_scope_vid ON vlans (COALESCE(group_id, 0), COALESCE(site_id, 0), vid);
+
+DROP TRIGGER IF EXISTS update_vlans_updated_at ON vlans;
+CREATE TRIGGER update_vlans_updated_at BEFORE UPDATE ON vlans
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Access/trunk tagging on interfaces and connections
+ALTER TABLE device_interfaces
+ADD COLUMN IF NOT EXISTS vlan_mode VARCHAR(10) CHECK (vlan_mode IN ('access', 'trunk')),
+ADD COLUMN IF NOT EXISTS untagged_vlan_id INTEGER REFERENCES vlans(id) ON DELETE SET NULL;
+
+ALTER TABLE network_connections
+ADD COLUMN IF NOT EXISTS vlan_mode VARCHAR(10) CHECK (vlan_mode IN ('access', 'trunk')),
+ADD COLUMN IF NOT EXISTS untagged_vlan_id INTEGER REFERENCES vlans(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS interface_vlans (
+    interface_id INTEGER NOT NULL REFERENCES device_interfaces(id) ON DELETE CASCADE,
+    vlan_id INTEGER NOT NULL REFERENCES vlans(id) ON DELETE CASCADE,
+    PRIMARY KEY (interface_id, vlan_id)
+);
+
+CREATE TABLE IF NOT EXISTS connection_vlans (
+    connection_id INTEGER NOT NULL REFERENCES network_connections(id) ON DELETE CASCADE,
+    vlan_id INTEGER NOT NULL REFERENCES vlans(id) ON DELETE CASCADE,
+    PRIMARY KEY (connection_id, vlan_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interface_vlans_vlan_id ON interface_vlans(vlan_id);
+CREATE INDEX IF NOT EXISTS idx_connection_vlans_vlan_id ON connection_vlans(vlan_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN device_interfaces.untagged_vlan_id IS 'Access VLAN, or native VLAN of a trunk';
+COMMENT ON COLUMN network_connections.untagged_vlan_id IS 'Access VLAN, or native VLAN of a trunk; overrides the interface tagging';
+COMMENT ON TABLE interface_vlans IS 'Tagged VLANs allowed on a trunk interface; none means all';
+COMMENT ON TABLE connection_vlans IS 'Tagged VLANs allowed on a trunk connection; none means all';
@@ -0,0 +1,5 @@
+-- btree_gist stays installed; other objects may use it
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_no_overlap;
+
+DROP FUNCTION IF EXISTS device_faces(VARCHAR, VARCHAR);
+DROP FUNCTION IF EXISTS device_u_range(INTEGER, INTEGER);
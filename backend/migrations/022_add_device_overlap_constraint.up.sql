@@ -0,0 +1,48 @@
+-- U-mounted devices in a rack never share a U on a common face. A device covers the U range
+-- [position_u - size_u + 1, position_u], which is empty for zero-U and chassis bay devices
+-- (size_u = 0), and a range of faces: 0 is the front, 1 the rear. Full-depth devices cover both
+-- faces, half-depth devices only their mounting face.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE OR REPLACE FUNCTION device_u_range(position_u INTEGER, size_u INTEGER)
+RETURNS int4range AS $$
+    SELECT int4range(position_u - size_u + 1, position_u + 1);
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION device_faces(mount_face VARCHAR, depth VARCHAR)
+RETURNS int4range AS $$
+    SELECT CASE
+        WHEN depth = 'half' AND mount_face = 'rear' THEN int4range(1, 2)
+        WHEN depth = 'half' THEN int4range(0, 1)
+        ELSE int4range(0, 2)
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Name an existing overlap instead of failing with the constraint's generic error
+DO $$
+DECLARE
+    conflict RECORD;
+BEGIN
+    SELECT a.name AS a_name, b.name AS b_name, r.name AS rack_name INTO conflict
+    FROM devices a
+    JOIN devices b ON b.rack_id = a.rack_id AND b.id > a.id
+    JOIN racks r ON r.id = a.rack_id
+    WHERE device_u_range(a.position_u, a.size_u) && device_u_range(b.position_u, b.size_u)
+    AND device_faces(a.mount_face, a.depth) && device_faces(b.mount_face, b.depth)
+    LIMIT 1;
+
+    IF FOUND THEN
+        RAISE EXCEPTION 'devices % and % overlap in rack %; move one of them before upgrading',
+            conflict.a_name, conflict.b_name, conflict.rack_name;
+    END IF;
+END $$;
+
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_no_overlap;
+ALTER TABLE devices ADD CONSTRAINT devices_no_overlap EXCLUDE USING gist (
+    rack_id WITH =,
+    device_u_range(position_u, size_u) WITH &&,
+    device_faces(mount_face, depth) WITH &&
+);
+
+-- Add comment for documentation
+COMMENT ON CONSTRAINT devices_no_overlap ON devices IS 'Devices in a rack do not share a U on a common face';
@@ -0,0 +1,21 @@
+-- A device ip_address is assigned to at most one device. Empty values mean no address.
+
+-- Name an existing duplicate instead of failing with the index's generic error
+DO $$
+DECLARE
+    duplicate RECORD;
+BEGIN
+    SELECT a.ip_address, a.name AS a_name, b.name AS b_name INTO duplicate
+    FROM devices a
+    JOIN devices b ON b.ip_address = a.ip_address AND b.id > a.id
+    WHERE a.ip_address IS NOT NULL AND a.ip_address != ''
+    LIMIT 1;
+
+    IF FOUND THEN
+        RAISE EXCEPTION 'devices % and % share ip address %; change one of them before upgrading',
+            duplicate.a_name, duplicate.b_name, duplicate.ip_address;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_ip_address ON devices(ip_address)
+    WHERE ip_address IS NOT NULL AND ip_address != '';
@@ -0,0 +1,22 @@
+-- Revert the consolidated schema; indexes and triggers are dropped with their tables
+DROP TABLE IF EXISTS device_alert_state;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS device_health_probes;
+DROP TABLE IF EXISTS health_checks;
+DROP TABLE IF EXISTS ip_addresses;
+DROP TABLE IF EXISTS ip_prefixes;
+DROP TABLE IF EXISTS connection_vlans;
+DROP TABLE IF EXISTS interface_vlans;
+DROP TABLE IF EXISTS network_connections;
+DROP TABLE IF EXISTS device_interfaces;
+DROP TABLE IF EXISTS vlans;
+DROP TABLE IF EXISTS vlan_groups;
+DROP TABLE IF EXISTS cables;
+DROP TABLE IF EXISTS device_specs;
+DROP TABLE IF EXISTS devices;
+DROP TABLE IF EXISTS device_types;
+DROP TABLE IF EXISTS racks;
+DROP TABLE IF EXISTS rack_rows;
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS sites;
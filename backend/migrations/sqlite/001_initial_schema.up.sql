@@ -0,0 +1,433 @@
+-- RackView Database Schema for SQLite
+-- Mirrors the Postgres schema as of migration 021; new Postgres migrations need a counterpart here.
+-- Types follow SQLite affinity: INET/CIDR and JSONB columns are TEXT, SERIAL ids are INTEGER PRIMARY KEY.
+
+-- Location hierarchy above racks: site > room > row
+CREATE TABLE IF NOT EXISTS sites (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    address TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    site_id INTEGER NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(site_id, name)
+);
+
+-- "rows" is an SQL keyword, so the table is rack_rows
+CREATE TABLE IF NOT EXISTS rack_rows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    room_id INTEGER NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(room_id, name)
+);
+
+-- Racks reference their most specific location; parent IDs are kept in sync by the service
+CREATE TABLE IF NOT EXISTS racks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    size_u INTEGER NOT NULL CHECK (size_u > 0),
+    site_id INTEGER REFERENCES sites(id) ON DELETE SET NULL,
+    room_id INTEGER REFERENCES rooms(id) ON DELETE SET NULL,
+    row_id INTEGER REFERENCES rack_rows(id) ON DELETE SET NULL,
+    power_capacity_watts INTEGER CHECK (power_capacity_watts IS NULL OR power_capacity_watts > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Device type catalog: reusable templates for devices of the same make and model
+CREATE TABLE IF NOT EXISTS device_types (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    manufacturer VARCHAR(255) NOT NULL,
+    model VARCHAR(255) NOT NULL,
+    type VARCHAR(50) NOT NULL CHECK (type IN ('server', 'network', 'storage')),
+    size_u INTEGER NOT NULL DEFAULT 1 CHECK (size_u >= 0),
+    icon VARCHAR(50),
+    specs TEXT NOT NULL DEFAULT '{}',
+    nameplate_watts INTEGER CHECK (nameplate_watts IS NULL OR nameplate_watts >= 0),
+    psu_count INTEGER CHECK (psu_count IS NULL OR psu_count > 0),
+    psu_redundancy VARCHAR(10) NOT NULL DEFAULT 'none' CHECK (psu_redundancy IN ('none', 'n+1', '2n')),
+    bay_count INTEGER CHECK (bay_count IS NULL OR bay_count > 0),
+    ports TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(manufacturer, model)
+);
+
+-- Devices mount on a U range, on a zero-U side of the rack, or in a bay of a parent chassis
+CREATE TABLE IF NOT EXISTS devices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    icon VARCHAR(50) DEFAULT '🖥️',
+    type VARCHAR(50) NOT NULL CHECK (type IN ('server', 'network', 'storage')),
+    position_u INTEGER NOT NULL,
+    size_u INTEGER NOT NULL,
+    status VARCHAR(50) DEFAULT 'online',
+    model VARCHAR(255),
+    ip_address VARCHAR(255),
+    health_check_url VARCHAR(500),
+    health_check_interval INTEGER CHECK (health_check_interval IS NULL OR health_check_interval > 0),
+    health_check_mode VARCHAR(20) NOT NULL DEFAULT 'all' CHECK (health_check_mode IN ('all', 'any', 'majority')),
+    nameplate_watts INTEGER CHECK (nameplate_watts IS NULL OR nameplate_watts >= 0),
+    measured_watts INTEGER CHECK (measured_watts IS NULL OR measured_watts >= 0),
+    psu_count INTEGER CHECK (psu_count IS NULL OR psu_count > 0),
+    psu_redundancy VARCHAR(10) NOT NULL DEFAULT 'none' CHECK (psu_redundancy IN ('none', 'n+1', '2n')),
+    mount_face VARCHAR(10) NOT NULL DEFAULT 'front' CHECK (mount_face IN ('front', 'rear')),
+    depth VARCHAR(10) NOT NULL DEFAULT 'full' CHECK (depth IN ('full', 'half')),
+    zero_u_side VARCHAR(10) CHECK (zero_u_side IN ('left', 'right')),
+    zero_u_slot VARCHAR(1) CHECK (zero_u_slot IN ('A', 'B')),
+    parent_device_id INTEGER REFERENCES devices(id) ON DELETE CASCADE,
+    bay INTEGER CHECK (bay IS NULL OR bay > 0),
+    bay_count INTEGER CHECK (bay_count IS NULL OR bay_count > 0),
+    device_type_id INTEGER REFERENCES device_types(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT devices_status_check CHECK (status IN ('online', 'offline', 'warning', 'unknown')),
+    -- Zero-U and child devices take no U space
+    CONSTRAINT devices_placement_check CHECK (
+        (parent_device_id IS NULL AND zero_u_side IS NULL AND zero_u_slot IS NULL AND position_u > 0 AND size_u > 0) OR
+        (parent_device_id IS NULL AND zero_u_side IS NOT NULL AND position_u = 0 AND size_u = 0) OR
+        (parent_device_id IS NOT NULL AND bay IS NOT NULL AND zero_u_side IS NULL AND position_u = 0 AND size_u = 0)
+    )
+);
+
+-- Device specs table (key-value pairs for flexible specifications)
+CREATE TABLE IF NOT EXISTS device_specs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    spec_key VARCHAR(255) NOT NULL,
+    spec_value TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(device_id, spec_key)
+);
+
+-- Physical and logical ports of a device
+CREATE TABLE IF NOT EXISTS device_interfaces (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(50),
+    speed VARCHAR(50),
+    mac_address VARCHAR(17),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    description TEXT,
+    rear_port_id INTEGER REFERENCES device_interfaces(id) ON DELETE SET NULL,
+    vlan_mode VARCHAR(10) CHECK (vlan_mode IN ('access', 'trunk')),
+    untagged_vlan_id INTEGER REFERENCES vlans(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(device_id, name),
+    CONSTRAINT device_interfaces_rear_port_check CHECK (rear_port_id != id)
+);
+
+-- Physical cable inventory
+CREATE TABLE IF NOT EXISTS cables (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    label VARCHAR(100) NOT NULL UNIQUE,
+    media VARCHAR(20) CHECK (media IN ('Cat5e', 'Cat6', 'Cat6a', 'OM3', 'OM4', 'OS2', 'DAC', 'AOC')),
+    length_m NUMERIC(6, 2) CHECK (length_m > 0),
+    color VARCHAR(30),
+    status VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'installed', 'decommissioning')),
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Network connections table (for network mapping)
+CREATE TABLE IF NOT EXISTS network_connections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    target_device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    source_interface_id INTEGER REFERENCES device_interfaces(id) ON DELETE SET NULL,
+    target_interface_id INTEGER REFERENCES device_interfaces(id) ON DELETE SET NULL,
+    cable_id INTEGER REFERENCES cables(id) ON DELETE SET NULL,
+    connection_type VARCHAR(100),
+    port_info TEXT,
+    speed VARCHAR(50),
+    vlan_mode VARCHAR(10) CHECK (vlan_mode IN ('access', 'trunk')),
+    untagged_vlan_id INTEGER REFERENCES vlans(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (source_device_id != target_device_id),
+    CONSTRAINT network_connections_interfaces_check CHECK (source_interface_id != target_interface_id)
+);
+
+-- VLAN groups scope VIDs, optionally to a site
+CREATE TABLE IF NOT EXISTS vlan_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    site_id INTEGER REFERENCES sites(id) ON DELETE CASCADE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS vlans (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    vid INTEGER NOT NULL CHECK (vid BETWEEN 1 AND 4094),
+    name VARCHAR(100) NOT NULL,
+    group_id INTEGER REFERENCES vlan_groups(id) ON DELETE CASCADE,
+    site_id INTEGER REFERENCES sites(id) ON DELETE CASCADE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS interface_vlans (
+    interface_id INTEGER NOT NULL REFERENCES device_interfaces(id) ON DELETE CASCADE,
+    vlan_id INTEGER NOT NULL REFERENCES vlans(id) ON DELETE CASCADE,
+    PRIMARY KEY (interface_id, vlan_id)
+);
+
+CREATE TABLE IF NOT EXISTS connection_vlans (
+    connection_id INTEGER NOT NULL REFERENCES network_connections(id) ON DELETE CASCADE,
+    vlan_id INTEGER NOT NULL REFERENCES vlans(id) ON DELETE CASCADE,
+    PRIMARY KEY (connection_id, vlan_id)
+);
+
+-- IP prefixes (subnets) and host addresses, stored in canonical text form
+CREATE TABLE IF NOT EXISTS ip_prefixes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    prefix TEXT NOT NULL UNIQUE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS ip_addresses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    address TEXT NOT NULL,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    interface_id INTEGER REFERENCES device_interfaces(id) ON DELETE CASCADE,
+    prefix_id INTEGER REFERENCES ip_prefixes(id) ON DELETE SET NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT ip_addresses_host_check CHECK (instr(address, '/') = 0)
+);
+
+-- Health check history (one row per manual or scheduled check)
+CREATE TABLE IF NOT EXISTS health_checks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL CHECK (status IN ('online', 'offline', 'warning', 'unknown')),
+    latency_ms INTEGER,
+    message TEXT,
+    checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Configurable health probes per device
+CREATE TABLE IF NOT EXISTS device_health_probes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    device_id INTEGER NOT NULL REFERENCES devices(id) ON DELETE CASCADE,
+    name VARCHAR(255),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('tcp', 'http', 'tls', 'dns')),
+    config TEXT NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Webhook subscriptions for device status alerts
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(1000) NOT NULL,
+    secret VARCHAR(255),
+    statuses TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per delivery attempt
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event VARCHAR(100) NOT NULL,
+    device_id INTEGER REFERENCES devices(id) ON DELETE SET NULL,
+    payload TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    success BOOLEAN NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    duration_ms INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Debounce state for status transition alerts
+CREATE TABLE IF NOT EXISTS device_alert_state (
+    device_id INTEGER PRIMARY KEY REFERENCES devices(id) ON DELETE CASCADE,
+    alert_status VARCHAR(50) NOT NULL,
+    candidate_status VARCHAR(50),
+    candidate_count INTEGER NOT NULL DEFAULT 0,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes for performance
+CREATE INDEX IF NOT EXISTS idx_rooms_site_id ON rooms(site_id);
+CREATE INDEX IF NOT EXISTS idx_rack_rows_room_id ON rack_rows(room_id);
+CREATE INDEX IF NOT EXISTS idx_racks_site_id ON racks(site_id);
+CREATE INDEX IF NOT EXISTS idx_racks_room_id ON racks(room_id);
+CREATE INDEX IF NOT EXISTS idx_racks_row_id ON racks(row_id);
+CREATE INDEX IF NOT EXISTS idx_devices_rack_id ON devices(rack_id);
+CREATE INDEX IF NOT EXISTS idx_devices_position ON devices(rack_id, position_u);
+CREATE INDEX IF NOT EXISTS idx_devices_device_type_id ON devices(device_type_id);
+CREATE INDEX IF NOT EXISTS idx_device_specs_device_id ON device_specs(device_id);
+CREATE INDEX IF NOT EXISTS idx_device_interfaces_device_id ON device_interfaces(device_id);
+CREATE INDEX IF NOT EXISTS idx_network_connections_source ON network_connections(source_device_id);
+CREATE INDEX IF NOT EXISTS idx_network_connections_target ON network_connections(target_device_id);
+CREATE INDEX IF NOT EXISTS idx_cables_status ON cables(status);
+CREATE INDEX IF NOT EXISTS idx_interface_vlans_vlan_id ON interface_vlans(vlan_id);
+CREATE INDEX IF NOT EXISTS idx_connection_vlans_vlan_id ON connection_vlans(vlan_id);
+CREATE INDEX IF NOT EXISTS idx_ip_addresses_device_id ON ip_addresses(device_id);
+CREATE INDEX IF NOT EXISTS idx_ip_addresses_prefix_id ON ip_addresses(prefix_id);
+CREATE INDEX IF NOT EXISTS idx_health_checks_device_checked_at ON health_checks(device_id, checked_at DESC);
+CREATE INDEX IF NOT EXISTS idx_health_checks_checked_at ON health_checks(checked_at);
+CREATE INDEX IF NOT EXISTS idx_device_health_probes_device_id ON device_health_probes(device_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
+
+-- A side slot holds a single zero-U device, and a bay a single child device
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_zero_u_slot
+    ON devices(rack_id, zero_u_side, zero_u_slot) WHERE zero_u_slot IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_parent_bay ON devices(parent_device_id, bay) WHERE parent_device_id IS NOT NULL;
+
+-- A rear port pairs with at most one front port
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_interfaces_rear_port
+    ON device_interfaces(rear_port_id) WHERE rear_port_id IS NOT NULL;
+
+-- An interface carries at most one connection, and a cable makes at most one connection
+CREATE UNIQUE INDEX IF NOT EXISTS idx_network_connections_source_interface
+    ON network_connections(source_interface_id) WHERE source_interface_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_network_connections_target_interface
+    ON network_connections(target_interface_id) WHERE target_interface_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_network_connections_cable
+    ON network_connections(cable_id) WHERE cable_id IS NOT NULL;
+
+-- A VID is unique within its group, or within its site (or globally) for ungrouped VLANs
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vlans_scope_vid ON vlans (COALESCE(group_id, 0), COALESCE(site_id, 0), vid);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ip_addresses_address ON ip_addresses(address);
+
+-- The unique indexes cover each column; these cover an interface appearing as the source of one
+-- connection and the target of another. SQLite serializes writers, so no lock is needed.
+CREATE TRIGGER IF NOT EXISTS check_network_connections_interfaces_insert
+    BEFORE INSERT ON network_connections
+    FOR EACH ROW WHEN EXISTS (
+        SELECT 1 FROM network_connections
+        WHERE source_interface_id IN (NEW.source_interface_id, NEW.target_interface_id)
+        OR target_interface_id IN (NEW.source_interface_id, NEW.target_interface_id)
+    )
+BEGIN
+    SELECT RAISE(ABORT, 'interface already carries a cable');
+END;
+
+CREATE TRIGGER IF NOT EXISTS check_network_connections_interfaces_update
+    BEFORE UPDATE OF source_interface_id, target_interface_id ON network_connections
+    FOR EACH ROW WHEN EXISTS (
+        SELECT 1 FROM network_connections
+        WHERE id != NEW.id
+        AND (source_interface_id IN (NEW.source_interface_id, NEW.target_interface_id)
+            OR target_interface_id IN (NEW.source_interface_id, NEW.target_interface_id))
+    )
+BEGIN
+    SELECT RAISE(ABORT, 'interface already carries a cable');
+END;
+
+-- Triggers to auto-update updated_at. RETURNING does not see trigger changes, so the driver also
+-- sets updated_at in every UPDATE it runs; these cover statements from other clients.
+CREATE TRIGGER IF NOT EXISTS update_sites_updated_at AFTER UPDATE ON sites
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE sites SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_rooms_updated_at AFTER UPDATE ON rooms
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE rooms SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_rack_rows_updated_at AFTER UPDATE ON rack_rows
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE rack_rows SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_racks_updated_at AFTER UPDATE ON racks
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE racks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_device_types_updated_at AFTER UPDATE ON device_types
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE device_types SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_devices_updated_at AFTER UPDATE ON devices
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE devices SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_device_interfaces_updated_at AFTER UPDATE ON device_interfaces
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE device_interfaces SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_cables_updated_at AFTER UPDATE ON cables
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE cables SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_vlan_groups_updated_at AFTER UPDATE ON vlan_groups
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE vlan_groups SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_vlans_updated_at AFTER UPDATE ON vlans
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE vlans SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_ip_prefixes_updated_at AFTER UPDATE ON ip_prefixes
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE ip_prefixes SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_ip_addresses_updated_at AFTER UPDATE ON ip_addresses
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE ip_addresses SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_device_health_probes_updated_at AFTER UPDATE ON device_health_probes
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE device_health_probes SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_webhooks_updated_at AFTER UPDATE ON webhooks
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE webhooks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
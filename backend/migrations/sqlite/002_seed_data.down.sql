@@ -0,0 +1,25 @@
+-- Remove only the seeded devices, matched by their seeded rack, name and position; devices added
+-- since stay, and so do the seed racks holding them. Specs go with their devices.
+DELETE FROM devices
+WHERE (rack_id, name, position_u) IN (VALUES
+    (1, 'Atlas 01', 21),
+    (1, 'TrueNAS', 18),
+    (1, 'JBOD 12x3.5', 16),
+    (1, 'JBOD 24x2.5', 14),
+    (1, 'JBOD 24x2.5', 12),
+    (1, 'HP BladeCenter', 10),
+    (2, 'Router', 24),
+    (2, 'Fiber PatchPanel', 23),
+    (2, 'Core Switch', 22),
+    (2, 'RJ45 PatchPanel', 21),
+    (2, 'RJ45 PoE Switch', 20),
+    (2, 'UPS-PDU 1', 17),
+    (2, 'UPS-PDU 2', 15),
+    (2, 'UPS System', 10),
+    (2, 'UPS System', 4)
+);
+
+-- Remove the seed racks that are still empty
+DELETE FROM racks
+WHERE (id, name) IN (VALUES (1, 'Rack A — Compute'), (2, 'Rack B — Network & Storage'))
+AND NOT EXISTS (SELECT 1 FROM devices WHERE devices.rack_id = racks.id);
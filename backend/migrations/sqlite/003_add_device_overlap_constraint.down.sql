@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS check_devices_no_overlap_insert;
+DROP TRIGGER IF EXISTS check_devices_no_overlap_update;
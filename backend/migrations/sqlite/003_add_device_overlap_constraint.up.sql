@@ -0,0 +1,29 @@
+-- U-mounted devices in a rack never share a U on a common face; zero-U and chassis bay devices
+-- (size_u = 0) take no U. Full-depth devices cover both faces, half-depth devices only their
+-- mounting face. SQLite has no exclusion constraints, so triggers raise the error the Postgres
+-- devices_no_overlap constraint would.
+CREATE TRIGGER IF NOT EXISTS check_devices_no_overlap_insert
+    BEFORE INSERT ON devices
+    FOR EACH ROW WHEN NEW.size_u > 0 AND EXISTS (
+        SELECT 1 FROM devices d
+        WHERE d.rack_id = NEW.rack_id AND d.size_u > 0
+        AND d.position_u - d.size_u + 1 <= NEW.position_u
+        AND NEW.position_u - NEW.size_u + 1 <= d.position_u
+        AND NOT (d.depth = 'half' AND NEW.depth = 'half' AND d.mount_face != NEW.mount_face)
+    )
+BEGIN
+    SELECT RAISE(ABORT, 'EXCLUDE constraint failed: devices_no_overlap');
+END;
+
+CREATE TRIGGER IF NOT EXISTS check_devices_no_overlap_update
+    BEFORE UPDATE OF rack_id, position_u, size_u, mount_face, depth ON devices
+    FOR EACH ROW WHEN NEW.size_u > 0 AND EXISTS (
+        SELECT 1 FROM devices d
+        WHERE d.id != NEW.id AND d.rack_id = NEW.rack_id AND d.size_u > 0
+        AND d.position_u - d.size_u + 1 <= NEW.position_u
+        AND NEW.position_u - NEW.size_u + 1 <= d.position_u
+        AND NOT (d.depth = 'half' AND NEW.depth = 'half' AND d.mount_face != NEW.mount_face)
+    )
+BEGIN
+    SELECT RAISE(ABORT, 'EXCLUDE constraint failed: devices_no_overlap');
+END;
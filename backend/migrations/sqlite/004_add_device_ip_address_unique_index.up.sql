@@ -0,0 +1,3 @@
+-- A device ip_address is assigned to at most one device. Empty values mean no address.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_ip_address ON devices(ip_address)
+    WHERE ip_address IS NOT NULL AND ip_address != '';